@@ -0,0 +1,271 @@
+package aircraft
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl aircraft [list]
+  openppl aircraft check [tail] [--date YYYY-MM-DD]
+  openppl aircraft set <tail> [--type C172] [--for-hire] [--ifr] [--annual YYYY-MM-DD]
+      [--hundred-hour-tach N] [--tach N] [--transponder YYYY-MM-DD] [--elt YYYY-MM-DD]
+      [--elt-battery YYYY-MM-DD] [--pitot-static YYYY-MM-DD] [--vor YYYY-MM-DD]
+      [--cruise KTAS] [--fuel-burn GPH] [--usable-fuel GAL] [--notes "<text>"]
+  openppl aircraft remove <tail>
+  openppl aircraft ad add <tail> <number> [--due YYYY-MM-DD] [--due-tach N] [--desc "<text>"]
+  openppl aircraft ad remove <tail> <number>`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl aircraft [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "list":
+		return runCheck(database, nil, stdout, false)
+	case "check":
+		return runCheck(database, args[1:], stdout, true)
+	case "set", "add":
+		return runSet(database, args[1:], stdout)
+	case "remove", "rm", "delete":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl aircraft remove <tail>")
+			return 1
+		}
+		if err := services.DeleteAircraft(database, args[1], now()); err != nil {
+			fmt.Fprintf(stdout, "Could not remove aircraft: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Removed %s\n", strings.ToUpper(args[1]))
+		return 0
+	case "ad":
+		return runAD(database, args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+// runCheck prints the airworthiness of every aircraft (or one). With strict
+// set, it exits 1 when any check fails, so it can gate scripts.
+func runCheck(database *gorm.DB, args []string, stdout io.Writer, strict bool) int {
+	flags := flag.NewFlagSet("aircraft check", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	date := flags.String("date", "", "checkride date, YYYY-MM-DD (default: the study plan's)")
+	tail := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		tail, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	var checkride time.Time
+	if *date != "" {
+		parsed, ok := parseDate("--date", *date, stdout)
+		if !ok {
+			return 1
+		}
+		checkride = parsed
+	}
+
+	reports, err := services.LoadAircraftReports(database, now())
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load aircraft: %v\n", err)
+		return 1
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(stdout, "No aircraft registered yet. Add one with `openppl aircraft set N12345 --type C172 --annual YYYY-MM-DD`.")
+		return 0
+	}
+
+	failed := false
+	shown := 0
+	for _, report := range reports {
+		if tail != "" && !strings.EqualFold(report.Aircraft.TailNumber, tail) {
+			continue
+		}
+		if !checkride.IsZero() {
+			report.Checkride = checkride
+			report.Checks = services.CheckAirworthiness(report.Aircraft, checkride, now())
+		}
+		printReport(report, stdout)
+		shown++
+		failed = failed || !report.Airworthy()
+	}
+	if shown == 0 {
+		fmt.Fprintf(stdout, "Aircraft %s is not registered.\n", strings.ToUpper(tail))
+		return 1
+	}
+	if strict && failed {
+		return 1
+	}
+	return 0
+}
+
+func printReport(report services.AircraftReport, stdout io.Writer) {
+	a := report.Aircraft
+	title := a.TailNumber
+	if a.Type != "" {
+		title += " (" + a.Type + ")"
+	}
+	verdict := "airworthy"
+	if !report.Airworthy() {
+		verdict = "NOT airworthy"
+	}
+	if !report.Checkride.IsZero() {
+		verdict += " for the " + report.Checkride.Format("2006-01-02") + " checkride"
+	}
+	fmt.Fprintf(stdout, "%s — %s\n", title, verdict)
+	if a.CruiseKTAS > 0 || a.FuelBurnGPH > 0 || a.UsableFuelGal > 0 {
+		fmt.Fprintf(stdout, "  cruise %.0f KTAS, %.1f gal/h, %.0f gal usable\n", a.CruiseKTAS, a.FuelBurnGPH, a.UsableFuelGal)
+	}
+	for _, check := range report.Checks {
+		mark := "ok"
+		if check.Failed() {
+			mark = strings.ToUpper(check.Status)
+		}
+		reference := ""
+		if check.Reference != "" {
+			reference = " (" + check.Reference + ")"
+		}
+		fmt.Fprintf(stdout, "  %-8s %s%s: %s\n", mark, check.Item, reference, check.Detail)
+	}
+}
+
+func runSet(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	tail := args[0]
+
+	flags := flag.NewFlagSet("aircraft set", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	aircraftType := flags.String("type", "", "aircraft type, e.g. C172")
+	forHire := flags.Bool("for-hire", false, "used for hire or flight instruction (needs 100-hour inspections)")
+	ifr := flags.Bool("ifr", false, "operated IFR (needs pitot-static and VOR checks)")
+	hundredHourTach := flags.Float64("hundred-hour-tach", 0, "tach time at the last 100-hour inspection")
+	tach := flags.Float64("tach", 0, "current tach time")
+	cruise := flags.Float64("cruise", 0, "cruise true airspeed in knots")
+	fuelBurn := flags.Float64("fuel-burn", 0, "cruise fuel burn in gallons per hour")
+	usableFuel := flags.Float64("usable-fuel", 0, "usable fuel in gallons")
+	notes := flags.String("notes", "", "notes")
+	dates := map[string]*string{}
+	for _, name := range []string{"annual", "transponder", "elt", "elt-battery", "pitot-static", "vor"} {
+		dates[name] = flags.String(name, "", name+" date, YYYY-MM-DD")
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	var update services.AircraftUpdate
+	ok := true
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "type":
+			update.Type = aircraftType
+		case "for-hire":
+			update.ForHire = forHire
+		case "ifr":
+			update.IFR = ifr
+		case "hundred-hour-tach":
+			update.HundredHourTach = hundredHourTach
+		case "tach":
+			update.CurrentTach = tach
+		case "cruise":
+			update.CruiseKTAS = cruise
+		case "fuel-burn":
+			update.FuelBurnGPH = fuelBurn
+		case "usable-fuel":
+			update.UsableFuelGal = usableFuel
+		case "notes":
+			update.Notes = notes
+		default:
+			parsed, valid := parseDate("--"+f.Name, *dates[f.Name], stdout)
+			ok = ok && valid
+			target := map[string]**time.Time{
+				"annual":       &update.Annual,
+				"transponder":  &update.Transponder,
+				"elt":          &update.ELTInspection,
+				"elt-battery":  &update.ELTBatteryDue,
+				"pitot-static": &update.PitotStatic,
+				"vor":          &update.VORCheck,
+			}[f.Name]
+			*target = &parsed
+		}
+	})
+	if !ok {
+		return 1
+	}
+
+	aircraft, err := services.SaveAircraft(database, tail, update, now())
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not save aircraft: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Saved %s\n", aircraft.TailNumber)
+	return 0
+}
+
+func runAD(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) < 3 || (args[0] != "add" && args[0] != "remove") {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	tail, number := args[1], args[2]
+	if args[0] == "remove" {
+		if err := services.DeleteAircraftAD(database, tail, number, now()); err != nil {
+			fmt.Fprintf(stdout, "Could not remove AD: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Removed AD %s from %s\n", number, strings.ToUpper(tail))
+		return 0
+	}
+
+	flags := flag.NewFlagSet("aircraft ad add", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	due := flags.String("due", "", "next due date, YYYY-MM-DD")
+	dueTach := flags.Float64("due-tach", 0, "next due tach time")
+	description := flags.String("desc", "", "what the AD covers")
+	if err := flags.Parse(args[3:]); err != nil {
+		return 1
+	}
+
+	ad := model.AircraftAD{Number: number, Description: *description, NextDueTach: *dueTach}
+	if *due != "" {
+		parsed, ok := parseDate("--due", *due, stdout)
+		if !ok {
+			return 1
+		}
+		ad.NextDueDate = &parsed
+	}
+	saved, err := services.SaveAircraftAD(database, tail, ad, now())
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not save AD: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Saved AD %s on %s\n", saved.Number, strings.ToUpper(tail))
+	return 0
+}
+
+func parseDate(name, value string, stdout io.Writer) (time.Time, bool) {
+	parsed, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		fmt.Fprintf(stdout, "Invalid %s %q, want YYYY-MM-DD\n", name, value)
+		return time.Time{}, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,126 @@
+package airports
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl airports [search <text>]
+  openppl airports show <ICAO>
+  openppl airports distance [<from>] <to> [<to> ...]`
+
+// Execute is the dispatcher for `openppl airports [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	airports, err := services.LoadAirports()
+	if err != nil {
+		fmt.Fprintf(stdout, "Warning: %v\n", err)
+		if airports == nil {
+			return 1
+		}
+	}
+
+	switch sub {
+	case "", "list", "search", "find":
+		query := ""
+		if len(args) > 1 {
+			query = strings.Join(args[1:], " ")
+		}
+		return runSearch(airports, query, stdout)
+	case "show", "info":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl airports show <ICAO>")
+			return 1
+		}
+		return runShow(airports, args[1], stdout)
+	case "distance", "route", "xc":
+		return runDistance(database, airports, args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runSearch(airports services.AirportDatabase, query string, stdout io.Writer) int {
+	matches := airports.Search(query)
+	if len(matches) == 0 {
+		fmt.Fprintf(stdout, "No airports match %q. Add your own to ~/.openppl/airports.csv.\n", query)
+		return 0
+	}
+	for _, airport := range matches {
+		fmt.Fprintf(stdout, "  %-5s %-50s %s\n", airport.ICAO, airport.Name, towerLabel(airport))
+	}
+	fmt.Fprintf(stdout, "%d airports\n", len(matches))
+	return 0
+}
+
+func runShow(airports services.AirportDatabase, code string, stdout io.Writer) int {
+	airport, ok := airports.Lookup(code)
+	if !ok {
+		fmt.Fprintf(stdout, "%s is not in the airport database. Add it to ~/.openppl/airports.csv.\n", strings.ToUpper(code))
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s %s\n", airport.ICAO, airport.Name)
+	fmt.Fprintf(stdout, "  Position:  %.4f, %.4f\n", airport.Latitude, airport.Longitude)
+	fmt.Fprintf(stdout, "  Elevation: %d ft\n", airport.ElevationFt)
+	fmt.Fprintf(stdout, "  Runways:   %s\n", strings.Join(airport.Runways, ", "))
+	fmt.Fprintf(stdout, "  Tower:     %s\n", towerLabel(airport))
+	fmt.Fprintf(stdout, "  Time zone: %s\n", airport.TimeZone)
+	return 0
+}
+
+// runDistance measures a route. With a single airport the route starts at
+// the school airport.
+func runDistance(database *gorm.DB, airports services.AirportDatabase, codes []string, stdout io.Writer) int {
+	if len(codes) == 1 {
+		school, err := services.SchoolAirport(database)
+		if err != nil || school == "" {
+			fmt.Fprintln(stdout, "No school airport set; pass the departure airport too.")
+			return 1
+		}
+		codes = append([]string{school}, codes...)
+	}
+	route, err := services.PlanRoute(airports, codes)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+
+	for _, leg := range route.Legs {
+		fmt.Fprintf(stdout, "  %s -> %s  %6.1f nm\n", leg.From.ICAO, leg.To.ICAO, leg.DistanceNM)
+	}
+	fmt.Fprintf(stdout, "Total: %.1f nm\n", route.TotalNM())
+
+	farthest, distance := route.FarthestLandingNM()
+	if route.CountsAsCrossCountry() {
+		fmt.Fprintf(stdout, "Counts as cross-country time: landing at %s, %.1f nm from %s (61.1).\n", farthest.ICAO, distance, route.Airports[0].ICAO)
+	} else {
+		fmt.Fprintf(stdout, "Not cross-country time: no landing more than 50 nm from %s (farthest %.1f nm).\n", route.Airports[0].ICAO, distance)
+	}
+	if len(route.Airports) > 2 {
+		if problems := route.SoloCrossCountryProblems(); len(problems) == 0 {
+			fmt.Fprintln(stdout, "Meets the long solo cross-country of 61.109(a)(5)(ii).")
+		} else {
+			fmt.Fprintf(stdout, "Short of the long solo cross-country of 61.109(a)(5)(ii): %s.\n", strings.Join(problems, "; "))
+		}
+	}
+	return 0
+}
+
+func towerLabel(airport services.Airport) string {
+	if airport.Towered {
+		return "towered"
+	}
+	return "non-towered"
+}
@@ -0,0 +1,109 @@
+package airports
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestSearchAndShow(t *testing.T) {
+	db := setupAirportsCLITestDB(t)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"search", "fort", "lauderdale"}, &out); code != 0 {
+		t.Fatalf("search = %d", code)
+	}
+	if !strings.Contains(out.String(), "KFXE") || !strings.Contains(out.String(), "2 airports") {
+		t.Fatalf("unexpected search output %q", out.String())
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"show", "fxe"}, &out); code != 0 {
+		t.Fatalf("show = %d", code)
+	}
+	if !strings.Contains(out.String(), "Runways:   09/27") || !strings.Contains(out.String(), "Tower:     towered") {
+		t.Fatalf("unexpected show output %q", out.String())
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"search", "nowhere at all"}, &out); code != 0 || !strings.Contains(out.String(), `No airports match "nowhere at all"`) {
+		t.Fatalf("empty search = %d, output %q", code, out.String())
+	}
+}
+
+func TestDistance_ChecksCrossCountryRules(t *testing.T) {
+	db := setupAirportsCLITestDB(t)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"distance", "KFXE", "KVRB", "KOBE", "KFXE"}, &out); code != 0 {
+		t.Fatalf("distance = %d; output %q", code, out.String())
+	}
+	for _, want := range []string{"KFXE -> KVRB", "Counts as cross-country time: landing at KVRB", "Meets the long solo cross-country"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got %q", want, out.String())
+		}
+	}
+
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+	out.Reset()
+	if code := Execute(db, []string{"distance", "KPBI"}, &out); code != 0 {
+		t.Fatalf("distance from school = %d; output %q", code, out.String())
+	}
+	if !strings.Contains(out.String(), "KFXE -> KPBI") || !strings.Contains(out.String(), "Not cross-country time") {
+		t.Fatalf("expected a leg from the school airport, got %q", out.String())
+	}
+}
+
+func TestInvalidInputExitsOne(t *testing.T) {
+	db := setupAirportsCLITestDB(t)
+	for _, args := range [][]string{
+		{"show"},
+		{"show", "KNOPE"},
+		{"distance", "KPBI"},
+		{"distance", "KFXE", "KNOPE"},
+		{"fly"},
+	} {
+		var out bytes.Buffer
+		if code := Execute(db, args, &out); code != 1 {
+			t.Fatalf("Execute(%v) = %d, want 1; output %q", args, code, out.String())
+		}
+	}
+}
+
+func TestUserAirportsFileErrorStillUsesEmbeddedData(t *testing.T) {
+	db := setupAirportsCLITestDB(t)
+	path := filepath.Join(t.TempDir(), "airports.csv")
+	if err := os.WriteFile(path, []byte("not,a,valid\n"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("OPENPPL_AIRPORTS_PATH", path)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"show", "KFXE"}, &out); code != 0 {
+		t.Fatalf("show = %d; output %q", code, out.String())
+	}
+	if !strings.HasPrefix(out.String(), "Warning: ") || !strings.Contains(out.String(), "KFXE") {
+		t.Fatalf("expected a warning and the embedded airport, got %q", out.String())
+	}
+}
+
+func setupAirportsCLITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("OPENPPL_AIRPORTS_PATH", filepath.Join(t.TempDir(), "airports.csv"))
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
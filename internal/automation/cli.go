@@ -88,6 +88,8 @@ func runAction(database *gorm.DB, args []string, stdout io.Writer, stderr io.Wri
 	name := fs.String("name", "", "allowlisted action name")
 	requestID := fs.String("request-id", "", "idempotency request identifier")
 	actorScope := fs.String("actor-scope", "default", "actor scope for idempotency")
+	actionArgs := actionArgFlag{}
+	fs.Var(actionArgs, "arg", "action argument as key=value, repeatable")
 
 	if err := fs.Parse(args); err != nil {
 		writeError(stderr, services.AutomationActionResponse{
@@ -112,7 +114,7 @@ func runAction(database *gorm.DB, args []string, stdout io.Writer, stderr io.Wri
 		Name:       strings.TrimSpace(*name),
 		RequestID:  strings.TrimSpace(*requestID),
 		ActorScope: strings.TrimSpace(*actorScope),
-		Args:       map[string]string{},
+		Args:       actionArgs,
 	})
 	if err != nil {
 		actionErr := mapCommandError(err, services.AutomationResultStateRejected)
@@ -156,3 +158,20 @@ func writeJSON(writer io.Writer, payload any) {
 func writeError(stderr io.Writer, payload any) {
 	writeJSON(stderr, payload)
 }
+
+// actionArgFlag collects repeated --arg key=value flags into action args.
+type actionArgFlag map[string]string
+
+func (a actionArgFlag) String() string {
+	return fmt.Sprint(map[string]string(a))
+}
+
+func (a actionArgFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("invalid --arg %q, want key=value", value)
+	}
+	a[key] = val
+	return nil
+}
@@ -109,6 +109,23 @@ func TestAutomationAction(t *testing.T) {
 	if !strings.Contains(stderr.String(), "action.not_allowlisted") {
 		t.Fatalf("expected allowlist rejection, got %s", stderr.String())
 	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = Execute(db, []string{"action", "--name", "task.add", "--request-id", "req-3", "--arg", "date=2026-03-02", "--arg", "category=CFI Flights", "--arg", "title=Pattern work"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected task.add success, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `"task_title":"Pattern work"`) {
+		t.Fatalf("expected added task payload, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = Execute(db, []string{"action", "--name", "task.add", "--request-id", "req-4", "--arg", "title"}, &stdout, &stderr)
+	if code == 0 || !strings.Contains(stderr.String(), "action.invalid_flags") {
+		t.Fatalf("expected malformed --arg to be rejected, got %d, stderr=%s", code, stderr.String())
+	}
 }
 
 func setupAutomationCLITestDB(t *testing.T) *gorm.DB {
@@ -118,7 +135,7 @@ func setupAutomationCLITestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("open sqlite: %v", err)
 	}
-	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.AutomationIdempotency{}); err != nil {
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.AutomationIdempotency{}, &model.ChecklistItem{}, &model.Milestone{}, &model.OutboxEvent{}); err != nil {
 		t.Fatalf("automigrate: %v", err)
 	}
 	return db
@@ -0,0 +1,143 @@
+package checklist
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl checklist [list]
+  openppl checklist upgrade                 Merge a newer default checklist, keeping completion state
+  openppl checklist reset --yes             Replace the checklist (custom items included) with the default
+  openppl checklist add --category <name> "<title>"
+  openppl checklist remove <id>`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl checklist [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "list":
+		return runList(database, stdout)
+	case "upgrade":
+		return runUpgrade(database, stdout)
+	case "reset":
+		return runReset(database, args[1:], stdout)
+	case "add":
+		return runAdd(database, args[1:], stdout)
+	case "remove", "rm", "delete":
+		return runRemove(database, args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runList(database *gorm.DB, stdout io.Writer) int {
+	if err := services.SeedChecklist(database); err != nil {
+		fmt.Fprintf(stdout, "Could not seed checklist: %v\n", err)
+		return 1
+	}
+	items, err := services.ListChecklistItems(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load checklist: %v\n", err)
+		return 1
+	}
+	version, _ := services.ChecklistVersion(database)
+
+	done := 0
+	category := ""
+	fmt.Fprintf(stdout, "Pre-checkride checklist (default v%d):\n", version)
+	for _, item := range items {
+		if string(item.Category) != category {
+			category = string(item.Category)
+			fmt.Fprintf(stdout, "%s\n", category)
+		}
+		mark := "[ ]"
+		if item.Completed {
+			mark = "[x]"
+			done++
+		}
+		suffix := ""
+		if item.TemplateKey == "" {
+			suffix = " (custom)"
+		}
+		fmt.Fprintf(stdout, "  %s #%d %s%s\n", mark, item.ID, item.Title, suffix)
+	}
+	fmt.Fprintf(stdout, "%d/%d complete\n", done, len(items))
+	return 0
+}
+
+func runUpgrade(database *gorm.DB, stdout io.Writer) int {
+	result, err := services.UpgradeChecklist(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Checklist upgrade failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Checklist upgraded from v%d to v%d: %d added, %d updated, %d kept as custom items.\n",
+		result.FromVersion, result.ToVersion, result.Added, result.Updated, result.Retired)
+	return 0
+}
+
+func runReset(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 || args[0] != "--yes" {
+		fmt.Fprintln(stdout, "This clears every checklist item, including custom items and completion state.")
+		fmt.Fprintln(stdout, "Run `openppl checklist reset --yes` to continue, or `openppl checklist upgrade` to merge instead.")
+		return 1
+	}
+	result, err := services.ResetChecklist(database, now())
+	if err != nil {
+		fmt.Fprintf(stdout, "Checklist reset failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Checklist reset to the default v%d (%d items).\n", result.ToVersion, result.Added)
+	return 0
+}
+
+func runAdd(database *gorm.DB, args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("checklist add", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	category := flags.String("category", "", "Documents, Aircraft, Ground or Flight")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	item, err := services.AddChecklistItem(database, *category, strings.Join(flags.Args(), " "))
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not add checklist item: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Added checklist item #%d under %s: %s\n", item.ID, item.Category, item.Title)
+	return 0
+}
+
+func runRemove(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stdout, "usage: openppl checklist remove <id>")
+		return 1
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || id == 0 {
+		fmt.Fprintf(stdout, "Invalid checklist item id %q\n", args[0])
+		return 1
+	}
+	if err := services.DeleteChecklistItem(database, uint(id)); err != nil {
+		fmt.Fprintf(stdout, "Could not remove checklist item: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Removed checklist item #%d\n", id)
+	return 0
+}
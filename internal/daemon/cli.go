@@ -0,0 +1,190 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const (
+	tickInterval = 30 * time.Second
+	// snapshotInterval is how often the daemon records a progress snapshot.
+	snapshotInterval = time.Hour
+	unitName         = "openppl-daemon.service"
+)
+
+var (
+	loadConfig     = services.LoadNotificationConfig
+	newNotifier    = services.NewDesktopNotifier
+	openMOTDDB     = services.InitMOTDDB
+	executablePath = os.Executable
+)
+
+// Execute is the dispatcher for `openppl daemon [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "run":
+		return runDaemon(database, stdout, false)
+	case "once":
+		return runDaemon(database, stdout, true)
+	case "install":
+		return runInstall(stdout)
+	default:
+		fmt.Fprintln(stdout, "usage: openppl daemon [run|once|install]")
+		return 1
+	}
+}
+
+func runDaemon(database *gorm.DB, stdout io.Writer, once bool) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return serve(ctx, database, stdout, once, tickInterval)
+}
+
+// serve runs the scheduler until ctx ends, or for a single tick when once
+// is set. The MOTD database is opened once and shared by every tick.
+func serve(ctx context.Context, database *gorm.DB, stdout io.Writer, once bool, interval time.Duration) int {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load daemon config: %v\n", err)
+		return 1
+	}
+
+	notifier, err := newNotifier(ctx)
+	if err != nil {
+		fmt.Fprintf(stdout, "Desktop notifications unavailable: %v\n", err)
+		return 1
+	}
+
+	motdDB, err := openMOTDDB()
+	if err != nil {
+		fmt.Fprintf(stdout, "Quiz database unavailable, the quiz nudge assumes no answer: %v\n", err)
+		motdDB = nil
+	} else {
+		defer func() {
+			if sqlDB, err := motdDB.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}()
+	}
+
+	scheduler := NewScheduler(database, cfg, notifier, stdout).
+		WithQuizCheck(quizAnsweredChecker(motdDB)).
+		WithSnapshots(func(now time.Time) error {
+			var attempts []services.MOTDAnswer
+			if motdDB != nil {
+				attempts, _ = services.LoadMOTDAttempts(motdDB)
+			}
+			_, err := services.RecordProgressSnapshot(database, attempts, now)
+			return err
+		})
+	if once {
+		sent, err := scheduler.Tick(ctx)
+		if err != nil {
+			fmt.Fprintf(stdout, "Reminder check failed: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%d notifications sent via %s\n", sent, notifier.Name())
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "openppl daemon running (notifications via %s). Press Ctrl+C to stop.\n", notifier.Name())
+	if err := scheduler.Run(ctx, interval); err != nil {
+		fmt.Fprintf(stdout, "Daemon stopped: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// quizAnsweredChecker reads the per-user MOTD database. Without it the quiz
+// counts as unanswered, so the nudge still fires.
+func quizAnsweredChecker(db *gorm.DB) func(time.Time) bool {
+	return func(now time.Time) bool {
+		if db == nil {
+			return false
+		}
+		answered, err := services.HasMOTDAttemptOn(db, now)
+		return err == nil && answered
+	}
+}
+
+// runInstall writes a systemd user unit that keeps the daemon running in the
+// desktop session. Unlike `motd install` it does not need root.
+func runInstall(stdout io.Writer) int {
+	exePath, err := executablePath()
+	if err != nil {
+		fmt.Fprintf(stdout, "daemon install: failed to determine binary path: %v\n", err)
+		return 1
+	}
+	if resolved, err := filepath.EvalSymlinks(exePath); err == nil {
+		exePath = resolved
+	}
+
+	unitDir, err := userUnitDir()
+	if err != nil {
+		fmt.Fprintf(stdout, "daemon install: %v\n", err)
+		return 1
+	}
+	if err := os.MkdirAll(unitDir, 0o755); err != nil {
+		fmt.Fprintf(stdout, "daemon install: failed to create %s: %v\n", unitDir, err)
+		return 1
+	}
+
+	unitPath := filepath.Join(unitDir, unitName)
+	if err := os.WriteFile(unitPath, []byte(systemdUnit(exePath)), 0o644); err != nil {
+		fmt.Fprintf(stdout, "daemon install: failed to write unit: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, `Installed:
+  %s
+Binary path: %s
+Enable it with:
+  systemctl --user daemon-reload
+  systemctl --user enable --now %s
+Schedule: edit ~/.openppl/daemon.json, then: systemctl --user restart %s
+`, unitPath, exePath, unitName, unitName)
+	return 0
+}
+
+func systemdUnit(exePath string) string {
+	return fmt.Sprintf(`[Unit]
+Description=openppl study reminders
+After=graphical-session.target
+PartOf=graphical-session.target
+
+[Service]
+Type=simple
+ExecStart="%s" daemon run
+Restart=on-failure
+RestartSec=30
+
+[Install]
+WantedBy=default.target
+`, exePath)
+}
+
+func userUnitDir() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "systemd", "user"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "systemd", "user"), nil
+}
@@ -0,0 +1,182 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+// Scheduler decides which reminders are due and remembers what it already
+// showed, so each category fires once a day unless snoozed.
+type Scheduler struct {
+	db           *gorm.DB
+	cfg          services.NotificationConfig
+	notifier     services.DesktopNotifier
+	now          func() time.Time
+	quizAnswered func(time.Time) bool
+	snapshot     func(time.Time) error
+	lastSnapshot time.Time
+	out          io.Writer
+
+	mu      sync.Mutex
+	day     string
+	fired   map[string]services.DesktopNotification
+	snoozed map[string]time.Time
+}
+
+func NewScheduler(database *gorm.DB, cfg services.NotificationConfig, notifier services.DesktopNotifier, out io.Writer) *Scheduler {
+	return &Scheduler{
+		db:           database,
+		cfg:          cfg,
+		notifier:     notifier,
+		now:          time.Now,
+		quizAnswered: func(time.Time) bool { return false },
+		out:          out,
+		fired:        map[string]services.DesktopNotification{},
+		snoozed:      map[string]time.Time{},
+	}
+}
+
+func (s *Scheduler) WithClock(clock func() time.Time) *Scheduler {
+	if clock != nil {
+		s.now = clock
+	}
+	return s
+}
+
+func (s *Scheduler) WithQuizCheck(check func(time.Time) bool) *Scheduler {
+	if check != nil {
+		s.quizAnswered = check
+	}
+	return s
+}
+
+// WithSnapshots records a progress snapshot at most once per
+// snapshotInterval, so trends keep building while only the daemon runs.
+func (s *Scheduler) WithSnapshots(record func(time.Time) error) *Scheduler {
+	s.snapshot = record
+	return s
+}
+
+// Tick shows every due reminder for the latest study plan that has not
+// fired yet today and whose snooze, if any, has expired. It returns how
+// many notifications were sent.
+func (s *Scheduler) Tick(ctx context.Context) (int, error) {
+	tasks, err := services.LoadLatestPlanTasks(s.db)
+	if err != nil {
+		return 0, fmt.Errorf("load tasks: %w", err)
+	}
+
+	now := s.now()
+	s.forgetEarlierDays(now)
+	if s.snapshot != nil && now.Sub(s.lastSnapshot) >= snapshotInterval {
+		if err := s.snapshot(now); err != nil {
+			fmt.Fprintf(s.out, "progress snapshot failed: %v\n", err)
+		}
+		s.lastSnapshot = now
+	}
+	due := services.PlanDueNotifications(tasks, s.cfg, now, s.quizAnswered(now))
+
+	sent := 0
+	for _, notification := range due {
+		s.mu.Lock()
+		_, alreadyFired := s.fired[notification.Key]
+		until, snoozed := s.snoozed[notification.Key]
+		s.mu.Unlock()
+		if alreadyFired || (snoozed && now.Before(until)) {
+			continue
+		}
+
+		if err := s.notifier.Notify(ctx, notification); err != nil {
+			fmt.Fprintf(s.out, "notify %s failed: %v\n", notification.Key, err)
+			continue
+		}
+		s.mu.Lock()
+		s.fired[notification.Key] = notification
+		delete(s.snoozed, notification.Key)
+		s.mu.Unlock()
+		sent++
+		fmt.Fprintf(s.out, "%s notified: %s\n", now.Format("15:04"), notification.Summary)
+	}
+	return sent, nil
+}
+
+// forgetEarlierDays drops fired and snoozed reminders from earlier days once
+// the date rolls over. Reminder keys end in the date they are for, so a
+// long-running daemon only ever remembers today's.
+func (s *Scheduler) forgetEarlierDays(now time.Time) {
+	day := now.Format("2006-01-02")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if day == s.day {
+		return
+	}
+	s.day = day
+	suffix := ":" + day
+	for key := range s.fired {
+		if !strings.HasSuffix(key, suffix) {
+			delete(s.fired, key)
+		}
+	}
+	for key := range s.snoozed {
+		if !strings.HasSuffix(key, suffix) {
+			delete(s.snoozed, key)
+		}
+	}
+}
+
+// HandleAction applies a notification button press: "done" completes the
+// listed tasks, "snooze" re-arms the reminder after the snooze interval.
+func (s *Scheduler) HandleAction(action services.NotificationAction) error {
+	s.mu.Lock()
+	notification, ok := s.fired[action.Key]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	switch action.Action {
+	case services.NotificationActionDone:
+		count, err := services.CompleteNotificationTasks(s.db, notification)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "marked %d tasks done from notification\n", count)
+	case services.NotificationActionSnooze:
+		until := s.now().Add(s.cfg.SnoozeDuration())
+		s.mu.Lock()
+		delete(s.fired, action.Key)
+		s.snoozed[action.Key] = until
+		s.mu.Unlock()
+		fmt.Fprintf(s.out, "snoozed %q until %s\n", notification.Summary, until.Format("15:04"))
+	}
+	return nil
+}
+
+// Run ticks every interval and handles notification actions until ctx ends.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Tick(ctx); err != nil {
+			fmt.Fprintf(s.out, "tick failed: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case action := <-s.notifier.Actions():
+			if err := s.HandleAction(action); err != nil {
+				fmt.Fprintf(s.out, "action %s failed: %v\n", action.Action, err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
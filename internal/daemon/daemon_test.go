@@ -0,0 +1,218 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+type fakeNotifier struct {
+	sent    []services.DesktopNotification
+	actions chan services.NotificationAction
+}
+
+func (f *fakeNotifier) Name() string { return "fake" }
+
+func (f *fakeNotifier) Notify(ctx context.Context, n services.DesktopNotification) error {
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func (f *fakeNotifier) Actions() <-chan services.NotificationAction { return f.actions }
+
+func TestScheduler_FiresOnceSnoozesAndCompletes(t *testing.T) {
+	db := setupDaemonTestDB(t)
+	today := time.Date(2026, 5, 10, 0, 0, 0, 0, time.Local)
+	oldPlan := model.StudyPlan{CheckrideDate: today.AddDate(0, 1, 0)}
+	db.Create(&oldPlan)
+	db.Create(&model.DailyTask{StudyPlanID: oldPlan.ID, Date: today, Category: "Flight Planning", Title: "Superseded"})
+	plan := model.StudyPlan{CheckrideDate: today.AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: today, Category: "Theory", Title: "Airspace"})
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: today, Category: "Theory", Title: "Weather"})
+
+	cfg := services.DefaultNotificationConfig()
+	cfg.QuizTime = ""
+	now := today.Add(10 * time.Hour)
+	notifier := &fakeNotifier{}
+	scheduler := NewScheduler(db, cfg, notifier, &bytes.Buffer{}).WithClock(func() time.Time { return now })
+
+	for i := 0; i < 2; i++ {
+		if _, err := scheduler.Tick(context.Background()); err != nil {
+			t.Fatalf("Tick returned error: %v", err)
+		}
+	}
+	if len(notifier.sent) != 1 {
+		t.Fatalf("expected a single notification across ticks, got %d", len(notifier.sent))
+	}
+	key := notifier.sent[0].Key
+
+	if err := scheduler.HandleAction(services.NotificationAction{Key: key, Action: services.NotificationActionSnooze}); err != nil {
+		t.Fatalf("snooze returned error: %v", err)
+	}
+	now = now.Add(10 * time.Minute)
+	scheduler.Tick(context.Background())
+	if len(notifier.sent) != 1 {
+		t.Fatal("expected snoozed reminder to stay quiet")
+	}
+	now = now.Add(25 * time.Minute)
+	scheduler.Tick(context.Background())
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected reminder to fire again after snooze, got %d", len(notifier.sent))
+	}
+
+	if err := scheduler.HandleAction(services.NotificationAction{Key: key, Action: services.NotificationActionDone}); err != nil {
+		t.Fatalf("done returned error: %v", err)
+	}
+	var open int64
+	db.Model(&model.DailyTask{}).Where("study_plan_id = ? AND completed = ?", plan.ID, false).Count(&open)
+	if open != 0 {
+		t.Fatalf("expected done action to complete both tasks, %d still open", open)
+	}
+}
+
+func TestInstallWritesSystemdUserUnit(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	previous := executablePath
+	executablePath = func() (string, error) { return "/opt/openppl/bin/openppl", nil }
+	t.Cleanup(func() { executablePath = previous })
+
+	var out bytes.Buffer
+	if code := Execute(nil, []string{"install"}, &out); code != 0 {
+		t.Fatalf("install exited %d: %s", code, out.String())
+	}
+
+	unit, err := os.ReadFile(filepath.Join(configHome, "systemd", "user", unitName))
+	if err != nil {
+		t.Fatalf("read unit: %v", err)
+	}
+	if !strings.Contains(string(unit), `ExecStart="/opt/openppl/bin/openppl" daemon run`) {
+		t.Fatalf("unexpected unit:\n%s", unit)
+	}
+	if !strings.Contains(out.String(), "systemctl --user enable --now openppl-daemon.service") {
+		t.Fatalf("expected enable instructions, got:\n%s", out.String())
+	}
+}
+
+func setupDaemonTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.Progress{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestScheduler_RecordsSnapshotsHourly(t *testing.T) {
+	db := setupDaemonTestDB(t)
+	now := time.Date(2026, 5, 10, 10, 0, 0, 0, time.Local)
+	var recorded []time.Time
+	scheduler := NewScheduler(db, services.DefaultNotificationConfig(), &fakeNotifier{}, &bytes.Buffer{}).
+		WithClock(func() time.Time { return now }).
+		WithSnapshots(func(at time.Time) error {
+			recorded = append(recorded, at)
+			return nil
+		})
+
+	for _, step := range []time.Duration{0, 30 * time.Minute, 31 * time.Minute} {
+		now = now.Add(step)
+		if _, err := scheduler.Tick(context.Background()); err != nil {
+			t.Fatalf("Tick returned error: %v", err)
+		}
+	}
+	if len(recorded) != 2 || recorded[1].Sub(recorded[0]) != 61*time.Minute {
+		t.Fatalf("expected snapshots an hour apart, got %v", recorded)
+	}
+}
+
+func TestServeOpensTheQuizDatabaseOnceAndClosesIt(t *testing.T) {
+	db := setupDaemonTestDB(t)
+	motdDB, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s_motd?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open motd db: %v", err)
+	}
+	if err := motdDB.AutoMigrate(&services.MOTDAnswer{}); err != nil {
+		t.Fatalf("migrate motd db: %v", err)
+	}
+	checks := 0
+	if err := motdDB.Callback().Query().After("gorm:query").Register("count_checks", func(*gorm.DB) { checks++ }); err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	opens := 0
+	restoreConfig, restoreNotifier, restoreOpen := loadConfig, newNotifier, openMOTDDB
+	defer func() { loadConfig, newNotifier, openMOTDDB = restoreConfig, restoreNotifier, restoreOpen }()
+	loadConfig = func() (services.NotificationConfig, error) { return services.DefaultNotificationConfig(), nil }
+	newNotifier = func(context.Context) (services.DesktopNotifier, error) { return &fakeNotifier{}, nil }
+	openMOTDDB = func() (*gorm.DB, error) {
+		opens++
+		return motdDB, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	if code := serve(ctx, db, &bytes.Buffer{}, false, 5*time.Millisecond); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if checks < 10 {
+		t.Fatalf("expected many ticks to check the quiz, got %d", checks)
+	}
+	if opens != 1 {
+		t.Fatalf("expected the quiz database to be opened once, got %d", opens)
+	}
+	sqlDB, err := motdDB.DB()
+	if err != nil {
+		t.Fatalf("sql db: %v", err)
+	}
+	if err := sqlDB.Ping(); err == nil {
+		t.Fatal("expected the quiz database to be closed when the daemon stops")
+	}
+}
+
+func TestScheduler_ForgetsRemindersFromEarlierDays(t *testing.T) {
+	db := setupDaemonTestDB(t)
+	start := time.Date(2026, 5, 10, 0, 0, 0, 0, time.Local)
+	cfg := services.DefaultNotificationConfig()
+	cfg.QuizTime = ""
+	now := start.Add(10 * time.Hour)
+	scheduler := NewScheduler(db, cfg, &fakeNotifier{}, &bytes.Buffer{}).WithClock(func() time.Time { return now })
+	plan := model.StudyPlan{CheckrideDate: start.AddDate(0, 2, 0)}
+	db.Create(&plan)
+
+	for day := 0; day < 5; day++ {
+		db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: start.AddDate(0, 0, day), Category: "Theory", Title: fmt.Sprintf("Day %d", day)})
+		now = start.AddDate(0, 0, day).Add(10 * time.Hour)
+		if _, err := scheduler.Tick(context.Background()); err != nil {
+			t.Fatalf("Tick returned error: %v", err)
+		}
+		scheduler.snoozed[fmt.Sprintf("quiz:%s", now.Format("2006-01-02"))] = now.Add(time.Hour)
+	}
+
+	today := ":" + now.Format("2006-01-02")
+	if len(scheduler.fired) == 0 {
+		t.Fatal("expected today's reminder to be remembered")
+	}
+	for key := range scheduler.fired {
+		if !strings.HasSuffix(key, today) {
+			t.Fatalf("expected only today's reminders, still remembering %q", key)
+		}
+	}
+	if len(scheduler.snoozed) != 1 {
+		t.Fatalf("expected only today's snooze, got %v", scheduler.snoozed)
+	}
+}
@@ -38,10 +38,20 @@ func Initialize() (*gorm.DB, error) {
 		&model.StudyPlan{},
 		&model.DailyTask{},
 		&model.Progress{},
+		&model.StudySession{},
 		&model.ChecklistItem{},
+		&model.Milestone{},
+		&model.Endorsement{},
+		&model.PilotDocument{},
+		&model.Aircraft{},
+		&model.AircraftAD{},
+		&model.GoNoGoDecision{},
+		&model.LogbookEntry{},
+		&model.ProgressSnapshot{},
 		&model.Budget{},
 		&model.AppConfig{},
 		&model.AutomationIdempotency{},
+		&model.OutboxEvent{},
 	); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,182 @@
+package documents
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl documents [list]
+  openppl documents kinds
+  openppl documents set <kind> --date YYYY-MM-DD [--expires YYYY-MM-DD] [--notes "<text>"]
+  openppl documents birthdate YYYY-MM-DD
+  openppl documents remove <kind>`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl documents [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "list":
+		return runList(database, stdout)
+	case "kinds":
+		return runKinds(stdout)
+	case "set", "add":
+		return runSet(database, args[1:], stdout)
+	case "birthdate", "born":
+		return runBirthDate(database, args[1:], stdout)
+	case "remove", "rm", "delete":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl documents remove <kind>")
+			return 1
+		}
+		if err := services.DeletePilotDocument(database, args[1]); err != nil {
+			fmt.Fprintf(stdout, "Could not remove document: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Removed %s\n", args[1])
+		return 0
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runList(database *gorm.DB, stdout io.Writer) int {
+	documents, err := services.ListPilotDocuments(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load documents: %v\n", err)
+		return 1
+	}
+	if len(documents) == 0 {
+		fmt.Fprintln(stdout, "No documents recorded yet. Add one with `openppl documents set medical --date YYYY-MM-DD` (see `openppl documents kinds`).")
+		return 0
+	}
+
+	fmt.Fprintln(stdout, "Documents:")
+	for _, document := range documents {
+		kind, _ := services.LookupDocumentKind(document.Kind)
+		validity := "does not expire"
+		if document.ExpiresOn != nil {
+			validity = "valid through " + document.ExpiresOn.Format("2006-01-02")
+		}
+		fmt.Fprintf(stdout, "  %-26s issued %s, %s (%s)\n", kind.Title, document.IssuedOn.Format("2006-01-02"), validity, kind.Reference)
+		if document.Notes != "" {
+			fmt.Fprintf(stdout, "      %s\n", document.Notes)
+		}
+	}
+
+	var plan model.StudyPlan
+	checkride := time.Time{}
+	if err := database.Limit(1).Order("id desc").Find(&plan).Error; err == nil && plan.ID != 0 {
+		checkride = plan.CheckrideDate
+	}
+	for _, warning := range services.DocumentWarnings(documents, checkride, now()) {
+		fmt.Fprintf(stdout, "! %s\n", warning)
+	}
+	var blocked *services.CheckrideBlockedError
+	if err := services.CheckCheckrideDate(database, checkride); !checkride.IsZero() && errors.As(err, &blocked) {
+		fmt.Fprintf(stdout, "! Renew or move the checkride to %s or earlier.\n", blocked.LatestCheckride().Format("2006-01-02"))
+	}
+	return 0
+}
+
+func runKinds(stdout io.Writer) int {
+	fmt.Fprintln(stdout, "Document kinds:")
+	for _, kind := range services.DocumentKinds {
+		rule := ""
+		switch {
+		case kind.Key == services.DocumentMedical:
+			rule = "60 calendar months if examined before age 40, otherwise 24 (set `documents birthdate`)"
+		case kind.Key == services.DocumentStudentCertificate:
+			rule = "does not expire if issued after March 2016"
+		case kind.CalendarMonths > 0:
+			rule = fmt.Sprintf("%d calendar months", kind.CalendarMonths)
+		case kind.EntersExpiry:
+			rule = "enter the printed expiration with --expires"
+		}
+		fmt.Fprintf(stdout, "  %-20s %s (%s): %s\n", kind.Key, kind.Title, kind.Reference, rule)
+	}
+	return 0
+}
+
+func runSet(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	kind := args[0]
+
+	flags := flag.NewFlagSet("documents set", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	date := flags.String("date", "", "issue or exam date, YYYY-MM-DD")
+	expires := flags.String("expires", "", "expiration printed on the document, YYYY-MM-DD")
+	notes := flags.String("notes", "", "notes")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+
+	input := services.PilotDocumentInput{Kind: kind, Notes: *notes}
+	var ok bool
+	if input.IssuedOn, ok = parseDate("--date", *date, stdout); !ok {
+		return 1
+	}
+	if *expires != "" {
+		if input.ExpiresOn, ok = parseDate("--expires", *expires, stdout); !ok {
+			return 1
+		}
+	}
+
+	document, err := services.SavePilotDocument(database, input)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not save document: %v\n", err)
+		return 1
+	}
+	saved, _ := services.LookupDocumentKind(document.Kind)
+	message := "Saved " + strings.ToLower(saved.Title)
+	if document.ExpiresOn != nil {
+		message += ", valid through " + document.ExpiresOn.Format("2006-01-02")
+	}
+	fmt.Fprintln(stdout, message)
+	return 0
+}
+
+func runBirthDate(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stdout, "usage: openppl documents birthdate YYYY-MM-DD")
+		return 1
+	}
+	born, ok := parseDate("birth date", args[0], stdout)
+	if !ok {
+		return 1
+	}
+	if err := services.SetPilotBirthDate(database, born); err != nil {
+		fmt.Fprintf(stdout, "Could not save birth date: %v\n", err)
+		return 1
+	}
+	fmt.Fprintln(stdout, "Saved birth date; medical certificate expiration updated.")
+	return 0
+}
+
+func parseDate(name, value string, stdout io.Writer) (time.Time, bool) {
+	parsed, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		fmt.Fprintf(stdout, "Invalid %s %q, want YYYY-MM-DD\n", name, value)
+		return time.Time{}, false
+	}
+	return parsed, true
+}
@@ -0,0 +1,167 @@
+package endorsements
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl endorsements [list]
+  openppl endorsements kinds
+  openppl endorsements add --kind <kind> --date YYYY-MM-DD --cfi "<name>" --cert <number> [--cfi-expires YYYY-MM-DD] [--notes "<text>"]
+  openppl endorsements remove <id>`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl endorsements [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "list":
+		return runList(database, stdout)
+	case "kinds":
+		return runKinds(stdout)
+	case "add":
+		return runAdd(database, args[1:], stdout)
+	case "remove", "rm", "delete":
+		return runRemove(database, args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runList(database *gorm.DB, stdout io.Writer) int {
+	endorsements, err := services.ListEndorsements(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load endorsements: %v\n", err)
+		return 1
+	}
+	if len(endorsements) == 0 {
+		fmt.Fprintln(stdout, "No endorsements recorded yet. Add one with `openppl endorsements add` (see `openppl endorsements kinds`).")
+		return 0
+	}
+
+	today := now()
+	fmt.Fprintln(stdout, "Endorsements:")
+	for _, e := range endorsements {
+		kind, _ := services.LookupEndorsementKind(e.Kind)
+		validity := "no expiration"
+		if e.ExpiresOn != nil {
+			validity = "valid through " + e.ExpiresOn.Format("2006-01-02")
+			if !services.EndorsementCurrent(e, today) {
+				validity = "LAPSED " + e.ExpiresOn.Format("2006-01-02")
+			}
+		}
+		fmt.Fprintf(stdout, "  #%d %s — %s (%s)\n", e.ID, e.IssuedOn.Format("2006-01-02"), kind.Title, kind.Reference)
+		cfi := fmt.Sprintf("%s, %s", e.CFIName, e.CFICertificate)
+		if e.CFIExpiresOn != nil {
+			cfi += " exp " + e.CFIExpiresOn.Format("2006-01-02")
+		}
+		fmt.Fprintf(stdout, "      CFI %s · %s\n", cfi, validity)
+		if e.Notes != "" {
+			fmt.Fprintf(stdout, "      %s\n", e.Notes)
+		}
+	}
+
+	for _, warning := range services.EndorsementWarnings(endorsements, today) {
+		fmt.Fprintf(stdout, "! %s\n", warning)
+	}
+	return 0
+}
+
+func runKinds(stdout io.Writer) int {
+	fmt.Fprintln(stdout, "Endorsement kinds:")
+	for _, kind := range services.EndorsementKinds {
+		validity := ""
+		switch {
+		case kind.ValidDays > 0:
+			validity = fmt.Sprintf(", valid %d days", kind.ValidDays)
+		case kind.CalendarMonths > 0:
+			validity = fmt.Sprintf(", valid %d calendar months", kind.CalendarMonths)
+		}
+		fmt.Fprintf(stdout, "  %-20s %s (%s%s)\n", kind.Key, kind.Title, kind.Reference, validity)
+	}
+	return 0
+}
+
+func runAdd(database *gorm.DB, args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("endorsements add", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	kind := flags.String("kind", "", "endorsement kind (see `openppl endorsements kinds`)")
+	date := flags.String("date", "", "issue date, YYYY-MM-DD (default today)")
+	cfi := flags.String("cfi", "", "CFI name")
+	cert := flags.String("cert", "", "CFI certificate number")
+	cfiExpires := flags.String("cfi-expires", "", "CFI certificate expiration, YYYY-MM-DD")
+	notes := flags.String("notes", "", "notes, e.g. the route for a solo cross-country flight")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	input := services.EndorsementInput{
+		Kind:           *kind,
+		IssuedOn:       now(),
+		CFIName:        *cfi,
+		CFICertificate: *cert,
+		Notes:          *notes,
+	}
+	if strings.TrimSpace(*date) != "" {
+		parsed, err := time.Parse("2006-01-02", strings.TrimSpace(*date))
+		if err != nil {
+			fmt.Fprintf(stdout, "Invalid --date %q, want YYYY-MM-DD\n", *date)
+			return 1
+		}
+		input.IssuedOn = parsed
+	}
+	if strings.TrimSpace(*cfiExpires) != "" {
+		parsed, err := time.Parse("2006-01-02", strings.TrimSpace(*cfiExpires))
+		if err != nil {
+			fmt.Fprintf(stdout, "Invalid --cfi-expires %q, want YYYY-MM-DD\n", *cfiExpires)
+			return 1
+		}
+		input.CFIExpiresOn = parsed
+	}
+
+	endorsement, err := services.AddEndorsement(database, input, now())
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not add endorsement: %v\n", err)
+		return 1
+	}
+	added, _ := services.LookupEndorsementKind(endorsement.Kind)
+	message := fmt.Sprintf("Recorded %s endorsement #%d from %s", strings.ToLower(added.Title), endorsement.ID, endorsement.CFIName)
+	if endorsement.ExpiresOn != nil {
+		message += ", valid through " + endorsement.ExpiresOn.Format("2006-01-02")
+	}
+	fmt.Fprintln(stdout, message)
+	return 0
+}
+
+func runRemove(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stdout, "usage: openppl endorsements remove <id>")
+		return 1
+	}
+	id, err := strconv.ParseUint(strings.TrimPrefix(args[0], "#"), 10, 64)
+	if err != nil || id == 0 {
+		fmt.Fprintf(stdout, "Invalid endorsement id %q\n", args[0])
+		return 1
+	}
+	if err := services.DeleteEndorsement(database, uint(id), now()); err != nil {
+		fmt.Fprintf(stdout, "Could not remove endorsement: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Removed endorsement #%d\n", id)
+	return 0
+}
@@ -0,0 +1,315 @@
+package gonogo
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl gonogo [--airport KFXE] [--solo] [--night] [--at "YYYY-MM-DD HH:MM"] [--hours 2] [--tail N12345] [--note "<text>"] [--no-log]
+  openppl gonogo history [--limit 20]
+  openppl gonogo minimums
+  openppl gonogo minimums set --dual|--solo [--ceiling FT] [--visibility SM] [--wind KT] [--crosswind KT] [--gust-spread KT] [--night=true|false]
+  openppl gonogo minimums runways 09,27`
+
+var (
+	now                = time.Now
+	newWeatherProvider = services.DefaultWeatherProvider
+)
+
+// Execute is the dispatcher for `openppl gonogo [subcommand]`. The check
+// exits 1 on a NO-GO so scripts can act on it.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "check":
+		if sub != "" {
+			args = args[1:]
+		}
+		return runCheck(database, args, stdout)
+	case "history", "log":
+		return runHistory(database, args[1:], stdout)
+	case "minimums", "mins":
+		return runMinimums(args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runCheck(database *gorm.DB, args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("gonogo", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	airport := flags.String("airport", "", "ICAO airport (default: school airport)")
+	solo := flags.Bool("solo", false, "solo flight (default: dual lesson)")
+	night := flags.Bool("night", false, "flight after dark")
+	at := flags.String("at", "", `departure, "YYYY-MM-DD HH:MM" or "HH:MM" today`)
+	hours := flags.Int("hours", 2, "planned flight length in hours")
+	tail := flags.String("tail", "", "aircraft tail number")
+	note := flags.String("note", "", "note for the go/no-go history")
+	noLog := flags.Bool("no-log", false, "do not record the decision")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	current := now()
+	request := services.GoNoGoRequest{Airport: *airport, Solo: *solo, Night: *night, Hours: *hours, Tail: *tail}
+	if *at != "" {
+		departure, err := parseDeparture(*at, current)
+		if err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+		request.At = departure
+	}
+
+	minimums, err := services.LoadPersonalMinimums()
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load minimums, using the defaults: %v\n", err)
+	}
+	result, err := services.EvaluateGoNoGo(context.Background(), database, newWeatherProvider(), minimums, request, current)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not evaluate go/no-go: %v\n", err)
+		return 1
+	}
+	printResult(stdout, result)
+
+	if !*noLog {
+		decision, err := services.RecordGoNoGo(database, result, *note)
+		if err != nil {
+			fmt.Fprintf(stdout, "Could not log the decision: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Logged as decision #%d (`openppl gonogo history`).\n", decision.ID)
+	}
+	if result.Decision() == services.NoGoDecision {
+		return 1
+	}
+	return 0
+}
+
+func printResult(stdout io.Writer, result services.GoNoGoResult) {
+	request := result.Request
+	kind := "Dual"
+	if request.Solo {
+		kind = "Solo"
+	}
+	if request.Night {
+		kind += " night"
+	}
+	fmt.Fprintf(stdout, "Go/no-go: %s flight from %s, %s for %dh\n", kind, request.Airport, request.At.Format("Mon Jan 2 15:04"), request.Hours)
+	set := result.Minimums
+	fmt.Fprintf(stdout, "Minimums: ceiling %d ft, visibility %s SM, wind %d kt, crosswind %d kt, gust spread %d kt, night %s\n",
+		set.CeilingFt, formatMiles(set.VisibilitySM), set.MaxWindKt, set.MaxCrosswindKt, set.MaxGustSpreadKt, yesNo(set.Night))
+	if result.METAR != nil {
+		fmt.Fprintf(stdout, "  %s\n", result.METAR.Raw)
+	}
+	if result.TAF != nil {
+		fmt.Fprintf(stdout, "  %s\n", result.TAF.Raw)
+	}
+
+	fmt.Fprintln(stdout, "\nPAVE:")
+	for _, area := range []string{services.PAVEPilot, services.PAVEAircraft, services.PAVEEnvironment} {
+		fmt.Fprintf(stdout, "  %s\n", area)
+		for _, item := range result.Items {
+			if item.Area == area {
+				fmt.Fprintf(stdout, "    %-8s %s: %s\n", strings.ToUpper(item.Status), item.Item, item.Detail)
+			}
+		}
+	}
+	fmt.Fprintln(stdout, "  External pressures")
+	for _, prompt := range services.ExternalPressuresChecklist {
+		fmt.Fprintf(stdout, "    [ ] %s\n", prompt)
+	}
+
+	fmt.Fprintln(stdout, "\nIMSAFE:")
+	for _, prompt := range services.IMSAFEChecklist {
+		fmt.Fprintf(stdout, "  [ ] %s\n", prompt)
+	}
+
+	fmt.Fprintf(stdout, "\nDecision: %s\n", result.Decision())
+	for _, reason := range result.Reasons() {
+		fmt.Fprintf(stdout, "  - %s\n", reason)
+	}
+}
+
+func runHistory(database *gorm.DB, args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("gonogo history", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	limit := flags.Int("limit", 20, "number of decisions to show (0 for all)")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+
+	decisions, err := services.ListGoNoGoDecisions(database, *limit)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load go/no-go history: %v\n", err)
+		return 1
+	}
+	if len(decisions) == 0 {
+		fmt.Fprintln(stdout, "No go/no-go decisions logged yet. Run `openppl gonogo` before your next flight.")
+		return 0
+	}
+
+	noGo := 0
+	fmt.Fprintln(stdout, "Go/no-go history:")
+	for _, decision := range decisions {
+		if decision.Decision == services.NoGoDecision {
+			noGo++
+		}
+		kind := "dual"
+		if decision.Solo {
+			kind = "solo"
+		}
+		if decision.Night {
+			kind += " night"
+		}
+		fmt.Fprintf(stdout, "  #%-4d %s  %-5s %-4s %s\n", decision.ID, decision.FlightAt.Format("2006-01-02 15:04"), decision.Decision, decision.Airport, kind)
+		if decision.Reasons != "" {
+			fmt.Fprintf(stdout, "        %s\n", decision.Reasons)
+		}
+		if decision.Note != "" {
+			fmt.Fprintf(stdout, "        note: %s\n", decision.Note)
+		}
+	}
+	fmt.Fprintf(stdout, "%d shown: %d GO, %d NO-GO\n", len(decisions), len(decisions)-noGo, noGo)
+	return 0
+}
+
+func runMinimums(args []string, stdout io.Writer) int {
+	minimums, err := services.LoadPersonalMinimums()
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load minimums: %v\n", err)
+		return 1
+	}
+
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	switch sub {
+	case "", "show":
+	case "set":
+		if !setMinimums(&minimums, args[1:], stdout) {
+			return 1
+		}
+	case "runways":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl gonogo minimums runways 09,27")
+			return 1
+		}
+		minimums.Runways = nil
+		for _, runway := range strings.Split(args[1], ",") {
+			if runway = strings.ToUpper(strings.TrimSpace(runway)); runway != "" {
+				minimums.Runways = append(minimums.Runways, runway)
+			}
+		}
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	if sub == "set" || sub == "runways" {
+		if err := services.SavePersonalMinimums(minimums); err != nil {
+			fmt.Fprintf(stdout, "Could not save minimums: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, "Saved personal minimums.")
+	}
+
+	fmt.Fprintln(stdout, "Personal minimums:")
+	fmt.Fprintf(stdout, "  %-5s %8s %10s %6s %10s %12s %6s\n", "", "ceiling", "visibility", "wind", "crosswind", "gust spread", "night")
+	for _, solo := range []bool{false, true} {
+		name := "dual"
+		if solo {
+			name = "solo"
+		}
+		set := minimums.Set(solo)
+		fmt.Fprintf(stdout, "  %-5s %5d ft %7s SM %3d kt %7d kt %9d kt %6s\n",
+			name, set.CeilingFt, formatMiles(set.VisibilitySM), set.MaxWindKt, set.MaxCrosswindKt, set.MaxGustSpreadKt, yesNo(set.Night))
+	}
+	runways := "none (crosswind not checked)"
+	if len(minimums.Runways) > 0 {
+		runways = strings.Join(minimums.Runways, ", ")
+	}
+	fmt.Fprintf(stdout, "  Runways: %s\n", runways)
+	return 0
+}
+
+func setMinimums(minimums *services.PersonalMinimums, args []string, stdout io.Writer) bool {
+	flags := flag.NewFlagSet("gonogo minimums set", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	dual := flags.Bool("dual", false, "change the dual limits")
+	solo := flags.Bool("solo", false, "change the solo limits")
+	ceiling := flags.Int("ceiling", 0, "lowest ceiling in feet")
+	visibility := flags.Float64("visibility", 0, "lowest visibility in statute miles")
+	wind := flags.Int("wind", 0, "highest wind or gust in knots")
+	crosswind := flags.Int("crosswind", 0, "highest crosswind component in knots")
+	gustSpread := flags.Int("gust-spread", 0, "highest gust spread in knots")
+	night := flags.Bool("night", false, "whether night flights are within the limits")
+	if err := flags.Parse(args); err != nil {
+		return false
+	}
+	if *dual == *solo {
+		fmt.Fprintln(stdout, "Pass exactly one of --dual or --solo.")
+		return false
+	}
+
+	set := &minimums.Dual
+	if *solo {
+		set = &minimums.Solo
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "ceiling":
+			set.CeilingFt = *ceiling
+		case "visibility":
+			set.VisibilitySM = *visibility
+		case "wind":
+			set.MaxWindKt = *wind
+		case "crosswind":
+			set.MaxCrosswindKt = *crosswind
+		case "gust-spread":
+			set.MaxGustSpreadKt = *gustSpread
+		case "night":
+			set.Night = *night
+		}
+	})
+	return true
+}
+
+// parseDeparture reads "YYYY-MM-DD HH:MM" or "HH:MM" (today) in local time.
+func parseDeparture(value string, current time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if departure, err := time.ParseInLocation("2006-01-02 15:04", value, current.Location()); err == nil {
+		return departure, nil
+	}
+	clock, err := time.ParseInLocation("15:04", value, current.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf(`Invalid --at %q, want "YYYY-MM-DD HH:MM" or "HH:MM"`, value)
+	}
+	return time.Date(current.Year(), current.Month(), current.Day(), clock.Hour(), clock.Minute(), 0, 0, current.Location()), nil
+}
+
+func formatMiles(miles float64) string {
+	return strconv.FormatFloat(miles, 'f', -1, 64)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,126 @@
+package gonogo
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestCheck_GoExitsZeroAndLogsTheDecision(t *testing.T) {
+	db := setupGoNoGoCLITestDB(t)
+	useFixtureWeather(t)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"--at", "12:30", "--note", "pattern work"}, &out); code != 0 {
+		t.Fatalf("gonogo = %d, want 0; output %q", code, out.String())
+	}
+	for _, want := range []string{"Go/no-go: Dual flight from KFXE, Mon Jun 1 12:30 for 2h", "Decision: GO", "Logged as decision #1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got %q", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"history"}, &out); code != 0 {
+		t.Fatalf("history = %d", code)
+	}
+	if !strings.Contains(out.String(), "note: pattern work") || !strings.Contains(out.String(), "1 shown: 1 GO, 0 NO-GO") {
+		t.Fatalf("unexpected history %q", out.String())
+	}
+}
+
+func TestCheck_NoGoExitsOne(t *testing.T) {
+	db := setupGoNoGoCLITestDB(t)
+	useFixtureWeather(t)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"--solo", "--at", "2026-06-01 12:30", "--no-log"}, &out); code != 1 {
+		t.Fatalf("gonogo --solo = %d, want 1; output %q", code, out.String())
+	}
+	if !strings.Contains(out.String(), "Decision: NO-GO") || !strings.Contains(out.String(), "Solo endorsement: none recorded") {
+		t.Fatalf("expected a NO-GO for the missing endorsement, got %q", out.String())
+	}
+	var logged int64
+	db.Model(&model.GoNoGoDecision{}).Count(&logged)
+	if logged != 0 {
+		t.Fatalf("expected --no-log to skip the history, got %d decisions", logged)
+	}
+}
+
+func TestCheck_RejectsBadFlags(t *testing.T) {
+	db := setupGoNoGoCLITestDB(t)
+	useFixtureWeather(t)
+	for _, args := range [][]string{
+		{"--at", "noon"},
+		{"--hours", "two"},
+		{"--bogus"},
+		{"fly"},
+	} {
+		var out bytes.Buffer
+		if code := Execute(db, args, &out); code != 1 {
+			t.Fatalf("Execute(%v) = %d, want 1; output %q", args, code, out.String())
+		}
+	}
+}
+
+func TestMinimums_SetAndRunways(t *testing.T) {
+	db := setupGoNoGoCLITestDB(t)
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"minimums", "set", "--crosswind", "8"}, &out); code != 1 || !strings.Contains(out.String(), "exactly one of --dual or --solo") {
+		t.Fatalf("expected set without --dual/--solo to fail, got %d %q", code, out.String())
+	}
+	out.Reset()
+	if code := Execute(db, []string{"minimums", "set", "--solo", "--crosswind", "8"}, &out); code != 0 {
+		t.Fatalf("minimums set = %d; output %q", code, out.String())
+	}
+	out.Reset()
+	if code := Execute(db, []string{"minimums", "runways", "09, 27"}, &out); code != 0 || !strings.Contains(out.String(), "Runways: 09, 27") {
+		t.Fatalf("minimums runways = %d; output %q", code, out.String())
+	}
+
+	minimums, err := services.LoadPersonalMinimums()
+	if err != nil {
+		t.Fatalf("LoadPersonalMinimums failed: %v", err)
+	}
+	if minimums.Solo.MaxCrosswindKt != 8 || minimums.Dual.MaxCrosswindKt == 8 || len(minimums.Runways) != 2 {
+		t.Fatalf("unexpected saved minimums %+v", minimums)
+	}
+}
+
+func setupGoNoGoCLITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("OPENPPL_MINIMUMS_PATH", filepath.Join(t.TempDir(), "minimums.json"))
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.Endorsement{}, &model.PilotDocument{}, &model.Aircraft{}, &model.AircraftAD{}, &model.GoNoGoDecision{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+	return db
+}
+
+// useFixtureWeather reads the services test METAR and TAF for KFXE, which
+// are valid around noon UTC on June 1, and stops the clock there.
+func useFixtureWeather(t *testing.T) {
+	t.Helper()
+	now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+	newWeatherProvider = func() services.WeatherProvider {
+		return services.FileWeatherProvider{Dir: filepath.Join("..", "services", "testdata", "weather")}
+	}
+	t.Cleanup(func() {
+		now = time.Now
+		newWeatherProvider = services.DefaultWeatherProvider
+	})
+}
@@ -0,0 +1,39 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+// Execute is the dispatcher for `openppl import <format> <file>`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) < 2 || args[0] != "ics" {
+		fmt.Fprintln(stdout, "usage: openppl import ics <file>")
+		return 1
+	}
+	return runICS(database, args[1], stdout)
+}
+
+func runICS(database *gorm.DB, path string, stdout io.Writer) int {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not open %s: %v\n", path, err)
+		return 1
+	}
+	defer file.Close()
+
+	result, err := services.ImportICSCompletion(database, file, "ics-import")
+	if err != nil {
+		fmt.Fprintf(stdout, "ICS import failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Read %d items from %s (%d matched openppl tasks, %d unmatched)\n", result.Items, path, result.Matched, result.Unmatched)
+	fmt.Fprintf(stdout, "Completed %d tasks, %d already complete, %d still open\n", result.Completed, result.AlreadyCompleted, result.Pending)
+	return 0
+}
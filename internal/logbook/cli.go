@@ -0,0 +1,151 @@
+package logbook
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl logbook [list]
+  openppl logbook add --hours 1.3 [--solo] [--xc] [--long-xc] [--date YYYY-MM-DD]
+      [--route "KFXE local"] [--tail N12345] [--remarks "<text>"]
+  openppl logbook delete <id>`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl logbook [subcommand]`. It logs any
+// flight; cross-countries planned with `openppl xc plan --log` share the
+// same logbook.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "list":
+		return runList(database, stdout)
+	case "add", "log":
+		return runAdd(database, args[1:], stdout)
+	case "delete", "rm":
+		return runDelete(database, args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprintln(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runList(database *gorm.DB, stdout io.Writer) int {
+	entries, err := services.ListLogbook(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load logbook: %v\n", err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "No flights logged yet. Add one with `openppl logbook add --hours 1.3`.")
+	}
+	for _, entry := range entries {
+		status := fmt.Sprintf("planned %.1fh", entry.PlannedHours)
+		if entry.FlownAt != nil {
+			status = fmt.Sprintf("flown %.1fh", entry.Hours)
+		}
+		kind := "dual"
+		if entry.Solo {
+			kind = "solo"
+		}
+		if entry.LongSoloXC {
+			kind += ", long XC"
+		} else if entry.CrossCountry {
+			kind += ", XC"
+		}
+		fmt.Fprintf(stdout, "  #%-4d %s  %-13s %-16s %s\n", entry.ID, entry.Date.Format("2006-01-02"), status, kind, entry.Route)
+	}
+
+	flown := services.SummarizeFlightTime(entries)
+	xc := services.SummarizeCrossCountry(entries)
+	fmt.Fprintf(stdout, "Flight time: %.1fh total, %.1fh dual, %.1fh solo\n", flown.Total, flown.Dual, flown.Solo)
+	fmt.Fprintf(stdout, "Cross-country flown: %.1fh dual, %.1fh solo; %d planned\n", xc.DualHours, xc.SoloHours, xc.Planned)
+	for _, deficit := range append(flown.Deficits(), xc.Deficits()...) {
+		fmt.Fprintf(stdout, "  - %s\n", deficit)
+	}
+	return 0
+}
+
+func runAdd(database *gorm.DB, args []string, stdout io.Writer) int {
+	flags := flag.NewFlagSet("logbook add", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	hours := flags.Float64("hours", 0, "flight time")
+	solo := flags.Bool("solo", false, "solo flight (default: dual with a CFI)")
+	xc := flags.Bool("xc", false, "cross-country flight (61.1)")
+	longXC := flags.Bool("long-xc", false, "long solo cross-country (61.109(a)(5)(ii)); implies --solo --xc")
+	date := flags.String("date", "", "date flown, YYYY-MM-DD (default: today)")
+	route := flags.String("route", "", "route or description (default: Local)")
+	tail := flags.String("tail", "", "aircraft tail number")
+	remarks := flags.String("remarks", "", "remarks")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+
+	flownAt := now()
+	if *date != "" {
+		var err error
+		if flownAt, err = time.Parse("2006-01-02", *date); err != nil {
+			fmt.Fprintf(stdout, "Invalid --date %q, want YYYY-MM-DD\n", *date)
+			return 1
+		}
+	}
+
+	entry, err := services.LogFlight(database, services.FlightInput{
+		Date:         flownAt,
+		Hours:        *hours,
+		Solo:         *solo || *longXC,
+		CrossCountry: *xc || *longXC,
+		LongSoloXC:   *longXC,
+		Route:        *route,
+		Tail:         *tail,
+		Remarks:      *remarks,
+	})
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not log the flight: %v\n", err)
+		return 1
+	}
+	kind := "dual"
+	if entry.Solo {
+		kind = "solo"
+	}
+	fmt.Fprintf(stdout, "Logged #%d %s, %.1fh %s on %s\n", entry.ID, entry.Route, entry.Hours, kind, entry.Date.Format("2006-01-02"))
+	return 0
+}
+
+func runDelete(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(stdout, "Invalid logbook entry %q\n", args[0])
+		return 1
+	}
+	entry, err := services.DeleteLogbookEntry(database, uint(id))
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not delete the entry: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Deleted #%d %s\n", entry.ID, entry.Route)
+	return 0
+}
@@ -0,0 +1,82 @@
+package logbook
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestAddThenList(t *testing.T) {
+	db := setupLogbookCLITestDB(t)
+	now = func() time.Time { return time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+
+	var out bytes.Buffer
+	if code := Execute(db, []string{"add", "--hours", "1.3", "--tail", "n12345"}, &out); code != 0 {
+		t.Fatalf("add = %d, output %q", code, out.String())
+	}
+	if !strings.Contains(out.String(), "Logged #1 Local, 1.3h dual on 2026-06-01") {
+		t.Fatalf("unexpected add output %q", out.String())
+	}
+	out.Reset()
+	if code := Execute(db, []string{"add", "--hours", "2.4", "--long-xc", "--date", "2026-05-20"}, &out); code != 0 {
+		t.Fatalf("add long xc = %d, output %q", code, out.String())
+	}
+
+	out.Reset()
+	if code := Execute(db, nil, &out); code != 0 {
+		t.Fatalf("list = %d", code)
+	}
+	for _, want := range []string{
+		"solo, long XC",
+		"Flight time: 3.7h total, 1.3h dual, 2.4h solo",
+		"3.7 of 40 hours total time",
+		"Cross-country flown: 0.0h dual, 2.4h solo",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected list output to contain %q, got %q", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"delete", "1"}, &out); code != 0 || !strings.Contains(out.String(), "Deleted #1 Local") {
+		t.Fatalf("delete = %d, output %q", code, out.String())
+	}
+}
+
+func TestInvalidInputExitsOne(t *testing.T) {
+	db := setupLogbookCLITestDB(t)
+	for _, args := range [][]string{
+		{"add"},
+		{"add", "--hours", "1", "--date", "June 1"},
+		{"add", "--hours", "abc"},
+		{"delete"},
+		{"delete", "one"},
+		{"delete", "9"},
+		{"fly"},
+	} {
+		var out bytes.Buffer
+		if code := Execute(db, args, &out); code != 1 {
+			t.Fatalf("Execute(%v) = %d, want 1; output %q", args, code, out.String())
+		}
+	}
+}
+
+func setupLogbookCLITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.LogbookEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
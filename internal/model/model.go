@@ -15,24 +15,148 @@ type StudyPlan struct {
 	DailyTasks    []DailyTask `gorm:"foreignKey:StudyPlanID" json:"daily_tasks,omitempty"`
 }
 
-// DailyTask represents a single task in the study plan
+// DailyTask represents a single task in the study plan. UserCreated tasks
+// were added by hand and survive plan regeneration. RescheduledFrom is the
+// day the task was first scheduled, once it has been moved.
 type DailyTask struct {
-	ID          uint       `gorm:"primaryKey" json:"id"`
-	StudyPlanID uint       `gorm:"study_plan_id" json:"study_plan_id"`
-	Date        time.Time  `gorm:"date" json:"date"`
-	Category    string     `gorm:"category" json:"category"`
-	Title       string     `gorm:"title" json:"title"`
-	Description string     `gorm:"description" json:"description"`
-	Completed   bool       `gorm:"completed" json:"completed"`
-	CreatedAt   time.Time  `json:"created_at"`
-	Progress    []Progress `gorm:"foreignKey:DailyTaskID" json:"progress,omitempty"`
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	StudyPlanID     uint       `gorm:"study_plan_id" json:"study_plan_id"`
+	Date            time.Time  `gorm:"date" json:"date"`
+	Category        string     `gorm:"category" json:"category"`
+	Title           string     `gorm:"title" json:"title"`
+	Description     string     `gorm:"description" json:"description"`
+	ACSRefs         string     `gorm:"acs_refs" json:"acs_refs,omitempty"`
+	TemplateTaskID  string     `gorm:"index" json:"template_task_id,omitempty"`
+	DurationMinutes int        `gorm:"default:0" json:"duration_minutes,omitempty"`
+	Completed       bool       `gorm:"completed" json:"completed"`
+	Sequence        int        `gorm:"default:0" json:"sequence"`
+	Notes           string     `json:"notes,omitempty"`
+	Priority        string     `gorm:"size:16;default:normal" json:"priority"`
+	RescheduledFrom *time.Time `json:"rescheduled_from,omitempty"`
+	UserCreated     bool       `gorm:"index" json:"user_created"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Progress        []Progress `gorm:"foreignKey:DailyTaskID" json:"progress,omitempty"`
 }
 
-// Progress tracks when a task was completed
+// Progress records one completion or un-completion of a task. CompletedAt
+// is when it happened and Completed is false when the task was unchecked.
+// TaskDate and Category are copied from the task so the history outlives a
+// regenerated plan.
 type Progress struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
-	DailyTaskID uint      `gorm:"daily_task_id" json:"daily_task_id"`
-	CompletedAt time.Time `gorm:"completed_at" json:"completed_at"`
+	DailyTaskID uint      `gorm:"daily_task_id;index" json:"daily_task_id"`
+	CompletedAt time.Time `gorm:"completed_at;index" json:"completed_at"`
+	Completed   bool      `json:"completed"`
+	TaskDate    time.Time `json:"task_date"`
+	Category    string    `gorm:"size:64" json:"category,omitempty"`
+	Source      string    `gorm:"size:32" json:"source,omitempty"`
+}
+
+// StudySession is time spent on a task with the focus timer. Seconds holds
+// the time worked up to ResumedAt; while the session runs the time since
+// ResumedAt is added on top. A paused session has PausedAt set and a
+// finished one EndedAt. The task's category, ACS references, template task
+// and planned minutes are copied so the history outlives a regenerated plan.
+type StudySession struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	DailyTaskID    uint       `gorm:"index" json:"daily_task_id"`
+	TaskTitle      string     `json:"task_title"`
+	Category       string     `gorm:"size:64" json:"category"`
+	ACSRefs        string     `json:"acs_refs,omitempty"`
+	TemplateTaskID string     `gorm:"size:64" json:"template_task_id,omitempty"`
+	PlannedMinutes int        `json:"planned_minutes,omitempty"`
+	Pomodoro       bool       `json:"pomodoro"`
+	StartedAt      time.Time  `json:"started_at"`
+	ResumedAt      time.Time  `json:"resumed_at"`
+	PausedAt       *time.Time `json:"paused_at,omitempty"`
+	EndedAt        *time.Time `gorm:"index" json:"ended_at,omitempty"`
+	Seconds        int        `json:"seconds"`
+	Source         string     `gorm:"size:32" json:"source,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// Milestone records when a plan template milestone (first solo, knowledge
+// test, ...) was achieved. Key is the template milestone id.
+type Milestone struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Key        string     `gorm:"size:64;uniqueIndex;not null" json:"key"`
+	AchievedAt *time.Time `json:"achieved_at,omitempty"`
+	Source     string     `gorm:"size:32" json:"source,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Endorsement is a CFI logbook endorsement (14 CFR 61.87, 61.93, 61.39).
+// Kind is one of the keys in services.EndorsementKinds. ExpiresOn is nil for
+// endorsements that do not lapse; CFIExpiresOn is the instructor's
+// certificate expiration as written in the endorsement.
+type Endorsement struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	Kind           string     `gorm:"size:32;index;not null" json:"kind"`
+	IssuedOn       time.Time  `json:"issued_on"`
+	ExpiresOn      *time.Time `json:"expires_on,omitempty"`
+	CFIName        string     `gorm:"size:128" json:"cfi_name"`
+	CFICertificate string     `gorm:"size:32" json:"cfi_certificate"`
+	CFIExpiresOn   *time.Time `json:"cfi_expires_on,omitempty"`
+	Notes          string     `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PilotDocument is one of the student's own certificates or records, such as
+// the medical or knowledge test report. Kind is one of the keys in
+// services.DocumentKinds; ExpiresOn is computed from IssuedOn unless the
+// document carries its own expiration date.
+type PilotDocument struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Kind      string     `gorm:"size:32;uniqueIndex;not null" json:"kind"`
+	IssuedOn  time.Time  `json:"issued_on"`
+	ExpiresOn *time.Time `json:"expires_on,omitempty"`
+	Notes     string     `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Aircraft is an airplane the student flies, with the dates of its
+// inspections (14 CFR 91.409-91.413, 91.207, 91.171). ForHire aircraft need
+// 100-hour inspections; IFR aircraft need pitot-static and VOR checks.
+// CruiseKTAS, FuelBurnGPH and UsableFuelGal feed the cross-country navlog.
+type Aircraft struct {
+	ID              uint         `gorm:"primaryKey" json:"id"`
+	TailNumber      string       `gorm:"size:16;uniqueIndex;not null" json:"tail_number"`
+	Type            string       `gorm:"size:64" json:"type"`
+	ForHire         bool         `json:"for_hire"`
+	IFR             bool         `json:"ifr"`
+	Annual          *time.Time   `json:"annual,omitempty"`
+	HundredHourTach float64      `json:"hundred_hour_tach,omitempty"`
+	CurrentTach     float64      `json:"current_tach,omitempty"`
+	Transponder     *time.Time   `json:"transponder,omitempty"`
+	ELTInspection   *time.Time   `json:"elt_inspection,omitempty"`
+	ELTBatteryDue   *time.Time   `json:"elt_battery_due,omitempty"`
+	PitotStatic     *time.Time   `json:"pitot_static,omitempty"`
+	VORCheck        *time.Time   `json:"vor_check,omitempty"`
+	CruiseKTAS      float64      `gorm:"column:cruise_ktas" json:"cruise_ktas,omitempty"`
+	FuelBurnGPH     float64      `gorm:"column:fuel_burn_gph" json:"fuel_burn_gph,omitempty"`
+	UsableFuelGal   float64      `json:"usable_fuel_gal,omitempty"`
+	Notes           string       `gorm:"type:text" json:"notes,omitempty"`
+	Directives      []AircraftAD `gorm:"foreignKey:AircraftID;constraint:OnDelete:CASCADE" json:"directives,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	UpdatedAt       time.Time    `json:"updated_at"`
+}
+
+// AircraftAD is a recurring airworthiness directive on an aircraft, due by
+// date, tach time, or whichever comes first.
+type AircraftAD struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	AircraftID  uint       `gorm:"index;not null" json:"aircraft_id"`
+	Number      string     `gorm:"size:32;not null" json:"number"`
+	Description string     `gorm:"size:255" json:"description,omitempty"`
+	NextDueDate *time.Time `json:"next_due_date,omitempty"`
+	NextDueTach float64    `json:"next_due_tach,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 }
 
 // ChecklistCategory represents FAA ACS categories
@@ -45,13 +169,74 @@ const (
 	CategoryFlight    ChecklistCategory = "Flight"
 )
 
-// ChecklistItem represents an item in the pre-checkride checklist
+// ChecklistItem represents an item in the pre-checkride checklist. TemplateKey
+// links it to the default checklist; custom items have none.
 type ChecklistItem struct {
-	ID        uint              `gorm:"primaryKey" json:"id"`
-	Category  ChecklistCategory `gorm:"category" json:"category"`
-	Title     string            `gorm:"title" json:"title"`
-	Completed bool              `gorm:"completed" json:"completed"`
-	CreatedAt time.Time         `json:"created_at"`
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	Category    ChecklistCategory `gorm:"category" json:"category"`
+	Title       string            `gorm:"title" json:"title"`
+	Completed   bool              `gorm:"completed" json:"completed"`
+	TemplateKey string            `gorm:"size:64;index" json:"template_key,omitempty"`
+	Position    int               `gorm:"default:0" json:"position"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// GoNoGoDecision is one logged go/no-go evaluation. Reasons holds the
+// no-go and caution findings joined with "; "; METAR and TAF keep the raw
+// reports the decision was made on.
+type GoNoGoDecision struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Airport   string    `gorm:"size:8;index" json:"airport"`
+	FlightAt  time.Time `json:"flight_at"`
+	Solo      bool      `json:"solo"`
+	Night     bool      `json:"night"`
+	Tail      string    `gorm:"size:16" json:"tail,omitempty"`
+	Decision  string    `gorm:"size:8;not null" json:"decision"`
+	Reasons   string    `gorm:"type:text" json:"reasons,omitempty"`
+	METAR     string    `gorm:"column:metar;type:text" json:"metar,omitempty"`
+	TAF       string    `gorm:"column:taf;type:text" json:"taf,omitempty"`
+	Note      string    `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LogbookEntry is a flight in the student's logbook. Entries logged from a
+// cross-country plan start out planned, with PlannedHours from the navlog,
+// and are marked flown with the actual Hours. CrossCountry and LongSoloXC
+// record whether the route meets 61.1 and 61.109(a)(5)(ii).
+type LogbookEntry struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Date         time.Time  `gorm:"index" json:"date"`
+	Route        string     `gorm:"size:255" json:"route"`
+	Tail         string     `gorm:"size:16" json:"tail,omitempty"`
+	Solo         bool       `json:"solo"`
+	CrossCountry bool       `json:"cross_country"`
+	LongSoloXC   bool       `gorm:"column:long_solo_xc" json:"long_solo_xc"`
+	DistanceNM   float64    `gorm:"column:distance_nm" json:"distance_nm"`
+	PlannedHours float64    `json:"planned_hours"`
+	Hours        float64    `json:"hours,omitempty"`
+	FlownAt      *time.Time `json:"flown_at,omitempty"`
+	Remarks      string     `gorm:"type:text" json:"remarks,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ProgressSnapshot is the state of the training on one day, kept for trend
+// charts and the projected completion date. Task counts cover the latest
+// study plan. PlannedPercent is the share of its tasks scheduled on or before
+// Date. SpentUSD estimates spend so far from
+// the flown logbook hours and the budget's plane and CFI rates.
+type ProgressSnapshot struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	Date            time.Time `gorm:"uniqueIndex" json:"date"`
+	TasksCompleted  int       `json:"tasks_completed"`
+	TasksTotal      int       `json:"tasks_total"`
+	ProgressPercent float64   `json:"progress_percent"`
+	PlannedPercent  float64   `json:"planned_percent"`
+	ReadinessScore  float64   `json:"readiness_score"`
+	SpentUSD        float64   `gorm:"column:spent_usd" json:"spent_usd"`
+	BudgetUSD       float64   `gorm:"column:budget_usd" json:"budget_usd"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // BudgetItemType represents types of budget items
@@ -95,6 +280,21 @@ type AutomationIdempotency struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// OutboxEvent is a durable record of a plan change waiting for webhook delivery.
+type OutboxEvent struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	EventType      string     `gorm:"size:64;not null;index" json:"event_type"`
+	PayloadJSON    string     `gorm:"type:text;not null" json:"payload_json"`
+	Status         string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
 // DB wraps gorm.DB for convenience
 type DB struct {
 	*gorm.DB
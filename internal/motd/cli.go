@@ -11,10 +11,17 @@ import (
 	"time"
 
 	"github.com/mattn/go-isatty"
+	"gorm.io/gorm"
 
 	"ppl-study-planner/internal/services"
 )
 
+// OpenStudyDB opens the study plan database for `motd progress`, which
+// combines the quiz with the rest of the training record. main sets it so
+// the default display stays database-free; when nil or failing, progress
+// shows the quiz alone.
+var OpenStudyDB func() (*gorm.DB, error)
+
 // Execute is the top-level dispatcher for all `openppl motd [subcommand]`
 // invocations. It is called from main.go and returns a process exit code.
 //
@@ -81,7 +88,16 @@ func runDisplay(stdout io.Writer) int {
 		fmt.Fprintf(stdout, "Question:  What does ACS %s require?\n", entry.Code)
 		fmt.Fprintf(stdout, "Answer:    %s\n", objective)
 	}
-	fmt.Fprintf(stdout, "Insight:   %s\n\n", studyInsight(entry.Section, entry.Category))
+	fmt.Fprintf(stdout, "Insight:   %s\n", studyInsight(entry.Section, entry.Category))
+	cache := services.LoadMOTDCache()
+	if line := quizStreakLine(cache.QuizStreaks(time.Now())); line != "" {
+		fmt.Fprintf(stdout, "Streak:    %s\n", line)
+	}
+	fmt.Fprintln(stdout)
+
+	for _, warning := range cache.DocumentWarnings(time.Now()) {
+		fmt.Fprintf(stdout, "Warning:   %s\n", warning)
+	}
 
 	current := currentVersionTag()
 	if current != "" {
@@ -350,9 +366,64 @@ func runProgress(stdout io.Writer) int {
 		}
 	}
 
+	if OpenStudyDB == nil {
+		return 0
+	}
+	studyDB, err := OpenStudyDB()
+	if err != nil {
+		fmt.Fprintf(stdout, "\nCheckride readiness unavailable: %v\n", err)
+		return 0
+	}
+	printReadiness(stdout, services.LoadReadiness(studyDB, attempts, time.Now()))
+	if streaks, err := services.LoadStudyStreaks(studyDB, attempts, time.Now(), services.StreakWeeks); err == nil {
+		printStreaks(stdout, streaks)
+	}
 	return 0
 }
 
+// quizStreakLine is the display card's streak line. The card reads the
+// streak from the MOTD cache instead of any database at login, so it counts
+// quiz answers only. It is empty before the first answer.
+func quizStreakLine(streaks services.StudyStreaks) string {
+	if streaks.Longest == 0 {
+		return ""
+	}
+	line := fmt.Sprintf("%d days of quiz answers in a row (longest %d)", streaks.Current, streaks.Longest)
+	if !streaks.Today {
+		line += ", answer today's quiz to keep it going"
+	}
+	return line
+}
+
+// printStreaks prints the study streak over tasks and quiz answers with
+// the weekly consistency, most recent week last.
+func printStreaks(stdout io.Writer, streaks services.StudyStreaks) {
+	fmt.Fprintln(stdout, "\nStudy Streak")
+	fmt.Fprintf(stdout, "Current: %d days, longest: %d days\n", streaks.Current, streaks.Longest)
+	fmt.Fprintf(stdout, "Studied on %.0f%% of days over the last %d weeks (%d missed)\n", streaks.Consistency(), len(streaks.Weeks), streaks.MissedDays)
+	for _, week := range streaks.Weeks {
+		if week.Days > 0 {
+			fmt.Fprintf(stdout, "  Week of %s: %d/%d days\n", week.Start.Format("Jan 2"), week.ActiveDays, week.Days)
+		}
+	}
+}
+
+// printReadiness prints the combined checkride readiness score with each
+// factor and the recommended next actions.
+func printReadiness(stdout io.Writer, readiness services.Readiness) {
+	fmt.Fprintln(stdout, "\nCheckride Readiness")
+	fmt.Fprintf(stdout, "Score: %.1f/100 (%s)\n", readiness.Score, readiness.Label)
+	for _, factor := range readiness.Factors {
+		fmt.Fprintf(stdout, "  %-20s %5.1f  (weight %2.0f%%)  %s\n", factor.Name, factor.Score, factor.Weight*100, factor.Detail)
+	}
+	if len(readiness.NextActions) > 0 {
+		fmt.Fprintln(stdout, "\nNext actions:")
+		for _, action := range readiness.NextActions {
+			fmt.Fprintf(stdout, "  - %s\n", action)
+		}
+	}
+}
+
 func runWeakAreas(stdout io.Writer) int {
 	db, err := services.InitMOTDDB()
 	if err != nil {
@@ -75,3 +75,15 @@ func TestDisplay_UsesReviewLayoutWhenQuizDisabled(t *testing.T) {
 		t.Fatalf("expected hint to re-enable quiz mode, got %q", out)
 	}
 }
+
+func TestQuizStreakLine(t *testing.T) {
+	if got := quizStreakLine(services.StudyStreaks{}); got != "" {
+		t.Fatalf("expected no streak line before any answer, got %q", got)
+	}
+	if got := quizStreakLine(services.StudyStreaks{Current: 2, Longest: 2}); got != "2 days of quiz answers in a row (longest 2), answer today's quiz to keep it going" {
+		t.Fatalf("unexpected streak line %q", got)
+	}
+	if got := quizStreakLine(services.StudyStreaks{Current: 3, Longest: 5, Today: true}); got != "3 days of quiz answers in a row (longest 5)" {
+		t.Fatalf("unexpected streak line %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package onboarding
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -75,7 +76,7 @@ func RunInteractiveSetup(db *gorm.DB, in io.Reader, out io.Writer, force bool) e
 	fmt.Fprintln(out, "\nopenppl onboarding")
 	fmt.Fprintln(out, "Choose planning mode and answer setup questions. Press Enter to accept defaults.")
 
-	values, err := collectValues(scanner, out)
+	values, err := collectValues(db, scanner, out)
 	if err != nil {
 		return err
 	}
@@ -92,7 +93,7 @@ func RunInteractiveSetup(db *gorm.DB, in io.Reader, out io.Writer, force bool) e
 	}
 	fmt.Fprintf(out, "- Study window: today -> %s (%d days)\n", values.CheckrideDate.Format("2006-01-02"), values.PlanDays)
 	fmt.Fprintf(out, "- School airport: %s\n", values.SchoolAirport)
-	fmt.Fprintf(out, "- Weather outlook: %s\n", values.WeatherNote)
+	fmt.Fprintf(out, "- Weather: %s\n", values.WeatherNote)
 	fmt.Fprintf(out, "- Airport traffic outlook: %s\n", values.BusyNote)
 	if len(values.RiskNotes) == 0 {
 		fmt.Fprintln(out, "- Plan risk: Low (timeline looks healthy).")
@@ -116,7 +117,7 @@ func hasExistingStudyPlan(db *gorm.DB) (bool, error) {
 	return count > 0, nil
 }
 
-func collectValues(scanner *bufio.Scanner, out io.Writer) (SetupValues, error) {
+func collectValues(db *gorm.DB, scanner *bufio.Scanner, out io.Writer) (SetupValues, error) {
 	var v SetupValues
 
 	now := time.Now()
@@ -128,20 +129,30 @@ func collectValues(scanner *bufio.Scanner, out io.Writer) (SetupValues, error) {
 	}
 	v.PlanningMode = mode
 
-	if mode == modeByDate {
-		date, dateErr := promptFutureDate(scanner, out, "Checkride date (YYYY-MM-DD)", today.AddDate(0, 3, 0), today)
-		if dateErr != nil {
-			return v, dateErr
+	for {
+		if mode == modeByDate {
+			date, dateErr := promptFutureDate(scanner, out, "Checkride date (YYYY-MM-DD)", today.AddDate(0, 3, 0), today)
+			if dateErr != nil {
+				return v, dateErr
+			}
+			v.CheckrideDate = date
+			v.PlanDays = planDaysFrom(today, date)
+		} else {
+			days, daysErr := promptInt(scanner, out, "Planning horizon in days", defaultPlanDays, 7, 730)
+			if daysErr != nil {
+				return v, daysErr
+			}
+			v.PlanDays = days
+			v.CheckrideDate = today.AddDate(0, 0, days)
 		}
-		v.CheckrideDate = date
-		v.PlanDays = planDaysFrom(today, date)
-	} else {
-		days, daysErr := promptInt(scanner, out, "Planning horizon in days", defaultPlanDays, 7, 730)
-		if daysErr != nil {
-			return v, daysErr
+
+		var blocked *services.CheckrideBlockedError
+		if err := services.CheckCheckrideDate(db, v.CheckrideDate); errors.As(err, &blocked) {
+			fmt.Fprintf(out, "  Cannot schedule: %v.\n  Pick a checkride on or before %s, or renew the document first.\n",
+				blocked, blocked.LatestCheckride().Format("2006-01-02"))
+			continue
 		}
-		v.PlanDays = days
-		v.CheckrideDate = today.AddDate(0, 0, days)
+		break
 	}
 
 	airport, err := promptAirport(scanner, out, "School airport ICAO", defaultAirport)
@@ -149,7 +160,7 @@ func collectValues(scanner *bufio.Scanner, out io.Writer) (SetupValues, error) {
 		return v, err
 	}
 	v.SchoolAirport = airport
-	v.WeatherNote = weatherOutlook(airport, today)
+	v.WeatherNote = weatherNote(services.DefaultWeatherProvider(), airport)
 	v.BusyNote = trafficOutlook(airport)
 
 	planeRate, err := promptFloat(scanner, out, "Plane rental rate ($/hr)", defaultPlaneRate, 0, 1000)
@@ -176,7 +187,7 @@ func collectValues(scanner *bufio.Scanner, out io.Writer) (SetupValues, error) {
 	v.CfiRate = cfiRate
 	v.TravelCost = travelCost
 	v.BudgetLimit = budget
-	v.RiskNotes = riskAssessment(v.PlanDays)
+	v.RiskNotes = append(riskAssessment(v.PlanDays), services.CheckrideRiskNotes(db, v.CheckrideDate)...)
 
 	return v, nil
 }
@@ -239,7 +250,15 @@ func promptFutureDate(scanner *bufio.Scanner, out io.Writer, label string, defau
 	}
 }
 
+// promptAirport accepts airports from the airport database, resolving
+// three-letter US identifiers to ICAO codes. A code the database does not
+// know is accepted when it is entered twice in a row.
 func promptAirport(scanner *bufio.Scanner, out io.Writer, label string, defaultValue string) (string, error) {
+	airports, err := services.LoadAirports()
+	if err != nil {
+		fmt.Fprintf(out, "  Note: %v\n", err)
+	}
+	unconfirmed := ""
 	for {
 		fmt.Fprintf(out, "%s [%s]: ", label, defaultValue)
 		if !scanner.Scan() {
@@ -251,27 +270,39 @@ func promptAirport(scanner *bufio.Scanner, out io.Writer, label string, defaultV
 
 		value := strings.ToUpper(strings.TrimSpace(scanner.Text()))
 		if value == "" {
-			return defaultValue, nil
+			value = defaultValue
 		}
 
 		if len(value) < 3 || len(value) > 4 {
-			fmt.Fprintln(out, "  Airport code should be 3-4 letters (e.g. KFXE).")
+			fmt.Fprintln(out, "  Airport code should be 3-4 characters (e.g. KFXE).")
 			continue
 		}
 
 		valid := true
 		for _, r := range value {
-			if r < 'A' || r > 'Z' {
+			if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
 				valid = false
 				break
 			}
 		}
 		if !valid {
-			fmt.Fprintln(out, "  Use letters only for airport code.")
+			fmt.Fprintln(out, "  Use letters and digits only for airport code.")
 			continue
 		}
 
-		return value, nil
+		if airport, ok := airports.Lookup(value); ok {
+			tower := "non-towered"
+			if airport.Towered {
+				tower = "towered"
+			}
+			fmt.Fprintf(out, "  %s %s (%s, %s)\n", airport.ICAO, airport.Name, tower, airport.TimeZone)
+			return airport.ICAO, nil
+		}
+		if value == unconfirmed {
+			return value, nil
+		}
+		unconfirmed = value
+		fmt.Fprintf(out, "  %s is not in the airport database. Check the code, or enter it again to use it anyway\n  (exports then use UTC; add it to ~/.openppl/airports.csv for time zone and traffic advice).\n", value)
 	}
 }
 
@@ -328,6 +359,10 @@ func applySetup(db *gorm.DB, values SetupValues) error {
 		return errors.New("database is not initialized")
 	}
 
+	if err := services.CheckCheckrideDate(db, values.CheckrideDate); err != nil {
+		return err
+	}
+
 	return db.Transaction(func(tx *gorm.DB) error {
 		var plan model.StudyPlan
 		err := tx.Order("id desc").Limit(1).Find(&plan).Error
@@ -347,11 +382,14 @@ func applySetup(db *gorm.DB, values SetupValues) error {
 			}
 		}
 
-		if err := tx.Where("study_plan_id = ?", plan.ID).Delete(&model.DailyTask{}).Error; err != nil {
+		if err := services.DeleteGeneratedTasks(tx, plan.ID); err != nil {
 			return err
 		}
 
 		tasks := services.GenerateStudyPlan(values.CheckrideDate, values.PlanDays)
+		if err := services.ApplyActualDurations(tx, tasks); err != nil {
+			return err
+		}
 		for i := range tasks {
 			tasks[i].StudyPlanID = plan.ID
 		}
@@ -360,17 +398,24 @@ func applySetup(db *gorm.DB, values SetupValues) error {
 				return err
 			}
 		}
+		if err := services.RecordPlanRegenerated(tx, plan, len(tasks), "onboarding"); err != nil {
+			return err
+		}
 
-		if err := upsertBudgetItem(tx, model.BudgetPlaneRate, values.PlaneRate); err != nil {
+		if err := services.SeedChecklist(tx); err != nil {
 			return err
 		}
-		if err := upsertBudgetItem(tx, model.BudgetCfiRate, values.CfiRate); err != nil {
+
+		if err := services.SaveBudgetItem(tx, model.BudgetPlaneRate, values.PlaneRate, "onboarding"); err != nil {
 			return err
 		}
-		if err := upsertBudgetItem(tx, model.BudgetLiving, values.TravelCost); err != nil {
+		if err := services.SaveBudgetItem(tx, model.BudgetCfiRate, values.CfiRate, "onboarding"); err != nil {
 			return err
 		}
-		if err := upsertBudgetItem(tx, model.BudgetLimit, values.BudgetLimit); err != nil {
+		if err := services.SaveBudgetItem(tx, model.BudgetLiving, values.TravelCost, "onboarding"); err != nil {
+			return err
+		}
+		if err := services.SaveBudgetItem(tx, model.BudgetLimit, values.BudgetLimit, "onboarding"); err != nil {
 			return err
 		}
 
@@ -380,7 +425,8 @@ func applySetup(db *gorm.DB, values SetupValues) error {
 		if err := upsertConfig(tx, "school_airport", values.SchoolAirport); err != nil {
 			return err
 		}
-		if err := upsertConfig(tx, "weather_outlook", values.WeatherNote); err != nil {
+		// Weather is fetched live now; drop the snapshot older setups saved.
+		if err := tx.Where("key = ?", "weather_outlook").Delete(&model.AppConfig{}).Error; err != nil {
 			return err
 		}
 		if err := upsertConfig(tx, "traffic_outlook", values.BusyNote); err != nil {
@@ -394,20 +440,6 @@ func applySetup(db *gorm.DB, values SetupValues) error {
 	})
 }
 
-func upsertBudgetItem(tx *gorm.DB, itemType model.BudgetItemType, amount float64) error {
-	var b model.Budget
-	if err := tx.Where("item_type = ?", itemType).Order("id asc").Limit(1).Find(&b).Error; err != nil {
-		return err
-	}
-
-	if b.ID == 0 {
-		return tx.Create(&model.Budget{ItemType: itemType, Amount: amount}).Error
-	}
-
-	b.Amount = amount
-	return tx.Save(&b).Error
-}
-
 func upsertConfig(tx *gorm.DB, key string, value string) error {
 	var cfg model.AppConfig
 	if err := tx.Where("key = ?", key).Limit(1).Find(&cfg).Error; err != nil {
@@ -452,53 +484,35 @@ func riskAssessment(planDays int) []string {
 	return risks
 }
 
-func weatherOutlook(airport string, today time.Time) string {
-	month := today.Month()
+// weatherNote summarizes the school airport's current flight category and
+// the lowest category in its TAF.
+func weatherNote(provider services.WeatherProvider, airport string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	florida := map[string]bool{
-		"KFXE": true,
-		"KFLL": true,
-		"KPMP": true,
-		"KMIA": true,
-		"KOPF": true,
-		"KPBI": true,
+	metar, err := provider.METAR(ctx, airport)
+	if err != nil || metar.Category == services.FlightCategoryUnknown {
+		return fmt.Sprintf("Live weather for %s is unavailable right now; the dashboard shows its flight category once it is.", airport)
 	}
-
-	if florida[airport] {
-		if month >= time.May && month <= time.October {
-			return "South Florida wet season: expect afternoon convective storms, gust fronts, and frequent temporary delays."
-		}
-		return "South Florida dry season: generally flyable mornings with periodic windy afternoons and occasional frontal passage."
-	}
-
-	if strings.HasPrefix(airport, "K") {
-		if month == time.December || month == time.January || month == time.February {
-			return "Winter season: monitor icing ceilings/visibility trends and keep alternate training blocks ready."
-		}
-		if month >= time.June && month <= time.August {
-			return "Summer season: expect heat turbulence and occasional thunderstorm constraints in the afternoon."
+	note := fmt.Sprintf("%s is %s now", airport, metar.Category)
+	if taf, err := provider.TAF(ctx, airport); err == nil {
+		if worst, ok := taf.WorstCategoryBetween(taf.ValidFrom, taf.ValidTo); ok && worst.WorseThan(metar.Category) {
+			note += fmt.Sprintf(", forecast down to %s before %s", worst, taf.ValidTo.Local().Format("Jan 2 15:04"))
 		}
 	}
-
-	return "Typical mixed conditions expected. Keep a rolling weather backup plan for 2-3 training sessions per week."
+	return note + ". The dashboard tracks it live and flags CFI flights on forecast-IFR days."
 }
 
-func trafficOutlook(airport string) string {
-	busy := map[string]bool{
-		"KFXE": true,
-		"KFLL": true,
-		"KMIA": true,
-		"KJFK": true,
-		"KLAX": true,
-		"KATL": true,
-		"KORD": true,
-		"KSFO": true,
-		"KLAS": true,
+// trafficOutlook gives pattern and scheduling advice for towered and
+// non-towered airports.
+func trafficOutlook(code string) string {
+	airport, ok := services.LookupAirport(code)
+	switch {
+	case !ok:
+		return "Moderate traffic profile expected. Keep flexibility around local events/weekends when pattern activity can spike."
+	case airport.Towered:
+		return fmt.Sprintf("%s is towered: expect ATC sequencing delays at peak flow, often 07:00-10:00 and 16:00-19:00 local. Prefer mid-day blocks for training flights, and chair-fly tower calls before each lesson.", airport.ICAO)
+	default:
+		return fmt.Sprintf("%s is non-towered: self-announce on the CTAF and watch for no-radio traffic. Pattern activity spikes on weekend mornings and good-weather evenings; fly pattern work early on weekdays.", airport.ICAO)
 	}
-
-	if busy[airport] {
-		return "High traffic airport profile: peak flow often 07:00-10:00 and 16:00-19:00 local. Prefer mid-day blocks for training flights."
-	}
-
-	return "Moderate traffic profile expected. Keep flexibility around local events/weekends when pattern activity can spike."
 }
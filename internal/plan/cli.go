@@ -0,0 +1,144 @@
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl plan template export [file]   Write the active plan template (stdout by default)
+  openppl plan template validate <file> Check a plan template for errors
+  openppl plan template use <file>      Make a plan template active for new plans
+  openppl plan template use default     Go back to the built-in template`
+
+// Execute is the dispatcher for `openppl plan ...`.
+func Execute(args []string, stdout io.Writer) int {
+	if len(args) == 0 || args[0] != "template" {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	args = args[1:]
+	if len(args) == 0 {
+		return runShow(stdout)
+	}
+
+	switch args[0] {
+	case "show":
+		return runShow(stdout)
+	case "export":
+		path := ""
+		if len(args) > 1 {
+			path = args[1]
+		}
+		return runExport(path, stdout)
+	case "validate":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl plan template validate <file>")
+			return 1
+		}
+		return runValidate(args[1], stdout)
+	case "use":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, "usage: openppl plan template use <file|default>")
+			return 1
+		}
+		return runUse(args[1], stdout)
+	default:
+		fmt.Fprintf(stdout, "Unknown plan template command %q\n%s\n", args[0], usage)
+		return 1
+	}
+}
+
+func runShow(stdout io.Writer) int {
+	tpl, source, err := services.LoadPlanTemplate()
+	if err != nil {
+		fmt.Fprintf(stdout, "Warning: %v\nUsing the built-in template instead.\n", err)
+	}
+	fmt.Fprintf(stdout, "Active plan template: %s (%s)\n", tpl.Name, source)
+	for _, category := range tpl.Categories {
+		fmt.Fprintf(stdout, "  %-14s every %d day(s), %d tasks\n", category.Name, category.EveryDays, len(category.Tasks))
+	}
+	return 0
+}
+
+func runExport(path string, stdout io.Writer) int {
+	tpl, _, err := services.LoadPlanTemplate()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\nExporting the built-in template instead.\n", err)
+	}
+	data, err := services.MarshalPlanTemplate(tpl)
+	if err != nil {
+		fmt.Fprintf(stdout, "Export failed: %v\n", err)
+		return 1
+	}
+	if path == "" || path == "-" {
+		_, _ = stdout.Write(data)
+		return 0
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fmt.Fprintf(stdout, "Export failed: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Wrote plan template %q to %s\n", tpl.Name, path)
+	return 0
+}
+
+func runValidate(path string, stdout io.Writer) int {
+	tpl, ok := readTemplate(path, stdout)
+	if !ok {
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s is valid: %q, %d categories, %d tasks\n", path, tpl.Name, len(tpl.Categories), tpl.TaskCount())
+	return 0
+}
+
+func runUse(path string, stdout io.Writer) int {
+	if path == "default" {
+		if err := services.ResetPlanTemplate(); err != nil {
+			fmt.Fprintf(stdout, "Could not reset plan template: %v\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, "Using the built-in plan template.")
+		fmt.Fprintln(stdout, "Regenerate your plan (openppl --configure) to apply it.")
+		return 0
+	}
+
+	tpl, ok := readTemplate(path, stdout)
+	if !ok {
+		return 1
+	}
+	saved, err := services.UsePlanTemplate(tpl)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not activate plan template: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Using plan template %q (saved to %s).\n", tpl.Name, saved)
+	fmt.Fprintln(stdout, "Regenerate your plan (openppl --configure) to apply it.")
+	return 0
+}
+
+func readTemplate(path string, stdout io.Writer) (services.PlanTemplate, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not read %s: %v\n", path, err)
+		return services.PlanTemplate{}, false
+	}
+	tpl, err := services.ParsePlanTemplate(data)
+	if err != nil {
+		var templateErr *services.PlanTemplateError
+		if errors.As(err, &templateErr) {
+			fmt.Fprintf(stdout, "%s is not a valid plan template:\n", path)
+			for _, problem := range templateErr.Problems {
+				fmt.Fprintf(stdout, "  - %s\n", problem)
+			}
+			return services.PlanTemplate{}, false
+		}
+		fmt.Fprintf(stdout, "%s is not a valid plan template: %v\n", path, err)
+		return services.PlanTemplate{}, false
+	}
+	return tpl, true
+}
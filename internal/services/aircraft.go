@@ -0,0 +1,473 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	AirworthinessCurrent = "current"
+	AirworthinessDue     = "due"
+	AirworthinessOverdue = "overdue"
+	AirworthinessMissing = "missing"
+
+	// hundredHourWarningTach is how many tach hours before a 100-hour
+	// inspection or tach-based AD the check is reported as due.
+	hundredHourWarningTach = 10
+
+	aircraftInspectionsChecklistKey = "inspections"
+	aircraftDirectivesChecklistKey  = "airworthiness-directives"
+)
+
+var tailNumberPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{1,9}$`)
+
+// AirworthinessCheck is one inspection or AD evaluated for a checkride date.
+// DueDate or DueTach is set for date- or tach-based items respectively.
+type AirworthinessCheck struct {
+	Item      string
+	Reference string
+	Status    string
+	DueDate   time.Time
+	DueTach   float64
+	Directive bool
+	Detail    string
+}
+
+// Failed reports whether the check keeps the aircraft off the checkride.
+func (c AirworthinessCheck) Failed() bool {
+	return c.Status != AirworthinessCurrent
+}
+
+// AircraftReport is the airworthiness of one aircraft for a checkride date.
+type AircraftReport struct {
+	Aircraft  model.Aircraft
+	Checkride time.Time
+	Checks    []AirworthinessCheck
+}
+
+// Airworthy reports whether every check passes.
+func (r AircraftReport) Airworthy() bool {
+	for _, check := range r.Checks {
+		if check.Failed() {
+			return false
+		}
+	}
+	return true
+}
+
+// AircraftUpdate changes the fields that are set and leaves the rest alone.
+type AircraftUpdate struct {
+	Type            *string
+	ForHire         *bool
+	IFR             *bool
+	Annual          *time.Time
+	HundredHourTach *float64
+	CurrentTach     *float64
+	Transponder     *time.Time
+	ELTInspection   *time.Time
+	ELTBatteryDue   *time.Time
+	PitotStatic     *time.Time
+	VORCheck        *time.Time
+	CruiseKTAS      *float64
+	FuelBurnGPH     *float64
+	UsableFuelGal   *float64
+	Notes           *string
+}
+
+// NormalizeTailNumber upper-cases and validates a registration mark.
+func NormalizeTailNumber(tail string) (string, error) {
+	tail = strings.ToUpper(strings.TrimSpace(tail))
+	if !tailNumberPattern.MatchString(tail) {
+		return "", fmt.Errorf("invalid tail number %q", tail)
+	}
+	return tail, nil
+}
+
+// CheckAirworthiness evaluates an aircraft's inspections and ADs. Date-based
+// items must still be valid on the checkride date (or today, if no checkride
+// is set); tach-based items are due within 10 hours of their limit.
+func CheckAirworthiness(aircraft model.Aircraft, checkride time.Time, now time.Time) []AirworthinessCheck {
+	today := dateOnlyUTC(now)
+	target := today
+	if !checkride.IsZero() && checkride.After(today) {
+		target = dateOnlyUTC(checkride)
+	}
+
+	dateCheck := func(item, reference string, done *time.Time, validUntil func(time.Time) time.Time) AirworthinessCheck {
+		check := AirworthinessCheck{Item: item, Reference: reference}
+		if done == nil {
+			check.Status = AirworthinessMissing
+			check.Detail = "no date recorded"
+			return check
+		}
+		check.DueDate = validUntil(*done)
+		check.Status, check.Detail = dateStatus(check.DueDate, today, target)
+		return check
+	}
+	months := func(n int) func(time.Time) time.Time {
+		return func(done time.Time) time.Time { return endOfCalendarMonths(done, n) }
+	}
+
+	checks := []AirworthinessCheck{
+		dateCheck("Annual inspection", "91.409(a)", aircraft.Annual, months(12)),
+	}
+	if aircraft.ForHire {
+		check := AirworthinessCheck{Item: "100-hour inspection", Reference: "91.409(b)"}
+		if aircraft.HundredHourTach <= 0 || aircraft.CurrentTach <= 0 {
+			check.Status = AirworthinessMissing
+			check.Detail = "record the tach time at the last 100-hour and the current tach"
+		} else {
+			check.DueTach = aircraft.HundredHourTach + 100
+			check.Status, check.Detail = tachStatus(check.DueTach, aircraft.CurrentTach)
+		}
+		checks = append(checks, check)
+	}
+	checks = append(checks,
+		dateCheck("Transponder inspection", "91.413", aircraft.Transponder, months(24)),
+		dateCheck("ELT inspection", "91.207(d)", aircraft.ELTInspection, months(12)),
+		dateCheck("ELT battery", "91.207(c)", aircraft.ELTBatteryDue, func(due time.Time) time.Time { return dateOnlyUTC(due) }),
+	)
+	if aircraft.IFR {
+		checks = append(checks,
+			dateCheck("Pitot-static and altimeter", "91.411", aircraft.PitotStatic, months(24)),
+			dateCheck("VOR check", "91.171", aircraft.VORCheck, func(done time.Time) time.Time { return dateOnlyUTC(done).AddDate(0, 0, 30) }),
+		)
+	}
+
+	for _, ad := range aircraft.Directives {
+		check := AirworthinessCheck{Item: "AD " + ad.Number, Reference: ad.Description, Status: AirworthinessCurrent, Directive: true}
+		if ad.NextDueDate != nil {
+			check.DueDate = dateOnlyUTC(*ad.NextDueDate)
+			check.Status, check.Detail = dateStatus(check.DueDate, today, target)
+		}
+		if ad.NextDueTach > 0 && aircraft.CurrentTach > 0 {
+			status, detail := tachStatus(ad.NextDueTach, aircraft.CurrentTach)
+			if airworthinessSeverity(status) > airworthinessSeverity(check.Status) {
+				check.Status, check.Detail = status, detail
+			}
+			check.DueTach = ad.NextDueTach
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func dateStatus(due, today, target time.Time) (string, string) {
+	switch {
+	case due.Before(today):
+		return AirworthinessOverdue, "expired " + due.Format("2006-01-02")
+	case due.Before(target):
+		return AirworthinessDue, fmt.Sprintf("expires %s, before the %s checkride", due.Format("2006-01-02"), target.Format("2006-01-02"))
+	default:
+		return AirworthinessCurrent, "valid through " + due.Format("2006-01-02")
+	}
+}
+
+func tachStatus(dueTach, currentTach float64) (string, string) {
+	remaining := dueTach - currentTach
+	switch {
+	case remaining <= 0:
+		return AirworthinessOverdue, fmt.Sprintf("due at %.1f tach, now %.1f", dueTach, currentTach)
+	case remaining <= hundredHourWarningTach:
+		return AirworthinessDue, fmt.Sprintf("%.1f tach hours left (due at %.1f)", remaining, dueTach)
+	default:
+		return AirworthinessCurrent, fmt.Sprintf("due at %.1f tach", dueTach)
+	}
+}
+
+func airworthinessSeverity(status string) int {
+	switch status {
+	case AirworthinessOverdue:
+		return 3
+	case AirworthinessMissing:
+		return 2
+	case AirworthinessDue:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// endOfCalendarMonths returns the last day of the nth calendar month after
+// the month of t, the way "within the preceding N calendar months" is counted.
+func endOfCalendarMonths(t time.Time, n int) time.Time {
+	firstOfMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return firstOfMonth.AddDate(0, n+1, -1)
+}
+
+// ListAircraft returns all aircraft with their ADs, by tail number.
+func ListAircraft(database *gorm.DB) ([]model.Aircraft, error) {
+	var aircraft []model.Aircraft
+	if err := database.Preload("Directives", func(db *gorm.DB) *gorm.DB {
+		return db.Order("number asc")
+	}).Order("tail_number asc").Find(&aircraft).Error; err != nil {
+		return nil, fmt.Errorf("load aircraft: %w", err)
+	}
+	return aircraft, nil
+}
+
+// LoadAircraftReports evaluates every aircraft against the current study
+// plan's checkride date.
+func LoadAircraftReports(database *gorm.DB, now time.Time) ([]AircraftReport, error) {
+	aircraft, err := ListAircraft(database)
+	if err != nil || len(aircraft) == 0 {
+		return nil, err
+	}
+
+	var checkride time.Time
+	var plan model.StudyPlan
+	if err := database.Last(&plan).Error; err == nil {
+		checkride = plan.CheckrideDate
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("aircraft: load study plan: %w", err)
+	}
+
+	reports := make([]AircraftReport, 0, len(aircraft))
+	for _, a := range aircraft {
+		reports = append(reports, AircraftReport{Aircraft: a, Checkride: checkride, Checks: CheckAirworthiness(a, checkride, now)})
+	}
+	return reports, nil
+}
+
+// AircraftWarnings returns a dashboard warning for every failed check.
+func AircraftWarnings(reports []AircraftReport) []string {
+	var warnings []string
+	for _, report := range reports {
+		for _, check := range report.Checks {
+			if check.Failed() {
+				warnings = append(warnings, fmt.Sprintf("%s %s %s: %s", report.Aircraft.TailNumber, strings.ToLower(check.Item), check.Status, check.Detail))
+			}
+		}
+	}
+	return warnings
+}
+
+// SyncAircraftChecklist checks off the aircraft inspection and AD items of
+// the default checklist when every registered aircraft passes, and clears
+// them when one does not. Without registered aircraft the items are left to
+// the student.
+func SyncAircraftChecklist(database *gorm.DB, now time.Time) error {
+	reports, err := LoadAircraftReports(database, now)
+	if err != nil || len(reports) == 0 {
+		return err
+	}
+
+	inspectionsOK, directivesOK := true, true
+	for _, report := range reports {
+		for _, check := range report.Checks {
+			if !check.Failed() {
+				continue
+			}
+			if check.Directive {
+				directivesOK = false
+			} else {
+				inspectionsOK = false
+			}
+		}
+	}
+
+	for key, ok := range map[string]bool{aircraftInspectionsChecklistKey: inspectionsOK, aircraftDirectivesChecklistKey: directivesOK} {
+		if err := database.Model(&model.ChecklistItem{}).
+			Where("template_key = ? AND completed <> ?", key, ok).
+			Update("completed", ok).Error; err != nil {
+			return fmt.Errorf("update checklist item %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// SaveAircraft creates or updates an aircraft and refreshes the checklist.
+func SaveAircraft(database *gorm.DB, tail string, update AircraftUpdate, now time.Time) (model.Aircraft, error) {
+	tail, err := NormalizeTailNumber(tail)
+	if err != nil {
+		return model.Aircraft{}, err
+	}
+
+	var aircraft model.Aircraft
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tail_number = ?", tail).Limit(1).Find(&aircraft).Error; err != nil {
+			return fmt.Errorf("load aircraft %s: %w", tail, err)
+		}
+		aircraft.TailNumber = tail
+		if err := applyAircraftUpdate(&aircraft, update, now); err != nil {
+			return err
+		}
+		if err := tx.Omit("Directives").Save(&aircraft).Error; err != nil {
+			return fmt.Errorf("save aircraft %s: %w", tail, err)
+		}
+		return SyncAircraftChecklist(tx, now)
+	})
+	return aircraft, err
+}
+
+func applyAircraftUpdate(aircraft *model.Aircraft, update AircraftUpdate, now time.Time) error {
+	inspected := func(name string, value *time.Time, target **time.Time) error {
+		if value == nil {
+			return nil
+		}
+		day := dateOnlyUTC(*value)
+		if day.After(dateOnlyUTC(now)) {
+			return fmt.Errorf("%s date %s is in the future", name, day.Format("2006-01-02"))
+		}
+		*target = &day
+		return nil
+	}
+
+	if update.Type != nil {
+		aircraft.Type = strings.TrimSpace(*update.Type)
+	}
+	if update.ForHire != nil {
+		aircraft.ForHire = *update.ForHire
+	}
+	if update.IFR != nil {
+		aircraft.IFR = *update.IFR
+	}
+	if update.Notes != nil {
+		aircraft.Notes = strings.TrimSpace(*update.Notes)
+	}
+	if update.HundredHourTach != nil {
+		aircraft.HundredHourTach = *update.HundredHourTach
+	}
+	if update.CurrentTach != nil {
+		aircraft.CurrentTach = *update.CurrentTach
+	}
+	if aircraft.HundredHourTach < 0 || aircraft.CurrentTach < 0 {
+		return errors.New("tach times cannot be negative")
+	}
+	if aircraft.CurrentTach > 0 && aircraft.HundredHourTach > aircraft.CurrentTach {
+		return fmt.Errorf("100-hour tach %.1f is after the current tach %.1f", aircraft.HundredHourTach, aircraft.CurrentTach)
+	}
+	if update.CruiseKTAS != nil {
+		aircraft.CruiseKTAS = *update.CruiseKTAS
+	}
+	if update.FuelBurnGPH != nil {
+		aircraft.FuelBurnGPH = *update.FuelBurnGPH
+	}
+	if update.UsableFuelGal != nil {
+		aircraft.UsableFuelGal = *update.UsableFuelGal
+	}
+	if aircraft.CruiseKTAS < 0 || aircraft.FuelBurnGPH < 0 || aircraft.UsableFuelGal < 0 {
+		return errors.New("cruise speed, fuel burn and usable fuel cannot be negative")
+	}
+	if update.ELTBatteryDue != nil {
+		due := dateOnlyUTC(*update.ELTBatteryDue)
+		aircraft.ELTBatteryDue = &due
+	}
+
+	for _, field := range []struct {
+		name   string
+		value  *time.Time
+		target **time.Time
+	}{
+		{"annual", update.Annual, &aircraft.Annual},
+		{"transponder", update.Transponder, &aircraft.Transponder},
+		{"ELT inspection", update.ELTInspection, &aircraft.ELTInspection},
+		{"pitot-static", update.PitotStatic, &aircraft.PitotStatic},
+		{"VOR check", update.VORCheck, &aircraft.VORCheck},
+	} {
+		if err := inspected(field.name, field.value, field.target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteAircraft removes an aircraft and its ADs.
+func DeleteAircraft(database *gorm.DB, tail string, now time.Time) error {
+	tail, err := NormalizeTailNumber(tail)
+	if err != nil {
+		return err
+	}
+	return database.Transaction(func(tx *gorm.DB) error {
+		aircraft, err := findAircraft(tx, tail)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("aircraft_id = ?", aircraft.ID).Delete(&model.AircraftAD{}).Error; err != nil {
+			return fmt.Errorf("delete ADs for %s: %w", tail, err)
+		}
+		if err := tx.Delete(&aircraft).Error; err != nil {
+			return fmt.Errorf("delete aircraft %s: %w", tail, err)
+		}
+		return SyncAircraftChecklist(tx, now)
+	})
+}
+
+// SaveAircraftAD records or updates a recurring AD on an aircraft.
+func SaveAircraftAD(database *gorm.DB, tail string, ad model.AircraftAD, now time.Time) (model.AircraftAD, error) {
+	tail, err := NormalizeTailNumber(tail)
+	if err != nil {
+		return model.AircraftAD{}, err
+	}
+	ad.Number = strings.TrimSpace(ad.Number)
+	if ad.Number == "" {
+		return model.AircraftAD{}, errors.New("AD number is required (e.g. 2011-10-09)")
+	}
+	if ad.NextDueTach < 0 {
+		return model.AircraftAD{}, errors.New("AD due tach cannot be negative")
+	}
+	if ad.NextDueDate != nil {
+		due := dateOnlyUTC(*ad.NextDueDate)
+		ad.NextDueDate = &due
+	}
+
+	err = database.Transaction(func(tx *gorm.DB) error {
+		aircraft, err := findAircraft(tx, tail)
+		if err != nil {
+			return err
+		}
+		var existing model.AircraftAD
+		if err := tx.Where("aircraft_id = ? AND number = ?", aircraft.ID, ad.Number).Limit(1).Find(&existing).Error; err != nil {
+			return fmt.Errorf("load AD %s: %w", ad.Number, err)
+		}
+		ad.ID = existing.ID
+		ad.CreatedAt = existing.CreatedAt
+		ad.AircraftID = aircraft.ID
+		if err := tx.Save(&ad).Error; err != nil {
+			return fmt.Errorf("save AD %s: %w", ad.Number, err)
+		}
+		return SyncAircraftChecklist(tx, now)
+	})
+	return ad, err
+}
+
+// DeleteAircraftAD removes an AD from an aircraft.
+func DeleteAircraftAD(database *gorm.DB, tail string, number string, now time.Time) error {
+	tail, err := NormalizeTailNumber(tail)
+	if err != nil {
+		return err
+	}
+	return database.Transaction(func(tx *gorm.DB) error {
+		aircraft, err := findAircraft(tx, tail)
+		if err != nil {
+			return err
+		}
+		result := tx.Where("aircraft_id = ? AND number = ?", aircraft.ID, strings.TrimSpace(number)).Delete(&model.AircraftAD{})
+		if result.Error != nil {
+			return fmt.Errorf("delete AD %s: %w", number, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("AD %s not recorded for %s", number, tail)
+		}
+		return SyncAircraftChecklist(tx, now)
+	})
+}
+
+func findAircraft(database *gorm.DB, tail string) (model.Aircraft, error) {
+	var aircraft model.Aircraft
+	err := database.Where("tail_number = ?", tail).First(&aircraft).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.Aircraft{}, fmt.Errorf("aircraft %s is not registered", tail)
+	}
+	if err != nil {
+		return model.Aircraft{}, fmt.Errorf("load aircraft %s: %w", tail, err)
+	}
+	return aircraft, nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func aircraftDate(value string) *time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return &parsed
+}
+
+func TestCheckAirworthiness(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	checkride := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	aircraft := model.Aircraft{
+		TailNumber:      "N12345",
+		ForHire:         true,
+		Annual:          aircraftDate("2025-06-10"),
+		HundredHourTach: 1200,
+		CurrentTach:     1295,
+		Transponder:     aircraftDate("2024-05-01"),
+		ELTBatteryDue:   aircraftDate("2027-01-31"),
+		Directives: []model.AircraftAD{
+			{Number: "2011-10-09", NextDueDate: aircraftDate("2026-08-01")},
+			{Number: "2020-03-16", NextDueTach: 1290},
+		},
+	}
+
+	want := map[string]string{
+		"Annual inspection":      AirworthinessDue,     // through Jun 30, checkride Jul 10
+		"100-hour inspection":    AirworthinessDue,     // 5 tach hours left
+		"Transponder inspection": AirworthinessOverdue, // through May 31
+		"ELT inspection":         AirworthinessMissing,
+		"ELT battery":            AirworthinessCurrent,
+		"AD 2011-10-09":          AirworthinessCurrent,
+		"AD 2020-03-16":          AirworthinessOverdue,
+	}
+	checks := CheckAirworthiness(aircraft, checkride, now)
+	if len(checks) != len(want) {
+		t.Fatalf("expected %d checks (no IFR items), got %+v", len(want), checks)
+	}
+	for _, check := range checks {
+		if check.Status != want[check.Item] {
+			t.Fatalf("%s: expected %s, got %s (%s)", check.Item, want[check.Item], check.Status, check.Detail)
+		}
+	}
+	if annual := checks[0]; !annual.DueDate.Equal(time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected annual to run through the end of the 12th calendar month, got %s", annual.DueDate)
+	}
+
+	aircraft.IFR = true
+	aircraft.ForHire = false
+	checks = CheckAirworthiness(aircraft, time.Time{}, now)
+	items := map[string]bool{}
+	for _, check := range checks {
+		items[check.Item] = true
+	}
+	if items["100-hour inspection"] || !items["Pitot-static and altimeter"] || !items["VOR check"] {
+		t.Fatalf("expected IFR checks without the 100-hour, got %+v", checks)
+	}
+}
+
+func TestSaveAircraft_DrivesChecklistAndWarnings(t *testing.T) {
+	db := setupChecklistTestDB(t)
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	if err := SeedChecklist(db); err != nil {
+		t.Fatalf("SeedChecklist returned error: %v", err)
+	}
+	db.Create(&model.StudyPlan{CheckrideDate: time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)})
+
+	annual := *aircraftDate("2025-06-10")
+	if _, err := SaveAircraft(db, "n12345", AircraftUpdate{Annual: &annual}, now); err != nil {
+		t.Fatalf("SaveAircraft returned error: %v", err)
+	}
+	if _, err := SaveAircraftAD(db, "N12345", model.AircraftAD{Number: "2011-10-09", NextDueDate: aircraftDate("2026-12-01")}, now); err != nil {
+		t.Fatalf("SaveAircraftAD returned error: %v", err)
+	}
+
+	completed := func(key string) bool {
+		var item model.ChecklistItem
+		db.Where("template_key = ?", key).First(&item)
+		return item.Completed
+	}
+	if completed("inspections") || !completed("airworthiness-directives") {
+		t.Fatal("expected failing inspections to leave the item unchecked and current ADs to check theirs")
+	}
+	warnings := DashboardWarnings(db, now)
+	if len(warnings) == 0 || !strings.Contains(warnings[0], "N12345 annual inspection due") {
+		t.Fatalf("expected annual warning first, got %v", warnings)
+	}
+
+	fresh := *aircraftDate("2026-05-20")
+	update := AircraftUpdate{Annual: &fresh, Transponder: &fresh, ELTInspection: &fresh, ELTBatteryDue: aircraftDate("2028-01-01")}
+	if _, err := SaveAircraft(db, "N12345", update, now); err != nil {
+		t.Fatalf("SaveAircraft returned error: %v", err)
+	}
+	if !completed("inspections") {
+		t.Fatalf("expected current inspections to check the item, warnings %v", DashboardWarnings(db, now))
+	}
+
+	future := now.AddDate(0, 0, 3)
+	if _, err := SaveAircraft(db, "N12345", AircraftUpdate{Annual: &future}, now); err == nil {
+		t.Fatal("expected a future annual date to be rejected")
+	}
+	if _, err := SaveAircraft(db, "N 123", AircraftUpdate{}, now); err == nil {
+		t.Fatal("expected an invalid tail number to be rejected")
+	}
+
+	if err := DeleteAircraft(db, "N12345", now); err != nil {
+		t.Fatalf("DeleteAircraft returned error: %v", err)
+	}
+	var ads int64
+	db.Model(&model.AircraftAD{}).Count(&ads)
+	if ads != 0 {
+		t.Fatalf("expected ADs to be removed with the aircraft, got %d", ads)
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Airport time zones must resolve on systems without a zoneinfo database.
+	_ "time/tzdata"
+
+	"gorm.io/gorm"
+)
+
+const (
+	earthRadiusNM = 3440.065
+
+	// crossCountryMinimumNM is the straight-line distance from the departure
+	// point a landing must exceed to count as cross-country time (61.1), and
+	// the length of the long leg of the solo cross-country (61.109(a)(5)(ii)).
+	crossCountryMinimumNM = 50
+	// soloCrossCountryTotalNM is the total distance of the long solo
+	// cross-country (61.109(a)(5)(ii)).
+	soloCrossCountryTotalNM = 150
+	// soloCrossCountryLandings is how many points it needs full-stop
+	// landings at.
+	soloCrossCountryLandings = 3
+)
+
+// airports.csv is a subset of OurAirports (ourairports.com, public domain)
+// with tower and time zone columns added.
+//
+//go:embed airports.csv
+var airportsCSV []byte
+
+var (
+	embeddedAirportsOnce sync.Once
+	embeddedAirports     AirportDatabase
+	embeddedAirportsErr  error
+)
+
+// Airport is one airport from the dataset. Runways are pairs of opposite
+// ends, such as "09/27" or "10L/28R".
+type Airport struct {
+	ICAO        string
+	Name        string
+	Latitude    float64
+	Longitude   float64
+	ElevationFt int
+	Runways     []string
+	Towered     bool
+	TimeZone    string
+}
+
+// RunwayEnds lists every runway designator, in the form personal minimums
+// use for crosswind checks.
+func (a Airport) RunwayEnds() []string {
+	var ends []string
+	for _, runway := range a.Runways {
+		ends = append(ends, strings.Split(runway, "/")...)
+	}
+	return ends
+}
+
+// Location is the airport's time zone.
+func (a Airport) Location() (*time.Location, error) {
+	return time.LoadLocation(a.TimeZone)
+}
+
+// AirportDatabase maps ICAO codes to airports.
+type AirportDatabase map[string]Airport
+
+// Lookup finds an airport by ICAO code, or by a three-letter US identifier
+// ("FXE" for KFXE).
+func (d AirportDatabase) Lookup(code string) (Airport, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if airport, ok := d[code]; ok {
+		return airport, true
+	}
+	if len(code) == 3 {
+		airport, ok := d["K"+code]
+		return airport, ok
+	}
+	return Airport{}, false
+}
+
+// Search returns airports whose code or name contains text, by code.
+func (d AirportDatabase) Search(text string) []Airport {
+	text = strings.ToLower(strings.TrimSpace(text))
+	var matches []Airport
+	for _, airport := range d {
+		if strings.Contains(strings.ToLower(airport.ICAO), text) || strings.Contains(strings.ToLower(airport.Name), text) {
+			matches = append(matches, airport)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ICAO < matches[j].ICAO })
+	return matches
+}
+
+func airportsPath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("OPENPPL_AIRPORTS_PATH")); path != "" {
+		return path, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "airports.csv"), nil
+}
+
+// LoadAirports returns the embedded dataset merged with the user's own
+// airports from ~/.openppl/airports.csv (or OPENPPL_AIRPORTS_PATH), which
+// use the same columns and win over embedded rows. When the user file
+// cannot be read the embedded dataset is returned with the error.
+func LoadAirports() (AirportDatabase, error) {
+	embeddedAirportsOnce.Do(func() {
+		embeddedAirports, embeddedAirportsErr = parseAirportsCSV(bytes.NewReader(airportsCSV))
+	})
+	if embeddedAirportsErr != nil {
+		return nil, fmt.Errorf("airports: embedded dataset: %w", embeddedAirportsErr)
+	}
+
+	database := make(AirportDatabase, len(embeddedAirports))
+	for code, airport := range embeddedAirports {
+		database[code] = airport
+	}
+	path, err := airportsPath()
+	if err != nil {
+		return database, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return database, nil
+		}
+		return database, fmt.Errorf("airports: read %s: %w", path, err)
+	}
+	custom, err := parseAirportsCSV(bytes.NewReader(data))
+	if err != nil {
+		return database, fmt.Errorf("airports: %s: %w", path, err)
+	}
+	for code, airport := range custom {
+		database[code] = airport
+	}
+	return database, nil
+}
+
+// LookupAirport finds an airport in LoadAirports, ignoring a broken user
+// file.
+func LookupAirport(code string) (Airport, bool) {
+	database, _ := LoadAirports()
+	return database.Lookup(code)
+}
+
+func parseAirportsCSV(r io.Reader) (AirportDatabase, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 8
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if strings.ToLower(strings.TrimSpace(header[0])) != "icao" {
+		return nil, errors.New("expected the header icao,name,latitude_deg,longitude_deg,elevation_ft,runways,towered,time_zone")
+	}
+
+	database := AirportDatabase{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return database, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		airport, err := parseAirportRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		database[airport.ICAO] = airport
+	}
+}
+
+func parseAirportRecord(record []string) (Airport, error) {
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	airport := Airport{ICAO: strings.ToUpper(record[0]), Name: record[1], TimeZone: record[7]}
+	if len(airport.ICAO) < 3 || len(airport.ICAO) > 4 {
+		return Airport{}, fmt.Errorf("invalid ICAO code %q", record[0])
+	}
+	var err error
+	if airport.Latitude, err = strconv.ParseFloat(record[2], 64); err != nil || math.Abs(airport.Latitude) > 90 {
+		return Airport{}, fmt.Errorf("%s: invalid latitude %q", airport.ICAO, record[2])
+	}
+	if airport.Longitude, err = strconv.ParseFloat(record[3], 64); err != nil || math.Abs(airport.Longitude) > 180 {
+		return Airport{}, fmt.Errorf("%s: invalid longitude %q", airport.ICAO, record[3])
+	}
+	if airport.ElevationFt, err = strconv.Atoi(record[4]); err != nil {
+		return Airport{}, fmt.Errorf("%s: invalid elevation %q", airport.ICAO, record[4])
+	}
+	for _, runway := range strings.Split(record[5], ";") {
+		if runway = strings.ToUpper(strings.TrimSpace(runway)); runway == "" {
+			continue
+		}
+		for _, end := range strings.Split(runway, "/") {
+			if _, err := RunwayHeading(end); err != nil {
+				return Airport{}, fmt.Errorf("%s: %w", airport.ICAO, err)
+			}
+		}
+		airport.Runways = append(airport.Runways, runway)
+	}
+	switch strings.ToLower(record[6]) {
+	case "yes", "y", "true", "1":
+		airport.Towered = true
+	case "no", "n", "false", "0", "":
+	default:
+		return Airport{}, fmt.Errorf("%s: towered must be yes or no, got %q", airport.ICAO, record[6])
+	}
+	if _, err := airport.Location(); err != nil {
+		return Airport{}, fmt.Errorf("%s: unknown time zone %q", airport.ICAO, airport.TimeZone)
+	}
+	return airport, nil
+}
+
+// SchoolAirportLocation is the time zone of the school airport, which
+// exports schedule study tasks in. It falls back to UTC when no airport is
+// set or the airport is not in the dataset.
+func SchoolAirportLocation(database *gorm.DB) *time.Location {
+	code, err := SchoolAirport(database)
+	if err != nil || code == "" {
+		return time.UTC
+	}
+	airport, ok := LookupAirport(code)
+	if !ok {
+		return time.UTC
+	}
+	loc, err := airport.Location()
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// DistanceNM is the great-circle distance between two airports in nautical
+// miles.
+func DistanceNM(from, to Airport) float64 {
+	lat1, lat2 := from.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (to.Longitude - from.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusNM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// RouteLeg is one leg of a route.
+type RouteLeg struct {
+	From       Airport
+	To         Airport
+	DistanceNM float64
+}
+
+// Route is a flight through two or more airports, each one after the first
+// a full-stop landing.
+type Route struct {
+	Airports []Airport
+	Legs     []RouteLeg
+}
+
+// PlanRoute looks up each code and measures the legs between them.
+func PlanRoute(database AirportDatabase, codes []string) (Route, error) {
+	if len(codes) < 2 {
+		return Route{}, errors.New("a route needs at least two airports")
+	}
+	var route Route
+	var unknown []string
+	for _, code := range codes {
+		airport, ok := database.Lookup(code)
+		if !ok {
+			unknown = append(unknown, strings.ToUpper(strings.TrimSpace(code)))
+			continue
+		}
+		route.Airports = append(route.Airports, airport)
+	}
+	if len(unknown) > 0 {
+		return Route{}, fmt.Errorf("not in the airport database: %s (add them to ~/.openppl/airports.csv)", strings.Join(unknown, ", "))
+	}
+	for i := 1; i < len(route.Airports); i++ {
+		from, to := route.Airports[i-1], route.Airports[i]
+		route.Legs = append(route.Legs, RouteLeg{From: from, To: to, DistanceNM: DistanceNM(from, to)})
+	}
+	return route, nil
+}
+
+// TotalNM is the sum of the legs.
+func (r Route) TotalNM() float64 {
+	total := 0.0
+	for _, leg := range r.Legs {
+		total += leg.DistanceNM
+	}
+	return total
+}
+
+// FarthestLandingNM is the landing farthest in a straight line from the
+// departure airport, and its distance.
+func (r Route) FarthestLandingNM() (Airport, float64) {
+	var farthest Airport
+	best := 0.0
+	for _, airport := range r.Airports[1:] {
+		if distance := DistanceNM(r.Airports[0], airport); distance > best {
+			farthest, best = airport, distance
+		}
+	}
+	return farthest, best
+}
+
+// CountsAsCrossCountry reports whether the route includes a landing more
+// than 50 nm in a straight line from the departure airport, which makes it
+// cross-country time toward the private pilot certificate (61.1).
+func (r Route) CountsAsCrossCountry() bool {
+	_, distance := r.FarthestLandingNM()
+	return distance > crossCountryMinimumNM
+}
+
+// SoloCrossCountryProblems lists what keeps the route from meeting the long
+// solo cross-country of 61.109(a)(5)(ii): 150 nm total, full-stop landings
+// at three points, and one leg of more than 50 nm straight-line distance.
+// An empty result means it qualifies.
+func (r Route) SoloCrossCountryProblems() []string {
+	var problems []string
+	if total := r.TotalNM(); total < soloCrossCountryTotalNM {
+		problems = append(problems, fmt.Sprintf("total distance %.0f nm is under %d nm", total, soloCrossCountryTotalNM))
+	}
+	landings := map[string]bool{}
+	for _, airport := range r.Airports[1:] {
+		landings[airport.ICAO] = true
+	}
+	if len(landings) < soloCrossCountryLandings {
+		problems = append(problems, fmt.Sprintf("full-stop landings at %d points, need %d", len(landings), soloCrossCountryLandings))
+	}
+	longest := 0.0
+	for _, leg := range r.Legs {
+		longest = math.Max(longest, leg.DistanceNM)
+	}
+	if longest <= crossCountryMinimumNM {
+		problems = append(problems, fmt.Sprintf("longest leg %.0f nm is not over %d nm", longest, crossCountryMinimumNM))
+	}
+	return problems
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func loadTestAirports(t *testing.T) AirportDatabase {
+	t.Helper()
+	t.Setenv("OPENPPL_AIRPORTS_PATH", filepath.Join(t.TempDir(), "airports.csv"))
+	airports, err := LoadAirports()
+	if err != nil {
+		t.Fatalf("LoadAirports failed: %v", err)
+	}
+	return airports
+}
+
+func TestLoadAirports_EmbeddedDataset(t *testing.T) {
+	airports := loadTestAirports(t)
+
+	fxe, ok := airports.Lookup("fxe")
+	if !ok || fxe.ICAO != "KFXE" || !fxe.Towered || fxe.TimeZone != "America/New_York" {
+		t.Fatalf("expected KFXE from its three-letter code, got %+v, %v", fxe, ok)
+	}
+	if got := strings.Join(fxe.RunwayEnds(), ","); got != "09,27,13,31" {
+		t.Fatalf("unexpected runway ends %s", got)
+	}
+	if lna, _ := airports.Lookup("KLNA"); lna.Towered {
+		t.Fatal("expected KLNA to be non-towered")
+	}
+	if _, ok := airports.Lookup("KXXX"); ok {
+		t.Fatal("expected KXXX to be unknown")
+	}
+	if matches := airports.Search("fort lauderdale"); len(matches) != 2 || matches[0].ICAO != "KFLL" || matches[1].ICAO != "KFXE" {
+		t.Fatalf("unexpected search results %+v", matches)
+	}
+}
+
+func TestLoadAirports_UserFileOverridesAndExtends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "airports.csv")
+	t.Setenv("OPENPPL_AIRPORTS_PATH", path)
+	csv := "icao,name,latitude_deg,longitude_deg,elevation_ft,runways,towered,time_zone\n" +
+		"X51,Miami Homestead General Aviation Airport,25.4999,-80.5543,7,10/28,no,America/New_York\n" +
+		"KFXE,Fort Lauderdale Executive (tower closed),26.1973,-80.1707,13,09/27,no,America/New_York\n"
+	if err := os.WriteFile(path, []byte(csv), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	airports, err := LoadAirports()
+	if err != nil {
+		t.Fatalf("LoadAirports failed: %v", err)
+	}
+	if _, ok := airports.Lookup("X51"); !ok {
+		t.Fatal("expected the user's X51 to be added")
+	}
+	if fxe, _ := airports.Lookup("KFXE"); fxe.Towered || len(fxe.Runways) != 1 {
+		t.Fatalf("expected the user's KFXE row to win, got %+v", fxe)
+	}
+	if _, ok := airports.Lookup("KPBI"); !ok {
+		t.Fatal("expected embedded airports to remain")
+	}
+
+	if err := os.WriteFile(path, []byte(csv+"KBAD,Bad,26,-80,10,09/27,maybe,America/New_York\n"), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	airports, err = LoadAirports()
+	if err == nil || !strings.Contains(err.Error(), "line 4: KBAD: towered must be yes or no") {
+		t.Fatalf("expected a line-numbered error, got %v", err)
+	}
+	if _, ok := airports.Lookup("KFXE"); !ok {
+		t.Fatal("expected the embedded dataset despite a broken user file")
+	}
+}
+
+func TestDistanceNM(t *testing.T) {
+	airports := loadTestAirports(t)
+	sfo, _ := airports.Lookup("KSFO")
+	jfk, _ := airports.Lookup("KJFK")
+	if got := DistanceNM(sfo, jfk); math.Abs(got-2242) > 5 {
+		t.Fatalf("expected about 2242 nm from KSFO to KJFK, got %.1f", got)
+	}
+	if got := DistanceNM(jfk, jfk); got != 0 {
+		t.Fatalf("expected zero distance to itself, got %v", got)
+	}
+}
+
+func TestPlanRoute_ChecksCrossCountryRequirements(t *testing.T) {
+	airports := loadTestAirports(t)
+
+	short, err := PlanRoute(airports, []string{"KFXE", "KPBI", "KFXE"})
+	if err != nil {
+		t.Fatalf("PlanRoute failed: %v", err)
+	}
+	if short.CountsAsCrossCountry() {
+		t.Fatal("expected KFXE-KPBI (about 30 nm) not to count as cross-country")
+	}
+	if got := strings.Join(short.SoloCrossCountryProblems(), "; "); got != "total distance 59 nm is under 150 nm; full-stop landings at 2 points, need 3; longest leg 29 nm is not over 50 nm" {
+		t.Fatalf("unexpected problems %q", got)
+	}
+
+	long, err := PlanRoute(airports, []string{"KFXE", "KVRB", "KOBE", "KFXE"})
+	if err != nil {
+		t.Fatalf("PlanRoute failed: %v", err)
+	}
+	if farthest, distance := long.FarthestLandingNM(); farthest.ICAO != "KVRB" || distance < 85 {
+		t.Fatalf("expected KVRB to be the farthest landing, got %s at %.1f", farthest.ICAO, distance)
+	}
+	if !long.CountsAsCrossCountry() || len(long.SoloCrossCountryProblems()) != 0 {
+		t.Fatalf("expected the KVRB-KOBE triangle (%.0f nm) to qualify, got %v", long.TotalNM(), long.SoloCrossCountryProblems())
+	}
+
+	if _, err := PlanRoute(airports, []string{"KFXE", "KNOPE"}); err == nil || !strings.Contains(err.Error(), "KNOPE") {
+		t.Fatalf("expected an unknown airport error, got %v", err)
+	}
+}
+
+func TestSchoolAirportLocationAndRunways(t *testing.T) {
+	t.Setenv("OPENPPL_AIRPORTS_PATH", filepath.Join(t.TempDir(), "airports.csv"))
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	if loc := SchoolAirportLocation(db); loc.String() != "UTC" {
+		t.Fatalf("expected UTC without a school airport, got %s", loc)
+	}
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KPAO"})
+	if loc := SchoolAirportLocation(db); loc.String() != "America/Los_Angeles" {
+		t.Fatalf("expected the airport's time zone, got %s", loc)
+	}
+
+	if got := DefaultPersonalMinimums().ForAirport("KPAO").Runways; strings.Join(got, ",") != "13,31" {
+		t.Fatalf("expected KPAO runways, got %v", got)
+	}
+	configured := PersonalMinimums{Runways: []string{"09"}}
+	if got := configured.ForAirport("KPAO").Runways; len(got) != 1 || got[0] != "09" {
+		t.Fatalf("expected configured runways to win, got %v", got)
+	}
+}
@@ -16,7 +16,7 @@ import (
 
 type reminderExporter func(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error)
 
-var automationReminderExporter reminderExporter = ExportAppleReminders
+var automationReminderExporter reminderExporter = ExportReminders
 
 type AutomationActionService struct {
 	db       *gorm.DB
@@ -69,7 +69,7 @@ func (s *AutomationActionService) RunAutomationAction(req AutomationActionReques
 	if req.RequestID == "" {
 		return AutomationActionResponse{}, newAutomationValidationError("action.request_id_required", errors.New("request_id is required"))
 	}
-	if name != "remind" {
+	if name != "remind" && !isTaskAction(name) {
 		return AutomationActionResponse{}, newAutomationValidationError("action.not_allowlisted", fmt.Errorf("unsupported action %q", req.Name))
 	}
 
@@ -103,7 +103,12 @@ func (s *AutomationActionService) RunAutomationAction(req AutomationActionReques
 		return AutomationActionResponse{}, newAutomationRuntimeError("action.idempotency_lookup_failed", err)
 	}
 
-	response, err := s.executeRemind(req)
+	var response AutomationActionResponse
+	if name == "remind" {
+		response, err = s.executeRemind(req)
+	} else {
+		response, err = s.executeTaskAction(name, req)
+	}
 	if err != nil {
 		return AutomationActionResponse{}, err
 	}
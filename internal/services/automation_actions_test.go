@@ -77,6 +77,57 @@ func TestRunAutomationAction_NoPendingTask(t *testing.T) {
 	}
 }
 
+func TestRunAutomationAction_Tasks(t *testing.T) {
+	db := setupAutomationActionsTestDB(t)
+	service := NewAutomationActionService(db).WithClock(func() time.Time { return time.Date(2026, 2, 26, 12, 0, 0, 0, time.UTC) })
+	add := AutomationActionRequest{Name: "task.add", RequestID: "cfi-1", Args: map[string]string{
+		"date": "2026-03-04", "category": "CFI Flights", "title": "Stalls with CFI", "priority": "high", "minutes": "90",
+	}}
+	if _, err := service.RunAutomationAction(add); err == nil {
+		t.Fatal("expected task.add to fail without a study plan")
+	}
+	db.Create(&model.StudyPlan{CheckrideDate: time.Date(2026, 8, 10, 0, 0, 0, 0, time.UTC)})
+
+	add.RequestID = "cfi-2"
+	added, err := service.RunAutomationAction(add)
+	if err != nil {
+		t.Fatalf("task.add: %v", err)
+	}
+	if added.Action == nil || added.Action.TaskID == 0 || added.Action.TaskDate != "2026-03-04" {
+		t.Fatalf("unexpected task.add payload %+v", added.Action)
+	}
+	if replay, err := service.RunAutomationAction(add); err != nil || replay.ResultState != AutomationResultStateReplayed {
+		t.Fatalf("expected task.add replay, got %+v, %v", replay, err)
+	}
+	var count int64
+	db.Model(&model.DailyTask{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected one task after replay, got %d", count)
+	}
+
+	id := fmt.Sprint(added.Action.TaskID)
+	updated, err := service.RunAutomationAction(AutomationActionRequest{Name: "task.update", RequestID: "cfi-3", Args: map[string]string{"id": id, "date": "2026-03-06", "notes": "Bring the POH"}})
+	if err != nil {
+		t.Fatalf("task.update: %v", err)
+	}
+	var task model.DailyTask
+	db.First(&task, added.Action.TaskID)
+	if updated.Action.TaskDate != "2026-03-06" || task.Notes != "Bring the POH" || task.Title != "Stalls with CFI" || task.Priority != TaskPriorityHigh || task.RescheduledFrom == nil {
+		t.Fatalf("expected a moved task keeping its other fields, got %+v", task)
+	}
+	if _, err := service.RunAutomationAction(AutomationActionRequest{Name: "task.update", RequestID: "cfi-4", Args: map[string]string{"id": id, "colour": "red"}}); err == nil {
+		t.Fatal("expected an unknown arg to be rejected")
+	}
+
+	if _, err := service.RunAutomationAction(AutomationActionRequest{Name: "task.delete", RequestID: "cfi-5", Args: map[string]string{"id": id}}); err != nil {
+		t.Fatalf("task.delete: %v", err)
+	}
+	db.Model(&model.DailyTask{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected the task to be deleted, got %d", count)
+	}
+}
+
 func setupAutomationActionsTestDB(t *testing.T) *gorm.DB {
 	t.Helper()
 	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
@@ -84,7 +135,7 @@ func setupAutomationActionsTestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("open sqlite: %v", err)
 	}
-	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.AutomationIdempotency{}); err != nil {
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.AutomationIdempotency{}, &model.OutboxEvent{}); err != nil {
 		t.Fatalf("automigrate: %v", err)
 	}
 	return db
@@ -3,6 +3,7 @@ package services
 import (
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"ppl-study-planner/internal/model"
 )
 
+// automationMOTDAttempts reads the quiz answers for the readiness score.
+var automationMOTDAttempts = ExistingMOTDAttempts
+
 func BuildAutomationStatus(database *gorm.DB, now time.Time) (AutomationStatusResponse, error) {
 	if database == nil {
 		return AutomationStatusResponse{}, newAutomationValidationError("status.db_required", errors.New("database is required"))
@@ -59,6 +63,26 @@ func BuildAutomationStatus(database *gorm.DB, now time.Time) (AutomationStatusRe
 		payload.CheckrideDate = plan.CheckrideDate.UTC().Format("2006-01-02")
 	}
 
+	milestones, err := LoadMilestoneStatuses(database, now)
+	if err != nil {
+		return AutomationStatusResponse{}, newAutomationRuntimeError("status.milestones_query_failed", err)
+	}
+	payload.Milestones = make([]AutomationStatusMilestone, 0, len(milestones))
+	for _, milestone := range milestones {
+		payload.Milestones = append(payload.Milestones, newAutomationStatusMilestone(milestone))
+	}
+	if next, ok := NextMilestone(milestones); ok {
+		entry := newAutomationStatusMilestone(next)
+		payload.NextMilestone = &entry
+	}
+
+	payload.Readiness = newAutomationStatusReadiness(LoadReadiness(database, automationMOTDAttempts(), now))
+
+	payload.Warnings = DashboardWarnings(database, now)
+	if payload.Warnings == nil {
+		payload.Warnings = []string{}
+	}
+
 	return AutomationStatusResponse{
 		Version:     AutomationVersionV1,
 		ResultState: AutomationResultStateOK,
@@ -66,3 +90,45 @@ func BuildAutomationStatus(database *gorm.DB, now time.Time) (AutomationStatusRe
 		Status:      payload,
 	}, nil
 }
+
+func newAutomationStatusReadiness(readiness Readiness) AutomationStatusReadiness {
+	entry := AutomationStatusReadiness{
+		Score:       readiness.Score,
+		Label:       readiness.Label,
+		Factors:     make([]AutomationStatusReadinessFactor, 0, len(readiness.Factors)),
+		NextActions: readiness.NextActions,
+	}
+	for _, factor := range readiness.Factors {
+		entry.Factors = append(entry.Factors, AutomationStatusReadinessFactor{
+			Key:     factor.Key,
+			Name:    factor.Name,
+			Score:   math.Round(factor.Score*10) / 10,
+			Weight:  factor.Weight,
+			Detail:  factor.Detail,
+			Actions: factor.Actions,
+		})
+	}
+	if entry.NextActions == nil {
+		entry.NextActions = []string{}
+	}
+	return entry
+}
+
+func newAutomationStatusMilestone(status MilestoneStatus) AutomationStatusMilestone {
+	entry := AutomationStatusMilestone{
+		ID:                status.ID,
+		Name:              status.Name,
+		State:             status.State,
+		Overdue:           status.Overdue,
+		MissingTasks:      status.MissingTasks,
+		MissingChecklist:  status.MissingChecklist,
+		MissingMilestones: status.MissingMilestones,
+	}
+	if !status.TargetDate.IsZero() {
+		entry.TargetDate = status.TargetDate.UTC().Format("2006-01-02")
+	}
+	if status.AchievedAt != nil {
+		entry.AchievedAt = status.AchievedAt.UTC().Format(time.RFC3339)
+	}
+	return entry
+}
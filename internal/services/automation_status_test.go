@@ -59,6 +59,9 @@ func TestAutomationTypesStatusEnvelope(t *testing.T) {
 
 func TestBuildAutomationStatus(t *testing.T) {
 	db := setupAutomationStatusTestDB(t)
+	automationMOTDAttempts = func() []MOTDAnswer { return nil }
+	t.Cleanup(func() { automationMOTDAttempts = ExistingMOTDAttempts })
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", t.TempDir()+"/plan_template.json")
 
 	plan := model.StudyPlan{CheckrideDate: time.Date(2026, 8, 10, 0, 0, 0, 0, time.UTC)}
 	if err := db.Create(&plan).Error; err != nil {
@@ -98,6 +101,22 @@ func TestBuildAutomationStatus(t *testing.T) {
 	if statusA.Status.NextTasks[0] != statusB.Status.NextTasks[0] || statusA.Status.NextTasks[1] != statusB.Status.NextTasks[1] {
 		t.Fatal("expected deterministic ordering across repeated calls")
 	}
+
+	if len(statusA.Status.Milestones) != len(DefaultPlanTemplate().Milestones) {
+		t.Fatalf("expected every template milestone in status, got %d", len(statusA.Status.Milestones))
+	}
+	next := statusA.Status.NextMilestone
+	if next == nil || next.ID != "pre-solo-knowledge-test" || next.State != MilestoneStateBlocked || next.TargetDate != "2026-06-01" {
+		t.Fatalf("unexpected next milestone: %+v", next)
+	}
+
+	readiness := statusA.Status.Readiness
+	if len(readiness.Factors) != 5 || readiness.Factors[0].Key != ReadinessFactorStudy || readiness.Factors[0].Score != 50 || readiness.Score != 15 {
+		t.Fatalf("unexpected readiness: %+v", readiness)
+	}
+	if len(readiness.NextActions) == 0 {
+		t.Fatal("expected readiness next actions")
+	}
 }
 
 func setupAutomationStatusTestDB(t *testing.T) *gorm.DB {
@@ -107,7 +126,7 @@ func setupAutomationStatusTestDB(t *testing.T) *gorm.DB {
 	if err != nil {
 		t.Fatalf("open sqlite: %v", err)
 	}
-	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}); err != nil {
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.ChecklistItem{}, &model.Milestone{}); err != nil {
 		t.Fatalf("automigrate: %v", err)
 	}
 	return db
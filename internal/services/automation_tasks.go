@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+// Task automation actions. task.add takes date, category and title args
+// plus optional priority, minutes, notes and description; task.update takes
+// id and any of those; task.delete takes id.
+const (
+	AutomationActionTaskAdd    = "task.add"
+	AutomationActionTaskUpdate = "task.update"
+	AutomationActionTaskDelete = "task.delete"
+)
+
+func isTaskAction(name string) bool {
+	switch name {
+	case AutomationActionTaskAdd, AutomationActionTaskUpdate, AutomationActionTaskDelete:
+		return true
+	}
+	return false
+}
+
+func (s *AutomationActionService) executeTaskAction(name string, req AutomationActionRequest) (AutomationActionResponse, error) {
+	var task model.DailyTask
+	var err error
+	switch name {
+	case AutomationActionTaskAdd:
+		var input TaskInput
+		if input, err = taskInputFromArgs(TaskInput{}, req.Args); err == nil {
+			task, err = CreateTask(s.db, input, "automation")
+		}
+	case AutomationActionTaskUpdate:
+		var id uint
+		if id, err = taskIDArg(req.Args); err != nil {
+			break
+		}
+		if err = s.db.First(&task, id).Error; err != nil {
+			err = fmt.Errorf("task %d not found", id)
+			break
+		}
+		var input TaskInput
+		if input, err = taskInputFromArgs(TaskInputFrom(task), req.Args); err == nil {
+			task, err = UpdateTask(s.db, id, input, "automation")
+		}
+	case AutomationActionTaskDelete:
+		var id uint
+		if id, err = taskIDArg(req.Args); err == nil {
+			task, err = DeleteTask(s.db, id, "automation")
+		}
+	}
+	if err != nil {
+		return AutomationActionResponse{}, newAutomationValidationError("action.task_failed", err)
+	}
+
+	return AutomationActionResponse{
+		Version:     AutomationVersionV1,
+		ResultState: AutomationResultStateExecuted,
+		Timestamp:   utcTimestamp(s.now()),
+		Action: &AutomationActionPayload{
+			ActionName: name,
+			RequestID:  req.RequestID,
+			ActorScope: req.ActorScope,
+			TaskID:     task.ID,
+			TaskTitle:  task.Title,
+			TaskDate:   task.Date.UTC().Format("2006-01-02"),
+		},
+	}, nil
+}
+
+func taskIDArg(args map[string]string) (uint, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(args["id"]), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("id arg must be a task id")
+	}
+	return uint(id), nil
+}
+
+// taskInputFromArgs overrides the fields of input named in args.
+func taskInputFromArgs(input TaskInput, args map[string]string) (TaskInput, error) {
+	for key, value := range args {
+		switch key {
+		case "id":
+		case "date":
+			date, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+			if err != nil {
+				return input, fmt.Errorf("invalid date %q, want YYYY-MM-DD", value)
+			}
+			input.Date = date
+		case "category":
+			input.Category = value
+		case "title":
+			input.Title = value
+		case "description":
+			input.Description = value
+		case "notes":
+			input.Notes = value
+		case "priority":
+			input.Priority = value
+		case "minutes":
+			minutes, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return input, fmt.Errorf("invalid minutes %q", value)
+			}
+			input.DurationMinutes = minutes
+		default:
+			return input, fmt.Errorf("unknown task arg %q", key)
+		}
+	}
+	return input, nil
+}
@@ -32,10 +32,42 @@ type AutomationStatusTask struct {
 	Title    string `json:"title"`
 }
 
+type AutomationStatusMilestone struct {
+	ID                string   `json:"id"`
+	Name              string   `json:"name"`
+	State             string   `json:"state"`
+	TargetDate        string   `json:"target_date,omitempty"`
+	AchievedAt        string   `json:"achieved_at,omitempty"`
+	Overdue           bool     `json:"overdue"`
+	MissingTasks      []string `json:"missing_tasks,omitempty"`
+	MissingChecklist  []string `json:"missing_checklist,omitempty"`
+	MissingMilestones []string `json:"missing_milestones,omitempty"`
+}
+
+type AutomationStatusReadinessFactor struct {
+	Key     string   `json:"key"`
+	Name    string   `json:"name"`
+	Score   float64  `json:"score"`
+	Weight  float64  `json:"weight"`
+	Detail  string   `json:"detail"`
+	Actions []string `json:"actions,omitempty"`
+}
+
+type AutomationStatusReadiness struct {
+	Score       float64                           `json:"score"`
+	Label       string                            `json:"label"`
+	Factors     []AutomationStatusReadinessFactor `json:"factors"`
+	NextActions []string                          `json:"next_actions"`
+}
+
 type AutomationStatusPayload struct {
-	CheckrideDate string                  `json:"checkride_date,omitempty"`
-	Summary       AutomationStatusSummary `json:"summary"`
-	NextTasks     []AutomationStatusTask  `json:"next_tasks"`
+	CheckrideDate string                      `json:"checkride_date,omitempty"`
+	Summary       AutomationStatusSummary     `json:"summary"`
+	NextTasks     []AutomationStatusTask      `json:"next_tasks"`
+	NextMilestone *AutomationStatusMilestone  `json:"next_milestone,omitempty"`
+	Milestones    []AutomationStatusMilestone `json:"milestones"`
+	Readiness     AutomationStatusReadiness   `json:"readiness"`
+	Warnings      []string                    `json:"warnings"`
 }
 
 type AutomationStatusResponse struct {
@@ -61,6 +93,9 @@ type AutomationActionPayload struct {
 	ReminderDueDate string `json:"reminder_due_date,omitempty"`
 	TargetList      string `json:"target_list,omitempty"`
 	CreatedCount    int    `json:"created_count"`
+	TaskID          uint   `json:"task_id,omitempty"`
+	TaskTitle       string `json:"task_title,omitempty"`
+	TaskDate        string `json:"task_date,omitempty"`
 }
 
 type AutomationActionResponse struct {
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// SaveBudgetItem sets a budget item and records a budget.changed event in
+// the same transaction. Saving the amount it already has does nothing.
+func SaveBudgetItem(database *gorm.DB, itemType model.BudgetItemType, amount float64, source string) error {
+	return database.Transaction(func(tx *gorm.DB) error {
+		var budget model.Budget
+		if err := tx.Where("item_type = ?", itemType).Order("id asc").Limit(1).Find(&budget).Error; err != nil {
+			return fmt.Errorf("load budget %s: %w", itemType, err)
+		}
+		if budget.ID != 0 && budget.Amount == amount {
+			return nil
+		}
+		budget.ItemType = itemType
+		budget.Amount = amount
+		if err := tx.Save(&budget).Error; err != nil {
+			return fmt.Errorf("save budget %s: %w", itemType, err)
+		}
+		return RecordEvent(tx, EventBudgetChanged, BudgetEventPayload{ItemType: string(itemType), Amount: amount, Source: source})
+	})
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"testing"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestSaveBudgetItemRecordsChangesOnly(t *testing.T) {
+	db := setupTasksTestDB(t)
+	if err := db.AutoMigrate(&model.Budget{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	for _, amount := range []float64{150, 150, 165} {
+		if err := SaveBudgetItem(db, model.BudgetPlaneRate, amount, "tui"); err != nil {
+			t.Fatalf("save %.0f: %v", amount, err)
+		}
+	}
+
+	var budgets []model.Budget
+	db.Find(&budgets)
+	if len(budgets) != 1 || budgets[0].Amount != 165 {
+		t.Fatalf("expected one plane rate row at 165, got %+v", budgets)
+	}
+	var events []model.OutboxEvent
+	db.Where("event_type = ?", EventBudgetChanged).Order("id asc").Find(&events)
+	if len(events) != 2 || events[1].PayloadJSON != `{"item_type":"plane_rate","amount":165,"source":"tui"}` {
+		t.Fatalf("expected two budget.changed events, got %+v", events)
+	}
+}
@@ -0,0 +1,489 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	CalDAVComponentEvent = "VEVENT"
+	CalDAVComponentTodo  = "VTODO"
+
+	CalDAVErrorValidation = "validation"
+	CalDAVErrorConfig     = "config"
+	CalDAVErrorAuth       = "auth"
+	CalDAVErrorList       = "list"
+
+	defaultCalDAVTimeout = 30 * time.Second
+	caldavResourceSuffix = "@openppl.ics"
+)
+
+// CalDAVConfig points at a single calendar collection on a CalDAV server
+// (Fastmail, Nextcloud, iCloud, Radicale, ...). Use either Username and
+// Password for basic auth or BearerToken, not both.
+type CalDAVConfig struct {
+	URL         string `json:"url"`
+	Username    string `json:"username,omitempty"`
+	Password    string `json:"password,omitempty"`
+	BearerToken string `json:"bearer_token,omitempty"`
+	Component   string `json:"component,omitempty"`
+}
+
+type CalDAVSyncOptions struct {
+	Config     CalDAVConfig
+	HTTPClient *http.Client
+
+	// KeepRemoved leaves resources for tasks that no longer exist in place
+	// instead of deleting them.
+	KeepRemoved bool
+
+	// Location is the time zone tasks are scheduled in; nil means UTC.
+	Location *time.Location
+}
+
+type CalDAVTaskFailure struct {
+	TaskID     uint
+	TaskTitle  string
+	StatusCode int
+	Message    string
+}
+
+type CalDAVSyncResult struct {
+	CollectionURL string
+	Component     string
+	Attempted     int
+	Created       int
+	Updated       int
+	Deleted       int
+	Unchanged     int
+	Failed        int
+	Failures      []CalDAVTaskFailure
+}
+
+type CalDAVError struct {
+	Kind string
+	Err  error
+}
+
+func (e *CalDAVError) Error() string {
+	if e == nil {
+		return "caldav sync failed"
+	}
+	if e.Err == nil {
+		return fmt.Sprintf("caldav sync failed: %s", e.Kind)
+	}
+	return fmt.Sprintf("caldav sync failed: %s: %v", e.Kind, e.Err)
+}
+
+func (e *CalDAVError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+var loadCalDAVConfig = LoadCalDAVConfig
+
+// LoadCalDAVConfig reads ~/.openppl/caldav.json (or OPENPPL_CALDAV_PATH).
+// OPENPPL_CALDAV_PASSWORD and OPENPPL_CALDAV_TOKEN override the stored
+// secrets so they can be kept out of the file.
+func LoadCalDAVConfig() (CalDAVConfig, error) {
+	path, err := caldavConfigPath()
+	if err != nil {
+		return CalDAVConfig{}, err
+	}
+	return loadCalDAVConfigFromPath(path)
+}
+
+func caldavConfigPath() (string, error) {
+	if override := strings.TrimSpace(os.Getenv("OPENPPL_CALDAV_PATH")); override != "" {
+		return override, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "caldav.json"), nil
+}
+
+func loadCalDAVConfigFromPath(path string) (CalDAVConfig, error) {
+	var cfg CalDAVConfig
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return cfg, fmt.Errorf("caldav: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return CalDAVConfig{}, fmt.Errorf("caldav: parse config: %w", err)
+		}
+	}
+	if password := os.Getenv("OPENPPL_CALDAV_PASSWORD"); password != "" {
+		cfg.Password = password
+	}
+	if token := os.Getenv("OPENPPL_CALDAV_TOKEN"); token != "" {
+		cfg.BearerToken = token
+	}
+	return cfg, nil
+}
+
+// SyncTasksToCalDAV writes each task as its own calendar resource named after
+// its deterministic UID. Existing resources are overwritten with If-Match on
+// their ETag unless the server already has the same content, new ones are
+// created with If-None-Match, and openppl resources whose task is gone are
+// deleted.
+func SyncTasksToCalDAV(ctx context.Context, tasks []model.DailyTask, opts CalDAVSyncOptions) (CalDAVSyncResult, error) {
+	if strings.TrimSpace(opts.Config.URL) == "" {
+		cfg, err := loadCalDAVConfig()
+		if err != nil {
+			return CalDAVSyncResult{}, &CalDAVError{Kind: CalDAVErrorConfig, Err: err}
+		}
+		opts.Config = cfg
+	}
+
+	client, err := newCalDAVClient(opts)
+	if err != nil {
+		return CalDAVSyncResult{}, err
+	}
+	if len(tasks) == 0 {
+		return CalDAVSyncResult{}, &CalDAVError{Kind: CalDAVErrorValidation, Err: errors.New("no tasks available for CalDAV sync")}
+	}
+
+	result := CalDAVSyncResult{
+		CollectionURL: client.collection.String(),
+		Component:     client.component,
+		Attempted:     len(tasks),
+	}
+
+	existing, err := client.listResources(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	stamp := time.Now().UTC()
+	desired := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		name := caldavResourceName(task)
+		desired[name] = true
+
+		current, found := existing[name]
+		target := client.resourceURL(name)
+		if found {
+			target = current.url
+		}
+
+		body := client.serialize(task, stamp)
+		if found && sameCalDAVContent(current.data, body) {
+			result.Unchanged++
+			continue
+		}
+
+		statusCode, err := client.put(ctx, target, body, current.etag, found)
+		if err != nil {
+			result.addFailure(task, statusCode, err)
+			continue
+		}
+		if found {
+			result.Updated++
+		} else {
+			result.Created++
+		}
+	}
+
+	if opts.KeepRemoved {
+		return result, nil
+	}
+	for name, resource := range existing {
+		if desired[name] || !isOpenPPLCalDAVResource(name) {
+			continue
+		}
+		if statusCode, err := client.delete(ctx, resource.url, resource.etag); err != nil {
+			result.addFailure(model.DailyTask{Title: strings.TrimSuffix(name, ".ics")}, statusCode, err)
+			continue
+		}
+		result.Deleted++
+	}
+
+	return result, nil
+}
+
+func (r *CalDAVSyncResult) addFailure(task model.DailyTask, statusCode int, err error) {
+	r.Failed++
+	r.Failures = append(r.Failures, CalDAVTaskFailure{
+		TaskID:     task.ID,
+		TaskTitle:  task.Title,
+		StatusCode: statusCode,
+		Message:    err.Error(),
+	})
+}
+
+func caldavResourceName(task model.DailyTask) string {
+	return deterministicTaskUID(task) + ".ics"
+}
+
+// isOpenPPLCalDAVResource reports whether a resource was written by openppl,
+// so stale cleanup never touches the user's own events.
+func isOpenPPLCalDAVResource(name string) bool {
+	return strings.HasPrefix(name, "task-") && strings.HasSuffix(name, caldavResourceSuffix)
+}
+
+type caldavClient struct {
+	http       *http.Client
+	collection *url.URL
+	component  string
+	username   string
+	password   string
+	token      string
+	location   *time.Location
+}
+
+type caldavResource struct {
+	url  string
+	etag string
+	// data is the calendar data listed with the ETag; servers that do not
+	// return it in a PROPFIND leave it empty and the resource is rewritten.
+	data string
+}
+
+func newCalDAVClient(opts CalDAVSyncOptions) (*caldavClient, error) {
+	cfg := opts.Config
+	rawURL := strings.TrimSpace(cfg.URL)
+	if rawURL == "" {
+		return nil, &CalDAVError{Kind: CalDAVErrorConfig, Err: errors.New("calendar url is not configured")}
+	}
+	if !strings.HasSuffix(rawURL, "/") {
+		rawURL += "/"
+	}
+	collection, err := url.Parse(rawURL)
+	if err != nil || (collection.Scheme != "http" && collection.Scheme != "https") || collection.Host == "" {
+		return nil, &CalDAVError{Kind: CalDAVErrorConfig, Err: fmt.Errorf("invalid calendar url %q", cfg.URL)}
+	}
+	if cfg.BearerToken != "" && (cfg.Username != "" || cfg.Password != "") {
+		return nil, &CalDAVError{Kind: CalDAVErrorConfig, Err: errors.New("use either username/password or bearer_token, not both")}
+	}
+
+	component := strings.ToUpper(strings.TrimSpace(cfg.Component))
+	switch component {
+	case "":
+		component = CalDAVComponentEvent
+	case CalDAVComponentEvent, CalDAVComponentTodo:
+	default:
+		return nil, &CalDAVError{Kind: CalDAVErrorConfig, Err: fmt.Errorf("unsupported component %q", cfg.Component)}
+	}
+
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: defaultCalDAVTimeout}
+	}
+
+	return &caldavClient{
+		http:       client,
+		collection: collection,
+		component:  component,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		token:      cfg.BearerToken,
+		location:   opts.Location,
+	}, nil
+}
+
+func (c *caldavClient) resourceURL(name string) string {
+	return c.collection.ResolveReference(&url.URL{Path: name}).String()
+}
+
+func (c *caldavClient) serialize(task model.DailyTask, stamp time.Time) []byte {
+	cal := ics.NewCalendar()
+	cal.SetProductId("-//openppl//study-plan//EN")
+	cal.SetVersion("2.0")
+	if c.component == CalDAVComponentTodo {
+		addTaskTodo(cal, task, stamp, c.location)
+	} else {
+		addTaskEvent(cal, task, stamp, c.location)
+	}
+	return []byte(cal.Serialize())
+}
+
+func (c *caldavClient) newRequest(ctx context.Context, method string, target string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+const caldavPropfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop><d:getetag/><c:calendar-data/></d:prop></d:propfind>`
+
+type caldavMultistatus struct {
+	Responses []caldavResponse `xml:"DAV: response"`
+}
+
+type caldavResponse struct {
+	Href      string           `xml:"DAV: href"`
+	Propstats []caldavPropstat `xml:"DAV: propstat"`
+}
+
+type caldavPropstat struct {
+	Status       string `xml:"DAV: status"`
+	ETag         string `xml:"DAV: prop>getetag"`
+	CalendarData string `xml:"urn:ietf:params:xml:ns:caldav prop>calendar-data"`
+}
+
+// listResources returns the collection's members keyed by resource name.
+func (c *caldavClient) listResources(ctx context.Context) (map[string]caldavResource, error) {
+	req, err := c.newRequest(ctx, "PROPFIND", c.collection.String(), []byte(caldavPropfindBody))
+	if err != nil {
+		return nil, &CalDAVError{Kind: CalDAVErrorList, Err: err}
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, &CalDAVError{Kind: CalDAVErrorList, Err: err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, &CalDAVError{Kind: CalDAVErrorAuth, Err: fmt.Errorf("server returned %s", resp.Status)}
+	case resp.StatusCode != http.StatusMultiStatus:
+		return nil, &CalDAVError{Kind: CalDAVErrorList, Err: fmt.Errorf("PROPFIND returned %s", resp.Status)}
+	}
+
+	var status caldavMultistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, &CalDAVError{Kind: CalDAVErrorList, Err: fmt.Errorf("decode PROPFIND response: %w", err)}
+	}
+
+	resources := make(map[string]caldavResource, len(status.Responses))
+	for _, response := range status.Responses {
+		href, err := url.Parse(strings.TrimSpace(response.Href))
+		if err != nil {
+			continue
+		}
+		resolved := c.collection.ResolveReference(href)
+		if strings.TrimSuffix(resolved.Path, "/") == strings.TrimSuffix(c.collection.Path, "/") {
+			continue
+		}
+		resource := caldavResource{url: resolved.String()}
+		for _, propstat := range response.Propstats {
+			if !strings.Contains(propstat.Status, " 200 ") {
+				continue
+			}
+			if propstat.ETag != "" {
+				resource.etag = propstat.ETag
+			}
+			if propstat.CalendarData != "" {
+				resource.data = propstat.CalendarData
+			}
+		}
+		resources[path.Base(resolved.Path)] = resource
+	}
+	return resources, nil
+}
+
+// sameCalDAVContent compares listed calendar data with a freshly rendered
+// body, ignoring DTSTAMP, which changes on every render, and line folding.
+func sameCalDAVContent(listed string, rendered []byte) bool {
+	if strings.TrimSpace(listed) == "" {
+		return false
+	}
+	return normalizeCalDAVContent(listed) == normalizeCalDAVContent(string(rendered))
+}
+
+func normalizeCalDAVContent(data string) string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "DTSTAMP") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *caldavClient) put(ctx context.Context, target string, body []byte, etag string, exists bool) (int, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, target, body)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	switch {
+	case exists && etag != "":
+		req.Header.Set("If-Match", etag)
+	case !exists:
+		req.Header.Set("If-None-Match", "*")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return resp.StatusCode, nil
+	case http.StatusPreconditionFailed:
+		return resp.StatusCode, errors.New("resource changed on the server since it was listed")
+	default:
+		return resp.StatusCode, fmt.Errorf("PUT returned %s", resp.Status)
+	}
+}
+
+func (c *caldavClient) delete(ctx context.Context, target string, etag string) (int, error) {
+	req, err := c.newRequest(ctx, http.MethodDelete, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	if etag != "" {
+		req.Header.Set("If-Match", etag)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound, http.StatusGone:
+		return resp.StatusCode, nil
+	case http.StatusPreconditionFailed:
+		return resp.StatusCode, errors.New("resource changed on the server since it was listed")
+	default:
+		return resp.StatusCode, fmt.Errorf("DELETE returned %s", resp.Status)
+	}
+}
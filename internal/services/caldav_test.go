@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestCalDAV_SyncCreatesUpdatesAndDeletesWithETags(t *testing.T) {
+	server := newFakeCalDAVServer(t, func(r *http.Request) bool {
+		user, pass, ok := r.BasicAuth()
+		return ok && user == "student" && pass == "secret"
+	})
+	server.seed("holiday.ics", "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+
+	cfg := CalDAVConfig{URL: server.collectionURL(), Username: "student", Password: "secret"}
+	tasks := []model.DailyTask{
+		{ID: 1, Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Title: "Area 1 Review", Category: "Theory"},
+		{ID: 2, Date: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), Title: "Pattern work", Category: "CFI Flights"},
+	}
+
+	first, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("first sync returned error: %v", err)
+	}
+	if first.Created != 2 || first.Updated != 0 || first.Failed != 0 {
+		t.Fatalf("unexpected first result: %+v", first)
+	}
+	if server.count() != 3 {
+		t.Fatalf("expected 2 task resources plus the user's own, got %d", server.count())
+	}
+	if body := server.body("task-1-20260501@openppl.ics"); !strings.Contains(body, "BEGIN:VEVENT") || !strings.Contains(body, "UID:task-1-20260501@openppl") {
+		t.Fatalf("unexpected resource body:\n%s", body)
+	}
+
+	tasks[0].Title = "Area 1 Review (updated)"
+	second, err := SyncTasksToCalDAV(context.Background(), tasks[:1], CalDAVSyncOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("second sync returned error: %v", err)
+	}
+	if second.Created != 0 || second.Updated != 1 || second.Deleted != 1 || second.Failed != 0 {
+		t.Fatalf("unexpected second result: %+v", second)
+	}
+	if !strings.Contains(server.body("task-1-20260501@openppl.ics"), "Area 1 Review (updated)") {
+		t.Fatal("expected updated summary on the server")
+	}
+	if server.has("task-2-20260502@openppl.ics") {
+		t.Fatal("expected stale task resource to be deleted")
+	}
+	if !server.has("holiday.ics") {
+		t.Fatal("sync must not delete resources it did not create")
+	}
+	if server.conditionalWrites() != 3 {
+		t.Fatalf("expected every write to carry If-Match or If-None-Match, got %d conditional of %d", server.conditionalWrites(), server.writes())
+	}
+}
+
+func TestCalDAV_SkipsResourcesTheServerAlreadyHas(t *testing.T) {
+	server := newFakeCalDAVServer(t, nil)
+	cfg := CalDAVConfig{URL: server.collectionURL()}
+	tasks := []model.DailyTask{
+		{ID: 1, Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Title: "Area 1 Review", Category: "Theory"},
+		{ID: 2, Date: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), Title: "Pattern work", Category: "CFI Flights"},
+	}
+	if _, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg}); err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+
+	tasks[1].Description = "Crosswind landings"
+	second, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if second.Unchanged != 1 || second.Updated != 1 || server.writes() != 3 {
+		t.Fatalf("expected only the changed task rewritten, got %+v after %d writes", second, server.writes())
+	}
+
+	// Without calendar data in the listing there is nothing to compare.
+	server.mu.Lock()
+	server.omitCalendarData = true
+	server.mu.Unlock()
+	third, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if third.Unchanged != 0 || third.Updated != 2 {
+		t.Fatalf("expected every resource rewritten without calendar data, got %+v", third)
+	}
+}
+
+func TestCalDAV_PutsTodosWithBearerAuth(t *testing.T) {
+	server := newFakeCalDAVServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer app-token"
+	})
+
+	tasks := []model.DailyTask{{ID: 7, Date: time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC), Title: "Chair fly steep turns", Completed: true}}
+	result, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{
+		Config: CalDAVConfig{URL: server.collectionURL(), BearerToken: "app-token", Component: "vtodo"},
+	})
+	if err != nil {
+		t.Fatalf("sync returned error: %v", err)
+	}
+	if result.Created != 1 || result.Component != CalDAVComponentTodo {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	body := server.body("task-7-20260603@openppl.ics")
+	for _, token := range []string{"BEGIN:VTODO", "DUE:20260603T093000Z", "STATUS:COMPLETED"} {
+		if !strings.Contains(body, token) {
+			t.Fatalf("expected VTODO resource to contain %q:\n%s", token, body)
+		}
+	}
+}
+
+func TestCalDAV_RejectedCredentialsReturnAuthError(t *testing.T) {
+	server := newFakeCalDAVServer(t, func(r *http.Request) bool { return false })
+
+	tasks := []model.DailyTask{{ID: 1, Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Title: "Task"}}
+	_, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{
+		Config: CalDAVConfig{URL: server.collectionURL(), Username: "student", Password: "wrong"},
+	})
+
+	var caldavErr *CalDAVError
+	if !errors.As(err, &caldavErr) || caldavErr.Kind != CalDAVErrorAuth {
+		t.Fatalf("expected auth error, got %v", err)
+	}
+}
+
+func TestCalDAV_ReportsConflictWhenResourceChangedOnServer(t *testing.T) {
+	server := newFakeCalDAVServer(t, nil)
+	cfg := CalDAVConfig{URL: server.collectionURL()}
+	tasks := []model.DailyTask{{ID: 3, Date: time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC), Title: "Garmin practice"}}
+
+	if _, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg}); err != nil {
+		t.Fatalf("first sync returned error: %v", err)
+	}
+	server.staleETagsOnList = true
+	tasks[0].Title = "Garmin practice: holds"
+
+	result, err := SyncTasksToCalDAV(context.Background(), tasks, CalDAVSyncOptions{Config: cfg})
+	if err != nil {
+		t.Fatalf("second sync returned error: %v", err)
+	}
+	if result.Failed != 1 || result.Failures[0].StatusCode != http.StatusPreconditionFailed {
+		t.Fatalf("expected a 412 failure, got %+v", result)
+	}
+}
+
+func TestCalDAV_ConfigValidation(t *testing.T) {
+	cases := []CalDAVConfig{
+		{},
+		{URL: "ftp://example.com/cal"},
+		{URL: "https://example.com/cal", Username: "u", BearerToken: "t"},
+		{URL: "https://example.com/cal", Component: "VJOURNAL"},
+	}
+	for _, cfg := range cases {
+		_, err := newCalDAVClient(CalDAVSyncOptions{Config: cfg})
+		var caldavErr *CalDAVError
+		if !errors.As(err, &caldavErr) || caldavErr.Kind != CalDAVErrorConfig {
+			t.Fatalf("expected config error for %+v, got %v", cfg, err)
+		}
+	}
+}
+
+func TestCalDAV_LoadConfigFromPathWithSecretOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "caldav.json")
+	if err := os.WriteFile(path, []byte(`{"url":"https://dav.example.com/cal/","username":"student","component":"VTODO"}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("OPENPPL_CALDAV_PASSWORD", "from-env")
+
+	cfg, err := loadCalDAVConfigFromPath(path)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.URL != "https://dav.example.com/cal/" || cfg.Username != "student" || cfg.Password != "from-env" || cfg.Component != "VTODO" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+// fakeCalDAVServer implements the slice of CalDAV the sync uses: PROPFIND
+// with Depth 1, conditional PUT, and conditional DELETE on one collection.
+type fakeCalDAVServer struct {
+	*httptest.Server
+
+	mu               sync.Mutex
+	resources        map[string]string
+	authorize        func(*http.Request) bool
+	staleETagsOnList bool
+	omitCalendarData bool
+	putCount         int
+	conditionalPuts  int
+}
+
+func newFakeCalDAVServer(t *testing.T, authorize func(*http.Request) bool) *fakeCalDAVServer {
+	t.Helper()
+	f := &fakeCalDAVServer{resources: map[string]string{}, authorize: authorize}
+	f.Server = httptest.NewServer(f)
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeCalDAVServer) collectionURL() string {
+	return f.URL + "/calendars/student/openppl"
+}
+
+func (f *fakeCalDAVServer) seed(name string, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.resources[name] = body
+}
+
+func (f *fakeCalDAVServer) body(name string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.resources[name]
+}
+
+func (f *fakeCalDAVServer) has(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.resources[name]
+	return ok
+}
+
+func (f *fakeCalDAVServer) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.resources)
+}
+
+func (f *fakeCalDAVServer) writes() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.putCount
+}
+
+func (f *fakeCalDAVServer) conditionalWrites() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.conditionalPuts
+}
+
+func fakeETag(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+func (f *fakeCalDAVServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.authorize != nil && !f.authorize(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	const collectionPath = "/calendars/student/openppl/"
+	if r.Method == "PROPFIND" {
+		if r.URL.Path != collectionPath || r.Header.Get("Depth") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		names := make([]string, 0, len(f.resources))
+		for name := range f.resources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0" encoding="utf-8"?><d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">`)
+		fmt.Fprintf(&b, `<d:response><d:href>%s</d:href><d:propstat><d:prop/><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, collectionPath)
+		for _, name := range names {
+			etag := fakeETag(f.resources[name])
+			if f.staleETagsOnList {
+				etag = `"stale"`
+			}
+			data := ""
+			if !f.omitCalendarData {
+				var escaped strings.Builder
+				_ = xml.EscapeText(&escaped, []byte(f.resources[name]))
+				data = "<c:calendar-data>" + escaped.String() + "</c:calendar-data>"
+			}
+			fmt.Fprintf(&b, `<d:response><d:href>%s%s</d:href><d:propstat><d:prop><d:getetag>%s</d:getetag>%s</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`, collectionPath, name, etag, data)
+		}
+		b.WriteString(`</d:multistatus>`)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, b.String())
+		return
+	}
+
+	dir, name := path.Split(r.URL.Path)
+	if dir != collectionPath || name == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	current, exists := f.resources[name]
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch != "" && (!exists || ifMatch != fakeETag(current)) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.putCount++
+		if ifMatch != "" || r.Header.Get("If-None-Match") != "" {
+			f.conditionalPuts++
+		}
+		if r.Header.Get("If-None-Match") == "*" && exists {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "text/calendar") {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.resources[name] = string(body)
+		w.Header().Set("ETag", fakeETag(string(body)))
+		if exists {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case http.MethodDelete:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.resources, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ics "github.com/arran4/golang-ical"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const defaultFeedReminder = 30 * time.Minute
+
+// CalendarFeedOptions controls the live ICS feed served in web mode.
+type CalendarFeedOptions struct {
+	Name     string
+	Reminder time.Duration
+	Now      time.Time
+	Location *time.Location
+}
+
+// CalendarFeedConfig holds the secret that authorizes feed subscriptions.
+type CalendarFeedConfig struct {
+	Token string `json:"token"`
+}
+
+// BuildCalendarFeed renders tasks as a subscribable calendar. UIDs match the
+// file export, SEQUENCE and LAST-MODIFIED track task edits so clients replace
+// events in place, and each event carries a display reminder.
+func BuildCalendarFeed(tasks []model.DailyTask, opts CalendarFeedOptions) string {
+	if opts.Reminder <= 0 {
+		opts.Reminder = defaultFeedReminder
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		name = "openppl study plan"
+	}
+
+	cal := ics.NewCalendar()
+	cal.SetMethod(ics.MethodPublish)
+	cal.SetProductId("-//openppl//study-plan//EN")
+	cal.SetVersion("2.0")
+	cal.SetName(name)
+	cal.SetXWRCalName(name)
+	cal.SetRefreshInterval("PT1H")
+	cal.SetXPublishedTTL("PT1H")
+
+	stamp := opts.Now.UTC()
+	trigger := fmt.Sprintf("-PT%dM", int(opts.Reminder.Minutes()))
+	for _, task := range tasks {
+		event := addTaskEvent(cal, task, stamp, opts.Location)
+		event.SetSequence(task.Sequence)
+		event.SetLastModifiedAt(taskLastModified(task))
+		event.SetProperty(ics.ComponentPropertyCategories, strings.TrimSpace(task.Category))
+		if task.Completed {
+			event.SetSummary("✓ " + taskICSSummary(task))
+			continue
+		}
+
+		alarm := event.AddAlarm()
+		alarm.SetAction(ics.ActionDisplay)
+		alarm.SetTrigger(trigger)
+		alarm.SetProperty(ics.ComponentPropertyDescription, taskICSSummary(task))
+	}
+	return cal.Serialize()
+}
+
+// LoadLatestPlanTasks returns the tasks of the latest study plan, the one
+// the TUI shows, by date. Tasks left over from older plans are left out.
+func LoadLatestPlanTasks(database *gorm.DB) ([]model.DailyTask, error) {
+	var plan model.StudyPlan
+	if err := database.Order("id desc").Limit(1).Find(&plan).Error; err != nil {
+		return nil, fmt.Errorf("load study plan: %w", err)
+	}
+	if plan.ID == 0 {
+		return nil, nil
+	}
+	var tasks []model.DailyTask
+	if err := database.Where("study_plan_id = ?", plan.ID).Order("date asc, id asc").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("load feed tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// FilterTasksByCategorySlug keeps tasks whose category matches slug, as
+// produced by CategorySlug ("CFI Flights" -> "cfi-flights").
+func FilterTasksByCategorySlug(tasks []model.DailyTask, slug string) []model.DailyTask {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	filtered := make([]model.DailyTask, 0, len(tasks))
+	for _, task := range tasks {
+		if CategorySlug(task.Category) == slug {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+// CategorySlug turns a task category into its URL form.
+func CategorySlug(category string) string {
+	return strings.Join(strings.Fields(strings.ToLower(category)), "-")
+}
+
+func taskLastModified(task model.DailyTask) time.Time {
+	if !task.UpdatedAt.IsZero() {
+		return task.UpdatedAt.UTC()
+	}
+	if !task.CreatedAt.IsZero() {
+		return task.CreatedAt.UTC()
+	}
+	return task.Date.UTC()
+}
+
+// LoadOrCreateCalendarFeedToken returns the feed secret from
+// OPENPPL_FEED_TOKEN or ~/.openppl/calendar_feed.json, generating and saving
+// one on first use.
+func LoadOrCreateCalendarFeedToken() (string, error) {
+	if token := strings.TrimSpace(os.Getenv("OPENPPL_FEED_TOKEN")); token != "" {
+		return token, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return loadOrCreateCalendarFeedTokenAtPath(filepath.Join(dir, "calendar_feed.json"))
+}
+
+func loadOrCreateCalendarFeedTokenAtPath(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("calendar feed: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		var cfg CalendarFeedConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return "", fmt.Errorf("calendar feed: parse config: %w", err)
+		}
+		if token := strings.TrimSpace(cfg.Token); token != "" {
+			return token, nil
+		}
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("calendar feed: generate token: %w", err)
+	}
+	cfg := CalendarFeedConfig{Token: hex.EncodeToString(raw)}
+	encoded, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("calendar feed: encode config: %w", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		return "", fmt.Errorf("calendar feed: write config: %w", err)
+	}
+	return cfg.Token, nil
+}
+
+// CalendarFeedTokenMatches compares a presented token in constant time.
+func CalendarFeedTokenMatches(expected string, presented string) bool {
+	if expected == "" || presented == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(presented)) == 1
+}
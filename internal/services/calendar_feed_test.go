@@ -0,0 +1,79 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestBuildCalendarFeed_IncludesSequenceLastModifiedAndAlarm(t *testing.T) {
+	tasks := []model.DailyTask{
+		{
+			ID:        11,
+			Date:      time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+			Category:  "Theory",
+			Title:     "Area 3 Weather",
+			Sequence:  2,
+			UpdatedAt: time.Date(2026, 6, 20, 8, 15, 0, 0, time.UTC),
+		},
+		{
+			ID:        12,
+			Date:      time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC),
+			Category:  "CFI Flights",
+			Title:     "Slow flight",
+			Completed: true,
+		},
+	}
+
+	feed := BuildCalendarFeed(tasks, CalendarFeedOptions{Name: "PPL", Now: time.Date(2026, 6, 21, 0, 0, 0, 0, time.UTC)})
+	for _, token := range []string{
+		"UID:task-11-20260701@openppl",
+		"SEQUENCE:2",
+		"LAST-MODIFIED:20260620T081500Z",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT30M",
+		"CATEGORIES:Theory",
+		"X-WR-CALNAME:PPL",
+		"SUMMARY:✓ Slow flight",
+	} {
+		if !strings.Contains(feed, token) {
+			t.Fatalf("expected feed to contain %q:\n%s", token, feed)
+		}
+	}
+	if strings.Count(feed, "BEGIN:VALARM") != 1 {
+		t.Fatalf("expected reminders only for open tasks:\n%s", feed)
+	}
+}
+
+func TestFilterTasksByCategorySlug(t *testing.T) {
+	tasks := []model.DailyTask{{ID: 1, Category: "CFI Flights"}, {ID: 2, Category: "Theory"}, {ID: 3, Category: "CFI Flights"}}
+	filtered := FilterTasksByCategorySlug(tasks, "cfi-flights")
+	if len(filtered) != 2 || filtered[0].ID != 1 || filtered[1].ID != 3 {
+		t.Fatalf("unexpected filtered tasks: %+v", filtered)
+	}
+}
+
+func TestCalendarFeedToken_GeneratedOnceAndReused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calendar_feed.json")
+	first, err := loadOrCreateCalendarFeedTokenAtPath(path)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	second, err := loadOrCreateCalendarFeedTokenAtPath(path)
+	if err != nil {
+		t.Fatalf("reload token: %v", err)
+	}
+	if len(first) != 48 || first != second {
+		t.Fatalf("expected stable 48-char token, got %q then %q", first, second)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600 token file, got %v (%v)", info.Mode().Perm(), err)
+	}
+	if CalendarFeedTokenMatches(first, "wrong") || !CalendarFeedTokenMatches(first, first) || CalendarFeedTokenMatches("", "") {
+		t.Fatal("unexpected token comparison result")
+	}
+}
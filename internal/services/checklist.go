@@ -0,0 +1,354 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+//go:embed checklist_template_default.json
+var defaultChecklistTemplateJSON []byte
+
+const checklistVersionConfigKey = "checklist_template_version"
+
+// ChecklistCategories lists checklist categories in display order.
+var ChecklistCategories = []model.ChecklistCategory{
+	model.CategoryDocuments,
+	model.CategoryAircraft,
+	model.CategoryGround,
+	model.CategoryFlight,
+}
+
+// ChecklistTemplate is the versioned default pre-checkride checklist. Bump
+// Version whenever items change so `openppl checklist upgrade` merges them.
+type ChecklistTemplate struct {
+	Version int                     `json:"version"`
+	Source  string                  `json:"source"`
+	Items   []ChecklistTemplateItem `json:"items"`
+}
+
+// ChecklistTemplateItem is one default checklist item. Replaces lists earlier
+// titles of the item, so an upgrade adopts them instead of adding a duplicate.
+type ChecklistTemplateItem struct {
+	Key      string                  `json:"key"`
+	Category model.ChecklistCategory `json:"category"`
+	Title    string                  `json:"title"`
+	Replaces []string                `json:"replaces,omitempty"`
+}
+
+// ChecklistUpgradeResult summarizes a checklist seed, upgrade or reset.
+type ChecklistUpgradeResult struct {
+	FromVersion int
+	ToVersion   int
+	Added       int
+	Updated     int
+	Retired     int
+}
+
+// DefaultChecklistTemplate returns the embedded default checklist.
+func DefaultChecklistTemplate() ChecklistTemplate {
+	var tpl ChecklistTemplate
+	if err := json.Unmarshal(defaultChecklistTemplateJSON, &tpl); err != nil {
+		panic(fmt.Sprintf("embedded checklist template: %v", err))
+	}
+	seen := map[string]bool{}
+	for _, item := range tpl.Items {
+		if item.Key == "" || item.Title == "" || seen[item.Key] || !validChecklistCategory(item.Category) {
+			panic(fmt.Sprintf("embedded checklist template: invalid item %+v", item))
+		}
+		seen[item.Key] = true
+	}
+	return tpl
+}
+
+// ParseChecklistCategory matches a category name, ignoring case.
+func ParseChecklistCategory(value string) (model.ChecklistCategory, error) {
+	for _, category := range ChecklistCategories {
+		if strings.EqualFold(strings.TrimSpace(value), string(category)) {
+			return category, nil
+		}
+	}
+	names := make([]string, 0, len(ChecklistCategories))
+	for _, category := range ChecklistCategories {
+		names = append(names, string(category))
+	}
+	return "", fmt.Errorf("unknown checklist category %q (want one of %s)", value, strings.Join(names, ", "))
+}
+
+func validChecklistCategory(category model.ChecklistCategory) bool {
+	_, err := ParseChecklistCategory(string(category))
+	return err == nil
+}
+
+// ChecklistVersion returns the default checklist version the database was
+// last seeded or upgraded to, or 0 if it never was.
+func ChecklistVersion(database *gorm.DB) (int, error) {
+	var cfg model.AppConfig
+	if err := database.Where("key = ?", checklistVersionConfigKey).Limit(1).Find(&cfg).Error; err != nil {
+		return 0, fmt.Errorf("load checklist version: %w", err)
+	}
+	if cfg.ID == 0 {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(cfg.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checklist version %q", cfg.Value)
+	}
+	return version, nil
+}
+
+func saveChecklistVersion(tx *gorm.DB, version int) error {
+	var cfg model.AppConfig
+	if err := tx.Where("key = ?", checklistVersionConfigKey).Limit(1).Find(&cfg).Error; err != nil {
+		return err
+	}
+	cfg.Key = checklistVersionConfigKey
+	cfg.Value = strconv.Itoa(version)
+	return tx.Save(&cfg).Error
+}
+
+// SeedChecklist brings the checklist up to the embedded default when the
+// database has not seen this template version yet. It is safe to call on
+// every start.
+func SeedChecklist(database *gorm.DB) error {
+	version, err := ChecklistVersion(database)
+	if err != nil {
+		return err
+	}
+	if version >= DefaultChecklistTemplate().Version {
+		return nil
+	}
+	_, err = UpgradeChecklist(database)
+	return err
+}
+
+// UpgradeChecklist merges the embedded default checklist into the database
+// without losing completion state. Items are matched by template key, then
+// by current or former title. Missing items are added, and items dropped
+// from the template become custom items so nothing checked off disappears.
+func UpgradeChecklist(database *gorm.DB) (ChecklistUpgradeResult, error) {
+	tpl := DefaultChecklistTemplate()
+	result := ChecklistUpgradeResult{ToVersion: tpl.Version}
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		from, err := ChecklistVersion(tx)
+		if err != nil {
+			return err
+		}
+		result.FromVersion = from
+
+		var items []model.ChecklistItem
+		if err := tx.Order("id asc").Find(&items).Error; err != nil {
+			return fmt.Errorf("load checklist: %w", err)
+		}
+		byKey := map[string]*model.ChecklistItem{}
+		byTitle := map[string]*model.ChecklistItem{}
+		for i := range items {
+			if items[i].TemplateKey != "" {
+				byKey[items[i].TemplateKey] = &items[i]
+			} else if _, ok := byTitle[checklistTitleKey(items[i].Title)]; !ok {
+				byTitle[checklistTitleKey(items[i].Title)] = &items[i]
+			}
+		}
+
+		inTemplate := map[string]bool{}
+		for position, entry := range tpl.Items {
+			inTemplate[entry.Key] = true
+			item := byKey[entry.Key]
+			for _, title := range append([]string{entry.Title}, entry.Replaces...) {
+				if item != nil {
+					break
+				}
+				if match := byTitle[checklistTitleKey(title)]; match != nil && match.TemplateKey == "" {
+					item = match
+				}
+			}
+
+			if item == nil {
+				created := model.ChecklistItem{Category: entry.Category, Title: entry.Title, TemplateKey: entry.Key, Position: position + 1}
+				if err := tx.Create(&created).Error; err != nil {
+					return fmt.Errorf("add checklist item %q: %w", entry.Title, err)
+				}
+				result.Added++
+				continue
+			}
+			if item.TemplateKey == entry.Key && item.Title == entry.Title && item.Category == entry.Category && item.Position == position+1 {
+				continue
+			}
+			item.TemplateKey = entry.Key
+			item.Title = entry.Title
+			item.Category = entry.Category
+			item.Position = position + 1
+			if err := tx.Save(item).Error; err != nil {
+				return fmt.Errorf("update checklist item %q: %w", entry.Title, err)
+			}
+			result.Updated++
+		}
+
+		for key, item := range byKey {
+			if inTemplate[key] {
+				continue
+			}
+			if err := tx.Model(item).Updates(map[string]interface{}{"template_key": "", "position": 0}).Error; err != nil {
+				return fmt.Errorf("retire checklist item %q: %w", item.Title, err)
+			}
+			result.Retired++
+		}
+
+		return saveChecklistVersion(tx, tpl.Version)
+	})
+	return result, err
+}
+
+// ResetChecklist replaces the whole checklist, custom items included, with a
+// fresh copy of the default. Items driven by recorded endorsements and
+// aircraft are checked off again.
+func ResetChecklist(database *gorm.DB, now time.Time) (ChecklistUpgradeResult, error) {
+	result := ChecklistUpgradeResult{}
+	err := database.Transaction(func(tx *gorm.DB) error {
+		from, err := ChecklistVersion(tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&model.ChecklistItem{}).Error; err != nil {
+			return fmt.Errorf("clear checklist: %w", err)
+		}
+		if err := saveChecklistVersion(tx, 0); err != nil {
+			return err
+		}
+		result, err = UpgradeChecklist(tx)
+		if err != nil {
+			return err
+		}
+		result.FromVersion = from
+		return RefreshChecklist(tx, now)
+	})
+	return result, err
+}
+
+// RefreshChecklist re-evaluates the checklist items driven by recorded data
+// (endorsements and aircraft), which can lapse with time alone.
+func RefreshChecklist(database *gorm.DB, now time.Time) error {
+	if err := SyncEndorsementChecklist(database, now); err != nil {
+		return err
+	}
+	return SyncAircraftChecklist(database, now)
+}
+
+// ListChecklistItems returns the checklist grouped by category, default items
+// in template order followed by custom items in the order they were added.
+func ListChecklistItems(database *gorm.DB) ([]model.ChecklistItem, error) {
+	var items []model.ChecklistItem
+	if err := database.Order("id asc").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("load checklist: %w", err)
+	}
+	rank := map[model.ChecklistCategory]int{}
+	for i, category := range ChecklistCategories {
+		rank[category] = i
+	}
+	categoryRank := func(category model.ChecklistCategory) int {
+		if r, ok := rank[category]; ok {
+			return r
+		}
+		return len(ChecklistCategories)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if categoryRank(a.Category) != categoryRank(b.Category) {
+			return categoryRank(a.Category) < categoryRank(b.Category)
+		}
+		if (a.TemplateKey == "") != (b.TemplateKey == "") {
+			return a.TemplateKey != ""
+		}
+		return a.Position < b.Position
+	})
+	return items, nil
+}
+
+// AddChecklistItem adds a custom checklist item.
+func AddChecklistItem(database *gorm.DB, category string, title string) (model.ChecklistItem, error) {
+	parsed, err := ParseChecklistCategory(category)
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return model.ChecklistItem{}, errors.New("checklist item title is required")
+	}
+	item := model.ChecklistItem{Category: parsed, Title: title}
+	if err := database.Create(&item).Error; err != nil {
+		return model.ChecklistItem{}, fmt.Errorf("add checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// UpdateChecklistItem renames or recategorizes a custom checklist item.
+func UpdateChecklistItem(database *gorm.DB, id uint, category string, title string) (model.ChecklistItem, error) {
+	item, err := loadCustomChecklistItem(database, id)
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	parsed, err := ParseChecklistCategory(category)
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return model.ChecklistItem{}, errors.New("checklist item title is required")
+	}
+	item.Category = parsed
+	item.Title = title
+	if err := database.Save(&item).Error; err != nil {
+		return model.ChecklistItem{}, fmt.Errorf("update checklist item: %w", err)
+	}
+	return item, nil
+}
+
+// DeleteChecklistItem removes a custom checklist item.
+func DeleteChecklistItem(database *gorm.DB, id uint) error {
+	item, err := loadCustomChecklistItem(database, id)
+	if err != nil {
+		return err
+	}
+	if err := database.Delete(&item).Error; err != nil {
+		return fmt.Errorf("delete checklist item: %w", err)
+	}
+	return nil
+}
+
+// ToggleChecklistItem flips an item's completion state.
+func ToggleChecklistItem(database *gorm.DB, id uint) (model.ChecklistItem, error) {
+	var item model.ChecklistItem
+	if err := database.First(&item, id).Error; err != nil {
+		return model.ChecklistItem{}, fmt.Errorf("checklist item %d not found", id)
+	}
+	item.Completed = !item.Completed
+	if err := database.Model(&item).Update("completed", item.Completed).Error; err != nil {
+		return model.ChecklistItem{}, fmt.Errorf("update checklist item: %w", err)
+	}
+	return item, nil
+}
+
+func loadCustomChecklistItem(database *gorm.DB, id uint) (model.ChecklistItem, error) {
+	var item model.ChecklistItem
+	if err := database.First(&item, id).Error; err != nil {
+		return model.ChecklistItem{}, fmt.Errorf("checklist item %d not found", id)
+	}
+	if item.TemplateKey != "" {
+		return model.ChecklistItem{}, fmt.Errorf("%q is part of the default checklist; only custom items can be edited or removed", item.Title)
+	}
+	return item, nil
+}
+
+func checklistTitleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
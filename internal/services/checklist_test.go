@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupChecklistTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ChecklistItem{}, &model.AppConfig{}, &model.Endorsement{}, &model.StudyPlan{}, &model.Aircraft{}, &model.AircraftAD{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestSeedChecklist_SeedsDefaultOnce(t *testing.T) {
+	db := setupChecklistTestDB(t)
+	tpl := DefaultChecklistTemplate()
+
+	if err := SeedChecklist(db); err != nil {
+		t.Fatalf("SeedChecklist returned error: %v", err)
+	}
+	items, _ := ListChecklistItems(db)
+	if len(items) != len(tpl.Items) {
+		t.Fatalf("expected %d seeded items, got %d", len(tpl.Items), len(items))
+	}
+	if version, _ := ChecklistVersion(db); version != tpl.Version {
+		t.Fatalf("expected checklist version %d, got %d", tpl.Version, version)
+	}
+
+	if _, err := AddChecklistItem(db, "documents", "Headset"); err != nil {
+		t.Fatalf("AddChecklistItem returned error: %v", err)
+	}
+	if err := SeedChecklist(db); err != nil {
+		t.Fatalf("SeedChecklist returned error: %v", err)
+	}
+	items, _ = ListChecklistItems(db)
+	if len(items) != len(tpl.Items)+1 {
+		t.Fatalf("expected seeding to be a no-op at the current version, got %d items", len(items))
+	}
+	// Custom items sort after the default items of their category.
+	if items[len(items)-1].Category == model.CategoryDocuments {
+		t.Fatal("expected items to be grouped by category")
+	}
+}
+
+func TestUpgradeChecklist_KeepsCompletionState(t *testing.T) {
+	db := setupChecklistTestDB(t)
+	legacy := []model.ChecklistItem{
+		{Category: model.CategoryDocuments, Title: "Medical certificate (Class 3 or higher)", Completed: true},
+		{Category: model.CategoryFlight, Title: "Solo endorsements (3 takeoffs/landings)", Completed: true},
+		{Category: model.CategoryFlight, Title: "Instrument proficiency"},
+		{Category: model.CategoryGround, Title: "Old template item", TemplateKey: "dropped-item", Completed: true},
+	}
+	db.Create(&legacy)
+
+	result, err := UpgradeChecklist(db)
+	if err != nil {
+		t.Fatalf("UpgradeChecklist returned error: %v", err)
+	}
+	if result.FromVersion != 0 || result.Updated != 2 || result.Retired != 1 {
+		t.Fatalf("unexpected upgrade result: %+v", result)
+	}
+
+	var medical model.ChecklistItem
+	db.First(&medical, legacy[0].ID)
+	if medical.TemplateKey != "medical" || !medical.Completed || !strings.Contains(medical.Title, "BasicMed") {
+		t.Fatalf("expected legacy medical item to be adopted with its completion, got %+v", medical)
+	}
+	var dropped model.ChecklistItem
+	db.First(&dropped, legacy[3].ID)
+	if dropped.TemplateKey != "" || !dropped.Completed {
+		t.Fatalf("expected dropped template item to be kept as a completed custom item, got %+v", dropped)
+	}
+
+	var count int64
+	db.Model(&model.ChecklistItem{}).Count(&count)
+	if want := int64(len(DefaultChecklistTemplate().Items) + 2); count != want {
+		t.Fatalf("expected %d items after upgrade, got %d", want, count)
+	}
+
+	again, err := UpgradeChecklist(db)
+	if err != nil || again.Added != 0 || again.Updated != 0 || again.Retired != 0 {
+		t.Fatalf("expected a second upgrade to change nothing, got %+v (%v)", again, err)
+	}
+}
+
+func TestChecklistCustomItemCRUD(t *testing.T) {
+	db := setupChecklistTestDB(t)
+	if err := SeedChecklist(db); err != nil {
+		t.Fatalf("SeedChecklist returned error: %v", err)
+	}
+
+	item, err := AddChecklistItem(db, "Aircraft", "Fuel receipt")
+	if err != nil {
+		t.Fatalf("AddChecklistItem returned error: %v", err)
+	}
+	if _, err := AddChecklistItem(db, "Paperwork", "Fuel receipt"); err == nil {
+		t.Fatal("expected unknown category to be rejected")
+	}
+	updated, err := UpdateChecklistItem(db, item.ID, "ground", "Fuel receipt and sump sample")
+	if err != nil || updated.Category != model.CategoryGround {
+		t.Fatalf("UpdateChecklistItem = %+v, %v", updated, err)
+	}
+	toggled, err := ToggleChecklistItem(db, item.ID)
+	if err != nil || !toggled.Completed {
+		t.Fatalf("ToggleChecklistItem = %+v, %v", toggled, err)
+	}
+
+	var seeded model.ChecklistItem
+	db.Where("template_key = ?", "iacra").First(&seeded)
+	if _, err := UpdateChecklistItem(db, seeded.ID, "Documents", "Renamed"); err == nil {
+		t.Fatal("expected default items to be read-only")
+	}
+	if err := DeleteChecklistItem(db, seeded.ID); err == nil {
+		t.Fatal("expected default items to be undeletable")
+	}
+	if err := DeleteChecklistItem(db, item.ID); err != nil {
+		t.Fatalf("DeleteChecklistItem returned error: %v", err)
+	}
+}
+
+func TestResetChecklist_RestoresDefaultAndEndorsements(t *testing.T) {
+	db := setupChecklistTestDB(t)
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	if err := SeedChecklist(db); err != nil {
+		t.Fatalf("SeedChecklist returned error: %v", err)
+	}
+	db.Model(&model.ChecklistItem{}).Where("1 = 1").Update("completed", true)
+	if _, err := AddChecklistItem(db, "Flight", "Headset batteries"); err != nil {
+		t.Fatalf("AddChecklistItem returned error: %v", err)
+	}
+	if _, err := AddEndorsement(db, EndorsementInput{Kind: EndorsementKnowledgeTest, IssuedOn: now, CFIName: "Jane Doe", CFICertificate: "1234567CFI"}, now); err != nil {
+		t.Fatalf("AddEndorsement returned error: %v", err)
+	}
+
+	result, err := ResetChecklist(db, now)
+	if err != nil {
+		t.Fatalf("ResetChecklist returned error: %v", err)
+	}
+	items, _ := ListChecklistItems(db)
+	if len(items) != len(DefaultChecklistTemplate().Items) || result.Added != len(items) {
+		t.Fatalf("expected a fresh default checklist, got %d items (%+v)", len(items), result)
+	}
+	for _, item := range items {
+		if item.Completed != (item.TemplateKey == "knowledge-test-endorsement") {
+			t.Fatalf("unexpected completion after reset: %+v", item)
+		}
+	}
+}
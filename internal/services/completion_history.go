@@ -0,0 +1,188 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// TimesOfDay are the CompletionHistory.ByTimeOfDay keys in display order.
+var TimesOfDay = []string{"Morning", "Afternoon", "Evening", "Night"}
+
+// CompletionHistory summarises the recorded task completions. A task counts
+// once, at its latest completion, and not at all if it was unchecked
+// afterwards. Days and hours are in the location of the time passed to
+// SummarizeCompletionHistory.
+type CompletionHistory struct {
+	Completions   int
+	Since         time.Time
+	CurrentStreak int
+	LongestStreak int
+	OnTime        int
+	Late          int
+	DaysLate      int
+	ByTimeOfDay   map[string]int
+	ByHour        [24]int
+}
+
+// AverageDaysLate is how late the late completions were on average.
+func (h CompletionHistory) AverageDaysLate() float64 {
+	if h.Late == 0 {
+		return 0
+	}
+	return float64(h.DaysLate) / float64(h.Late)
+}
+
+// BusiestHour is the hour of the day with the most completions, or -1
+// without any.
+func (h CompletionHistory) BusiestHour() int {
+	busiest := -1
+	for hour, count := range h.ByHour {
+		if count > 0 && (busiest < 0 || count > h.ByHour[busiest]) {
+			busiest = hour
+		}
+	}
+	return busiest
+}
+
+// recordTaskProgress appends a completion history entry for task, which has
+// just been checked or unchecked. Pass the transaction that saved the task.
+func recordTaskProgress(tx *gorm.DB, task model.DailyTask, source string, at time.Time) error {
+	entry := model.Progress{
+		DailyTaskID: task.ID,
+		CompletedAt: at.UTC(),
+		Completed:   task.Completed,
+		TaskDate:    dateOnlyUTC(task.Date),
+		Category:    task.Category,
+		Source:      source,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("record completion history for task %d: %w", task.ID, err)
+	}
+	return nil
+}
+
+// ListTaskProgress returns the completion history, oldest first.
+func ListTaskProgress(database *gorm.DB) ([]model.Progress, error) {
+	var entries []model.Progress
+	if err := database.Order("completed_at asc").Order("id asc").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("load completion history: %w", err)
+	}
+	return entries, nil
+}
+
+// LastCompletionChanges maps each task in the completion history to when it
+// was last checked or unchecked.
+func LastCompletionChanges(database *gorm.DB) (map[uint]time.Time, error) {
+	entries, err := ListTaskProgress(database)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(map[uint]time.Time, len(entries))
+	for _, entry := range entries {
+		changes[entry.DailyTaskID] = entry.CompletedAt
+	}
+	return changes, nil
+}
+
+// LoadCompletionHistory summarises the stored completion history as of now.
+func LoadCompletionHistory(database *gorm.DB, now time.Time) (CompletionHistory, error) {
+	entries, err := ListTaskProgress(database)
+	if err != nil {
+		return CompletionHistory{}, err
+	}
+	return SummarizeCompletionHistory(entries, now), nil
+}
+
+// SummarizeCompletionHistory works out streaks, on-time and late counts and
+// the time of day of the completions in entries, which must be oldest first.
+// A completion is late when it happened after the day the task was
+// scheduled for.
+func SummarizeCompletionHistory(entries []model.Progress, now time.Time) CompletionHistory {
+	history := CompletionHistory{ByTimeOfDay: map[string]int{}}
+	var days []time.Time
+	for _, entry := range latestCompletions(entries) {
+		at := entry.CompletedAt.In(now.Location())
+		day := dateOnlyUTC(at)
+		history.Completions++
+		if history.Since.IsZero() || at.Before(history.Since) {
+			history.Since = at
+		}
+		days = append(days, day)
+		if late := int(day.Sub(dateOnlyUTC(entry.TaskDate)).Hours() / 24); late > 0 {
+			history.Late++
+			history.DaysLate += late
+		} else {
+			history.OnTime++
+		}
+		history.ByHour[at.Hour()]++
+		history.ByTimeOfDay[TimeOfDay(at.Hour())]++
+	}
+	history.CurrentStreak, history.LongestStreak = dayStreaks(days, dateOnlyUTC(now))
+	return history
+}
+
+// latestCompletions keeps the latest entry per task, dropping tasks whose
+// latest entry unchecked them.
+func latestCompletions(entries []model.Progress) []model.Progress {
+	latest := map[uint]model.Progress{}
+	for _, entry := range entries {
+		latest[entry.DailyTaskID] = entry
+	}
+	completions := make([]model.Progress, 0, len(latest))
+	for _, entry := range latest {
+		if entry.Completed {
+			completions = append(completions, entry)
+		}
+	}
+	return completions
+}
+
+// TimeOfDay names the part of the day an hour falls in.
+func TimeOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "Morning"
+	case hour >= 12 && hour < 17:
+		return "Afternoon"
+	case hour >= 17 && hour < 22:
+		return "Evening"
+	default:
+		return "Night"
+	}
+}
+
+// dayStreaks returns the current and longest runs of consecutive days in
+// days, which are dates as from dateOnlyUTC, in any order and possibly
+// repeated. The current streak still counts when its last day is
+// yesterday, so it only breaks once a whole day is missed.
+func dayStreaks(days []time.Time, today time.Time) (current, longest int) {
+	if len(days) == 0 {
+		return 0, 0
+	}
+	sorted := append([]time.Time(nil), days...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	run := 0
+	var previous time.Time
+	for _, day := range sorted {
+		switch {
+		case run > 0 && day.Equal(previous):
+			continue
+		case run > 0 && day.Equal(previous.AddDate(0, 0, 1)):
+			run++
+		default:
+			run = 1
+		}
+		previous = day
+		longest = max(longest, run)
+	}
+	if !previous.Before(today.AddDate(0, 0, -1)) {
+		current = run
+	}
+	return current, longest
+}
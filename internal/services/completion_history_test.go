@@ -0,0 +1,74 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestToggleTaskCompletion_RecordsCompletionHistory(t *testing.T) {
+	db := setupEventsTestDB(t)
+	task := model.DailyTask{Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Airspace"}
+	if err := db.Create(&task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	for _, source := range []string{"web", "tui"} {
+		if _, err := ToggleTaskCompletion(db, task.ID, source); err != nil {
+			t.Fatalf("toggle from %s: %v", source, err)
+		}
+	}
+	if _, err := SetTaskCompletion(db, task.ID, false, "daemon"); err != nil {
+		t.Fatalf("set completion: %v", err)
+	}
+
+	entries, err := ListTaskProgress(db)
+	if err != nil {
+		t.Fatalf("ListTaskProgress: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected an entry per change, got %+v", entries)
+	}
+	if !entries[0].Completed || entries[0].Source != "web" || entries[1].Completed || entries[1].Source != "tui" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[0].DailyTaskID != task.ID || !entries[0].TaskDate.Equal(task.Date) || entries[0].Category != "Theory" {
+		t.Fatalf("expected the task details to be copied, got %+v", entries[0])
+	}
+}
+
+func TestSummarizeCompletionHistory(t *testing.T) {
+	now := time.Date(2026, 6, 10, 20, 0, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time { return time.Date(2026, 6, day, hour, 0, 0, 0, time.UTC) }
+	scheduled := func(day int) time.Time { return time.Date(2026, 6, day, 0, 0, 0, 0, time.UTC) }
+	entries := []model.Progress{
+		{DailyTaskID: 1, CompletedAt: at(1, 7), Completed: true, TaskDate: scheduled(1)},
+		{DailyTaskID: 2, CompletedAt: at(2, 8), Completed: true, TaskDate: scheduled(2)},
+		{DailyTaskID: 3, CompletedAt: at(3, 19), Completed: true, TaskDate: scheduled(1)},
+		{DailyTaskID: 4, CompletedAt: at(6, 9), Completed: true, TaskDate: scheduled(6)},
+		{DailyTaskID: 4, CompletedAt: at(6, 10), Completed: false, TaskDate: scheduled(6)},
+		{DailyTaskID: 5, CompletedAt: at(9, 23), Completed: true, TaskDate: scheduled(9)},
+		{DailyTaskID: 6, CompletedAt: at(10, 13), Completed: true, TaskDate: scheduled(12)},
+	}
+
+	history := SummarizeCompletionHistory(entries, now)
+	if history.Completions != 5 || !history.Since.Equal(at(1, 7)) {
+		t.Fatalf("expected 5 completions since Jun 1, got %+v", history)
+	}
+	if history.CurrentStreak != 2 || history.LongestStreak != 3 {
+		t.Fatalf("expected streaks 2 and 3, got %d and %d", history.CurrentStreak, history.LongestStreak)
+	}
+	if history.OnTime != 4 || history.Late != 1 || history.AverageDaysLate() != 2 {
+		t.Fatalf("unexpected on-time stats: %+v", history)
+	}
+	want := map[string]int{"Morning": 2, "Afternoon": 1, "Evening": 1, "Night": 1}
+	for key, count := range want {
+		if history.ByTimeOfDay[key] != count {
+			t.Fatalf("expected %d %s completions, got %v", count, key, history.ByTimeOfDay)
+		}
+	}
+
+	if later := SummarizeCompletionHistory(entries, now.AddDate(0, 0, 2)); later.CurrentStreak != 0 || later.LongestStreak != 3 {
+		t.Fatalf("expected the streak to break after a missed day, got %+v", later)
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	NotifierDBus       = "dbus"
+	NotifierNotifySend = "notify-send"
+
+	notificationsBusName    = "org.freedesktop.Notifications"
+	notificationsObjectPath = "/org/freedesktop/Notifications"
+	notificationWaitTimeout = 2 * time.Hour
+)
+
+// NotificationAction is a button the user pressed on a notification.
+type NotificationAction struct {
+	Key    string
+	Action string
+}
+
+// DesktopNotifier shows notifications and reports the action buttons users
+// press. Actions arrive asynchronously on the Actions channel.
+type DesktopNotifier interface {
+	Name() string
+	Notify(ctx context.Context, n DesktopNotification) error
+	Actions() <-chan NotificationAction
+}
+
+var (
+	notifierLookPath = exec.LookPath
+	notifierRunner   = defaultCommandRunner
+	notifierMonitor  = startGDBusMonitor
+)
+
+// NewDesktopNotifier talks to org.freedesktop.Notifications over the session
+// bus through gdbus when possible, and falls back to notify-send.
+func NewDesktopNotifier(ctx context.Context) (DesktopNotifier, error) {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") != "" {
+		if _, err := notifierLookPath("gdbus"); err == nil {
+			if notifier, err := newDBusNotifier(ctx, notifierRunner, notifierMonitor); err == nil {
+				return notifier, nil
+			}
+		}
+	}
+	if _, err := notifierLookPath("notify-send"); err == nil {
+		return newNotifySendNotifier(notifierRunner), nil
+	}
+	return nil, errors.New("no desktop notification service found: install libnotify-bin (notify-send) or run inside a desktop session")
+}
+
+func actionLabel(action string) string {
+	switch action {
+	case NotificationActionDone:
+		return "Done"
+	case NotificationActionSnooze:
+		return "Snooze"
+	default:
+		return action
+	}
+}
+
+// dbusNotifier calls Notify through `gdbus call` and watches ActionInvoked
+// signals with a single long-running `gdbus monitor`.
+type dbusNotifier struct {
+	run     commandRunner
+	actions chan NotificationAction
+
+	mu   sync.Mutex
+	keys map[uint32]string
+}
+
+func newDBusNotifier(ctx context.Context, run commandRunner, monitor func(context.Context) (io.ReadCloser, error)) (*dbusNotifier, error) {
+	stream, err := monitor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n := &dbusNotifier{run: run, actions: make(chan NotificationAction, 16), keys: map[uint32]string{}}
+	go n.watch(stream)
+	return n, nil
+}
+
+func (n *dbusNotifier) Name() string { return NotifierDBus }
+
+func (n *dbusNotifier) Actions() <-chan NotificationAction { return n.actions }
+
+func (n *dbusNotifier) Notify(ctx context.Context, notification DesktopNotification) error {
+	actions := make([]string, 0, len(notification.Actions)*2)
+	for _, action := range notification.Actions {
+		actions = append(actions, gvariantString(action), gvariantString(actionLabel(action)))
+	}
+
+	output, err := n.run(ctx, "gdbus", "call", "--session",
+		"--dest", notificationsBusName,
+		"--object-path", notificationsObjectPath,
+		"--method", notificationsBusName+".Notify",
+		"openppl",
+		"0",
+		"dialog-information",
+		notification.Summary,
+		notification.Body,
+		"["+strings.Join(actions, ", ")+"]",
+		"{'category': <'reminder'>}",
+		"0",
+	)
+	if err != nil {
+		return fmt.Errorf("gdbus notify: %w (%s)", err, strings.TrimSpace(string(output)))
+	}
+
+	id, err := parseGDBusNotifyReply(string(output))
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	n.keys[id] = notification.Key
+	n.mu.Unlock()
+	return nil
+}
+
+var (
+	gdbusNotifyReplyPattern = regexp.MustCompile(`\(uint32 (\d+),?\)`)
+	gdbusActionPattern      = regexp.MustCompile(`ActionInvoked \(uint32 (\d+), '([^']*)'\)`)
+	gdbusClosedPattern      = regexp.MustCompile(`NotificationClosed \(uint32 (\d+), uint32 \d+\)`)
+)
+
+func parseGDBusNotifyReply(output string) (uint32, error) {
+	match := gdbusNotifyReplyPattern.FindStringSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("unexpected gdbus reply %q", strings.TrimSpace(output))
+	}
+	id, err := strconv.ParseUint(match[1], 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(id), nil
+}
+
+func (n *dbusNotifier) watch(stream io.ReadCloser) {
+	defer stream.Close()
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := gdbusActionPattern.FindStringSubmatch(line); match != nil {
+			id, _ := strconv.ParseUint(match[1], 10, 32)
+			n.mu.Lock()
+			key, ok := n.keys[uint32(id)]
+			n.mu.Unlock()
+			if ok {
+				n.actions <- NotificationAction{Key: key, Action: match[2]}
+			}
+			continue
+		}
+		if match := gdbusClosedPattern.FindStringSubmatch(line); match != nil {
+			id, _ := strconv.ParseUint(match[1], 10, 32)
+			n.mu.Lock()
+			delete(n.keys, uint32(id))
+			n.mu.Unlock()
+		}
+	}
+}
+
+func startGDBusMonitor(ctx context.Context) (io.ReadCloser, error) {
+	cmd := exec.CommandContext(ctx, "gdbus", "monitor", "--session", "--dest", notificationsBusName, "--object-path", notificationsObjectPath)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() { _ = cmd.Wait() }()
+	return stdout, nil
+}
+
+// gvariantString quotes s as a GVariant text-format string for gdbus.
+func gvariantString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `'`, `\'`)
+	return "'" + s + "'"
+}
+
+// notifySendNotifier runs `notify-send --wait` once per notification; the
+// command blocks until the notification closes and prints the chosen action.
+type notifySendNotifier struct {
+	run     commandRunner
+	actions chan NotificationAction
+}
+
+func newNotifySendNotifier(run commandRunner) *notifySendNotifier {
+	return &notifySendNotifier{run: run, actions: make(chan NotificationAction, 16)}
+}
+
+func (n *notifySendNotifier) Name() string { return NotifierNotifySend }
+
+func (n *notifySendNotifier) Actions() <-chan NotificationAction { return n.actions }
+
+func (n *notifySendNotifier) Notify(ctx context.Context, notification DesktopNotification) error {
+	args := []string{"--app-name=openppl", "--icon=dialog-information", "--category=reminder"}
+	if len(notification.Actions) > 0 {
+		args = append(args, "--wait")
+		for _, action := range notification.Actions {
+			args = append(args, fmt.Sprintf("--action=%s=%s", action, actionLabel(action)))
+		}
+	}
+	args = append(args, "--", notification.Summary, notification.Body)
+
+	if len(notification.Actions) == 0 {
+		if output, err := n.run(ctx, "notify-send", args...); err != nil {
+			return fmt.Errorf("notify-send: %w (%s)", err, strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	go func() {
+		waitCtx, cancel := context.WithTimeout(ctx, notificationWaitTimeout)
+		defer cancel()
+		output, err := n.run(waitCtx, "notify-send", args...)
+		if err != nil {
+			return
+		}
+		action := strings.TrimSpace(string(output))
+		if action == "" {
+			return
+		}
+		select {
+		case n.actions <- NotificationAction{Key: notification.Key, Action: action}:
+		case <-ctx.Done():
+		}
+	}()
+	return nil
+}
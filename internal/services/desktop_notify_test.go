@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifySendNotifier_WaitsForActionKey(t *testing.T) {
+	var mu sync.Mutex
+	var gotArgs []string
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		mu.Lock()
+		gotArgs = append([]string{name}, args...)
+		mu.Unlock()
+		return []byte("snooze\n"), nil
+	}
+
+	notifier := newNotifySendNotifier(runner)
+	err := notifier.Notify(context.Background(), DesktopNotification{
+		Key:     "tasks:theory:2026-05-10",
+		Summary: "Theory: 1 due today",
+		Body:    "• Airspace",
+		Actions: []string{NotificationActionDone, NotificationActionSnooze},
+	})
+	if err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+
+	select {
+	case action := <-notifier.Actions():
+		if action.Key != "tasks:theory:2026-05-10" || action.Action != NotificationActionSnooze {
+			t.Fatalf("unexpected action: %+v", action)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for action")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	joined := strings.Join(gotArgs, "|")
+	for _, want := range []string{"notify-send", "--wait", "--action=done=Done", "--action=snooze=Snooze", "--|Theory: 1 due today|• Airspace"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected notify-send args to contain %q, got %q", want, joined)
+		}
+	}
+}
+
+func TestDBusNotifier_MapsActionInvokedSignals(t *testing.T) {
+	reader, writer := io.Pipe()
+	monitor := func(context.Context) (io.ReadCloser, error) { return reader, nil }
+
+	var gotArgs []string
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		gotArgs = append([]string{name}, args...)
+		return []byte("(uint32 42,)\n"), nil
+	}
+
+	notifier, err := newDBusNotifier(context.Background(), runner, monitor)
+	if err != nil {
+		t.Fatalf("newDBusNotifier returned error: %v", err)
+	}
+	if err := notifier.Notify(context.Background(), DesktopNotification{
+		Key:     "tasks:theory:2026-05-10",
+		Summary: "Pilot's notes",
+		Actions: []string{NotificationActionDone},
+	}); err != nil {
+		t.Fatalf("Notify returned error: %v", err)
+	}
+	if gotArgs[0] != "gdbus" || !strings.Contains(strings.Join(gotArgs, "|"), "org.freedesktop.Notifications.Notify") {
+		t.Fatalf("unexpected gdbus call: %v", gotArgs)
+	}
+	if actions := gotArgs[len(gotArgs)-3]; actions != "['done', 'Done']" {
+		t.Fatalf("unexpected actions argument: %q", actions)
+	}
+
+	go func() {
+		_, _ = io.WriteString(writer, "/org/freedesktop/Notifications: org.freedesktop.Notifications.ActionInvoked (uint32 7, 'done')\n")
+		_, _ = io.WriteString(writer, "/org/freedesktop/Notifications: org.freedesktop.Notifications.ActionInvoked (uint32 42, 'done')\n")
+	}()
+
+	select {
+	case action := <-notifier.Actions():
+		if action.Key != "tasks:theory:2026-05-10" || action.Action != NotificationActionDone {
+			t.Fatalf("unexpected action: %+v", action)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for action")
+	}
+	_ = writer.Close()
+}
+
+func TestGVariantStringEscapesQuotes(t *testing.T) {
+	if got := gvariantString(`Pilot's \ notes`); got != `'Pilot\'s \\ notes'` {
+		t.Fatalf("unexpected gvariant string: %s", got)
+	}
+}
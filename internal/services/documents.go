@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	DocumentMedical            = "medical"
+	DocumentStudentCertificate = "student-certificate"
+	DocumentKnowledgeTest      = "knowledge-test"
+	DocumentPhotoID            = "photo-id"
+
+	pilotBirthDateConfigKey = "pilot_birth_date"
+
+	// documentWarningDays is how early dashboards warn about an expiry.
+	documentWarningDays = 30
+	// documentRiskDays flags documents that expire soon after the checkride,
+	// where a weather or examiner delay would push the checkride past them.
+	documentRiskDays = 60
+)
+
+// studentCertificateNoExpiry is when student pilot certificates stopped
+// expiring (the plastic certificates issued under the 2016 rule).
+var studentCertificateNoExpiry = time.Date(2016, 4, 1, 0, 0, 0, 0, time.UTC)
+
+// DocumentKind describes a personal document and how it expires.
+// CalendarMonths > 0 means it is valid through the end of that many calendar
+// months after issue; EntersExpiry means the expiration is printed on the
+// document and must be entered. BlocksCheckride documents must still be
+// valid on the checkride date.
+type DocumentKind struct {
+	Key             string
+	Title           string
+	Reference       string
+	CalendarMonths  int
+	EntersExpiry    bool
+	BlocksCheckride bool
+}
+
+// DocumentKinds lists the personal documents the planner tracks.
+var DocumentKinds = []DocumentKind{
+	{Key: DocumentMedical, Title: "Medical certificate", Reference: "61.23(d)", BlocksCheckride: true},
+	{Key: DocumentStudentCertificate, Title: "Student pilot certificate", Reference: "61.19(b)", BlocksCheckride: true},
+	{Key: DocumentKnowledgeTest, Title: "Knowledge test report", Reference: "61.39(a)(1)", CalendarMonths: 24, BlocksCheckride: true},
+	{Key: DocumentPhotoID, Title: "Government-issued photo ID", Reference: "61.3(a)(2)", EntersExpiry: true, BlocksCheckride: true},
+}
+
+// LookupDocumentKind returns the document kind with the given key.
+func LookupDocumentKind(key string) (DocumentKind, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, kind := range DocumentKinds {
+		if kind.Key == key {
+			return kind, true
+		}
+	}
+	return DocumentKind{}, false
+}
+
+// PilotDocumentInput is a document as entered by the student. ExpiresOn is
+// only needed for documents that print their own expiration.
+type PilotDocumentInput struct {
+	Kind      string
+	IssuedOn  time.Time
+	ExpiresOn time.Time
+	Notes     string
+}
+
+// DocumentExpiresOn computes when a document expires, or nil if it does not.
+// Medical certificates carry third-class privileges for 60 calendar months
+// when the exam was before age 40 and 24 calendar months otherwise; without
+// a birth date the shorter period is assumed.
+func DocumentExpiresOn(kind DocumentKind, issued time.Time, explicit time.Time, bornOn *time.Time) (*time.Time, error) {
+	issued = dateOnlyUTC(issued)
+	if !explicit.IsZero() {
+		expires := dateOnlyUTC(explicit)
+		if expires.Before(issued) {
+			return nil, fmt.Errorf("expiration %s is before the issue date", expires.Format("2006-01-02"))
+		}
+		return &expires, nil
+	}
+
+	var expires time.Time
+	switch {
+	case kind.Key == DocumentMedical:
+		months := 24
+		if bornOn != nil && issued.Before(bornOn.AddDate(40, 0, 0)) {
+			months = 60
+		}
+		expires = endOfCalendarMonths(issued, months)
+	case kind.Key == DocumentStudentCertificate:
+		if !issued.Before(studentCertificateNoExpiry) {
+			return nil, nil
+		}
+		return nil, errors.New("student pilot certificates issued before April 2016 expire; enter the expiration date")
+	case kind.CalendarMonths > 0:
+		expires = endOfCalendarMonths(issued, kind.CalendarMonths)
+	case kind.EntersExpiry:
+		return nil, fmt.Errorf("enter the expiration date printed on the %s", strings.ToLower(kind.Title))
+	default:
+		return nil, nil
+	}
+	return &expires, nil
+}
+
+// SavePilotDocument records a document, replacing any earlier one of the
+// same kind.
+func SavePilotDocument(database *gorm.DB, input PilotDocumentInput) (model.PilotDocument, error) {
+	kind, ok := LookupDocumentKind(input.Kind)
+	if !ok {
+		keys := make([]string, 0, len(DocumentKinds))
+		for _, k := range DocumentKinds {
+			keys = append(keys, k.Key)
+		}
+		return model.PilotDocument{}, fmt.Errorf("unknown document %q (want one of %s)", input.Kind, strings.Join(keys, ", "))
+	}
+	if input.IssuedOn.IsZero() {
+		return model.PilotDocument{}, errors.New("issue date is required")
+	}
+	bornOn, err := PilotBirthDate(database)
+	if err != nil {
+		return model.PilotDocument{}, err
+	}
+	expires, err := DocumentExpiresOn(kind, input.IssuedOn, input.ExpiresOn, bornOn)
+	if err != nil {
+		return model.PilotDocument{}, err
+	}
+
+	var document model.PilotDocument
+	if err := database.Where("kind = ?", kind.Key).Limit(1).Find(&document).Error; err != nil {
+		return model.PilotDocument{}, fmt.Errorf("load %s: %w", kind.Key, err)
+	}
+	document.Kind = kind.Key
+	document.IssuedOn = dateOnlyUTC(input.IssuedOn)
+	document.ExpiresOn = expires
+	document.Notes = strings.TrimSpace(input.Notes)
+	if err := database.Save(&document).Error; err != nil {
+		return model.PilotDocument{}, fmt.Errorf("save %s: %w", kind.Key, err)
+	}
+	_ = writeDocumentCache(database)
+	return document, nil
+}
+
+// DeletePilotDocument removes the document of the given kind.
+func DeletePilotDocument(database *gorm.DB, kind string) error {
+	result := database.Where("kind = ?", strings.ToLower(strings.TrimSpace(kind))).Delete(&model.PilotDocument{})
+	if result.Error != nil {
+		return fmt.Errorf("delete %s: %w", kind, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no %s recorded", kind)
+	}
+	_ = writeDocumentCache(database)
+	return nil
+}
+
+// ListPilotDocuments returns recorded documents in DocumentKinds order.
+func ListPilotDocuments(database *gorm.DB) ([]model.PilotDocument, error) {
+	var documents []model.PilotDocument
+	if err := database.Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("load documents: %w", err)
+	}
+	ordered := make([]model.PilotDocument, 0, len(documents))
+	for _, kind := range DocumentKinds {
+		for _, document := range documents {
+			if document.Kind == kind.Key {
+				ordered = append(ordered, document)
+			}
+		}
+	}
+	return ordered, nil
+}
+
+// PilotBirthDate returns the stored birth date, or nil if it was never set.
+func PilotBirthDate(database *gorm.DB) (*time.Time, error) {
+	var cfg model.AppConfig
+	if err := database.Where("key = ?", pilotBirthDateConfigKey).Limit(1).Find(&cfg).Error; err != nil {
+		return nil, fmt.Errorf("load birth date: %w", err)
+	}
+	if cfg.ID == 0 || cfg.Value == "" {
+		return nil, nil
+	}
+	born, err := time.Parse("2006-01-02", cfg.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid stored birth date %q", cfg.Value)
+	}
+	return &born, nil
+}
+
+// SetPilotBirthDate stores the birth date used for medical expiry and
+// recomputes a recorded medical certificate's expiration.
+func SetPilotBirthDate(database *gorm.DB, born time.Time) error {
+	return database.Transaction(func(tx *gorm.DB) error {
+		var cfg model.AppConfig
+		if err := tx.Where("key = ?", pilotBirthDateConfigKey).Limit(1).Find(&cfg).Error; err != nil {
+			return fmt.Errorf("load birth date: %w", err)
+		}
+		cfg.Key = pilotBirthDateConfigKey
+		cfg.Value = dateOnlyUTC(born).Format("2006-01-02")
+		if err := tx.Save(&cfg).Error; err != nil {
+			return fmt.Errorf("save birth date: %w", err)
+		}
+
+		var medical model.PilotDocument
+		if err := tx.Where("kind = ?", DocumentMedical).Limit(1).Find(&medical).Error; err != nil || medical.ID == 0 {
+			return err
+		}
+		kind, _ := LookupDocumentKind(DocumentMedical)
+		expires, err := DocumentExpiresOn(kind, medical.IssuedOn, time.Time{}, &born)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&medical).Update("expires_on", expires).Error; err != nil {
+			return fmt.Errorf("update medical expiration: %w", err)
+		}
+		_ = writeDocumentCache(tx)
+		return nil
+	})
+}
+
+// CheckrideConflict is a document that expires before the checkride.
+type CheckrideConflict struct {
+	Title     string
+	Reference string
+	ExpiresOn time.Time
+}
+
+// CheckrideBlockedError reports documents that would be expired on the
+// proposed checkride date.
+type CheckrideBlockedError struct {
+	Checkride time.Time
+	Conflicts []CheckrideConflict
+}
+
+func (e *CheckrideBlockedError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, c := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("%s expires %s", strings.ToLower(c.Title), c.ExpiresOn.Format("2006-01-02")))
+	}
+	return fmt.Sprintf("checkride %s is after your %s", e.Checkride.Format("2006-01-02"), strings.Join(parts, " and "))
+}
+
+// LatestCheckride returns the last day a checkride can be scheduled before a
+// document expires.
+func (e *CheckrideBlockedError) LatestCheckride() time.Time {
+	latest := e.Conflicts[0].ExpiresOn
+	for _, c := range e.Conflicts[1:] {
+		if c.ExpiresOn.Before(latest) {
+			latest = c.ExpiresOn
+		}
+	}
+	return latest
+}
+
+// CheckCheckrideDate returns a *CheckrideBlockedError when a recorded
+// document expires before checkride. A document is valid through its
+// expiration date.
+func CheckCheckrideDate(database *gorm.DB, checkride time.Time) error {
+	documents, err := ListPilotDocuments(database)
+	if err != nil {
+		return err
+	}
+	day := dateOnlyUTC(checkride)
+	var conflicts []CheckrideConflict
+	for _, document := range documents {
+		kind, _ := LookupDocumentKind(document.Kind)
+		if !kind.BlocksCheckride || document.ExpiresOn == nil || !document.ExpiresOn.Before(day) {
+			continue
+		}
+		conflicts = append(conflicts, CheckrideConflict{Title: kind.Title, Reference: kind.Reference, ExpiresOn: *document.ExpiresOn})
+	}
+	if len(conflicts) > 0 {
+		return &CheckrideBlockedError{Checkride: day, Conflicts: conflicts}
+	}
+	return nil
+}
+
+// DocumentWarnings returns dashboard warnings for documents that have
+// expired, expire before the checkride, or expire within 30 days.
+func DocumentWarnings(documents []model.PilotDocument, checkride time.Time, now time.Time) []string {
+	today := dateOnlyUTC(now)
+	var warnings []string
+	for _, document := range documents {
+		if document.ExpiresOn == nil {
+			continue
+		}
+		kind, _ := LookupDocumentKind(document.Kind)
+		warnings = append(warnings, documentWarning(kind.Title, *document.ExpiresOn, checkride, today)...)
+	}
+	return warnings
+}
+
+func documentWarning(title string, expires time.Time, checkride time.Time, today time.Time) []string {
+	days := int(expires.Sub(today).Hours() / 24)
+	switch {
+	case days < 0:
+		return []string{fmt.Sprintf("%s expired on %s", title, expires.Format("Jan 2, 2006"))}
+	case !checkride.IsZero() && !checkride.Before(today) && expires.Before(dateOnlyUTC(checkride)):
+		return []string{fmt.Sprintf("%s expires %s, before the %s checkride", title, expires.Format("Jan 2"), checkride.Format("Jan 2"))}
+	case days <= documentWarningDays:
+		return []string{fmt.Sprintf("%s expires in %d days (%s)", title, days, expires.Format("Jan 2"))}
+	}
+	return nil
+}
+
+// CheckrideRiskNotes lists what could go wrong around a proposed checkride
+// date: documents that expire within 60 days after it, and endorsements
+// that lapse before it.
+func CheckrideRiskNotes(database *gorm.DB, checkride time.Time) []string {
+	day := dateOnlyUTC(checkride)
+	var notes []string
+	if documents, err := ListPilotDocuments(database); err == nil {
+		for _, document := range documents {
+			if document.ExpiresOn == nil || document.ExpiresOn.Before(day) || document.ExpiresOn.After(day.AddDate(0, 0, documentRiskDays)) {
+				continue
+			}
+			kind, _ := LookupDocumentKind(document.Kind)
+			notes = append(notes, fmt.Sprintf("%s expires %s, %d days after the checkride. A delayed checkride could not be rescheduled past it.",
+				kind.Title, document.ExpiresOn.Format("2006-01-02"), int(document.ExpiresOn.Sub(day).Hours()/24)))
+		}
+	}
+	if endorsements, err := ListEndorsements(database); err == nil {
+		for _, warning := range EndorsementWarnings(endorsements, day) {
+			notes = append(notes, "On the checkride date: "+warning+".")
+		}
+	}
+	return notes
+}
+
+// DocumentCacheEntry is one document expiration in the MOTD cache.
+type DocumentCacheEntry struct {
+	Title     string `json:"title"`
+	ExpiresOn string `json:"expires_on"`
+}
+
+// writeDocumentCache refreshes the document expirations in the MOTD cache,
+// so the login MOTD can warn without opening the database.
+func writeDocumentCache(database *gorm.DB) error {
+	documents, err := ListPilotDocuments(database)
+	if err != nil {
+		return err
+	}
+	entries := []DocumentCacheEntry{}
+	for _, document := range documents {
+		if document.ExpiresOn == nil {
+			continue
+		}
+		kind, _ := LookupDocumentKind(document.Kind)
+		entries = append(entries, DocumentCacheEntry{Title: kind.Title, ExpiresOn: document.ExpiresOn.Format("2006-01-02")})
+	}
+	return updateMOTDCache(func(cache *MOTDCache) {
+		cache.Documents = entries
+	})
+}
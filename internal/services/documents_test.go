@@ -0,0 +1,187 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupDocumentsTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.PilotDocument{}, &model.AppConfig{}, &model.Endorsement{}, &model.StudyPlan{}, &model.DailyTask{}, &model.ChecklistItem{}, &model.Milestone{}, &model.Aircraft{}, &model.AircraftAD{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func documentDate(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+func TestSavePilotDocument_ComputesExpiry(t *testing.T) {
+	db := setupDocumentsTestDB(t)
+
+	medical, err := SavePilotDocument(db, PilotDocumentInput{Kind: "Medical", IssuedOn: documentDate("2026-03-14")})
+	if err != nil {
+		t.Fatalf("save medical: %v", err)
+	}
+	if got := medical.ExpiresOn.Format("2006-01-02"); got != "2028-03-31" {
+		t.Fatalf("expected 24 calendar months without a birth date, got %s", got)
+	}
+
+	if err := SetPilotBirthDate(db, documentDate("1995-07-01")); err != nil {
+		t.Fatalf("set birth date: %v", err)
+	}
+	documents, _ := ListPilotDocuments(db)
+	if got := documents[0].ExpiresOn.Format("2006-01-02"); got != "2031-03-31" {
+		t.Fatalf("expected 60 calendar months for an exam before age 40, got %s", got)
+	}
+
+	knowledge, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentKnowledgeTest, IssuedOn: documentDate("2026-01-05")})
+	if err != nil {
+		t.Fatalf("save knowledge test: %v", err)
+	}
+	if got := knowledge.ExpiresOn.Format("2006-01-02"); got != "2028-01-31" {
+		t.Fatalf("expected knowledge test valid through the 24th calendar month, got %s", got)
+	}
+
+	student, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentStudentCertificate, IssuedOn: documentDate("2025-11-01")})
+	if err != nil || student.ExpiresOn != nil {
+		t.Fatalf("expected a current student certificate not to expire, got %+v, %v", student.ExpiresOn, err)
+	}
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentStudentCertificate, IssuedOn: documentDate("2015-06-01")}); err == nil {
+		t.Fatal("expected a pre-2016 student certificate to require an expiration")
+	}
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentPhotoID, IssuedOn: documentDate("2024-01-01")}); err == nil {
+		t.Fatal("expected a photo ID to require an expiration")
+	}
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: "passport", IssuedOn: documentDate("2024-01-01")}); err == nil {
+		t.Fatal("expected an unknown kind to be rejected")
+	}
+}
+
+func TestSetPilotBirthDate_OlderPilotGets24Months(t *testing.T) {
+	db := setupDocumentsTestDB(t)
+	if err := SetPilotBirthDate(db, documentDate("1980-02-01")); err != nil {
+		t.Fatalf("set birth date: %v", err)
+	}
+	medical, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentMedical, IssuedOn: documentDate("2026-03-14")})
+	if err != nil {
+		t.Fatalf("save medical: %v", err)
+	}
+	if got := medical.ExpiresOn.Format("2006-01-02"); got != "2028-03-31" {
+		t.Fatalf("expected 24 calendar months for an exam at 46, got %s", got)
+	}
+}
+
+func TestCheckCheckrideDate_BlocksAfterExpiry(t *testing.T) {
+	db := setupDocumentsTestDB(t)
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentKnowledgeTest, IssuedOn: documentDate("2024-09-10")}); err != nil {
+		t.Fatalf("save knowledge test: %v", err)
+	}
+
+	if err := CheckCheckrideDate(db, documentDate("2026-09-30")); err != nil {
+		t.Fatalf("expected the last valid day to be allowed, got %v", err)
+	}
+	err := CheckCheckrideDate(db, documentDate("2026-10-01"))
+	var blocked *CheckrideBlockedError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("expected CheckrideBlockedError, got %v", err)
+	}
+	if !strings.Contains(blocked.Error(), "knowledge test report expires 2026-09-30") {
+		t.Fatalf("unexpected message: %s", blocked.Error())
+	}
+	if got := blocked.LatestCheckride().Format("2006-01-02"); got != "2026-09-30" {
+		t.Fatalf("expected latest checkride 2026-09-30, got %s", got)
+	}
+
+	notes := CheckrideRiskNotes(db, documentDate("2026-08-20"))
+	if len(notes) != 1 || !strings.Contains(notes[0], "41 days after the checkride") {
+		t.Fatalf("expected a risk note for the expiry 41 days out, got %v", notes)
+	}
+}
+
+func TestDocumentWarnings(t *testing.T) {
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	expired := documentDate("2026-05-31")
+	soon := documentDate("2026-06-20")
+	beforeCheckride := documentDate("2026-08-31")
+	later := documentDate("2027-12-31")
+	documents := []model.PilotDocument{
+		{Kind: DocumentMedical, ExpiresOn: &expired},
+		{Kind: DocumentStudentCertificate},
+		{Kind: DocumentKnowledgeTest, ExpiresOn: &beforeCheckride},
+		{Kind: DocumentPhotoID, ExpiresOn: &later},
+	}
+	checkride := documentDate("2026-09-15")
+
+	warnings := DocumentWarnings(documents, checkride, now)
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "expired on May 31, 2026") || !strings.Contains(warnings[1], "before the Sep 15 checkride") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	documents[3].ExpiresOn = &soon
+	warnings = DocumentWarnings(documents, time.Time{}, now)
+	if len(warnings) != 2 || !strings.Contains(warnings[1], "expires in 19 days") {
+		t.Fatalf("expected a 30-day warning, got %v", warnings)
+	}
+}
+
+func TestMOTDCacheDocumentWarnings_FollowSavesAndDeletes(t *testing.T) {
+	db := setupDocumentsTestDB(t)
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	if warnings := LoadMOTDCache().DocumentWarnings(now); len(warnings) != 0 {
+		t.Fatalf("expected no warnings without a cache, got %v", warnings)
+	}
+
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentPhotoID, IssuedOn: documentDate("2020-06-01"), ExpiresOn: documentDate("2026-06-15")}); err != nil {
+		t.Fatalf("save photo ID: %v", err)
+	}
+	warnings := LoadMOTDCache().DocumentWarnings(now)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Government-issued photo ID expires in 14 days") {
+		t.Fatalf("expected cached photo ID warning, got %v", warnings)
+	}
+
+	if err := DeletePilotDocument(db, DocumentPhotoID); err != nil {
+		t.Fatalf("delete photo ID: %v", err)
+	}
+	if warnings := LoadMOTDCache().DocumentWarnings(now); len(warnings) != 0 {
+		t.Fatalf("expected the cache to drop deleted documents, got %v", warnings)
+	}
+}
+
+func TestBuildAutomationStatus_IncludesDocumentWarnings(t *testing.T) {
+	db := setupDocumentsTestDB(t)
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", t.TempDir()+"/plan_template.json")
+	if err := db.Create(&model.StudyPlan{CheckrideDate: documentDate("2026-10-15")}).Error; err != nil {
+		t.Fatalf("create plan: %v", err)
+	}
+	if _, err := SavePilotDocument(db, PilotDocumentInput{Kind: DocumentKnowledgeTest, IssuedOn: documentDate("2024-09-10")}); err != nil {
+		t.Fatalf("save knowledge test: %v", err)
+	}
+
+	status, err := BuildAutomationStatus(db, time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BuildAutomationStatus failed: %v", err)
+	}
+	found := false
+	for _, warning := range status.Status.Warnings {
+		found = found || strings.Contains(warning, "Knowledge test report expires Sep 30, before the Oct 15 checkride")
+	}
+	if !found {
+		t.Fatalf("expected knowledge test warning in automation status, got %v", status.Status.Warnings)
+	}
+}
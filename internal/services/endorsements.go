@@ -0,0 +1,269 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	EndorsementPreSoloKnowledge = "pre-solo-knowledge"
+	EndorsementSolo             = "solo"
+	EndorsementSoloRenewal      = "solo-90-day"
+	EndorsementSoloXC           = "solo-xc"
+	EndorsementSoloXCFlight     = "solo-xc-flight"
+	EndorsementKnowledgeTest    = "knowledge-test"
+	EndorsementPracticalTest    = "practical-test"
+
+	// endorsementWarningDays is how early the dashboard warns about a lapse.
+	endorsementWarningDays = 14
+)
+
+// EndorsementKind describes one student pilot endorsement from AC 61-65.
+// ValidDays > 0 means the endorsement lapses that many days after issue;
+// CalendarMonths > 0 means it lapses at the end of that many calendar months
+// after the month of issue. ChecklistTitle is the checkride checklist item the
+// endorsement completes while it is current.
+type EndorsementKind struct {
+	Key            string
+	Title          string
+	Reference      string
+	ValidDays      int
+	CalendarMonths int
+	Repeatable     bool
+	ChecklistTitle string
+}
+
+// EndorsementKinds lists the endorsements a PPL student collects, in order.
+var EndorsementKinds = []EndorsementKind{
+	{Key: EndorsementPreSoloKnowledge, Title: "Pre-solo aeronautical knowledge", Reference: "61.87(b)", ChecklistTitle: "Pre-solo knowledge test passed"},
+	{Key: EndorsementSolo, Title: "Pre-solo flight training and solo flight", Reference: "61.87(c)(1), 61.87(n)", ValidDays: 90, ChecklistTitle: "Solo endorsements (3 takeoffs/landings)"},
+	{Key: EndorsementSoloRenewal, Title: "Solo flight (additional 90-day period)", Reference: "61.87(p)", ValidDays: 90, Repeatable: true},
+	{Key: EndorsementSoloXC, Title: "Solo cross-country training", Reference: "61.93(c)(1)", ChecklistTitle: "Cross-country endorsements"},
+	{Key: EndorsementSoloXCFlight, Title: "Solo cross-country flight", Reference: "61.93(c)(2)", Repeatable: true},
+	{Key: EndorsementKnowledgeTest, Title: "Knowledge test", Reference: "61.35(a)(1), 61.103(d)", ChecklistTitle: "Knowledge test endorsement"},
+	{Key: EndorsementPracticalTest, Title: "Practical test (flight training within 2 calendar months)", Reference: "61.39(a)(6)(i)-(ii), 61.107(b), 61.109", CalendarMonths: 2, ChecklistTitle: "Practical test endorsement"},
+}
+
+// LookupEndorsementKind returns the kind with the given key.
+func LookupEndorsementKind(key string) (EndorsementKind, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, kind := range EndorsementKinds {
+		if kind.Key == key {
+			return kind, true
+		}
+	}
+	return EndorsementKind{}, false
+}
+
+// ExpiresOn returns when an endorsement issued on issued lapses, or the zero
+// time if it does not expire.
+func (k EndorsementKind) ExpiresOn(issued time.Time) time.Time {
+	day := time.Date(issued.Year(), issued.Month(), issued.Day(), 0, 0, 0, 0, time.UTC)
+	switch {
+	case k.ValidDays > 0:
+		return day.AddDate(0, 0, k.ValidDays)
+	case k.CalendarMonths > 0:
+		return endOfCalendarMonths(day, k.CalendarMonths)
+	default:
+		return time.Time{}
+	}
+}
+
+// EndorsementInput is a new endorsement as entered by the student.
+type EndorsementInput struct {
+	Kind           string
+	IssuedOn       time.Time
+	CFIName        string
+	CFICertificate string
+	CFIExpiresOn   time.Time
+	Notes          string
+}
+
+// AddEndorsement validates and stores an endorsement, computes its
+// expiration, and refreshes the checklist items endorsements drive.
+func AddEndorsement(database *gorm.DB, input EndorsementInput, now time.Time) (model.Endorsement, error) {
+	kind, ok := LookupEndorsementKind(input.Kind)
+	if !ok {
+		return model.Endorsement{}, fmt.Errorf("unknown endorsement kind %q (want one of %s)", input.Kind, strings.Join(endorsementKindKeys(), ", "))
+	}
+	if input.IssuedOn.IsZero() {
+		return model.Endorsement{}, errors.New("issue date is required")
+	}
+	if strings.TrimSpace(input.CFIName) == "" || strings.TrimSpace(input.CFICertificate) == "" {
+		return model.Endorsement{}, errors.New("CFI name and certificate number are required")
+	}
+	if !input.CFIExpiresOn.IsZero() && input.CFIExpiresOn.Before(input.IssuedOn) {
+		return model.Endorsement{}, fmt.Errorf("CFI certificate expired %s, before the endorsement was issued", input.CFIExpiresOn.Format("2006-01-02"))
+	}
+	if kind.Key == EndorsementSoloXCFlight && strings.TrimSpace(input.Notes) == "" {
+		return model.Endorsement{}, errors.New("solo cross-country flight endorsements need the route in notes (e.g. KFXE-KPBI-KFXE)")
+	}
+
+	endorsement := model.Endorsement{
+		Kind:           kind.Key,
+		IssuedOn:       dateOnlyUTC(input.IssuedOn),
+		CFIName:        strings.TrimSpace(input.CFIName),
+		CFICertificate: strings.ToUpper(strings.TrimSpace(input.CFICertificate)),
+		Notes:          strings.TrimSpace(input.Notes),
+	}
+	if expires := kind.ExpiresOn(input.IssuedOn); !expires.IsZero() {
+		endorsement.ExpiresOn = &expires
+	}
+	if !input.CFIExpiresOn.IsZero() {
+		cfiExpires := dateOnlyUTC(input.CFIExpiresOn)
+		endorsement.CFIExpiresOn = &cfiExpires
+	}
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if !kind.Repeatable {
+			// A re-issued one-time endorsement replaces the earlier record.
+			if err := tx.Where("kind = ?", kind.Key).Delete(&model.Endorsement{}).Error; err != nil {
+				return fmt.Errorf("replace endorsement: %w", err)
+			}
+		}
+		if err := tx.Create(&endorsement).Error; err != nil {
+			return fmt.Errorf("save endorsement: %w", err)
+		}
+		return SyncEndorsementChecklist(tx, now)
+	})
+	return endorsement, err
+}
+
+// DeleteEndorsement removes an endorsement and refreshes the checklist.
+func DeleteEndorsement(database *gorm.DB, id uint, now time.Time) error {
+	return database.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&model.Endorsement{}, id)
+		if result.Error != nil {
+			return fmt.Errorf("delete endorsement: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("endorsement %d not found", id)
+		}
+		return SyncEndorsementChecklist(tx, now)
+	})
+}
+
+// ListEndorsements returns all endorsements in issue order.
+func ListEndorsements(database *gorm.DB) ([]model.Endorsement, error) {
+	var endorsements []model.Endorsement
+	if err := database.Order("issued_on asc, id asc").Find(&endorsements).Error; err != nil {
+		return nil, fmt.Errorf("load endorsements: %w", err)
+	}
+	return endorsements, nil
+}
+
+// EndorsementCurrent reports whether e is valid on now.
+func EndorsementCurrent(e model.Endorsement, now time.Time) bool {
+	return e.ExpiresOn == nil || !e.ExpiresOn.Before(dateOnlyUTC(now))
+}
+
+// SyncEndorsementChecklist completes or clears the checklist items that
+// endorsements drive. Items whose endorsement has never been recorded keep
+// whatever the student set by hand. Solo renewals keep the solo item current.
+func SyncEndorsementChecklist(database *gorm.DB, now time.Time) error {
+	endorsements, err := ListEndorsements(database)
+	if err != nil {
+		return err
+	}
+
+	recorded := map[string]bool{}
+	current := map[string]bool{}
+	for _, e := range endorsements {
+		key := e.Kind
+		if key == EndorsementSoloRenewal {
+			key = EndorsementSolo
+		}
+		recorded[key] = true
+		if EndorsementCurrent(e, now) {
+			current[key] = true
+		}
+	}
+
+	for _, kind := range EndorsementKinds {
+		if kind.ChecklistTitle == "" || !recorded[kind.Key] {
+			continue
+		}
+		var item model.ChecklistItem
+		err := database.Where("title = ?", kind.ChecklistTitle).First(&item).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			item = model.ChecklistItem{Category: model.CategoryFlight, Title: kind.ChecklistTitle, Completed: current[kind.Key]}
+			if err := database.Create(&item).Error; err != nil {
+				return fmt.Errorf("create checklist item %q: %w", kind.ChecklistTitle, err)
+			}
+		case err != nil:
+			return fmt.Errorf("load checklist item %q: %w", kind.ChecklistTitle, err)
+		case item.Completed != current[kind.Key]:
+			if err := database.Model(&item).Update("completed", current[kind.Key]).Error; err != nil {
+				return fmt.Errorf("update checklist item %q: %w", kind.ChecklistTitle, err)
+			}
+		}
+	}
+	return nil
+}
+
+// EndorsementWarnings returns dashboard warnings for solo and practical test
+// endorsements that have lapsed or lapse within two weeks. Only the latest
+// endorsement of each kind matters, so a renewed solo endorsement silences
+// the warning for the one it replaced.
+func EndorsementWarnings(endorsements []model.Endorsement, now time.Time) []string {
+	today := dateOnlyUTC(now)
+	latest := map[string]model.Endorsement{}
+	for _, e := range endorsements {
+		key := e.Kind
+		if key == EndorsementSoloRenewal {
+			key = EndorsementSolo
+		}
+		if e.ExpiresOn == nil {
+			continue
+		}
+		if previous, ok := latest[key]; !ok || e.ExpiresOn.After(*previous.ExpiresOn) {
+			latest[key] = e
+		}
+	}
+
+	keys := make([]string, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var warnings []string
+	for _, key := range keys {
+		e := latest[key]
+		kind, _ := LookupEndorsementKind(key)
+		days := int(e.ExpiresOn.Sub(today).Hours() / 24)
+		switch {
+		case days < 0:
+			warnings = append(warnings, fmt.Sprintf("%s endorsement lapsed on %s — ask your CFI to renew it", kind.Title, e.ExpiresOn.Format("Jan 2")))
+		case days <= endorsementWarningDays:
+			warnings = append(warnings, fmt.Sprintf("%s endorsement lapses in %d days (%s)", kind.Title, days, e.ExpiresOn.Format("Jan 2")))
+		}
+	}
+	for _, e := range endorsements {
+		if e.CFIExpiresOn != nil && e.CFIExpiresOn.Before(today) && EndorsementCurrent(e, now) {
+			kind, _ := LookupEndorsementKind(e.Kind)
+			warnings = append(warnings, fmt.Sprintf("CFI %s's certificate on the %s endorsement expired %s", e.CFIName, strings.ToLower(kind.Title), e.CFIExpiresOn.Format("Jan 2")))
+		}
+	}
+	return warnings
+}
+
+func endorsementKindKeys() []string {
+	keys := make([]string, 0, len(EndorsementKinds))
+	for _, kind := range EndorsementKinds {
+		keys = append(keys, kind.Key)
+	}
+	return keys
+}
+
+func dateOnlyUTC(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
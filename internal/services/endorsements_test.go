@@ -0,0 +1,106 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestEndorsementKindExpiresOn(t *testing.T) {
+	solo, _ := LookupEndorsementKind(EndorsementSolo)
+	if got := solo.ExpiresOn(time.Date(2026, 1, 15, 14, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("solo endorsement should last 90 days, got %s", got)
+	}
+
+	practical, _ := LookupEndorsementKind(EndorsementPracticalTest)
+	if got := practical.ExpiresOn(time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("practical test endorsement should last through the 2nd calendar month, got %s", got)
+	}
+
+	xc, _ := LookupEndorsementKind(EndorsementSoloXC)
+	if !xc.ExpiresOn(time.Now()).IsZero() {
+		t.Fatal("solo cross-country training endorsement should not expire")
+	}
+}
+
+func TestAddEndorsement_DrivesChecklistAndWarnings(t *testing.T) {
+	db := setupEventsTestDB(t)
+	if err := db.AutoMigrate(&model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.ChecklistItem{Category: model.CategoryFlight, Title: "Solo endorsements (3 takeoffs/landings)"})
+
+	issued := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := issued.AddDate(0, 0, 80)
+	input := EndorsementInput{Kind: EndorsementSolo, IssuedOn: issued, CFIName: "Jane Doe", CFICertificate: "1234567cfi", CFIExpiresOn: issued.AddDate(1, 0, 0)}
+	endorsement, err := AddEndorsement(db, input, now)
+	if err != nil {
+		t.Fatalf("AddEndorsement returned error: %v", err)
+	}
+	if endorsement.CFICertificate != "1234567CFI" || endorsement.ExpiresOn == nil {
+		t.Fatalf("unexpected endorsement: %+v", endorsement)
+	}
+
+	var item model.ChecklistItem
+	db.Where("title = ?", "Solo endorsements (3 takeoffs/landings)").First(&item)
+	if !item.Completed {
+		t.Fatal("expected current solo endorsement to complete the checklist item")
+	}
+
+	endorsements, _ := ListEndorsements(db)
+	warnings := EndorsementWarnings(endorsements, now)
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "lapses in 10 days") {
+		t.Fatalf("expected a lapse warning, got %v", warnings)
+	}
+
+	later := issued.AddDate(0, 0, 95)
+	if err := SyncEndorsementChecklist(db, later); err != nil {
+		t.Fatalf("SyncEndorsementChecklist returned error: %v", err)
+	}
+	db.First(&item, item.ID)
+	if item.Completed {
+		t.Fatal("expected lapsed solo endorsement to clear the checklist item")
+	}
+
+	renewal := input
+	renewal.Kind = EndorsementSoloRenewal
+	renewal.IssuedOn = later
+	if _, err := AddEndorsement(db, renewal, later); err != nil {
+		t.Fatalf("AddEndorsement renewal returned error: %v", err)
+	}
+	db.First(&item, item.ID)
+	endorsements, _ = ListEndorsements(db)
+	if !item.Completed || len(EndorsementWarnings(endorsements, later)) != 0 {
+		t.Fatalf("expected 90-day renewal to restore the solo item and silence warnings, got %v", EndorsementWarnings(endorsements, later))
+	}
+
+	var practical model.ChecklistItem
+	if _, err := AddEndorsement(db, EndorsementInput{Kind: EndorsementPracticalTest, IssuedOn: later, CFIName: "Jane Doe", CFICertificate: "1234567CFI"}, later); err != nil {
+		t.Fatalf("AddEndorsement practical returned error: %v", err)
+	}
+	if err := db.Where("title = ?", "Practical test endorsement").First(&practical).Error; err != nil || !practical.Completed {
+		t.Fatalf("expected practical test checklist item to be created and completed, got %+v (%v)", practical, err)
+	}
+}
+
+func TestAddEndorsement_Validates(t *testing.T) {
+	db := setupEventsTestDB(t)
+	if err := db.AutoMigrate(&model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	issued := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]EndorsementInput{
+		"unknown endorsement kind": {Kind: "tailwheel", IssuedOn: issued, CFIName: "A", CFICertificate: "1"},
+		"CFI name and certificate": {Kind: EndorsementSolo, IssuedOn: issued},
+		"before the endorsement":   {Kind: EndorsementSolo, IssuedOn: issued, CFIName: "A", CFICertificate: "1", CFIExpiresOn: issued.AddDate(0, 0, -1)},
+		"need the route":           {Kind: EndorsementSoloXCFlight, IssuedOn: issued, CFIName: "A", CFICertificate: "1"},
+	}
+	for want, input := range cases {
+		if _, err := AddEndorsement(db, input, issued); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected error containing %q, got %v", want, err)
+		}
+	}
+}
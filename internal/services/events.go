@@ -0,0 +1,172 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	EventTaskCompleted     = "task.completed"
+	EventTaskUncompleted   = "task.uncompleted"
+	EventTaskRescheduled   = "task.rescheduled"
+	EventTaskCreated       = "task.created"
+	EventTaskDeleted       = "task.deleted"
+	EventPlanRegenerated   = "plan.regenerated"
+	EventQuizAnswered      = "quiz.answered"
+	EventBudgetChanged     = "budget.changed"
+	EventMilestoneAchieved = "milestone.achieved"
+	EventMilestoneReopened = "milestone.reopened"
+
+	OutboxStatusPending   = "pending"
+	OutboxStatusDelivered = "delivered"
+	OutboxStatusFailed    = "failed"
+)
+
+// EventTypes lists every event the outbox can carry, in documentation order.
+var EventTypes = []string{
+	EventTaskCompleted,
+	EventTaskUncompleted,
+	EventTaskRescheduled,
+	EventTaskCreated,
+	EventTaskDeleted,
+	EventPlanRegenerated,
+	EventQuizAnswered,
+	EventBudgetChanged,
+	EventMilestoneAchieved,
+	EventMilestoneReopened,
+}
+
+// TaskEventPayload describes a task completion change, or a task that was
+// added or deleted.
+type TaskEventPayload struct {
+	TaskID      uint   `json:"task_id"`
+	StudyPlanID uint   `json:"study_plan_id"`
+	Date        string `json:"date"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Completed   bool   `json:"completed"`
+	Source      string `json:"source"`
+}
+
+// PlanEventPayload describes a regenerated study plan.
+type PlanEventPayload struct {
+	StudyPlanID   uint   `json:"study_plan_id"`
+	CheckrideDate string `json:"checkride_date"`
+	TaskCount     int    `json:"task_count"`
+	Source        string `json:"source"`
+}
+
+// QuizEventPayload describes a recorded MOTD quiz attempt.
+type QuizEventPayload struct {
+	Date      string `json:"date"`
+	ACSCode   string `json:"acs_code"`
+	Selected  string `json:"selected,omitempty"`
+	Correct   string `json:"correct"`
+	IsCorrect bool   `json:"is_correct"`
+	Skipped   bool   `json:"skipped"`
+}
+
+// BudgetEventPayload describes a budget item change.
+type BudgetEventPayload struct {
+	ItemType string  `json:"item_type"`
+	Amount   float64 `json:"amount"`
+	Source   string  `json:"source"`
+}
+
+// RecordEvent appends an event to the durable outbox. Pass the active
+// transaction so the event commits atomically with the change it describes.
+func RecordEvent(database *gorm.DB, eventType string, payload any) error {
+	if database == nil {
+		return errors.New("database is required")
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode %s payload: %w", eventType, err)
+	}
+
+	event := model.OutboxEvent{
+		EventType:     eventType,
+		PayloadJSON:   string(encoded),
+		Status:        OutboxStatusPending,
+		NextAttemptAt: time.Now().UTC(),
+	}
+	if err := database.Create(&event).Error; err != nil {
+		return fmt.Errorf("write %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// ToggleTaskCompletion flips a task's completion state and records the
+// completion history entry and matching outbox event in the same
+// transaction.
+func ToggleTaskCompletion(database *gorm.DB, id uint, source string) (model.DailyTask, error) {
+	return updateTaskCompletion(database, id, source, func(current bool) bool { return !current })
+}
+
+// SetTaskCompletion sets a task's completion state, recording an event only
+// when the state actually changes.
+func SetTaskCompletion(database *gorm.DB, id uint, completed bool, source string) (model.DailyTask, error) {
+	return updateTaskCompletion(database, id, source, func(bool) bool { return completed })
+}
+
+func updateTaskCompletion(database *gorm.DB, id uint, source string, next func(current bool) bool) (model.DailyTask, error) {
+	var task model.DailyTask
+	if database == nil {
+		return task, errors.New("database is required")
+	}
+
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, id).Error; err != nil {
+			return err
+		}
+
+		completed := next(task.Completed)
+		if completed == task.Completed {
+			return nil
+		}
+		task.Completed = completed
+		task.Sequence++
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if err := recordTaskProgress(tx, task, source, time.Now()); err != nil {
+			return err
+		}
+
+		eventType := EventTaskUncompleted
+		if task.Completed {
+			eventType = EventTaskCompleted
+		}
+		return RecordEvent(tx, eventType, newTaskEventPayload(task, source))
+	})
+	return task, err
+}
+
+// RecordPlanRegenerated records a plan.regenerated event for the given plan.
+func RecordPlanRegenerated(database *gorm.DB, plan model.StudyPlan, taskCount int, source string) error {
+	return RecordEvent(database, EventPlanRegenerated, PlanEventPayload{
+		StudyPlanID:   plan.ID,
+		CheckrideDate: plan.CheckrideDate.UTC().Format("2006-01-02"),
+		TaskCount:     taskCount,
+		Source:        source,
+	})
+}
+
+func newTaskEventPayload(task model.DailyTask, source string) TaskEventPayload {
+	return TaskEventPayload{
+		TaskID:      task.ID,
+		StudyPlanID: task.StudyPlanID,
+		Date:        task.Date.UTC().Format("2006-01-02"),
+		Category:    task.Category,
+		Title:       task.Title,
+		Completed:   task.Completed,
+		Source:      source,
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestToggleTaskCompletion_RecordsOutboxEvents(t *testing.T) {
+	db := setupEventsTestDB(t)
+	task := model.DailyTask{Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Area 2: Regulations - Knowledge Review"}
+	if err := db.Create(&task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	toggled, err := ToggleTaskCompletion(db, task.ID, "web")
+	if err != nil {
+		t.Fatalf("ToggleTaskCompletion returned error: %v", err)
+	}
+	if !toggled.Completed {
+		t.Fatal("expected task to be completed after first toggle")
+	}
+	if _, err := ToggleTaskCompletion(db, task.ID, "tui"); err != nil {
+		t.Fatalf("second toggle returned error: %v", err)
+	}
+
+	var events []model.OutboxEvent
+	if err := db.Order("id asc").Find(&events).Error; err != nil {
+		t.Fatalf("load events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 outbox events, got %d", len(events))
+	}
+	if events[0].EventType != EventTaskCompleted || events[1].EventType != EventTaskUncompleted {
+		t.Fatalf("unexpected event types: %q, %q", events[0].EventType, events[1].EventType)
+	}
+	if events[0].Status != OutboxStatusPending {
+		t.Fatalf("expected pending status, got %q", events[0].Status)
+	}
+
+	var payload TaskEventPayload
+	if err := json.Unmarshal([]byte(events[1].PayloadJSON), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.TaskID != task.ID || payload.Completed || payload.Source != "tui" || payload.Date != "2026-05-01" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestSetTaskCompletion_RecordsOnlyChanges(t *testing.T) {
+	db := setupEventsTestDB(t)
+	task := model.DailyTask{Date: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), Category: "CFI Flight", Title: "Pattern work"}
+	if err := db.Create(&task).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		updated, err := SetTaskCompletion(db, task.ID, true, "google")
+		if err != nil {
+			t.Fatalf("SetTaskCompletion returned error: %v", err)
+		}
+		if !updated.Completed {
+			t.Fatal("expected task to be completed")
+		}
+	}
+
+	var count int64
+	if err := db.Model(&model.OutboxEvent{}).Count(&count).Error; err != nil {
+		t.Fatalf("count events: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 outbox event for a single state change, got %d", count)
+	}
+}
+
+func TestToggleTaskCompletion_MissingTaskWritesNoEvent(t *testing.T) {
+	db := setupEventsTestDB(t)
+	if _, err := ToggleTaskCompletion(db, 999, "web"); err == nil {
+		t.Fatal("expected error for missing task")
+	}
+
+	var count int64
+	db.Model(&model.OutboxEvent{}).Count(&count)
+	if count != 0 {
+		t.Fatalf("expected no events, got %d", count)
+	}
+}
+
+func setupEventsTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.Progress{}, &model.OutboxEvent{}, &model.ChecklistItem{}, &model.Milestone{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return db
+}
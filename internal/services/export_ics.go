@@ -13,19 +13,44 @@ import (
 	"ppl-study-planner/internal/model"
 )
 
+const (
+	ICSExportModeEvents = "events"
+	ICSExportModeTodos  = "todos"
+)
+
+// ICSExportOptions selects where the file goes and whether tasks become
+// calendar events (VEVENT, the default) or checkable to-dos (VTODO).
+type ICSExportOptions struct {
+	OutputDir string
+	Mode      string
+	// Location is the time zone tasks are scheduled in, usually
+	// SchoolAirportLocation; nil means UTC.
+	Location *time.Location
+}
+
 // ICSExportResult contains metadata for a generated ICS file.
 type ICSExportResult struct {
 	Path       string
+	Mode       string
 	EventCount int
 }
 
 // ExportICS writes study tasks into an RFC5545-compatible .ics file.
-func ExportICS(tasks []model.DailyTask, outputDir string) (ICSExportResult, error) {
+func ExportICS(tasks []model.DailyTask, opts ICSExportOptions) (ICSExportResult, error) {
 	if len(tasks) == 0 {
 		return ICSExportResult{}, errors.New("no tasks available for ICS export")
 	}
 
-	resolvedOutputDir, err := ResolveArtifactOutputDir(outputDir)
+	mode := strings.ToLower(strings.TrimSpace(opts.Mode))
+	switch mode {
+	case "":
+		mode = ICSExportModeEvents
+	case ICSExportModeEvents, ICSExportModeTodos:
+	default:
+		return ICSExportResult{}, fmt.Errorf("unsupported ICS export mode %q", opts.Mode)
+	}
+
+	resolvedOutputDir, err := ResolveArtifactOutputDir(opts.OutputDir)
 	if err != nil {
 		return ICSExportResult{}, fmt.Errorf("resolve export directory: %w", err)
 	}
@@ -41,28 +66,17 @@ func ExportICS(tasks []model.DailyTask, outputDir string) (ICSExportResult, erro
 
 	nowUTC := time.Now().UTC()
 	for _, task := range tasks {
-		uid := deterministicTaskUID(task)
-		event := cal.AddEvent(uid)
-		event.SetDtStampTime(nowUTC)
-
-		startUTC, endUTC := taskWindowUTC(task.Date)
-		event.SetStartAt(startUTC)
-		event.SetEndAt(endUTC)
-
-		title := strings.TrimSpace(task.Title)
-		if title == "" {
-			title = "Study Task"
-		}
-		event.SetSummary(title)
-
-		desc := strings.TrimSpace(task.Description)
-		if desc == "" {
-			desc = strings.TrimSpace(task.Category)
+		if mode == ICSExportModeTodos {
+			addTaskTodo(cal, task, nowUTC, opts.Location)
+		} else {
+			addTaskEvent(cal, task, nowUTC, opts.Location)
 		}
-		event.SetDescription(desc)
 	}
 
 	fileName := fmt.Sprintf("study-plan-%s.ics", nowUTC.Format("20060102-150405"))
+	if mode == ICSExportModeTodos {
+		fileName = fmt.Sprintf("study-todos-%s.ics", nowUTC.Format("20060102-150405"))
+	}
 	outputPath := filepath.Join(resolvedOutputDir, fileName)
 
 	if err := os.WriteFile(outputPath, []byte(cal.Serialize()), 0o644); err != nil {
@@ -71,10 +85,87 @@ func ExportICS(tasks []model.DailyTask, outputDir string) (ICSExportResult, erro
 
 	return ICSExportResult{
 		Path:       outputPath,
+		Mode:       mode,
 		EventCount: len(tasks),
 	}, nil
 }
 
+// addTaskEvent maps a study task onto a VEVENT in cal. It is shared by the
+// file export and live calendar sync targets so every output uses the same
+// UID, time window, and text.
+func addTaskEvent(cal *ics.Calendar, task model.DailyTask, stamp time.Time, loc *time.Location) *ics.VEvent {
+	event := cal.AddEvent(deterministicTaskUID(task))
+	event.SetDtStampTime(stamp)
+
+	startUTC, endUTC := taskWindowUTC(task.Date, loc)
+	event.SetStartAt(startUTC)
+	event.SetEndAt(endUTC)
+	event.SetSummary(taskICSSummary(task))
+	event.SetDescription(taskICSDescription(task))
+	return event
+}
+
+// addTaskTodo maps a study task onto a VTODO in cal, due at the end of the
+// task's study window.
+func addTaskTodo(cal *ics.Calendar, task model.DailyTask, stamp time.Time, loc *time.Location) *ics.VTodo {
+	todo := cal.AddTodo(deterministicTaskUID(task))
+	todo.SetDtStampTime(stamp)
+
+	startUTC, endUTC := taskWindowUTC(task.Date, loc)
+	todo.SetStartAt(startUTC)
+	todo.SetDueAt(endUTC)
+	todo.SetSummary(taskICSSummary(task))
+	todo.SetDescription(taskICSDescription(task))
+	todo.SetPriority(taskICSPriority(task))
+	if category := strings.TrimSpace(task.Category); category != "" {
+		todo.SetProperty(ics.ComponentPropertyCategories, category)
+	}
+	if task.Completed {
+		todo.SetStatus(ics.ObjectStatusCompleted)
+		todo.SetPercentComplete(100)
+		todo.SetCompletedAt(taskLastModified(task))
+	} else {
+		todo.SetStatus(ics.ObjectStatusNeedsAction)
+	}
+	return todo
+}
+
+// taskICSPriority maps categories onto RFC 5545 priorities (1 highest, 9
+// lowest). Scheduled flights cost money and instructor time, so they rank
+// above self-study. A high or low priority set on the task wins.
+func taskICSPriority(task model.DailyTask) int {
+	switch task.Priority {
+	case TaskPriorityHigh:
+		return 1
+	case TaskPriorityLow:
+		return 9
+	}
+	switch strings.TrimSpace(task.Category) {
+	case "CFI Flights":
+		return 1
+	case "Theory", "Chair Flying":
+		return 5
+	default:
+		return 9
+	}
+}
+
+func taskICSSummary(task model.DailyTask) string {
+	title := strings.TrimSpace(task.Title)
+	if title == "" {
+		title = "Study Task"
+	}
+	return title
+}
+
+func taskICSDescription(task model.DailyTask) string {
+	desc := strings.TrimSpace(task.Description)
+	if desc == "" {
+		desc = strings.TrimSpace(task.Category)
+	}
+	return desc
+}
+
 func deterministicTaskUID(task model.DailyTask) string {
 	datePart := task.Date.UTC().Format("20060102")
 	if task.ID != 0 {
@@ -90,8 +181,13 @@ func deterministicTaskUID(task model.DailyTask) string {
 	return fmt.Sprintf("task-%s-%s@openppl", titlePart, datePart)
 }
 
-func taskWindowUTC(taskDate time.Time) (time.Time, time.Time) {
-	start := time.Date(taskDate.Year(), taskDate.Month(), taskDate.Day(), 9, 0, 0, 0, time.UTC)
+// taskWindowUTC is the 09:00-09:30 study block on the task's day in loc
+// (UTC when nil), as UTC instants.
+func taskWindowUTC(taskDate time.Time, loc *time.Location) (time.Time, time.Time) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	start := time.Date(taskDate.Year(), taskDate.Month(), taskDate.Day(), 9, 0, 0, 0, loc).UTC()
 	end := start.Add(30 * time.Minute)
 	return start, end
 }
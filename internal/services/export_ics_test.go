@@ -29,7 +29,7 @@ func TestICSExport_GeneratesEnvelopeAndEvents(t *testing.T) {
 		},
 	}
 
-	result, err := ExportICS(tasks, testICSOutputDir(t))
+	result, err := ExportICS(tasks, ICSExportOptions{OutputDir: testICSOutputDir(t)})
 	if err != nil {
 		t.Fatalf("ExportICS failed: %v", err)
 	}
@@ -76,7 +76,7 @@ func TestICSExport_UsesUTCDateTimeFields(t *testing.T) {
 		},
 	}
 
-	result, err := ExportICS(tasks, testICSOutputDir(t))
+	result, err := ExportICS(tasks, ICSExportOptions{OutputDir: testICSOutputDir(t)})
 	if err != nil {
 		t.Fatalf("ExportICS failed: %v", err)
 	}
@@ -102,6 +102,26 @@ func TestICSExport_UsesUTCDateTimeFields(t *testing.T) {
 	}
 }
 
+func TestICSExport_SchedulesInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	tasks := []model.DailyTask{{ID: 3, Date: time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), Title: "Pacific morning"}}
+
+	result, err := ExportICS(tasks, ICSExportOptions{OutputDir: testICSOutputDir(t), Location: loc})
+	if err != nil {
+		t.Fatalf("ExportICS failed: %v", err)
+	}
+	b, err := os.ReadFile(result.Path)
+	if err != nil {
+		t.Fatalf("reading ICS output failed: %v", err)
+	}
+	if !strings.Contains(string(b), "DTSTART:20260115T170000Z") {
+		t.Fatalf("expected 09:00 Pacific (17:00Z), got:\n%s", b)
+	}
+}
+
 func TestICSDeterministicUID(t *testing.T) {
 	task := model.DailyTask{
 		ID:    88,
@@ -134,3 +154,46 @@ func testICSOutputDir(t *testing.T) string {
 
 	return dir
 }
+
+func TestICSExport_TodoModeWritesTaskFields(t *testing.T) {
+	tasks := []model.DailyTask{
+		{ID: 21, Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Stalls", Completed: true, UpdatedAt: time.Date(2026, 4, 2, 18, 0, 0, 0, time.UTC)},
+		{ID: 22, Date: time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Airspace"},
+	}
+
+	result, err := ExportICS(tasks, ICSExportOptions{OutputDir: testICSOutputDir(t), Mode: ICSExportModeTodos})
+	if err != nil {
+		t.Fatalf("ExportICS failed: %v", err)
+	}
+	if result.Mode != ICSExportModeTodos {
+		t.Fatalf("expected todos mode, got %q", result.Mode)
+	}
+
+	b, err := os.ReadFile(result.Path)
+	if err != nil {
+		t.Fatalf("reading ICS output failed: %v", err)
+	}
+	content := string(b)
+	for _, token := range []string{
+		"BEGIN:VTODO",
+		"UID:task-21-20260402@openppl",
+		"DUE:20260402T093000Z",
+		"PRIORITY:1",
+		"PRIORITY:5",
+		"CATEGORIES:CFI Flights",
+		"STATUS:COMPLETED",
+		"COMPLETED:20260402T180000Z",
+		"STATUS:NEEDS-ACTION",
+	} {
+		if !strings.Contains(content, token) {
+			t.Fatalf("expected VTODO output to contain %q:\n%s", token, content)
+		}
+	}
+	if strings.Contains(content, "BEGIN:VEVENT") {
+		t.Fatal("todo mode must not emit VEVENT components")
+	}
+
+	if _, err := ExportICS(tasks, ICSExportOptions{OutputDir: testICSOutputDir(t), Mode: "journal"}); err == nil {
+		t.Fatal("expected error for unsupported mode")
+	}
+}
@@ -40,10 +40,14 @@ end run`
 type RemindersExportOptions struct {
 	ListName string
 	Timeout  time.Duration
+	// Location is the time zone remote targets schedule tasks in; nil
+	// means UTC. Apple Reminders and Taskwarrior use the machine's zone.
+	Location *time.Location
 }
 
 // RemindersExportResult contains reminder export metadata.
 type RemindersExportResult struct {
+	Target   string
 	ListName string
 	Created  int
 }
@@ -56,7 +60,7 @@ type RemindersExportError struct {
 }
 
 func (e *RemindersExportError) Error() string {
-	base := "apple reminders export failed"
+	base := "reminders export failed"
 	if e.Kind != "" {
 		base = base + ": " + e.Kind
 	}
@@ -108,7 +112,7 @@ func exportAppleRemindersWithRunner(tasks []model.DailyTask, opts RemindersExpor
 
 	timeout := opts.Timeout
 	if timeout <= 0 {
-		timeout = 12 * time.Second
+		timeout = defaultReminderTimeout
 	}
 
 	created := 0
@@ -153,7 +157,7 @@ func exportAppleRemindersWithRunner(tasks []model.DailyTask, opts RemindersExpor
 		created++
 	}
 
-	return RemindersExportResult{ListName: listName, Created: created}, nil
+	return RemindersExportResult{Target: ReminderTargetApple, ListName: listName, Created: created}, nil
 }
 
 func isPermissionError(output string) bool {
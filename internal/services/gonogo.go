@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	GoDecision   = "GO"
+	NoGoDecision = "NO-GO"
+
+	GoNoGoOK      = "ok"
+	GoNoGoCaution = "caution"
+	GoNoGoFail    = "no-go"
+
+	PAVEPilot       = "Pilot"
+	PAVEAircraft    = "Aircraft"
+	PAVEEnvironment = "enVironment"
+
+	// defaultGoNoGoHours is how long a flight is assumed to last when the
+	// request does not say.
+	defaultGoNoGoHours = 2
+	// goNoGoMETARHours is how far from now a departure may be for the
+	// current METAR to still say something about it.
+	goNoGoMETARHours = 2
+)
+
+// IMSAFEChecklist is the pilot self-assessment printed with every go/no-go.
+var IMSAFEChecklist = []string{
+	"Illness: any symptoms, even a cold or congestion?",
+	"Medication: anything taken in the last 24 hours that is not FAA-approved?",
+	"Stress: work, money or family pressure on your mind?",
+	"Alcohol: 8 hours bottle to throttle, and no lingering effects?",
+	"Fatigue: slept well and rested enough for the whole flight?",
+	"Emotion/Eating: calm, fed and hydrated?",
+}
+
+// ExternalPressuresChecklist prompts the E of PAVE, which only the pilot can
+// answer.
+var ExternalPressuresChecklist = []string{
+	"Is there a deadline, passenger or appointment pushing you to go?",
+	"Would you cancel this flight if it were just for fun?",
+	"Do you have an alternate plan (another day, another airport) you are comfortable with?",
+}
+
+// GoNoGoRequest describes the planned flight. Airport defaults to the
+// school airport, At to now and Hours to two.
+type GoNoGoRequest struct {
+	Airport string
+	Solo    bool
+	Night   bool
+	At      time.Time
+	Hours   int
+	Tail    string
+}
+
+// GoNoGoItem is one PAVE finding. Status is GoNoGoOK, GoNoGoCaution or
+// GoNoGoFail.
+type GoNoGoItem struct {
+	Area   string
+	Item   string
+	Status string
+	Detail string
+}
+
+// GoNoGoResult is the evaluation of one planned flight.
+type GoNoGoResult struct {
+	Request  GoNoGoRequest
+	Minimums MinimumsSet
+	METAR    *METAR
+	TAF      *TAF
+	Items    []GoNoGoItem
+}
+
+// Decision is NO-GO when any item fails and GO otherwise; cautions are for
+// the pilot to weigh.
+func (r GoNoGoResult) Decision() string {
+	for _, item := range r.Items {
+		if item.Status == GoNoGoFail {
+			return NoGoDecision
+		}
+	}
+	return GoDecision
+}
+
+// Reasons lists the failed items, then the cautions, as "item: detail".
+func (r GoNoGoResult) Reasons() []string {
+	var failed, cautions []string
+	for _, item := range r.Items {
+		switch item.Status {
+		case GoNoGoFail:
+			failed = append(failed, item.Item+": "+item.Detail)
+		case GoNoGoCaution:
+			cautions = append(cautions, item.Item+": "+item.Detail)
+		}
+	}
+	return append(failed, cautions...)
+}
+
+// EvaluateGoNoGo checks a planned flight against the pilot's records, the
+// aircraft's inspections and the airport's METAR and TAF, using the solo or
+// dual minimums. Missing weather is a no-go: the decision cannot be made
+// without it.
+func EvaluateGoNoGo(ctx context.Context, database *gorm.DB, provider WeatherProvider, minimums PersonalMinimums, request GoNoGoRequest, now time.Time) (GoNoGoResult, error) {
+	request.Airport = strings.ToUpper(strings.TrimSpace(request.Airport))
+	if request.Airport == "" {
+		airport, err := SchoolAirport(database)
+		if err != nil {
+			return GoNoGoResult{}, err
+		}
+		request.Airport = airport
+	}
+	if request.Airport == "" {
+		return GoNoGoResult{}, errors.New("no airport: pass one or set the school airport")
+	}
+	minimums = minimums.ForAirport(request.Airport)
+	if request.At.IsZero() {
+		request.At = now
+	}
+	if request.Hours <= 0 {
+		request.Hours = defaultGoNoGoHours
+	}
+
+	result := GoNoGoResult{Request: request, Minimums: minimums.Set(request.Solo)}
+	pilot, err := pilotGoNoGoItems(database, request, result.Minimums)
+	if err != nil {
+		return GoNoGoResult{}, err
+	}
+	aircraft, err := aircraftGoNoGoItems(database, request)
+	if err != nil {
+		return GoNoGoResult{}, err
+	}
+	result.Items = append(pilot, aircraft...)
+	result.Items = append(result.Items, result.environmentItems(ctx, provider, minimums, now)...)
+	return result, nil
+}
+
+func pilotGoNoGoItems(database *gorm.DB, request GoNoGoRequest, set MinimumsSet) ([]GoNoGoItem, error) {
+	var items []GoNoGoItem
+	if request.Night && !set.Night {
+		items = append(items, GoNoGoItem{Area: PAVEPilot, Item: "Night", Status: GoNoGoFail, Detail: "night flight is outside your " + minimumsName(request.Solo) + " minimums"})
+	}
+	if !request.Solo {
+		return append(items, GoNoGoItem{Area: PAVEPilot, Item: "Instructor", Status: GoNoGoOK, Detail: "dual lesson; the instructor is pilot in command"}), nil
+	}
+
+	endorsements, err := ListEndorsements(database)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, soloEndorsementItem(endorsements, request.At))
+
+	documents, err := ListPilotDocuments(database)
+	if err != nil {
+		return nil, err
+	}
+	recorded := map[string]model.PilotDocument{}
+	for _, document := range documents {
+		recorded[document.Kind] = document
+	}
+	flightDay := dateOnlyUTC(request.At)
+	for _, key := range []string{DocumentMedical, DocumentStudentCertificate, DocumentPhotoID} {
+		kind, _ := LookupDocumentKind(key)
+		item := GoNoGoItem{Area: PAVEPilot, Item: kind.Title}
+		document, ok := recorded[key]
+		switch {
+		case !ok:
+			item.Status, item.Detail = GoNoGoCaution, "not recorded; carry it on the flight"
+		case document.ExpiresOn == nil:
+			item.Status, item.Detail = GoNoGoOK, "does not expire"
+		case document.ExpiresOn.Before(flightDay):
+			item.Status, item.Detail = GoNoGoFail, "expired "+document.ExpiresOn.Format("2006-01-02")
+		default:
+			item.Status, item.Detail = GoNoGoOK, "valid through "+document.ExpiresOn.Format("2006-01-02")
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// soloEndorsementItem looks for a solo or 90-day renewal endorsement valid
+// on the flight day.
+func soloEndorsementItem(endorsements []model.Endorsement, at time.Time) GoNoGoItem {
+	item := GoNoGoItem{Area: PAVEPilot, Item: "Solo endorsement", Status: GoNoGoFail, Detail: "none recorded"}
+	for _, endorsement := range endorsements {
+		if endorsement.Kind != EndorsementSolo && endorsement.Kind != EndorsementSoloRenewal {
+			continue
+		}
+		if EndorsementCurrent(endorsement, at) {
+			item.Status, item.Detail = GoNoGoOK, "current"
+			if endorsement.ExpiresOn != nil {
+				item.Detail = "valid through " + endorsement.ExpiresOn.Format("2006-01-02")
+			}
+			return item
+		}
+		item.Detail = "lapsed " + endorsement.ExpiresOn.Format("2006-01-02")
+	}
+	return item
+}
+
+// aircraftGoNoGoItems checks the named aircraft, or the only registered one,
+// as of the flight day. Inspections due within the tach margin or without a
+// recorded date are cautions; expired ones fail.
+func aircraftGoNoGoItems(database *gorm.DB, request GoNoGoRequest) ([]GoNoGoItem, error) {
+	var aircraft model.Aircraft
+	if request.Tail != "" {
+		tail, err := NormalizeTailNumber(request.Tail)
+		if err != nil {
+			return nil, err
+		}
+		if aircraft, err = findAircraft(database, tail); err != nil {
+			return nil, err
+		}
+		if err := database.Model(&aircraft).Order("number asc").Association("Directives").Find(&aircraft.Directives); err != nil {
+			return nil, fmt.Errorf("load aircraft %s: %w", tail, err)
+		}
+	} else {
+		registered, err := ListAircraft(database)
+		if err != nil {
+			return nil, err
+		}
+		if len(registered) != 1 {
+			return []GoNoGoItem{{Area: PAVEAircraft, Item: "Aircraft", Status: GoNoGoCaution, Detail: "no aircraft given; check the inspections and ADs in the logbooks"}}, nil
+		}
+		aircraft = registered[0]
+	}
+
+	flightDay := dateOnlyUTC(request.At)
+	var items []GoNoGoItem
+	for _, check := range CheckAirworthiness(aircraft, flightDay, flightDay) {
+		if !check.Failed() {
+			continue
+		}
+		status := GoNoGoCaution
+		if check.Status == AirworthinessOverdue {
+			status = GoNoGoFail
+		}
+		items = append(items, GoNoGoItem{Area: PAVEAircraft, Item: aircraft.TailNumber + " " + strings.ToLower(check.Item), Status: status, Detail: check.Detail})
+	}
+	if len(items) == 0 {
+		items = append(items, GoNoGoItem{Area: PAVEAircraft, Item: aircraft.TailNumber, Status: GoNoGoOK, Detail: "inspections and ADs current"})
+	}
+	return items, nil
+}
+
+func (r *GoNoGoResult) environmentItems(ctx context.Context, provider WeatherProvider, minimums PersonalMinimums, now time.Time) []GoNoGoItem {
+	request := r.Request
+	departure, arrival := request.At, request.At.Add(time.Duration(request.Hours)*time.Hour)
+	var items []GoNoGoItem
+
+	if departure.Sub(now).Abs() < goNoGoMETARHours*time.Hour {
+		item := GoNoGoItem{Area: PAVEEnvironment, Item: "METAR " + request.Airport}
+		if metar, err := provider.METAR(ctx, request.Airport); err != nil {
+			item.Status, item.Detail = GoNoGoFail, "unavailable: "+err.Error()
+		} else {
+			r.METAR = &metar
+			item.Status, item.Detail = weatherStatus(metar.Category, minimums.Violations(metar.WeatherConditions, request.Solo))
+		}
+		items = append(items, item)
+	}
+
+	item := GoNoGoItem{Area: PAVEEnvironment, Item: "TAF " + request.Airport}
+	taf, err := provider.TAF(ctx, request.Airport)
+	switch {
+	case err != nil:
+		item.Status, item.Detail = GoNoGoFail, "unavailable: "+err.Error()
+	default:
+		r.TAF = &taf
+		violations, _ := forecastViolations(taf, minimums, request.Solo, departure, arrival)
+		worst, covered := taf.WorstCategoryBetween(departure, arrival)
+		item.Status, item.Detail = weatherStatus(worst, violations)
+		if len(violations) == 0 && (!covered || taf.ValidTo.Before(arrival)) {
+			item.Status, item.Detail = GoNoGoCaution, "does not cover the whole flight; check again closer to departure"
+		}
+	}
+	return append(items, item)
+}
+
+func weatherStatus(category FlightCategory, violations []string) (string, string) {
+	if len(violations) > 0 {
+		return GoNoGoFail, strings.Join(violations, ", ")
+	}
+	return GoNoGoOK, fmt.Sprintf("%s, within minimums", category)
+}
+
+func minimumsName(solo bool) string {
+	if solo {
+		return "solo"
+	}
+	return "dual"
+}
+
+// RecordGoNoGo logs the decision to the go/no-go history.
+func RecordGoNoGo(database *gorm.DB, result GoNoGoResult, note string) (model.GoNoGoDecision, error) {
+	decision := model.GoNoGoDecision{
+		Airport:  result.Request.Airport,
+		FlightAt: result.Request.At,
+		Solo:     result.Request.Solo,
+		Night:    result.Request.Night,
+		Tail:     strings.ToUpper(strings.TrimSpace(result.Request.Tail)),
+		Decision: result.Decision(),
+		Reasons:  strings.Join(result.Reasons(), "; "),
+		Note:     strings.TrimSpace(note),
+	}
+	if result.METAR != nil {
+		decision.METAR = result.METAR.Raw
+	}
+	if result.TAF != nil {
+		decision.TAF = result.TAF.Raw
+	}
+	if err := database.Create(&decision).Error; err != nil {
+		return model.GoNoGoDecision{}, fmt.Errorf("record go/no-go: %w", err)
+	}
+	return decision, nil
+}
+
+// ListGoNoGoDecisions returns the most recent decisions first, at most limit
+// of them when limit > 0.
+func ListGoNoGoDecisions(database *gorm.DB, limit int) ([]model.GoNoGoDecision, error) {
+	query := database.Order("created_at desc, id desc")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	var decisions []model.GoNoGoDecision
+	if err := query.Find(&decisions).Error; err != nil {
+		return nil, fmt.Errorf("load go/no-go history: %w", err)
+	}
+	return decisions, nil
+}
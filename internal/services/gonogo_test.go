@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupGoNoGoTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.Endorsement{}, &model.PilotDocument{}, &model.Aircraft{}, &model.AircraftAD{}, &model.GoNoGoDecision{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+	return db
+}
+
+func goNoGoItem(result GoNoGoResult, item string) GoNoGoItem {
+	for _, candidate := range result.Items {
+		if candidate.Item == item {
+			return candidate
+		}
+	}
+	return GoNoGoItem{}
+}
+
+func TestEvaluateGoNoGo_DualLessonInGoodWeather(t *testing.T) {
+	db := setupGoNoGoTestDB(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	request := GoNoGoRequest{At: time.Date(2026, 6, 1, 12, 30, 0, 0, time.UTC)}
+
+	result, err := EvaluateGoNoGo(context.Background(), db, FileWeatherProvider{Dir: weatherFixtureDir}, DefaultPersonalMinimums(), request, now)
+	if err != nil {
+		t.Fatalf("EvaluateGoNoGo failed: %v", err)
+	}
+	if result.Request.Airport != "KFXE" || result.Request.Hours != 2 || result.METAR == nil {
+		t.Fatalf("expected the school airport, a 2h flight and the METAR, got %+v", result.Request)
+	}
+	if result.Decision() != GoDecision {
+		t.Fatalf("expected GO, got %s: %v", result.Decision(), result.Reasons())
+	}
+	if item := goNoGoItem(result, "TAF KFXE"); item.Status != GoNoGoOK || item.Detail != "VFR, within minimums" {
+		t.Fatalf("unexpected TAF item %+v", item)
+	}
+	if reasons := result.Reasons(); len(reasons) != 1 || !strings.HasPrefix(reasons[0], "Aircraft: no aircraft given") {
+		t.Fatalf("expected only the aircraft caution, got %v", reasons)
+	}
+}
+
+func TestEvaluateGoNoGo_SoloChecksMinimumsEndorsementsAndAircraft(t *testing.T) {
+	db := setupGoNoGoTestDB(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	lapsed := time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC)
+	db.Create(&model.Endorsement{Kind: EndorsementSolo, IssuedOn: lapsed.AddDate(0, 0, -90), ExpiresOn: &lapsed})
+	medical := time.Date(2028, 6, 30, 0, 0, 0, 0, time.UTC)
+	db.Create(&model.PilotDocument{Kind: DocumentMedical, IssuedOn: now.AddDate(-1, 0, 0), ExpiresOn: &medical})
+	annual := time.Date(2025, 4, 10, 0, 0, 0, 0, time.UTC)
+	db.Create(&model.Aircraft{TailNumber: "N12345", Annual: &annual})
+
+	// The fixture TAF has TEMPO 2SM BKN008 from 18Z.
+	request := GoNoGoRequest{Airport: "kfxe", Solo: true, Night: true, At: time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC), Tail: "n12345"}
+	result, err := EvaluateGoNoGo(context.Background(), db, FileWeatherProvider{Dir: weatherFixtureDir}, DefaultPersonalMinimums(), request, now)
+	if err != nil {
+		t.Fatalf("EvaluateGoNoGo failed: %v", err)
+	}
+	if result.Decision() != NoGoDecision || result.METAR != nil {
+		t.Fatalf("expected NO-GO without a METAR six hours out, got %s, %+v", result.Decision(), result.METAR)
+	}
+	for item, want := range map[string]string{
+		"Night":                     "night flight is outside your solo minimums",
+		"Solo endorsement":          "lapsed 2026-05-20",
+		"N12345 annual inspection":  "expired 2026-04-30",
+		"TAF KFXE":                  "TEMPO ceiling 800 ft below 3000 ft, TEMPO visibility 2 SM below 6 SM",
+		"Medical certificate":       "valid through 2028-06-30",
+		"Student pilot certificate": "not recorded; carry it on the flight",
+	} {
+		if got := goNoGoItem(result, item); got.Detail != want {
+			t.Fatalf("%s: expected %q, got %+v", item, want, got)
+		}
+	}
+
+	renewed := now.AddDate(0, 0, 80)
+	db.Create(&model.Endorsement{Kind: EndorsementSoloRenewal, IssuedOn: now.AddDate(0, 0, -10), ExpiresOn: &renewed})
+	result, _ = EvaluateGoNoGo(context.Background(), db, FileWeatherProvider{Dir: weatherFixtureDir}, DefaultPersonalMinimums(), request, now)
+	if got := goNoGoItem(result, "Solo endorsement"); got.Status != GoNoGoOK {
+		t.Fatalf("expected the 90-day renewal to count, got %+v", got)
+	}
+}
+
+func TestEvaluateGoNoGo_MissingWeatherIsNoGo(t *testing.T) {
+	db := setupGoNoGoTestDB(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := EvaluateGoNoGo(context.Background(), db, FileWeatherProvider{Dir: weatherFixtureDir}, DefaultPersonalMinimums(), GoNoGoRequest{Airport: "KXXX"}, now)
+	if err != nil {
+		t.Fatalf("EvaluateGoNoGo failed: %v", err)
+	}
+	if result.Decision() != NoGoDecision || goNoGoItem(result, "METAR KXXX").Status != GoNoGoFail || goNoGoItem(result, "TAF KXXX").Status != GoNoGoFail {
+		t.Fatalf("expected missing weather to be a no-go, got %+v", result.Items)
+	}
+}
+
+func TestRecordGoNoGo_BuildsHistory(t *testing.T) {
+	db := setupGoNoGoTestDB(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	provider := FileWeatherProvider{Dir: weatherFixtureDir}
+
+	good, _ := EvaluateGoNoGo(context.Background(), db, provider, DefaultPersonalMinimums(), GoNoGoRequest{At: now}, now)
+	if _, err := RecordGoNoGo(db, good, "  pattern work  "); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	bad, _ := EvaluateGoNoGo(context.Background(), db, provider, DefaultPersonalMinimums(), GoNoGoRequest{Solo: true, At: now.Add(6 * time.Hour)}, now)
+	if _, err := RecordGoNoGo(db, bad, ""); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	decisions, err := ListGoNoGoDecisions(db, 0)
+	if err != nil || len(decisions) != 2 {
+		t.Fatalf("expected two decisions, got %+v, %v", decisions, err)
+	}
+	latest, first := decisions[0], decisions[1]
+	if latest.Decision != NoGoDecision || !latest.Solo || !strings.Contains(latest.Reasons, "Solo endorsement: none recorded") || latest.METAR != "" || latest.TAF == "" {
+		t.Fatalf("unexpected latest decision %+v", latest)
+	}
+	if first.Decision != GoDecision || first.Note != "pattern work" || !strings.HasPrefix(first.METAR, "KFXE 011153Z") {
+		t.Fatalf("unexpected first decision %+v", first)
+	}
+	if limited, _ := ListGoNoGoDecisions(db, 1); len(limited) != 1 || limited[0].ID != latest.ID {
+		t.Fatalf("expected the limit to keep the latest decision, got %+v", limited)
+	}
+}
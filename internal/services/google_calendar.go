@@ -14,8 +14,18 @@ import (
 	"ppl-study-planner/internal/model"
 )
 
+const (
+	googleTaskIdentityKey = "openppl_task_identity"
+	googleTaskIDKey       = "openppl_task_id"
+	googleManagedKey      = "openppl_managed"
+)
+
 type googleCalendarEventsWriter interface {
 	Insert(calendarID string, event *calendar.Event) (*calendar.Event, error)
+	Update(calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error)
+	Delete(calendarID string, eventID string) error
+	// List returns every event whose private extended properties match key=value.
+	List(calendarID string, privateProperty string) ([]*calendar.Event, error)
 }
 
 type calendarServiceEventsWriter struct {
@@ -26,6 +36,37 @@ func (w *calendarServiceEventsWriter) Insert(calendarID string, event *calendar.
 	return w.service.Events.Insert(calendarID, event).Do()
 }
 
+func (w *calendarServiceEventsWriter) Update(calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error) {
+	return w.service.Events.Update(calendarID, eventID, event).Do()
+}
+
+func (w *calendarServiceEventsWriter) Delete(calendarID string, eventID string) error {
+	return w.service.Events.Delete(calendarID, eventID).Do()
+}
+
+func (w *calendarServiceEventsWriter) List(calendarID string, privateProperty string) ([]*calendar.Event, error) {
+	var events []*calendar.Event
+	pageToken := ""
+	for {
+		call := w.service.Events.List(calendarID).
+			PrivateExtendedProperty(privateProperty).
+			ShowDeleted(false).
+			MaxResults(250)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		page, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page.Items...)
+		if page.NextPageToken == "" {
+			return events, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
 var (
 	googleAuthClientProvider  = EnsureGoogleAuthClient
 	googleEventsWriterFactory = func(ctx context.Context, authResult GoogleAuthResult) (googleCalendarEventsWriter, error) {
@@ -38,6 +79,10 @@ var (
 	googleRetrySleepWithContext = sleepWithContext
 )
 
+// SyncTasksToGoogleCalendar reconciles the calendar with the given tasks.
+// Events are matched on the openppl_task_identity private property: missing
+// events are created, changed ones updated, unchanged ones left alone, and
+// openppl-managed events for tasks that no longer exist are deleted.
 func SyncTasksToGoogleCalendar(ctx context.Context, tasks []model.DailyTask, opts GoogleCalendarSyncOptions) (GoogleCalendarSyncResult, error) {
 	if len(tasks) == 0 {
 		return GoogleCalendarSyncResult{}, &GoogleCalendarError{Kind: GoogleCalendarErrorValidation, Err: errors.New("no tasks available for google calendar sync")}
@@ -64,28 +109,146 @@ func SyncTasksToGoogleCalendar(ctx context.Context, tasks []model.DailyTask, opt
 		UsedCachedAuth: authResult.UsedCachedToken,
 	}
 
+	var managed []*calendar.Event
+	err = withGoogleRetry(ctx, resolvedOpts, func() error {
+		var listErr error
+		managed, listErr = writer.List(resolvedOpts.CalendarID, googleManagedKey+"=true")
+		return listErr
+	})
+	if err != nil {
+		return GoogleCalendarSyncResult{}, &GoogleCalendarError{Kind: GoogleCalendarErrorList, Err: err}
+	}
+
+	existing := make(map[string]*calendar.Event, len(managed))
+	var duplicates []*calendar.Event
+	for _, event := range managed {
+		identity := googleEventIdentity(event)
+		if identity == "" {
+			continue
+		}
+		if _, ok := existing[identity]; ok {
+			duplicates = append(duplicates, event)
+			continue
+		}
+		existing[identity] = event
+	}
+
+	// Events written before the managed marker existed are only discoverable
+	// by their identity property. Look them up one by one on the first sync
+	// after upgrading, when no managed events exist yet.
+	adoptLegacy := len(managed) == 0
+
+	wanted := make(map[string]struct{}, len(tasks))
 	for _, task := range tasks {
-		event := mapTaskToGoogleEvent(task)
-		if err := insertGoogleEventWithRetry(ctx, writer, resolvedOpts.CalendarID, event, resolvedOpts.MaxRetries, resolvedOpts.InitialBackoff); err != nil {
-			statusCode := googleStatusCode(err)
-			result.Failures = append(result.Failures, GoogleCalendarTaskFailure{
-				TaskID:     task.ID,
-				TaskTitle:  strings.TrimSpace(task.Title),
-				StatusCode: statusCode,
-				Retryable:  isRetryableGoogleStatus(statusCode),
-				Message:    err.Error(),
+		identity := deterministicGoogleTaskIdentity(task)
+		wanted[identity] = struct{}{}
+
+		current, found := existing[identity]
+		if !found && adoptLegacy {
+			matches, err := listGoogleEventsByIdentity(ctx, writer, resolvedOpts, identity)
+			if err != nil {
+				result.addFailure(task, err)
+				continue
+			}
+			if len(matches) > 0 {
+				current, found = matches[0], true
+				duplicates = append(duplicates, matches[1:]...)
+			}
+		}
+
+		if found && resolvedOpts.PullCompletion && !task.Completed && googleEventMarkedDone(current, resolvedOpts) &&
+			googleEventUpdatedAfter(current, resolvedOpts.LastLocalChange[task.ID]) {
+			result.CompletedTaskIDs = append(result.CompletedTaskIDs, task.ID)
+			task.Completed = true
+		}
+
+		desired := mapTaskToGoogleEvent(task, opts.Location)
+		if !found {
+			err := withGoogleRetry(ctx, resolvedOpts, func() error {
+				_, insertErr := writer.Insert(resolvedOpts.CalendarID, desired)
+				return insertErr
 			})
+			if err != nil {
+				result.addFailure(task, err)
+				continue
+			}
+			result.Created++
+			continue
+		}
+
+		keepMarker := task.Completed && googleEventMarkedDone(current, resolvedOpts)
+		// A done colour on an open task is stale, like a done keyword, and
+		// is cleared.
+		staleColor := !keepMarker && resolvedOpts.CompletionColorID != "" && current.ColorId == resolvedOpts.CompletionColorID
+		if !staleColor && !googleEventNeedsUpdate(current, desired, keepMarker) {
+			result.Unchanged++
 			continue
 		}
 
-		result.Created++
+		err := withGoogleRetry(ctx, resolvedOpts, func() error {
+			merged := mergeGoogleEvent(current, desired, keepMarker)
+			if staleColor {
+				merged.ColorId = ""
+				merged.ForceSendFields = append(merged.ForceSendFields, "ColorId")
+			}
+			_, updateErr := writer.Update(resolvedOpts.CalendarID, current.Id, merged)
+			return updateErr
+		})
+		if err != nil {
+			result.addFailure(task, err)
+			continue
+		}
+		result.Updated++
+	}
+
+	if !resolvedOpts.KeepRemoved {
+		stale := duplicates
+		for identity, event := range existing {
+			if _, ok := wanted[identity]; !ok {
+				stale = append(stale, event)
+			}
+		}
+		for _, event := range stale {
+			err := withGoogleRetry(ctx, resolvedOpts, func() error {
+				return writer.Delete(resolvedOpts.CalendarID, event.Id)
+			})
+			if err != nil && googleStatusCode(err) != 404 && googleStatusCode(err) != 410 {
+				result.addFailure(model.DailyTask{Title: event.Summary}, err)
+				continue
+			}
+			result.Deleted++
+		}
 	}
 
 	result.Failed = len(result.Failures)
 	return result, nil
 }
 
-func mapTaskToGoogleEvent(task model.DailyTask) *calendar.Event {
+func (r *GoogleCalendarSyncResult) addFailure(task model.DailyTask, err error) {
+	statusCode := googleStatusCode(err)
+	r.Failures = append(r.Failures, GoogleCalendarTaskFailure{
+		TaskID:     task.ID,
+		TaskTitle:  strings.TrimSpace(task.Title),
+		StatusCode: statusCode,
+		Retryable:  isRetryableGoogleStatus(statusCode),
+		Message:    err.Error(),
+	})
+}
+
+func listGoogleEventsByIdentity(ctx context.Context, writer googleCalendarEventsWriter, opts GoogleCalendarSyncOptions, identity string) ([]*calendar.Event, error) {
+	var matches []*calendar.Event
+	err := withGoogleRetry(ctx, opts, func() error {
+		var listErr error
+		matches, listErr = writer.List(opts.CalendarID, googleTaskIdentityKey+"="+identity)
+		return listErr
+	})
+	return matches, err
+}
+
+func mapTaskToGoogleEvent(task model.DailyTask, loc *time.Location) *calendar.Event {
+	if loc == nil {
+		loc = time.UTC
+	}
 	title := strings.TrimSpace(task.Title)
 	if title == "" {
 		title = "Study Task"
@@ -96,29 +259,126 @@ func mapTaskToGoogleEvent(task model.DailyTask) *calendar.Event {
 		description = strings.TrimSpace(task.Category)
 	}
 
-	startUTC, endUTC := taskWindowUTC(task.Date)
+	startUTC, endUTC := taskWindowUTC(task.Date, loc)
 	identity := deterministicGoogleTaskIdentity(task)
 
 	return &calendar.Event{
 		Summary:     title,
 		Description: description,
 		Start: &calendar.EventDateTime{
-			DateTime: startUTC.Format(time.RFC3339),
-			TimeZone: "UTC",
+			DateTime: startUTC.In(loc).Format(time.RFC3339),
+			TimeZone: loc.String(),
 		},
 		End: &calendar.EventDateTime{
-			DateTime: endUTC.Format(time.RFC3339),
-			TimeZone: "UTC",
+			DateTime: endUTC.In(loc).Format(time.RFC3339),
+			TimeZone: loc.String(),
 		},
 		ExtendedProperties: &calendar.EventExtendedProperties{
 			Private: map[string]string{
-				"openppl_task_identity": identity,
-				"openppl_task_id":       fmt.Sprintf("%d", task.ID),
+				googleTaskIdentityKey: identity,
+				googleTaskIDKey:       fmt.Sprintf("%d", task.ID),
+				googleManagedKey:      "true",
 			},
 		},
 	}
 }
 
+func googleEventIdentity(event *calendar.Event) string {
+	if event == nil || event.ExtendedProperties == nil {
+		return ""
+	}
+	return event.ExtendedProperties.Private[googleTaskIdentityKey]
+}
+
+// googleEventNeedsUpdate compares the fields openppl owns. When the user has
+// marked a completed task's event as done, the title and colour are theirs.
+func googleEventNeedsUpdate(current, desired *calendar.Event, keepUserMarker bool) bool {
+	if current == nil {
+		return true
+	}
+	if !keepUserMarker && current.Summary != desired.Summary {
+		return true
+	}
+	if current.Description != desired.Description {
+		return true
+	}
+	if !sameGoogleInstant(current.Start, desired.Start) || !sameGoogleInstant(current.End, desired.End) {
+		return true
+	}
+	if current.ExtendedProperties == nil {
+		return true
+	}
+	for key, value := range desired.ExtendedProperties.Private {
+		if current.ExtendedProperties.Private[key] != value {
+			return true
+		}
+	}
+	return false
+}
+
+func sameGoogleInstant(a, b *calendar.EventDateTime) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	at, aErr := time.Parse(time.RFC3339, a.DateTime)
+	bt, bErr := time.Parse(time.RFC3339, b.DateTime)
+	if aErr != nil || bErr != nil {
+		return a.DateTime == b.DateTime
+	}
+	return at.Equal(bt)
+}
+
+// mergeGoogleEvent applies openppl-owned fields onto the existing event so
+// user-set reminders, colours and attendees survive the update.
+func mergeGoogleEvent(current, desired *calendar.Event, keepUserMarker bool) *calendar.Event {
+	merged := *current
+	if !keepUserMarker {
+		merged.Summary = desired.Summary
+	}
+	merged.Description = desired.Description
+	merged.Start = desired.Start
+	merged.End = desired.End
+
+	private := map[string]string{}
+	if current.ExtendedProperties != nil {
+		for key, value := range current.ExtendedProperties.Private {
+			private[key] = value
+		}
+	}
+	for key, value := range desired.ExtendedProperties.Private {
+		private[key] = value
+	}
+	props := &calendar.EventExtendedProperties{Private: private}
+	if current.ExtendedProperties != nil {
+		props.Shared = current.ExtendedProperties.Shared
+	}
+	merged.ExtendedProperties = props
+	return &merged
+}
+
+// googleEventMarkedDone reports whether the user flagged the event as done in
+// their calendar, either with the completion keyword or the completion colour.
+func googleEventMarkedDone(event *calendar.Event, opts GoogleCalendarSyncOptions) bool {
+	if event == nil {
+		return false
+	}
+	if opts.CompletionColorID != "" && event.ColorId == opts.CompletionColorID {
+		return true
+	}
+	keyword := strings.ToLower(strings.TrimSpace(opts.CompletionKeyword))
+	return keyword != "" && strings.Contains(strings.ToLower(event.Summary), keyword)
+}
+
+// googleEventUpdatedAfter reports whether event changed after since. With
+// no local change every marker counts; an unreadable update time does not.
+func googleEventUpdatedAfter(event *calendar.Event, since time.Time) bool {
+	if since.IsZero() {
+		return true
+	}
+	updated, err := time.Parse(time.RFC3339, event.Updated)
+	return err == nil && updated.After(since)
+}
+
 func deterministicGoogleTaskIdentity(task model.DailyTask) string {
 	datePart := task.Date.UTC().Format("20060102")
 	if task.ID != 0 {
@@ -134,28 +394,28 @@ func deterministicGoogleTaskIdentity(task model.DailyTask) string {
 	return fmt.Sprintf("task-%s-%s", titlePart, datePart)
 }
 
-func insertGoogleEventWithRetry(ctx context.Context, writer googleCalendarEventsWriter, calendarID string, event *calendar.Event, maxRetries int, initialBackoff time.Duration) error {
+func withGoogleRetry(ctx context.Context, opts GoogleCalendarSyncOptions, call func() error) error {
 	var lastErr error
 
-	for attempt := 0; attempt <= maxRetries; attempt++ {
-		_, err := writer.Insert(calendarID, event)
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		err := call()
 		if err == nil {
 			return nil
 		}
 
 		lastErr = err
 		statusCode := googleStatusCode(err)
-		if !isRetryableGoogleStatus(statusCode) || attempt == maxRetries {
+		if !isRetryableGoogleStatus(statusCode) || attempt == opts.MaxRetries {
 			return err
 		}
 
-		if sleepErr := googleRetrySleepWithContext(ctx, initialBackoff*time.Duration(1<<attempt)); sleepErr != nil {
+		if sleepErr := googleRetrySleepWithContext(ctx, opts.InitialBackoff*time.Duration(1<<attempt)); sleepErr != nil {
 			return sleepErr
 		}
 	}
 
 	if lastErr == nil {
-		return errors.New("google calendar insert failed")
+		return errors.New("google calendar request failed")
 	}
 
 	return lastErr
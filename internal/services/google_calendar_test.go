@@ -2,14 +2,20 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"google.golang.org/api/calendar/v3"
 	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
 
 	"ppl-study-planner/internal/model"
 )
@@ -23,7 +29,7 @@ func TestGoogleCalendar_MapsTaskToEventPayload(t *testing.T) {
 		Description: "Review winds aloft",
 	}
 
-	event := mapTaskToGoogleEvent(task)
+	event := mapTaskToGoogleEvent(task, nil)
 	if event == nil {
 		t.Fatal("expected event")
 	}
@@ -47,6 +53,17 @@ func TestGoogleCalendar_MapsTaskToEventPayload(t *testing.T) {
 	}
 }
 
+func TestGoogleCalendar_SchedulesInSchoolAirportTimeZone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	event := mapTaskToGoogleEvent(model.DailyTask{ID: 7, Date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Title: "Pattern work"}, loc)
+	if event.Start.TimeZone != "America/New_York" || event.Start.DateTime != "2026-07-01T09:00:00-04:00" {
+		t.Fatalf("expected 09:00 New York time, got %+v", event.Start)
+	}
+}
+
 func TestGoogleCalendar_RetriesRetryableFailures(t *testing.T) {
 	tasks := []model.DailyTask{{ID: 1, Date: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Title: "Task"}}
 	writer := &fakeGoogleCalendarWriter{
@@ -167,6 +184,18 @@ type fakeGoogleCalendarWriter struct {
 	events          []*calendar.Event
 }
 
+func (f *fakeGoogleCalendarWriter) Update(calendarID string, eventID string, event *calendar.Event) (*calendar.Event, error) {
+	return event, nil
+}
+
+func (f *fakeGoogleCalendarWriter) Delete(calendarID string, eventID string) error {
+	return nil
+}
+
+func (f *fakeGoogleCalendarWriter) List(calendarID string, privateProperty string) ([]*calendar.Event, error) {
+	return nil, nil
+}
+
 func (f *fakeGoogleCalendarWriter) Insert(calendarID string, event *calendar.Event) (*calendar.Event, error) {
 	f.insertCalls++
 	f.events = append(f.events, event)
@@ -200,3 +229,226 @@ func stubGoogleCalendarDependencies(t *testing.T, writer googleCalendarEventsWri
 		googleRetrySleepWithContext = originalSleep
 	}
 }
+
+func TestGoogleCalendar_SyncIsIdempotentAgainstFakeServer(t *testing.T) {
+	fake := newFakeGoogleCalendarServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	restore := stubGoogleCalendarDependencies(t, nil)
+	defer restore()
+	googleEventsWriterFactory = func(ctx context.Context, authResult GoogleAuthResult) (googleCalendarEventsWriter, error) {
+		svc, err := calendar.NewService(ctx, option.WithEndpoint(server.URL+"/"), option.WithHTTPClient(server.Client()))
+		if err != nil {
+			return nil, err
+		}
+		return &calendarServiceEventsWriter{service: svc}, nil
+	}
+
+	day := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	taskA := model.DailyTask{ID: 1, Date: day, Title: "Task A", Description: "Regulations"}
+	taskB := model.DailyTask{ID: 2, Date: day.AddDate(0, 0, 1), Title: "Task B", Description: "Weather"}
+
+	first, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{taskA, taskB}, GoogleCalendarSyncOptions{})
+	if err != nil {
+		t.Fatalf("first sync: %v", err)
+	}
+	if first.Created != 2 || len(fake.events) != 2 {
+		t.Fatalf("expected 2 created events, got result=%+v stored=%d", first, len(fake.events))
+	}
+
+	second, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{taskA, taskB}, GoogleCalendarSyncOptions{})
+	if err != nil {
+		t.Fatalf("second sync: %v", err)
+	}
+	if second.Created != 0 || second.Unchanged != 2 || len(fake.events) != 2 {
+		t.Fatalf("expected idempotent resync, got result=%+v stored=%d", second, len(fake.events))
+	}
+
+	taskA.Description = "Regulations and airspace"
+	taskC := model.DailyTask{ID: 3, Date: day.AddDate(0, 0, 2), Title: "Task C"}
+	third, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{taskA, taskC}, GoogleCalendarSyncOptions{})
+	if err != nil {
+		t.Fatalf("third sync: %v", err)
+	}
+	if third.Created != 1 || third.Updated != 1 || third.Deleted != 1 || third.Unchanged != 0 {
+		t.Fatalf("unexpected reconcile counts: %+v", third)
+	}
+	if len(fake.events) != 2 {
+		t.Fatalf("expected 2 stored events after delete, got %d", len(fake.events))
+	}
+
+	for _, event := range fake.events {
+		if event.ExtendedProperties.Private[googleTaskIdentityKey] == "task-3-20260403" {
+			event.Summary = "[done] " + event.Summary
+		}
+	}
+	fourth, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{taskA, taskC}, GoogleCalendarSyncOptions{PullCompletion: true})
+	if err != nil {
+		t.Fatalf("fourth sync: %v", err)
+	}
+	if len(fourth.CompletedTaskIDs) != 1 || fourth.CompletedTaskIDs[0] != 3 {
+		t.Fatalf("expected task 3 pulled back as completed, got %+v", fourth.CompletedTaskIDs)
+	}
+	if fourth.Unchanged != 2 {
+		t.Fatalf("expected user done marker to be preserved, got %+v", fourth)
+	}
+}
+
+func TestGoogleCalendar_AdoptsLegacyEventsAndRemovesDuplicates(t *testing.T) {
+	fake := newFakeGoogleCalendarServer()
+	task := model.DailyTask{ID: 7, Date: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Title: "Legacy"}
+	for i := 0; i < 3; i++ {
+		legacy := mapTaskToGoogleEvent(task, nil)
+		delete(legacy.ExtendedProperties.Private, googleManagedKey)
+		fake.store(legacy)
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	restore := stubGoogleCalendarDependencies(t, nil)
+	defer restore()
+	googleEventsWriterFactory = func(ctx context.Context, authResult GoogleAuthResult) (googleCalendarEventsWriter, error) {
+		svc, err := calendar.NewService(ctx, option.WithEndpoint(server.URL+"/"), option.WithHTTPClient(server.Client()))
+		if err != nil {
+			return nil, err
+		}
+		return &calendarServiceEventsWriter{service: svc}, nil
+	}
+
+	result, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{task}, GoogleCalendarSyncOptions{})
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if result.Created != 0 || result.Updated != 1 || result.Deleted != 2 {
+		t.Fatalf("expected legacy adoption with duplicate cleanup, got %+v", result)
+	}
+	if len(fake.events) != 1 {
+		t.Fatalf("expected a single remaining event, got %d", len(fake.events))
+	}
+}
+
+// fakeGoogleCalendarServer implements the subset of the Calendar v3 REST API
+// used by the sync: list by private property, insert, update and delete.
+type fakeGoogleCalendarServer struct {
+	mu     sync.Mutex
+	nextID int
+	events map[string]*calendar.Event
+}
+
+func newFakeGoogleCalendarServer() *fakeGoogleCalendarServer {
+	return &fakeGoogleCalendarServer{events: map[string]*calendar.Event{}}
+}
+
+func (f *fakeGoogleCalendarServer) store(event *calendar.Event) *calendar.Event {
+	f.nextID++
+	event.Id = fmt.Sprintf("evt%d", f.nextID)
+	f.events[event.Id] = event
+	return event
+}
+
+func (f *fakeGoogleCalendarServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "calendars" || parts[2] != "events" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 3:
+		filter := r.URL.Query().Get("privateExtendedProperty")
+		key, value, _ := strings.Cut(filter, "=")
+		items := make([]*calendar.Event, 0)
+		for _, event := range f.events {
+			if event.ExtendedProperties != nil && event.ExtendedProperties.Private[key] == value {
+				items = append(items, event)
+			}
+		}
+		sort.Slice(items, func(i, j int) bool { return items[i].Id < items[j].Id })
+		_ = json.NewEncoder(w).Encode(&calendar.Events{Items: items})
+	case r.Method == http.MethodPost && len(parts) == 3:
+		var event calendar.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.store(&event))
+	case r.Method == http.MethodPut && len(parts) == 4:
+		if _, ok := f.events[parts[3]]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		var event calendar.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		event.Id = parts[3]
+		f.events[parts[3]] = &event
+		_ = json.NewEncoder(w).Encode(&event)
+	case r.Method == http.MethodDelete && len(parts) == 4:
+		if _, ok := f.events[parts[3]]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(f.events, parts[3])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestGoogleCalendar_StaleDoneMarkerIsRemovedNotPulled(t *testing.T) {
+	fake := newFakeGoogleCalendarServer()
+	stale := model.DailyTask{ID: 4, Date: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Title: "Reopened"}
+	fresh := model.DailyTask{ID: 5, Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), Title: "Done in calendar"}
+	markedAt := time.Date(2026, 4, 1, 18, 0, 0, 0, time.UTC)
+	for _, task := range []model.DailyTask{stale, fresh} {
+		event := mapTaskToGoogleEvent(task, nil)
+		event.Summary = "[done] " + event.Summary
+		event.ColorId = "10"
+		event.Updated = markedAt.Format(time.RFC3339)
+		fake.store(event)
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	restore := stubGoogleCalendarDependencies(t, nil)
+	defer restore()
+	googleEventsWriterFactory = func(ctx context.Context, authResult GoogleAuthResult) (googleCalendarEventsWriter, error) {
+		svc, err := calendar.NewService(ctx, option.WithEndpoint(server.URL+"/"), option.WithHTTPClient(server.Client()))
+		if err != nil {
+			return nil, err
+		}
+		return &calendarServiceEventsWriter{service: svc}, nil
+	}
+
+	// Task 4 was unchecked in the app after it was marked in the calendar;
+	// task 5 was last changed in the app before.
+	opts := GoogleCalendarSyncOptions{PullCompletion: true, CompletionColorID: "10", LastLocalChange: map[uint]time.Time{
+		stale.ID: markedAt.Add(time.Hour),
+		fresh.ID: markedAt.Add(-time.Hour),
+	}}
+	result, err := SyncTasksToGoogleCalendar(context.Background(), []model.DailyTask{stale, fresh}, opts)
+	if err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if len(result.CompletedTaskIDs) != 1 || result.CompletedTaskIDs[0] != fresh.ID {
+		t.Fatalf("expected only task 5 pulled back, got %+v", result.CompletedTaskIDs)
+	}
+	if result.Updated != 1 || result.Unchanged != 1 {
+		t.Fatalf("expected the stale marker to be cleared, got %+v", result)
+	}
+	for _, event := range fake.events {
+		reopened := event.Summary == "Reopened"
+		if reopened && event.ColorId != "" {
+			t.Fatalf("expected the stale done colour to be cleared, got %q", event.ColorId)
+		}
+		if !reopened && event.Summary != "[done] Done in calendar" {
+			t.Fatalf("unexpected event %q", event.Summary)
+		}
+	}
+}
@@ -10,6 +10,9 @@ const (
 	GoogleCalendarErrorValidation  = "validation"
 	GoogleCalendarErrorAuth        = "auth"
 	GoogleCalendarErrorServiceInit = "service_init"
+	GoogleCalendarErrorList        = "list"
+
+	defaultGoogleCompletionKeyword = "[done]"
 )
 
 type GoogleCalendarSyncOptions struct {
@@ -17,6 +20,24 @@ type GoogleCalendarSyncOptions struct {
 	CalendarID     string
 	MaxRetries     int
 	InitialBackoff time.Duration
+
+	// KeepRemoved leaves events for tasks that no longer exist in place
+	// instead of deleting them.
+	KeepRemoved bool
+
+	// PullCompletion reports tasks whose events the user marked as done,
+	// either by CompletionKeyword in the title or by CompletionColorID.
+	PullCompletion    bool
+	CompletionKeyword string
+	CompletionColorID string
+
+	// LastLocalChange maps task IDs to when their completion last changed
+	// in the app. A done marker on an event last updated before then is
+	// stale: it is removed instead of completing the task again.
+	LastLocalChange map[uint]time.Time
+
+	// Location is the time zone events are scheduled in; nil means UTC.
+	Location *time.Location
 }
 
 type GoogleCalendarTaskFailure struct {
@@ -28,12 +49,16 @@ type GoogleCalendarTaskFailure struct {
 }
 
 type GoogleCalendarSyncResult struct {
-	CalendarID     string
-	Attempted      int
-	Created        int
-	Failed         int
-	Failures       []GoogleCalendarTaskFailure
-	UsedCachedAuth bool
+	CalendarID       string
+	Attempted        int
+	Created          int
+	Updated          int
+	Deleted          int
+	Unchanged        int
+	Failed           int
+	Failures         []GoogleCalendarTaskFailure
+	CompletedTaskIDs []uint
+	UsedCachedAuth   bool
 }
 
 type GoogleCalendarError struct {
@@ -71,5 +96,9 @@ func normalizeGoogleCalendarOptions(opts GoogleCalendarSyncOptions) (GoogleCalen
 		opts.InitialBackoff = 500 * time.Millisecond
 	}
 
+	if opts.PullCompletion && opts.CompletionKeyword == "" && opts.CompletionColorID == "" {
+		opts.CompletionKeyword = defaultGoogleCompletionKeyword
+	}
+
 	return opts, nil
 }
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ics "github.com/arran4/golang-ical"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// ICSImportResult summarizes an ICS completion import.
+type ICSImportResult struct {
+	Items            int
+	Matched          int
+	Completed        int
+	AlreadyCompleted int
+	Pending          int
+	Unmatched        int
+	CompletedTaskIDs []uint
+}
+
+var openpplTaskUIDPattern = regexp.MustCompile(`^task-(\d+)-(\d{8})@openppl$`)
+
+// ImportICSCompletion reads VTODO and VEVENT items carrying openppl UIDs and
+// marks the matching tasks completed when the item is. Items are matched on
+// both task ID and date so a file exported from a regenerated plan cannot
+// complete the wrong task. Incomplete items never reopen a task.
+func ImportICSCompletion(database *gorm.DB, r io.Reader, source string) (ICSImportResult, error) {
+	var result ICSImportResult
+	if database == nil {
+		return result, errors.New("database is required")
+	}
+
+	cal, err := ics.ParseCalendar(r)
+	if err != nil {
+		return result, fmt.Errorf("parse ICS: %w", err)
+	}
+
+	for _, component := range cal.Components {
+		var base *ics.ComponentBase
+		switch item := component.(type) {
+		case *ics.VTodo:
+			base = &item.ComponentBase
+		case *ics.VEvent:
+			base = &item.ComponentBase
+		default:
+			continue
+		}
+		result.Items++
+
+		taskID, date, ok := parseOpenPPLTaskUID(base)
+		if !ok {
+			result.Unmatched++
+			continue
+		}
+
+		var task model.DailyTask
+		if err := database.First(&task, taskID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Unmatched++
+				continue
+			}
+			return result, fmt.Errorf("load task %d: %w", taskID, err)
+		}
+		if task.Date.UTC().Format("20060102") != date {
+			result.Unmatched++
+			continue
+		}
+		result.Matched++
+
+		if !icsItemCompleted(base) {
+			result.Pending++
+			continue
+		}
+		if task.Completed {
+			result.AlreadyCompleted++
+			continue
+		}
+		if _, err := SetTaskCompletion(database, task.ID, true, source); err != nil {
+			return result, fmt.Errorf("complete task %d: %w", task.ID, err)
+		}
+		result.Completed++
+		result.CompletedTaskIDs = append(result.CompletedTaskIDs, task.ID)
+	}
+
+	return result, nil
+}
+
+func parseOpenPPLTaskUID(base *ics.ComponentBase) (uint, string, bool) {
+	prop := base.GetProperty(ics.ComponentPropertyUniqueId)
+	if prop == nil {
+		return 0, "", false
+	}
+	match := openpplTaskUIDPattern.FindStringSubmatch(strings.TrimSpace(prop.Value))
+	if match == nil {
+		return 0, "", false
+	}
+	id, err := strconv.ParseUint(match[1], 10, 64)
+	if err != nil || id == 0 {
+		return 0, "", false
+	}
+	return uint(id), match[2], true
+}
+
+// icsItemCompleted accepts the signals todo apps use to mark an item done:
+// STATUS:COMPLETED, a COMPLETED timestamp, or PERCENT-COMPLETE:100.
+func icsItemCompleted(base *ics.ComponentBase) bool {
+	if prop := base.GetProperty(ics.ComponentPropertyStatus); prop != nil {
+		switch strings.ToUpper(strings.TrimSpace(prop.Value)) {
+		case string(ics.ObjectStatusCompleted):
+			return true
+		case string(ics.ObjectStatusCancelled):
+			return false
+		}
+	}
+	if prop := base.GetProperty(ics.ComponentPropertyCompleted); prop != nil && strings.TrimSpace(prop.Value) != "" {
+		return true
+	}
+	if prop := base.GetProperty(ics.ComponentPropertyPercentComplete); prop != nil && strings.TrimSpace(prop.Value) == "100" {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestImportICSCompletion_AppliesCompletedItems(t *testing.T) {
+	db := setupEventsTestDB(t)
+	tasks := []model.DailyTask{
+		{Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Airspace"},
+		{Date: time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Weather"},
+		{Date: time.Date(2026, 4, 4, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Regulations", Completed: true},
+		{Date: time.Date(2026, 4, 5, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Performance"},
+	}
+	for i := range tasks {
+		if err := db.Create(&tasks[i]).Error; err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+	}
+
+	item := func(component string, uid string, extra string) string {
+		return "BEGIN:" + component + "\r\nUID:" + uid + "\r\nDTSTAMP:20260406T000000Z\r\nSUMMARY:x\r\n" + extra + "END:" + component + "\r\n"
+	}
+	file := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//todo app//EN\r\n" +
+		item("VTODO", deterministicTaskUID(tasks[0]), "STATUS:COMPLETED\r\n") +
+		item("VTODO", deterministicTaskUID(tasks[1]), "STATUS:NEEDS-ACTION\r\n") +
+		item("VTODO", deterministicTaskUID(tasks[2]), "PERCENT-COMPLETE:100\r\n") +
+		item("VEVENT", deterministicTaskUID(tasks[3]), "COMPLETED:20260405T120000Z\r\n") +
+		item("VTODO", "task-999-20260405@openppl", "STATUS:COMPLETED\r\n") +
+		item("VTODO", strings.Replace(deterministicTaskUID(tasks[1]), "20260403", "20260410", 1), "STATUS:COMPLETED\r\n") +
+		item("VTODO", "groceries@example.com", "STATUS:COMPLETED\r\n") +
+		"END:VCALENDAR\r\n"
+
+	result, err := ImportICSCompletion(db, strings.NewReader(file), "ics-import")
+	if err != nil {
+		t.Fatalf("ImportICSCompletion returned error: %v", err)
+	}
+	if result.Items != 7 || result.Matched != 4 || result.Unmatched != 3 || result.Completed != 2 || result.AlreadyCompleted != 1 || result.Pending != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	var reloaded []model.DailyTask
+	db.Order("id asc").Find(&reloaded)
+	want := []bool{true, false, true, true}
+	for i, task := range reloaded {
+		if task.Completed != want[i] {
+			t.Fatalf("task %d completed=%v, want %v", task.ID, task.Completed, want[i])
+		}
+	}
+
+	var events int64
+	db.Model(&model.OutboxEvent{}).Where("event_type = ?", EventTaskCompleted).Count(&events)
+	if events != 2 {
+		t.Fatalf("expected 2 task.completed events, got %d", events)
+	}
+}
+
+func TestImportICSCompletion_RejectsInvalidCalendar(t *testing.T) {
+	db := setupEventsTestDB(t)
+	if _, err := ImportICSCompletion(db, strings.NewReader("not a calendar"), "ics-import"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	// dualCrossCountryHours is the cross-country flight training of
+	// 61.109(a)(1).
+	dualCrossCountryHours = 3
+	// soloCrossCountryHours is the solo cross-country time of 61.109(a)(5)(i).
+	soloCrossCountryHours = 5
+)
+
+// LogPlannedCrossCountry records a navlog in the logbook as a planned flight
+// on date.
+func LogPlannedCrossCountry(database *gorm.DB, navlog Navlog, solo bool, date time.Time, remarks string) (model.LogbookEntry, error) {
+	entry := model.LogbookEntry{
+		Date:         dateOnlyUTC(date),
+		Route:        navlog.routeText(),
+		Tail:         navlog.Options.Performance.Tail,
+		Solo:         solo,
+		CrossCountry: navlog.Route.CountsAsCrossCountry(),
+		LongSoloXC:   solo && len(navlog.Route.SoloCrossCountryProblems()) == 0,
+		DistanceNM:   navlog.Route.TotalNM(),
+		PlannedHours: navlog.TotalMinutes() / 60,
+		Remarks:      strings.TrimSpace(remarks),
+	}
+	if err := database.Create(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("log cross-country %s: %w", entry.Route, err)
+	}
+	return entry, nil
+}
+
+// FlightInput is a flown flight to log by hand, such as a local lesson.
+type FlightInput struct {
+	Date         time.Time
+	Hours        float64
+	Solo         bool
+	CrossCountry bool
+	// LongSoloXC marks a solo cross-country that meets 61.109(a)(5)(ii).
+	LongSoloXC bool
+	Route      string
+	Tail       string
+	Remarks    string
+}
+
+// LogFlight records a flown flight in the logbook. Unlike cross-countries
+// logged from a navlog it has no planned time or distance.
+func LogFlight(database *gorm.DB, input FlightInput) (model.LogbookEntry, error) {
+	switch {
+	case input.Hours <= 0:
+		return model.LogbookEntry{}, errors.New("flight time must be positive")
+	case input.LongSoloXC && (!input.Solo || !input.CrossCountry):
+		return model.LogbookEntry{}, errors.New("a long solo cross-country must be logged as solo cross-country")
+	case input.Date.IsZero():
+		return model.LogbookEntry{}, errors.New("flight date is required")
+	}
+	day := dateOnlyUTC(input.Date)
+	route := strings.TrimSpace(input.Route)
+	if route == "" {
+		route = "Local"
+		if input.CrossCountry {
+			route = "Cross-country"
+		}
+	}
+	entry := model.LogbookEntry{
+		Date:         day,
+		Route:        route,
+		Tail:         strings.ToUpper(strings.TrimSpace(input.Tail)),
+		Solo:         input.Solo,
+		CrossCountry: input.CrossCountry,
+		LongSoloXC:   input.LongSoloXC,
+		Hours:        input.Hours,
+		FlownAt:      &day,
+		Remarks:      strings.TrimSpace(input.Remarks),
+	}
+	if err := database.Create(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("log flight: %w", err)
+	}
+	return entry, nil
+}
+
+// DeleteLogbookEntry removes a logbook entry.
+func DeleteLogbookEntry(database *gorm.DB, id uint) (model.LogbookEntry, error) {
+	var entry model.LogbookEntry
+	if err := database.Where("id = ?", id).Limit(1).Find(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("load logbook entry %d: %w", id, err)
+	}
+	if entry.ID == 0 {
+		return model.LogbookEntry{}, fmt.Errorf("no logbook entry %d", id)
+	}
+	if err := database.Delete(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("delete logbook entry %d: %w", id, err)
+	}
+	return entry, nil
+}
+
+// MarkLogbookFlown records the actual flight time of a logbook entry.
+func MarkLogbookFlown(database *gorm.DB, id uint, hours float64, flownAt time.Time) (model.LogbookEntry, error) {
+	if hours <= 0 {
+		return model.LogbookEntry{}, errors.New("flight time must be positive")
+	}
+	var entry model.LogbookEntry
+	if err := database.Where("id = ?", id).Limit(1).Find(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("load logbook entry %d: %w", id, err)
+	}
+	if entry.ID == 0 {
+		return model.LogbookEntry{}, fmt.Errorf("no logbook entry %d", id)
+	}
+	day := dateOnlyUTC(flownAt)
+	entry.Hours, entry.FlownAt, entry.Date = hours, &day, day
+	if err := database.Save(&entry).Error; err != nil {
+		return model.LogbookEntry{}, fmt.Errorf("save logbook entry %d: %w", id, err)
+	}
+	return entry, nil
+}
+
+// ListLogbook returns the logbook, newest first.
+func ListLogbook(database *gorm.DB) ([]model.LogbookEntry, error) {
+	var entries []model.LogbookEntry
+	if err := database.Order("date desc").Order("id desc").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("load logbook: %w", err)
+	}
+	return entries, nil
+}
+
+// FlightTime totals the flown logbook time against 61.109(a).
+type FlightTime struct {
+	Total float64
+	Dual  float64
+	Solo  float64
+}
+
+// SummarizeFlightTime adds up the flown entries; planned ones do not count.
+func SummarizeFlightTime(entries []model.LogbookEntry) FlightTime {
+	var totals FlightTime
+	for _, entry := range entries {
+		if entry.FlownAt == nil {
+			continue
+		}
+		totals.Total += entry.Hours
+		if entry.Solo {
+			totals.Solo += entry.Hours
+		} else {
+			totals.Dual += entry.Hours
+		}
+	}
+	return totals
+}
+
+// Deficits lists the flight time requirements of 61.109(a) still open.
+func (t FlightTime) Deficits() []string {
+	var deficits []string
+	if t.Total < totalFlightHours {
+		deficits = append(deficits, fmt.Sprintf("%.1f of %d hours total time (61.109(a))", t.Total, totalFlightHours))
+	}
+	if t.Dual < dualFlightHours {
+		deficits = append(deficits, fmt.Sprintf("%.1f of %d hours flight training (61.109(a))", t.Dual, dualFlightHours))
+	}
+	if t.Solo < soloFlightHours {
+		deficits = append(deficits, fmt.Sprintf("%.1f of %d hours solo (61.109(a)(5))", t.Solo, soloFlightHours))
+	}
+	return deficits
+}
+
+// CrossCountryProgress totals flown cross-country time against 61.109.
+type CrossCountryProgress struct {
+	DualHours  float64
+	SoloHours  float64
+	LongSoloXC bool
+	Planned    int
+}
+
+// SummarizeCrossCountry adds up the flown cross-country entries and counts
+// the planned ones.
+func SummarizeCrossCountry(entries []model.LogbookEntry) CrossCountryProgress {
+	var progress CrossCountryProgress
+	for _, entry := range entries {
+		if !entry.CrossCountry {
+			continue
+		}
+		if entry.FlownAt == nil {
+			progress.Planned++
+			continue
+		}
+		if entry.Solo {
+			progress.SoloHours += entry.Hours
+			progress.LongSoloXC = progress.LongSoloXC || entry.LongSoloXC
+		} else {
+			progress.DualHours += entry.Hours
+		}
+	}
+	return progress
+}
+
+// Deficits lists the cross-country requirements of 61.109 still open.
+func (p CrossCountryProgress) Deficits() []string {
+	var deficits []string
+	if p.DualHours < dualCrossCountryHours {
+		deficits = append(deficits, fmt.Sprintf("%.1f of %d hours dual cross-country (61.109(a)(1))", p.DualHours, dualCrossCountryHours))
+	}
+	if p.SoloHours < soloCrossCountryHours {
+		deficits = append(deficits, fmt.Sprintf("%.1f of %d hours solo cross-country (61.109(a)(5)(i))", p.SoloHours, soloCrossCountryHours))
+	}
+	if !p.LongSoloXC {
+		deficits = append(deficits, "long solo cross-country not flown (61.109(a)(5)(ii))")
+	}
+	return deficits
+}
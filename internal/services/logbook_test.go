@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestLogbook_PlannedCrossCountryCountsOnceFlown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.LogbookEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	performance := AircraftPerformance{Tail: "N12345", CruiseKTAS: 110, FuelBurnGPH: 8.5}
+	day := time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)
+
+	long := testNavlog(t, NavlogOptions{Performance: performance}, "KFXE", "KVRB", "KOBE", "KFXE")
+	solo, err := LogPlannedCrossCountry(db, long, true, day, "  long XC  ")
+	if err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if !solo.CrossCountry || !solo.LongSoloXC || solo.Route != "KFXE KVRB KOBE KFXE" || solo.Remarks != "long XC" || solo.FlownAt != nil {
+		t.Fatalf("unexpected planned entry %+v", solo)
+	}
+	if _, err := LogPlannedCrossCountry(db, long, false, day.AddDate(0, 0, -7), ""); err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	local := testNavlog(t, NavlogOptions{Performance: performance}, "KFXE", "KPBI", "KFXE")
+	if entry, _ := LogPlannedCrossCountry(db, local, false, day, ""); entry.CrossCountry {
+		t.Fatalf("expected KFXE-KPBI not to be cross-country, got %+v", entry)
+	}
+
+	entries, _ := ListLogbook(db)
+	if progress := SummarizeCrossCountry(entries); progress.Planned != 2 || progress.SoloHours != 0 || len(progress.Deficits()) != 3 {
+		t.Fatalf("expected planned flights not to count, got %+v", progress)
+	}
+
+	if _, err := MarkLogbookFlown(db, solo.ID, 0, day); err == nil {
+		t.Fatal("expected zero hours to be rejected")
+	}
+	if _, err := MarkLogbookFlown(db, 99, 2, day); err == nil || !strings.Contains(err.Error(), "no logbook entry 99") {
+		t.Fatalf("expected a missing entry error, got %v", err)
+	}
+	flown, err := MarkLogbookFlown(db, solo.ID, 2.1, day.AddDate(0, 0, 1))
+	if err != nil || flown.Hours != 2.1 || flown.FlownAt == nil || !flown.Date.Equal(time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected flown entry %+v, %v", flown, err)
+	}
+
+	entries, _ = ListLogbook(db)
+	if entries[0].ID != solo.ID {
+		t.Fatalf("expected the newest flight first, got %+v", entries[0])
+	}
+	progress := SummarizeCrossCountry(entries)
+	if progress.SoloHours != 2.1 || !progress.LongSoloXC || progress.Planned != 1 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	if deficits := strings.Join(progress.Deficits(), "; "); deficits != "0.0 of 3 hours dual cross-country (61.109(a)(1)); 2.1 of 5 hours solo cross-country (61.109(a)(5)(i))" {
+		t.Fatalf("unexpected deficits %q", deficits)
+	}
+}
+
+func TestLogFlight_CountsTowardFlightTime(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.LogbookEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	day := time.Date(2026, 6, 1, 15, 0, 0, 0, time.UTC)
+
+	lesson, err := LogFlight(db, FlightInput{Date: day, Hours: 1.3, Tail: " n12345 "})
+	if err != nil {
+		t.Fatalf("log lesson: %v", err)
+	}
+	if lesson.Route != "Local" || lesson.Tail != "N12345" || lesson.FlownAt == nil || lesson.Solo {
+		t.Fatalf("unexpected lesson %+v", lesson)
+	}
+	if _, err := LogFlight(db, FlightInput{Date: day, Hours: 2.4, Solo: true, CrossCountry: true, LongSoloXC: true}); err != nil {
+		t.Fatalf("log solo xc: %v", err)
+	}
+	for _, bad := range []FlightInput{
+		{Date: day},
+		{Date: day, Hours: 1, LongSoloXC: true},
+		{Hours: 1},
+	} {
+		if _, err := LogFlight(db, bad); err == nil {
+			t.Fatalf("expected %+v to be rejected", bad)
+		}
+	}
+
+	entries, err := ListLogbook(db)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	flown := SummarizeFlightTime(entries)
+	if flown != (FlightTime{Total: 3.7, Dual: 1.3, Solo: 2.4}) {
+		t.Fatalf("unexpected flight time %+v", flown)
+	}
+	if factor := flightHoursFactor(entries); factor.Score == 0 || !strings.Contains(factor.Detail, "3.7 hours logged") {
+		t.Fatalf("expected logged flights to count toward readiness, got %+v", factor)
+	}
+
+	if _, err := DeleteLogbookEntry(db, lesson.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := DeleteLogbookEntry(db, lesson.ID); err == nil {
+		t.Fatalf("expected deleting a missing entry to fail")
+	}
+	entries, _ = ListLogbook(db)
+	if flown := SummarizeFlightTime(entries); flown.Total != 2.4 || len(flown.Deficits()) != 3 {
+		t.Fatalf("unexpected flight time after delete %+v", flown)
+	}
+}
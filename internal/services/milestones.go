@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	MilestoneStateAchieved = "achieved"
+	MilestoneStateReady    = "ready"
+	MilestoneStateBlocked  = "blocked"
+)
+
+// MilestoneStatus is a template milestone evaluated against the database.
+type MilestoneStatus struct {
+	ID                string
+	Name              string
+	Description       string
+	State             string
+	TargetDate        time.Time
+	AchievedAt        *time.Time
+	Overdue           bool
+	TasksDone         int
+	TasksTotal        int
+	ChecklistDone     int
+	ChecklistTotal    int
+	MissingTasks      []string
+	MissingChecklist  []string
+	MissingMilestones []string
+}
+
+// Blockers summarizes what still stands between a milestone and ready.
+func (s MilestoneStatus) Blockers() string {
+	var parts []string
+	if len(s.MissingTasks) > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d tasks", s.TasksDone, s.TasksTotal))
+	}
+	if len(s.MissingChecklist) > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d checklist", s.ChecklistDone, s.ChecklistTotal))
+	}
+	if len(s.MissingMilestones) > 0 {
+		parts = append(parts, "after "+strings.Join(s.MissingMilestones, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// MilestoneEventPayload describes a milestone being achieved or reopened.
+type MilestoneEventPayload struct {
+	Milestone  string `json:"milestone"`
+	Achieved   bool   `json:"achieved"`
+	AchievedAt string `json:"achieved_at,omitempty"`
+	Source     string `json:"source"`
+}
+
+// LoadMilestoneStatuses evaluates the active plan template's milestones
+// against the current study plan.
+func LoadMilestoneStatuses(database *gorm.DB, now time.Time) ([]MilestoneStatus, error) {
+	tpl, _, err := LoadPlanTemplate()
+	if err != nil {
+		tpl = DefaultPlanTemplate()
+	}
+
+	var checkride time.Time
+	var plan model.StudyPlan
+	if err := database.Last(&plan).Error; err == nil {
+		checkride = plan.CheckrideDate
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("milestones: load study plan: %w", err)
+	}
+	return BuildMilestoneStatuses(database, tpl, checkride, now)
+}
+
+// BuildMilestoneStatuses evaluates every milestone in tpl. A prerequisite
+// task counts as done when any of its scheduled occurrences is completed;
+// checklist items are matched by title, ignoring case.
+func BuildMilestoneStatuses(database *gorm.DB, tpl PlanTemplate, checkride time.Time, now time.Time) ([]MilestoneStatus, error) {
+	if len(tpl.Milestones) == 0 {
+		return nil, nil
+	}
+
+	var completedIDs []string
+	if err := database.Model(&model.DailyTask{}).
+		Where("completed = ? AND template_task_id <> ''", true).
+		Distinct().Pluck("template_task_id", &completedIDs).Error; err != nil {
+		return nil, fmt.Errorf("milestones: load completed tasks: %w", err)
+	}
+	completedTasks := map[string]bool{}
+	for _, id := range completedIDs {
+		completedTasks[id] = true
+	}
+
+	var items []model.ChecklistItem
+	if err := database.Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("milestones: load checklist: %w", err)
+	}
+	checked := map[string]bool{}
+	for _, item := range items {
+		key := strings.ToLower(strings.TrimSpace(item.Title))
+		checked[key] = checked[key] || item.Completed
+	}
+
+	var records []model.Milestone
+	if err := database.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("milestones: load achievements: %w", err)
+	}
+	achieved := map[string]*time.Time{}
+	for _, record := range records {
+		if record.AchievedAt != nil {
+			achieved[record.Key] = record.AchievedAt
+		}
+	}
+
+	taskLabels := map[string]string{}
+	for _, category := range tpl.Categories {
+		for _, task := range category.Tasks {
+			taskLabels[task.ID] = firstNonEmpty(task.Area, category.TaskTitle(task))
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	names := map[string]string{}
+	statuses := make([]MilestoneStatus, 0, len(tpl.Milestones))
+	for _, milestone := range tpl.Milestones {
+		names[milestone.ID] = milestone.Name
+		status := MilestoneStatus{
+			ID:             milestone.ID,
+			Name:           milestone.Name,
+			Description:    milestone.Description,
+			TasksTotal:     len(milestone.Tasks),
+			ChecklistTotal: len(milestone.Checklist),
+			AchievedAt:     achieved[milestone.ID],
+		}
+		if !checkride.IsZero() {
+			status.TargetDate = checkride.AddDate(0, 0, -milestone.DaysBeforeCheckride)
+		}
+
+		for _, id := range milestone.Tasks {
+			if completedTasks[id] {
+				status.TasksDone++
+			} else {
+				status.MissingTasks = append(status.MissingTasks, taskLabels[id])
+			}
+		}
+		for _, title := range milestone.Checklist {
+			if checked[strings.ToLower(strings.TrimSpace(title))] {
+				status.ChecklistDone++
+			} else {
+				status.MissingChecklist = append(status.MissingChecklist, title)
+			}
+		}
+		for _, id := range milestone.Milestones {
+			if achieved[id] == nil {
+				status.MissingMilestones = append(status.MissingMilestones, names[id])
+			}
+		}
+
+		switch {
+		case status.AchievedAt != nil:
+			status.State = MilestoneStateAchieved
+		case len(status.MissingTasks) == 0 && len(status.MissingChecklist) == 0 && len(status.MissingMilestones) == 0:
+			status.State = MilestoneStateReady
+		default:
+			status.State = MilestoneStateBlocked
+		}
+		status.Overdue = status.AchievedAt == nil && !status.TargetDate.IsZero() && status.TargetDate.Before(today)
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// NextMilestone returns the first milestone that has not been achieved.
+func NextMilestone(statuses []MilestoneStatus) (MilestoneStatus, bool) {
+	for _, status := range statuses {
+		if status.State != MilestoneStateAchieved {
+			return status, true
+		}
+	}
+	return MilestoneStatus{}, false
+}
+
+// SetMilestoneAchieved marks a milestone achieved (or reopens it) and records
+// a milestone event in the same transaction. Setting the current state again
+// is a no-op and records nothing.
+func SetMilestoneAchieved(database *gorm.DB, key string, achieved bool, source string, now time.Time) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("milestone key is required")
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		var record model.Milestone
+		err := tx.Where("key = ?", key).First(&record).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("load milestone %q: %w", key, err)
+		}
+		if (record.AchievedAt != nil) == achieved {
+			return nil
+		}
+
+		record.Key = key
+		record.Source = source
+		record.AchievedAt = nil
+		payload := MilestoneEventPayload{Milestone: key, Achieved: achieved, Source: source}
+		eventType := EventMilestoneReopened
+		if achieved {
+			at := now.UTC()
+			record.AchievedAt = &at
+			payload.AchievedAt = at.Format(time.RFC3339)
+			eventType = EventMilestoneAchieved
+		}
+		if err := tx.Save(&record).Error; err != nil {
+			return fmt.Errorf("save milestone %q: %w", key, err)
+		}
+		return RecordEvent(tx, eventType, payload)
+	})
+}
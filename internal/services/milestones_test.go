@@ -0,0 +1,118 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func milestoneTestTemplate() PlanTemplate {
+	return PlanTemplate{
+		Version: 1,
+		Name:    "School",
+		Categories: []PlanTemplateCategory{{
+			Name: "Ground", EveryDays: 1, Tasks: []PlanTemplateTask{
+				{ID: "airspace", Area: "Airspace"},
+				{ID: "weather", Area: "Weather"},
+				{ID: "pattern", Area: "Pattern"},
+				{ID: "landings", Area: "Landings"},
+			},
+		}},
+		Milestones: []PlanTemplateMilestone{
+			{ID: "pre-solo", Name: "Pre-solo test", DaysBeforeCheckride: 10, Tasks: []string{"airspace"}, Checklist: []string{"Pre-solo knowledge test passed"}},
+			{ID: "first-solo", Name: "First solo", DaysBeforeCheckride: 5, Tasks: []string{"landings"}, Milestones: []string{"pre-solo"}},
+		},
+	}
+}
+
+func TestBuildMilestoneStatuses_TracksPrerequisites(t *testing.T) {
+	db := setupEventsTestDB(t)
+	tpl := milestoneTestTemplate()
+	checkride := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 6, 25, 12, 0, 0, 0, time.UTC)
+
+	db.Create(&model.DailyTask{Date: now, TemplateTaskID: "airspace", Title: "Airspace", Completed: true})
+	db.Create(&model.DailyTask{Date: now, TemplateTaskID: "landings", Title: "Landings"})
+	db.Create(&model.ChecklistItem{Category: model.CategoryFlight, Title: "pre-solo knowledge test passed"})
+
+	statuses, err := BuildMilestoneStatuses(db, tpl, checkride, now)
+	if err != nil {
+		t.Fatalf("BuildMilestoneStatuses returned error: %v", err)
+	}
+	preSolo, solo := statuses[0], statuses[1]
+	if preSolo.State != MilestoneStateBlocked || !preSolo.Overdue || preSolo.TasksDone != 1 || len(preSolo.MissingChecklist) != 1 {
+		t.Fatalf("unexpected pre-solo status: %+v", preSolo)
+	}
+	if !preSolo.TargetDate.Equal(time.Date(2026, 6, 20, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected target date: %s", preSolo.TargetDate)
+	}
+	if got := solo.Blockers(); got != "0/1 tasks · after Pre-solo test" {
+		t.Fatalf("unexpected first solo blockers: %q", got)
+	}
+
+	db.Model(&model.ChecklistItem{}).Where("1 = 1").Update("completed", true)
+	statuses, _ = BuildMilestoneStatuses(db, tpl, checkride, now)
+	if statuses[0].State != MilestoneStateReady {
+		t.Fatalf("expected pre-solo ready once checklist is checked, got %+v", statuses[0])
+	}
+
+	if err := SetMilestoneAchieved(db, "pre-solo", true, "test", now); err != nil {
+		t.Fatalf("SetMilestoneAchieved returned error: %v", err)
+	}
+	if err := SetMilestoneAchieved(db, "pre-solo", true, "test", now); err != nil {
+		t.Fatalf("repeat SetMilestoneAchieved returned error: %v", err)
+	}
+	statuses, _ = BuildMilestoneStatuses(db, tpl, checkride, now)
+	if statuses[0].State != MilestoneStateAchieved || statuses[0].Overdue {
+		t.Fatalf("expected pre-solo achieved, got %+v", statuses[0])
+	}
+	if next, ok := NextMilestone(statuses); !ok || next.ID != "first-solo" || len(next.MissingMilestones) != 0 {
+		t.Fatalf("expected first solo next with no milestone blockers, got %+v", next)
+	}
+
+	var events []model.OutboxEvent
+	db.Where("event_type LIKE ?", "milestone.%").Find(&events)
+	if len(events) != 1 || events[0].EventType != EventMilestoneAchieved || !strings.Contains(events[0].PayloadJSON, `"milestone":"pre-solo"`) {
+		t.Fatalf("expected a single milestone.achieved event, got %+v", events)
+	}
+}
+
+func TestGenerateStudyPlanFromTemplate_SchedulesGateTasksBeforeTarget(t *testing.T) {
+	tpl := milestoneTestTemplate()
+	tpl.Milestones = []PlanTemplateMilestone{{ID: "first-solo", Name: "First solo", DaysBeforeCheckride: 8, Tasks: []string{"landings"}}}
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tasks := GenerateStudyPlanFromTemplate(tpl, now.AddDate(0, 0, 9), 10, now)
+
+	if tasks[0].TemplateTaskID != "landings" {
+		t.Fatalf("expected gate task on the first day, got %q", tasks[0].TemplateTaskID)
+	}
+	var rest []string
+	for _, task := range tasks[1:5] {
+		rest = append(rest, task.TemplateTaskID)
+	}
+	if got := strings.Join(rest, ","); got != "airspace,weather,pattern,landings" {
+		t.Fatalf("expected rotation to resume after the gate task, got %s", got)
+	}
+}
+
+func TestValidatePlanTemplate_ChecksMilestoneReferences(t *testing.T) {
+	tpl := milestoneTestTemplate()
+	tpl.Milestones = append(tpl.Milestones, PlanTemplateMilestone{
+		ID: "checkride", Name: "Checkride", Tasks: []string{"night"}, Milestones: []string{"later"},
+	})
+	tpl.Milestones[0].Milestones = []string{"first-solo"}
+
+	joined := strings.Join(ValidatePlanTemplate(tpl), "\n")
+	for _, want := range []string{
+		`milestone "pre-solo": milestone "first-solo" must be listed before it`,
+		`milestone "checkride": unknown task "night"`,
+		`milestone "checkride": milestone "later" must be listed before it`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected problem %q in:\n%s", want, joined)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MinimumsSet is one set of personal weather limits. Night reports whether
+// flights after dark are within them.
+type MinimumsSet struct {
+	CeilingFt       int     `json:"ceiling_ft"`
+	VisibilitySM    float64 `json:"visibility_sm"`
+	MaxWindKt       int     `json:"max_wind_kt"`
+	MaxCrosswindKt  int     `json:"max_crosswind_kt"`
+	MaxGustSpreadKt int     `json:"max_gust_spread_kt"`
+	Night           bool    `json:"night"`
+}
+
+// PersonalMinimums is the student's minimums profile, read from
+// ~/.openppl/minimums.json (or OPENPPL_MINIMUMS_PATH), with separate limits
+// for dual lessons and solo flights. Runways lists the school airport's
+// runway designators ("09", "27L", ...); when it is empty, ForAirport takes
+// them from the airport database.
+type PersonalMinimums struct {
+	Dual    MinimumsSet `json:"dual"`
+	Solo    MinimumsSet `json:"solo"`
+	Runways []string    `json:"runways,omitempty"`
+}
+
+// DefaultPersonalMinimums are conservative student limits: VFR with margin
+// for dual lessons, and daytime, light-wind weather for solo flights.
+func DefaultPersonalMinimums() PersonalMinimums {
+	return PersonalMinimums{
+		Dual: MinimumsSet{CeilingFt: 2000, VisibilitySM: 5, MaxWindKt: 20, MaxCrosswindKt: 12, MaxGustSpreadKt: 10, Night: true},
+		Solo: MinimumsSet{CeilingFt: 3000, VisibilitySM: 6, MaxWindKt: 12, MaxCrosswindKt: 8, MaxGustSpreadKt: 5},
+	}
+}
+
+// Set returns the solo or dual limits.
+func (m PersonalMinimums) Set(solo bool) MinimumsSet {
+	if solo {
+		return m.Solo
+	}
+	return m.Dual
+}
+
+// ForAirport fills in the airport's runways from the airport database when
+// none are configured, so crosswind is checked wherever the runways are
+// known.
+func (m PersonalMinimums) ForAirport(code string) PersonalMinimums {
+	if len(m.Runways) > 0 {
+		return m
+	}
+	if airport, ok := LookupAirport(code); ok {
+		m.Runways = airport.RunwayEnds()
+	}
+	return m
+}
+
+func personalMinimumsPath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("OPENPPL_MINIMUMS_PATH")); path != "" {
+		return path, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "minimums.json"), nil
+}
+
+// LoadPersonalMinimums reads the saved minimums, falling back to the
+// defaults when none were saved.
+func LoadPersonalMinimums() (PersonalMinimums, error) {
+	path, err := personalMinimumsPath()
+	if err != nil {
+		return DefaultPersonalMinimums(), err
+	}
+	return loadPersonalMinimumsFromPath(path)
+}
+
+func loadPersonalMinimumsFromPath(path string) (PersonalMinimums, error) {
+	minimums := DefaultPersonalMinimums()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return minimums, nil
+		}
+		return minimums, fmt.Errorf("minimums: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return minimums, nil
+	}
+	if err := json.Unmarshal(data, &minimums); err != nil {
+		return DefaultPersonalMinimums(), fmt.Errorf("minimums: parse config: %w", err)
+	}
+	if err := minimums.Validate(); err != nil {
+		return DefaultPersonalMinimums(), err
+	}
+	return minimums, nil
+}
+
+// SavePersonalMinimums validates and writes the minimums.
+func SavePersonalMinimums(minimums PersonalMinimums) error {
+	if err := minimums.Validate(); err != nil {
+		return err
+	}
+	path, err := personalMinimumsPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(minimums, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Validate rejects negative limits and runway designators that are not
+// 01-36 with an optional L, C or R.
+func (m PersonalMinimums) Validate() error {
+	var problems []string
+	for name, set := range map[string]MinimumsSet{"dual": m.Dual, "solo": m.Solo} {
+		if set.CeilingFt < 0 || set.VisibilitySM < 0 || set.MaxWindKt < 0 || set.MaxCrosswindKt < 0 || set.MaxGustSpreadKt < 0 {
+			problems = append(problems, name+" limits must not be negative")
+		}
+	}
+	sort.Strings(problems)
+	for _, runway := range m.Runways {
+		if _, err := RunwayHeading(runway); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("minimums: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// RunwayHeading converts a runway designator such as "09" or "27L" to its
+// heading in degrees. Runway numbers are magnetic and winds true; the
+// difference is ignored.
+func RunwayHeading(designator string) (int, error) {
+	number := strings.TrimRightFunc(strings.ToUpper(strings.TrimSpace(designator)), func(r rune) bool {
+		return r == 'L' || r == 'C' || r == 'R'
+	})
+	value, err := strconv.Atoi(number)
+	if err != nil || value < 1 || value > 36 || strings.IndexFunc(number, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0 {
+		return 0, fmt.Errorf("invalid runway %q", designator)
+	}
+	return value * 10, nil
+}
+
+// Violations lists how conditions fall outside the solo or dual limits, or
+// nothing when they are within them. Crosswind is judged on the best
+// configured runway.
+func (m PersonalMinimums) Violations(conditions WeatherConditions, solo bool) []string {
+	set := m.Set(solo)
+	var violations []string
+	if ceiling, ok := conditions.CeilingFt(); ok && ceiling < set.CeilingFt {
+		violations = append(violations, fmt.Sprintf("ceiling %d ft below %d ft", ceiling, set.CeilingFt))
+	}
+	if conditions.VisibilitySM >= 0 && conditions.VisibilitySM < set.VisibilitySM {
+		violations = append(violations, fmt.Sprintf("visibility %s SM below %s SM", formatMiles(conditions.VisibilitySM), formatMiles(set.VisibilitySM)))
+	}
+	wind := conditions.Wind
+	if wind == nil {
+		return violations
+	}
+	if strongest := max(wind.SpeedKt, wind.GustKt); strongest > set.MaxWindKt {
+		violations = append(violations, fmt.Sprintf("wind %d kt above %d kt", strongest, set.MaxWindKt))
+	}
+	if wind.GustKt > 0 && wind.GustKt-wind.SpeedKt > set.MaxGustSpreadKt {
+		violations = append(violations, fmt.Sprintf("gust spread %d kt above %d kt", wind.GustKt-wind.SpeedKt, set.MaxGustSpreadKt))
+	}
+	if len(m.Runways) > 0 {
+		best, bestRunway := -1, ""
+		for _, runway := range m.Runways {
+			heading, err := RunwayHeading(runway)
+			if err != nil {
+				continue
+			}
+			if crosswind := wind.CrosswindKt(heading); best < 0 || crosswind < best {
+				best, bestRunway = crosswind, strings.ToUpper(runway)
+			}
+		}
+		if best > set.MaxCrosswindKt {
+			violations = append(violations, fmt.Sprintf("crosswind %d kt on runway %s above %d kt", best, bestRunway, set.MaxCrosswindKt))
+		}
+	}
+	return violations
+}
+
+func formatMiles(miles float64) string {
+	return strconv.FormatFloat(miles, 'f', -1, 64)
+}
@@ -18,6 +18,8 @@ import (
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
 )
 
 //go:embed acs_private_airplane_6c.json
@@ -369,13 +371,28 @@ func InitMOTDDB() (*gorm.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("motd: open db: %w", err)
 	}
-	if err := db.AutoMigrate(&MOTDAnswer{}); err != nil {
+	if err := db.AutoMigrate(&MOTDAnswer{}, &model.OutboxEvent{}); err != nil {
 		return nil, fmt.Errorf("motd: migrate: %w", err)
 	}
 	return db, nil
 }
 
-// SaveMOTDAttempt upserts a quiz attempt for the given date.
+// OpenExistingMOTDDB opens the MOTD database if the quiz has been taken,
+// and returns nil without creating it otherwise.
+func OpenExistingMOTDDB() (*gorm.DB, error) {
+	dir, err := motdDataDir()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, "motd_answers.db")); err != nil {
+		return nil, nil
+	}
+	return InitMOTDDB()
+}
+
+// SaveMOTDAttempt upserts a quiz attempt for the given date and records a
+// quiz.answered event in the same transaction, then refreshes the quiz
+// streak in the MOTD cache.
 func SaveMOTDAttempt(db *gorm.DB, date string, quiz MOTDDailyQuiz, selected string, skipped bool) error {
 	if strings.TrimSpace(date) == "" {
 		date = time.Now().Format("2006-01-02")
@@ -388,38 +405,58 @@ func SaveMOTDAttempt(db *gorm.DB, date string, quiz MOTDDailyQuiz, selected stri
 		legacyAnswer = ""
 	}
 
-	var record MOTDAnswer
-	result := db.Where(MOTDAnswer{Date: date}).
-		Assign(MOTDAnswer{
-			ACSCode:        quiz.Entry.Code,
-			Prompt:         quiz.Prompt,
-			SelectedOption: selected,
-			CorrectOption:  quiz.CorrectLabel,
-			SelectedText:   selectedText,
-			CorrectText:    correctText,
-			Answer:         legacyAnswer,
-			IsCorrect:      isCorrect,
-			Skipped:        skipped,
-		}).
-		FirstOrCreate(&record)
-	if result.Error != nil {
-		return fmt.Errorf("motd: save attempt: %w", result.Error)
-	}
-	// If the record already existed, FirstOrCreate won't apply Assign fields.
-	if result.RowsAffected == 0 {
-		record.ACSCode = quiz.Entry.Code
-		record.Prompt = quiz.Prompt
-		record.SelectedOption = selected
-		record.CorrectOption = quiz.CorrectLabel
-		record.SelectedText = selectedText
-		record.CorrectText = correctText
-		record.Answer = legacyAnswer
-		record.IsCorrect = isCorrect
-		record.Skipped = skipped
-		if err := db.Save(&record).Error; err != nil {
-			return fmt.Errorf("motd: update attempt: %w", err)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var record MOTDAnswer
+		result := tx.Where(MOTDAnswer{Date: date}).
+			Assign(MOTDAnswer{
+				ACSCode:        quiz.Entry.Code,
+				Prompt:         quiz.Prompt,
+				SelectedOption: selected,
+				CorrectOption:  quiz.CorrectLabel,
+				SelectedText:   selectedText,
+				CorrectText:    correctText,
+				Answer:         legacyAnswer,
+				IsCorrect:      isCorrect,
+				Skipped:        skipped,
+			}).
+			FirstOrCreate(&record)
+		if result.Error != nil {
+			return fmt.Errorf("motd: save attempt: %w", result.Error)
 		}
+		// If the record already existed, FirstOrCreate won't apply Assign fields.
+		if result.RowsAffected == 0 {
+			record.ACSCode = quiz.Entry.Code
+			record.Prompt = quiz.Prompt
+			record.SelectedOption = selected
+			record.CorrectOption = quiz.CorrectLabel
+			record.SelectedText = selectedText
+			record.CorrectText = correctText
+			record.Answer = legacyAnswer
+			record.IsCorrect = isCorrect
+			record.Skipped = skipped
+			if err := tx.Save(&record).Error; err != nil {
+				return fmt.Errorf("motd: update attempt: %w", err)
+			}
+		}
+
+		// Quiz answers live in the per-user MOTD database, so the event goes
+		// into that database's outbox; `openppl webhooks deliver` flushes both.
+		if err := RecordEvent(tx, EventQuizAnswered, QuizEventPayload{
+			Date:      date,
+			ACSCode:   quiz.Entry.Code,
+			Selected:  selected,
+			Correct:   quiz.CorrectLabel,
+			IsCorrect: isCorrect,
+			Skipped:   skipped,
+		}); err != nil {
+			return fmt.Errorf("motd: record event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	_ = writeQuizStreakCache(db)
 	return nil
 }
 
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MOTDCache is what the login MOTD shows besides the quiz itself. It is
+// written to ~/.openppl/motd_cache.json whenever documents change or a quiz
+// attempt is saved, so `openppl motd display` never opens a database.
+type MOTDCache struct {
+	Documents  []DocumentCacheEntry `json:"documents"`
+	QuizStreak QuizStreakCache      `json:"quiz_streak"`
+}
+
+// QuizStreakCache is the quiz streak as of the last day the quiz was
+// answered rather than skipped.
+type QuizStreakCache struct {
+	LastAnswered string `json:"last_answered,omitempty"`
+	Current      int    `json:"current"`
+	Longest      int    `json:"longest"`
+}
+
+func motdCachePath() (string, error) {
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "motd_cache.json"), nil
+}
+
+// LoadMOTDCache reads the MOTD cache. It never fails: a missing or
+// unreadable cache is empty.
+func LoadMOTDCache() MOTDCache {
+	var cache MOTDCache
+	path, err := motdCachePath()
+	if err != nil {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return MOTDCache{}
+	}
+	return cache
+}
+
+func updateMOTDCache(update func(*MOTDCache)) error {
+	cache := LoadMOTDCache()
+	update(&cache)
+	path, err := motdCachePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// DocumentWarnings returns expiry warnings for the cached documents.
+func (c MOTDCache) DocumentWarnings(now time.Time) []string {
+	var warnings []string
+	for _, entry := range c.Documents {
+		expires, err := time.Parse("2006-01-02", entry.ExpiresOn)
+		if err != nil {
+			continue
+		}
+		warnings = append(warnings, documentWarning(entry.Title, expires, time.Time{}, dateOnlyUTC(now))...)
+	}
+	return warnings
+}
+
+// QuizStreaks returns the cached quiz streak as of now. The current streak
+// survives until the end of the day after the last answer.
+func (c MOTDCache) QuizStreaks(now time.Time) StudyStreaks {
+	last, err := time.Parse("2006-01-02", c.QuizStreak.LastAnswered)
+	if err != nil {
+		return StudyStreaks{}
+	}
+	today := dateOnlyUTC(now)
+	streaks := StudyStreaks{Longest: c.QuizStreak.Longest, Today: last.Equal(today)}
+	if !last.Before(today.AddDate(0, 0, -1)) {
+		streaks.Current = c.QuizStreak.Current
+	}
+	return streaks
+}
+
+// writeQuizStreakCache refreshes the quiz streak in the MOTD cache from the
+// attempts in the MOTD database.
+func writeQuizStreakCache(db *gorm.DB) error {
+	attempts, err := LoadMOTDAttempts(db)
+	if err != nil {
+		return err
+	}
+	var streak QuizStreakCache
+	for _, attempt := range attempts {
+		if !attempt.Skipped && attempt.Date > streak.LastAnswered {
+			streak.LastAnswered = attempt.Date
+		}
+	}
+	if last, err := time.Parse("2006-01-02", streak.LastAnswered); err == nil {
+		streaks := ComputeStudyStreaks(nil, attempts, last, 1)
+		streak.Current, streak.Longest = streaks.Current, streaks.Longest
+	}
+	return updateMOTDCache(func(cache *MOTDCache) {
+		cache.QuizStreak = streak
+	})
+}
@@ -1,11 +1,16 @@
 package services_test
 
 import (
+	"fmt"
 	"math"
 	"strings"
 	"testing"
 	"time"
 
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
 	"ppl-study-planner/internal/services"
 )
 
@@ -83,6 +88,72 @@ func TestBuildDailyQuiz_DeterministicForSameDay(t *testing.T) {
 	}
 }
 
+func TestSaveMOTDAttempt_SavesAttemptAndEventTogether(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&services.MOTDAnswer{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	quiz, err := services.BuildDailyQuiz(time.Date(2026, 3, 16, 8, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("BuildDailyQuiz returned error: %v", err)
+	}
+
+	// Without an outbox table the event cannot be written, so the attempt
+	// must not be saved either.
+	if err := services.SaveMOTDAttempt(db, "2026-03-16", quiz, quiz.CorrectLabel, false); err == nil {
+		t.Fatal("expected an error without an outbox table")
+	}
+	attempts, err := services.LoadMOTDAttempts(db)
+	if err != nil || len(attempts) != 0 {
+		t.Fatalf("expected the attempt to roll back, got %+v (%v)", attempts, err)
+	}
+
+	if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+		t.Fatalf("automigrate outbox: %v", err)
+	}
+	if err := services.SaveMOTDAttempt(db, "2026-03-16", quiz, quiz.CorrectLabel, false); err != nil {
+		t.Fatalf("SaveMOTDAttempt returned error: %v", err)
+	}
+	var events int64
+	db.Model(&model.OutboxEvent{}).Where("event_type = ?", services.EventQuizAnswered).Count(&events)
+	if attempts, _ = services.LoadMOTDAttempts(db); len(attempts) != 1 || events != 1 {
+		t.Fatalf("expected one attempt and one event, got %d and %d", len(attempts), events)
+	}
+}
+
+func TestSaveMOTDAttempt_CachesQuizStreak(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&services.MOTDAnswer{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	for _, day := range []string{"2026-06-08", "2026-06-09", "2026-06-10"} {
+		date, _ := time.Parse("2006-01-02", day)
+		quiz, err := services.BuildDailyQuiz(date)
+		if err != nil {
+			t.Fatalf("BuildDailyQuiz returned error: %v", err)
+		}
+		if err := services.SaveMOTDAttempt(db, day, quiz, quiz.CorrectLabel, day == "2026-06-10"); err != nil {
+			t.Fatalf("SaveMOTDAttempt returned error: %v", err)
+		}
+	}
+
+	cache := services.LoadMOTDCache()
+	if streaks := cache.QuizStreaks(time.Date(2026, 6, 10, 7, 0, 0, 0, time.UTC)); streaks.Current != 2 || streaks.Longest != 2 || streaks.Today {
+		t.Fatalf("expected a 2-day streak still open today, got %+v", streaks)
+	}
+	if streaks := cache.QuizStreaks(time.Date(2026, 6, 11, 7, 0, 0, 0, time.UTC)); streaks.Current != 0 || streaks.Longest != 2 {
+		t.Fatalf("expected the streak to lapse after a missed day, got %+v", streaks)
+	}
+}
+
 func TestNormalizeQuizChoice(t *testing.T) {
 	cases := map[string]string{
 		"a":        "A",
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	// dayVFRReserveMinutes and nightVFRReserveMinutes are the fuel reserves
+	// at normal cruise of 91.151(a).
+	dayVFRReserveMinutes   = 30
+	nightVFRReserveMinutes = 45
+)
+
+// WindAloft is a winds aloft forecast for the cruise altitude, blowing from
+// DirectionTrue degrees true.
+type WindAloft struct {
+	DirectionTrue int
+	SpeedKt       int
+}
+
+// ParseWindAloft reads a wind as "270@15", "27015" (the winds aloft
+// forecast's form) or "calm".
+func ParseWindAloft(text string) (WindAloft, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "calm" || text == "0" {
+		return WindAloft{}, nil
+	}
+	direction, speed, found := strings.Cut(text, "@")
+	if !found && len(text) >= 4 {
+		direction, speed = text[:3], text[3:]
+	}
+	dir, dirErr := strconv.Atoi(direction)
+	kt, speedErr := strconv.Atoi(speed)
+	if dirErr != nil || speedErr != nil || dir < 0 || dir > 360 || kt < 0 {
+		return WindAloft{}, fmt.Errorf("invalid wind %q, want DDD@SS such as 270@15", text)
+	}
+	return WindAloft{DirectionTrue: dir % 360, SpeedKt: kt}, nil
+}
+
+func (w WindAloft) String() string {
+	if w.SpeedKt == 0 {
+		return "calm"
+	}
+	return fmt.Sprintf("%03d@%d", w.DirectionTrue, w.SpeedKt)
+}
+
+// AircraftPerformance is what the navlog takes from the aircraft profile.
+type AircraftPerformance struct {
+	Tail          string
+	Type          string
+	CruiseKTAS    float64
+	FuelBurnGPH   float64
+	UsableFuelGal float64
+}
+
+// PerformanceFor reads the performance numbers off an aircraft.
+func PerformanceFor(aircraft model.Aircraft) AircraftPerformance {
+	return AircraftPerformance{
+		Tail:          aircraft.TailNumber,
+		Type:          aircraft.Type,
+		CruiseKTAS:    aircraft.CruiseKTAS,
+		FuelBurnGPH:   aircraft.FuelBurnGPH,
+		UsableFuelGal: aircraft.UsableFuelGal,
+	}
+}
+
+// LoadAircraftPerformance reads the performance of the aircraft with the
+// tail number, or of the only registered aircraft when tail is empty. With
+// no tail and no single aircraft the performance is left empty.
+func LoadAircraftPerformance(database *gorm.DB, tail string) (AircraftPerformance, error) {
+	if strings.TrimSpace(tail) != "" {
+		tail, err := NormalizeTailNumber(tail)
+		if err != nil {
+			return AircraftPerformance{}, err
+		}
+		aircraft, err := findAircraft(database, tail)
+		if err != nil {
+			return AircraftPerformance{}, err
+		}
+		return PerformanceFor(aircraft), nil
+	}
+	registered, err := ListAircraft(database)
+	if err != nil || len(registered) != 1 {
+		return AircraftPerformance{}, err
+	}
+	return PerformanceFor(registered[0]), nil
+}
+
+// NavlogOptions are the inputs to a navlog besides the route. Winds has one
+// wind for every leg, or a single wind used for all of them. VariationDeg is
+// the magnetic variation, west positive ("east is least, west is best").
+type NavlogOptions struct {
+	Performance  AircraftPerformance
+	Winds        []WindAloft
+	VariationDeg float64
+	Night        bool
+	Date         time.Time
+}
+
+// NavlogLeg is one line of the navlog. WindCorrection is in degrees, positive
+// to the right.
+type NavlogLeg struct {
+	RouteLeg
+	TrueCourse      float64
+	Wind            WindAloft
+	WindCorrection  float64
+	TrueHeading     float64
+	MagneticHeading float64
+	GroundSpeedKt   float64
+	Minutes         float64
+	FuelGal         float64
+}
+
+// Navlog is a cross-country flight plan worked out leg by leg.
+type Navlog struct {
+	Route   Route
+	Options NavlogOptions
+	Legs    []NavlogLeg
+}
+
+// BuildNavlog works out course, wind correction, ground speed, time and fuel
+// for every leg of the route.
+func BuildNavlog(route Route, options NavlogOptions) (Navlog, error) {
+	tas := options.Performance.CruiseKTAS
+	if tas <= 0 {
+		return Navlog{}, errors.New("no cruise speed: set one on the aircraft with --cruise or pass --tas")
+	}
+	if n := len(options.Winds); n > 1 && n != len(route.Legs) {
+		return Navlog{}, fmt.Errorf("got %d winds for %d legs; give one wind or one per leg", n, len(route.Legs))
+	}
+
+	navlog := Navlog{Route: route, Options: options}
+	for i, leg := range route.Legs {
+		var wind WindAloft
+		switch len(options.Winds) {
+		case 0:
+		case 1:
+			wind = options.Winds[0]
+		default:
+			wind = options.Winds[i]
+		}
+
+		course := TrueCourse(leg.From, leg.To)
+		windAngle := (float64(wind.DirectionTrue) - course) * math.Pi / 180
+		crosswind := float64(wind.SpeedKt) * math.Sin(windAngle)
+		if math.Abs(crosswind) >= tas {
+			return Navlog{}, fmt.Errorf("%s-%s: wind %s is too strong for %.0f KTAS", leg.From.ICAO, leg.To.ICAO, wind, tas)
+		}
+		correction := math.Asin(crosswind / tas)
+		groundSpeed := tas*math.Cos(correction) - float64(wind.SpeedKt)*math.Cos(windAngle)
+		if groundSpeed <= 0 {
+			return Navlog{}, fmt.Errorf("%s-%s: wind %s leaves no ground speed at %.0f KTAS", leg.From.ICAO, leg.To.ICAO, wind, tas)
+		}
+
+		line := NavlogLeg{
+			RouteLeg:       leg,
+			TrueCourse:     course,
+			Wind:           wind,
+			WindCorrection: correction * 180 / math.Pi,
+			GroundSpeedKt:  groundSpeed,
+			Minutes:        leg.DistanceNM / groundSpeed * 60,
+		}
+		line.TrueHeading = normalizeHeading(course + line.WindCorrection)
+		line.MagneticHeading = normalizeHeading(line.TrueHeading + options.VariationDeg)
+		line.FuelGal = line.Minutes / 60 * options.Performance.FuelBurnGPH
+		navlog.Legs = append(navlog.Legs, line)
+	}
+	return navlog, nil
+}
+
+// TrueCourse is the initial great-circle course from one airport to another,
+// in degrees true.
+func TrueCourse(from, to Airport) float64 {
+	lat1, lat2 := from.Latitude*math.Pi/180, to.Latitude*math.Pi/180
+	dLon := (to.Longitude - from.Longitude) * math.Pi / 180
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return normalizeHeading(math.Atan2(y, x) * 180 / math.Pi)
+}
+
+func normalizeHeading(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// TotalMinutes is the time en route.
+func (n Navlog) TotalMinutes() float64 {
+	total := 0.0
+	for _, leg := range n.Legs {
+		total += leg.Minutes
+	}
+	return total
+}
+
+// TripFuelGal is the fuel burned en route.
+func (n Navlog) TripFuelGal() float64 {
+	total := 0.0
+	for _, leg := range n.Legs {
+		total += leg.FuelGal
+	}
+	return total
+}
+
+// ReserveMinutes is the VFR fuel reserve of 91.151(a).
+func (n Navlog) ReserveMinutes() int {
+	if n.Options.Night {
+		return nightVFRReserveMinutes
+	}
+	return dayVFRReserveMinutes
+}
+
+// RequiredFuelGal is the trip fuel plus the reserve.
+func (n Navlog) RequiredFuelGal() float64 {
+	return n.TripFuelGal() + float64(n.ReserveMinutes())/60*n.Options.Performance.FuelBurnGPH
+}
+
+// Problems lists what keeps the plan from working: fuel it cannot compute
+// or that the aircraft does not carry.
+func (n Navlog) Problems() []string {
+	performance := n.Options.Performance
+	if performance.FuelBurnGPH <= 0 {
+		return []string{"no fuel burn set; fuel was not computed"}
+	}
+	if performance.UsableFuelGal > 0 && n.RequiredFuelGal() > performance.UsableFuelGal {
+		return []string{fmt.Sprintf("needs %.1f gal with the %d-minute reserve, more than the %.0f gal usable", n.RequiredFuelGal(), n.ReserveMinutes(), performance.UsableFuelGal)}
+	}
+	return nil
+}
+
+// Lines renders the navlog as fixed-width text, with a blank ATA column to
+// fill in during the flight.
+func (n Navlog) Lines() []string {
+	performance := n.Options.Performance
+	title := "Navlog " + n.routeText()
+	if !n.Options.Date.IsZero() {
+		title += "  " + n.Options.Date.Format("2006-01-02")
+	}
+	aircraft := fmt.Sprintf("%.0f KTAS, %.1f gal/h", performance.CruiseKTAS, performance.FuelBurnGPH)
+	if performance.Tail != "" {
+		label := performance.Tail
+		if performance.Type != "" {
+			label += " (" + performance.Type + ")"
+		}
+		aircraft = label + ", " + aircraft
+	}
+	if n.Options.VariationDeg != 0 {
+		aircraft += ", variation " + formatVariation(n.Options.VariationDeg)
+	}
+
+	lines := []string{
+		title,
+		aircraft,
+		"",
+		fmt.Sprintf("%-11s %6s %4s %-7s %4s %4s %4s %4s %5s %5s  %s", "Leg", "Dist", "TC", "Wind", "WCA", "TH", "MH", "GS", "ETE", "Fuel", "ATA"),
+	}
+	for _, leg := range n.Legs {
+		lines = append(lines, fmt.Sprintf("%-11s %6.1f %4s %-7s %+4.0f %4s %4s %4.0f %5s %5.1f  _____",
+			leg.From.ICAO+"-"+leg.To.ICAO, leg.DistanceNM, formatHeading(leg.TrueCourse), leg.Wind,
+			leg.WindCorrection, formatHeading(leg.TrueHeading), formatHeading(leg.MagneticHeading),
+			leg.GroundSpeedKt, formatMinutes(leg.Minutes), leg.FuelGal))
+	}
+	lines = append(lines,
+		fmt.Sprintf("%-11s %6.1f %32s %5s %5.1f", "Total", n.Route.TotalNM(), "", formatMinutes(n.TotalMinutes()), n.TripFuelGal()),
+		fmt.Sprintf("Fuel: %.1f gal trip + %d min reserve (91.151) = %.1f gal", n.TripFuelGal(), n.ReserveMinutes(), n.RequiredFuelGal()),
+	)
+	if performance.UsableFuelGal > 0 {
+		lines[len(lines)-1] += fmt.Sprintf(" of %.0f usable", performance.UsableFuelGal)
+	}
+	for _, problem := range n.Problems() {
+		lines = append(lines, "  ! "+problem)
+	}
+
+	lines = append(lines, "")
+	farthest, distance := n.Route.FarthestLandingNM()
+	if n.Route.CountsAsCrossCountry() {
+		lines = append(lines, fmt.Sprintf("Cross-country (61.1): yes, landing at %s %.1f nm from %s", farthest.ICAO, distance, n.Route.Airports[0].ICAO))
+	} else {
+		lines = append(lines, fmt.Sprintf("Cross-country (61.1): no, farthest landing %.1f nm, needs over 50 nm", distance))
+	}
+	if problems := n.Route.SoloCrossCountryProblems(); len(problems) == 0 {
+		lines = append(lines, "Long solo cross-country (61.109(a)(5)(ii)): meets 150 nm total, 3 landings, a leg over 50 nm")
+	} else {
+		lines = append(lines, "Long solo cross-country (61.109(a)(5)(ii)): no, "+strings.Join(problems, "; "))
+	}
+	return lines
+}
+
+// WriteText writes the navlog as printed by Lines.
+func (n Navlog) WriteText(w io.Writer) error {
+	for _, line := range n.Lines() {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes one row per leg and a total row, for spreadsheets.
+func (n Navlog) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	rows := [][]string{{"from", "to", "distance_nm", "true_course", "wind", "wca", "true_heading", "magnetic_heading", "ground_speed_kt", "ete_min", "fuel_gal", "ata"}}
+	for _, leg := range n.Legs {
+		rows = append(rows, []string{
+			leg.From.ICAO, leg.To.ICAO,
+			strconv.FormatFloat(leg.DistanceNM, 'f', 1, 64),
+			formatHeading(leg.TrueCourse),
+			leg.Wind.String(),
+			strconv.FormatFloat(leg.WindCorrection, 'f', 0, 64),
+			formatHeading(leg.TrueHeading),
+			formatHeading(leg.MagneticHeading),
+			strconv.FormatFloat(leg.GroundSpeedKt, 'f', 0, 64),
+			strconv.FormatFloat(leg.Minutes, 'f', 0, 64),
+			strconv.FormatFloat(leg.FuelGal, 'f', 1, 64),
+			"",
+		})
+	}
+	rows = append(rows, []string{
+		n.Route.Airports[0].ICAO, n.Route.Airports[len(n.Route.Airports)-1].ICAO,
+		strconv.FormatFloat(n.Route.TotalNM(), 'f', 1, 64),
+		"", "", "", "", "", "",
+		strconv.FormatFloat(n.TotalMinutes(), 'f', 0, 64),
+		strconv.FormatFloat(n.TripFuelGal(), 'f', 1, 64),
+		"",
+	})
+	if err := writer.WriteAll(rows); err != nil {
+		return fmt.Errorf("write navlog csv: %w", err)
+	}
+	return nil
+}
+
+// WritePDF writes the navlog as a printable PDF.
+func (n Navlog) WritePDF(w io.Writer) error {
+	return writeTextPDF(w, n.Lines())
+}
+
+func (n Navlog) routeText() string {
+	codes := make([]string, 0, len(n.Route.Airports))
+	for _, airport := range n.Route.Airports {
+		codes = append(codes, airport.ICAO)
+	}
+	return strings.Join(codes, " ")
+}
+
+func formatHeading(degrees float64) string {
+	rounded := int(math.Round(degrees)) % 360
+	if rounded == 0 {
+		rounded = 360
+	}
+	return fmt.Sprintf("%03d", rounded)
+}
+
+func formatMinutes(minutes float64) string {
+	rounded := int(math.Round(minutes))
+	return fmt.Sprintf("%d:%02d", rounded/60, rounded%60)
+}
+
+// ParseVariation reads a magnetic variation such as "6W" or "3.5E" into
+// degrees, west positive. A bare number is taken as west positive.
+func ParseVariation(text string) (float64, error) {
+	original := text
+	text = strings.ToUpper(strings.TrimSpace(text))
+	sign := 1.0
+	switch {
+	case strings.HasSuffix(text, "W"):
+		text = strings.TrimSuffix(text, "W")
+	case strings.HasSuffix(text, "E"):
+		text, sign = strings.TrimSuffix(text, "E"), -1
+	}
+	degrees, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.Abs(degrees) > 180 {
+		return 0, fmt.Errorf("invalid variation %q, want a value such as 6W or 3E", original)
+	}
+	return sign * degrees, nil
+}
+
+func formatVariation(degrees float64) string {
+	if degrees < 0 {
+		return strconv.FormatFloat(-degrees, 'f', -1, 64) + "E"
+	}
+	return strconv.FormatFloat(degrees, 'f', -1, 64) + "W"
+}
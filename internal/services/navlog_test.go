@@ -0,0 +1,136 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWindAloft(t *testing.T) {
+	for text, want := range map[string]WindAloft{
+		"270@15": {DirectionTrue: 270, SpeedKt: 15},
+		"36020":  {DirectionTrue: 0, SpeedKt: 20},
+		"calm":   {},
+	} {
+		got, err := ParseWindAloft(text)
+		if err != nil || got != want {
+			t.Fatalf("%s: expected %+v, got %+v, %v", text, want, got, err)
+		}
+	}
+	if _, err := ParseWindAloft("west@15"); err == nil {
+		t.Fatal("expected an invalid wind error")
+	}
+	if got, err := ParseVariation("6W"); err != nil || got != 6 {
+		t.Fatalf("expected 6W to be +6, got %v, %v", got, err)
+	}
+	if got, err := ParseVariation("3.5e"); err != nil || got != -3.5 {
+		t.Fatalf("expected 3.5E to be -3.5, got %v, %v", got, err)
+	}
+}
+
+func testNavlog(t *testing.T, options NavlogOptions, codes ...string) Navlog {
+	t.Helper()
+	route, err := PlanRoute(loadTestAirports(t), codes)
+	if err != nil {
+		t.Fatalf("PlanRoute failed: %v", err)
+	}
+	navlog, err := BuildNavlog(route, options)
+	if err != nil {
+		t.Fatalf("BuildNavlog failed: %v", err)
+	}
+	return navlog
+}
+
+func TestBuildNavlog_WindTriangle(t *testing.T) {
+	performance := AircraftPerformance{Tail: "N12345", Type: "C172", CruiseKTAS: 110, FuelBurnGPH: 8.5, UsableFuelGal: 53}
+
+	// KFXE-KVRB is a course of about 351 true; a west wind is from the left.
+	navlog := testNavlog(t, NavlogOptions{Performance: performance, Winds: []WindAloft{{DirectionTrue: 270, SpeedKt: 15}}, VariationDeg: 6}, "KFXE", "KVRB", "KOBE", "KFXE")
+	leg := navlog.Legs[0]
+	if math.Abs(leg.TrueCourse-351) > 1 || math.Abs(leg.WindCorrection+7.7) > 0.5 || math.Abs(leg.GroundSpeedKt-106.6) > 1 {
+		t.Fatalf("unexpected first leg %+v", leg)
+	}
+	if math.Abs(leg.MagneticHeading-normalizeHeading(leg.TrueHeading+6)) > 1e-9 {
+		t.Fatalf("expected west variation to be added, got TH %.1f MH %.1f", leg.TrueHeading, leg.MagneticHeading)
+	}
+	if math.Abs(leg.FuelGal-leg.Minutes/60*8.5) > 1e-9 {
+		t.Fatalf("unexpected fuel %.2f for %.1f minutes", leg.FuelGal, leg.Minutes)
+	}
+	if got := navlog.RequiredFuelGal() - navlog.TripFuelGal(); math.Abs(got-4.25) > 1e-9 {
+		t.Fatalf("expected a 30-minute day reserve, got %.2f gal", got)
+	}
+	if len(navlog.Problems()) != 0 {
+		t.Fatalf("expected no problems, got %v", navlog.Problems())
+	}
+
+	calm := testNavlog(t, NavlogOptions{Performance: performance}, "KFXE", "KPBI")
+	if calm.Legs[0].WindCorrection != 0 || calm.Legs[0].GroundSpeedKt != 110 {
+		t.Fatalf("expected no correction in calm wind, got %+v", calm.Legs[0])
+	}
+
+	performance.UsableFuelGal = 10
+	short := testNavlog(t, NavlogOptions{Performance: performance, Night: true}, "KFXE", "KVRB", "KOBE", "KFXE")
+	if problems := short.Problems(); len(problems) != 1 || !strings.Contains(problems[0], "45-minute reserve") {
+		t.Fatalf("expected a fuel problem with the night reserve, got %v", problems)
+	}
+}
+
+func TestBuildNavlog_RejectsBadInputs(t *testing.T) {
+	route, err := PlanRoute(loadTestAirports(t), []string{"KFXE", "KPBI", "KSUA"})
+	if err != nil {
+		t.Fatalf("PlanRoute failed: %v", err)
+	}
+	if _, err := BuildNavlog(route, NavlogOptions{}); err == nil || !strings.Contains(err.Error(), "no cruise speed") {
+		t.Fatalf("expected a missing cruise speed error, got %v", err)
+	}
+	performance := AircraftPerformance{CruiseKTAS: 100}
+	winds := []WindAloft{{DirectionTrue: 270, SpeedKt: 10}, {DirectionTrue: 270, SpeedKt: 10}, {DirectionTrue: 270, SpeedKt: 10}}
+	if _, err := BuildNavlog(route, NavlogOptions{Performance: performance, Winds: winds}); err == nil || !strings.Contains(err.Error(), "3 winds for 2 legs") {
+		t.Fatalf("expected a wind count error, got %v", err)
+	}
+	if _, err := BuildNavlog(route, NavlogOptions{Performance: performance, Winds: []WindAloft{{DirectionTrue: 0, SpeedKt: 120}}}); err == nil {
+		t.Fatal("expected a headwind stronger than the airspeed to fail")
+	}
+}
+
+func TestNavlog_Outputs(t *testing.T) {
+	performance := AircraftPerformance{Tail: "N12345", CruiseKTAS: 110, FuelBurnGPH: 8.5}
+	navlog := testNavlog(t, NavlogOptions{Performance: performance, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}, "KFXE", "KPBI", "KSUA")
+
+	var text bytes.Buffer
+	if err := navlog.WriteText(&text); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	for _, want := range []string{"Navlog KFXE KPBI KSUA  2026-06-01", "KFXE-KPBI", "Cross-country (61.1): yes, landing at KSUA", "Long solo cross-country (61.109(a)(5)(ii)): no, total distance"} {
+		if !strings.Contains(text.String(), want) {
+			t.Fatalf("expected %q in\n%s", want, text.String())
+		}
+	}
+
+	var data bytes.Buffer
+	if err := navlog.WriteCSV(&data); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&data).ReadAll()
+	if err != nil || len(rows) != 4 || rows[1][0] != "KFXE" || rows[1][1] != "KPBI" || rows[3][2] != strconv.FormatFloat(navlog.Route.TotalNM(), 'f', 1, 64) {
+		t.Fatalf("unexpected CSV %v, %v", rows, err)
+	}
+
+	var pdf bytes.Buffer
+	if err := navlog.WritePDF(&pdf); err != nil {
+		t.Fatalf("WritePDF failed: %v", err)
+	}
+	out := pdf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") || !strings.Contains(out, "(Navlog KFXE KPBI KSUA  2026-06-01) Tj") {
+		t.Fatalf("unexpected PDF:\n%s", out)
+	}
+	start := strings.LastIndex(out, "startxref\n") + len("startxref\n")
+	offset, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(out[start:], "%%EOF\n")))
+	if err != nil || !strings.HasPrefix(out[offset:], "xref") {
+		t.Fatalf("startxref %d does not point at the xref table: %v", offset, err)
+	}
+}
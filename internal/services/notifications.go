@@ -0,0 +1,269 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	NotificationKindTasks = "tasks"
+	NotificationKindQuiz  = "quiz"
+
+	NotificationActionDone   = "done"
+	NotificationActionSnooze = "snooze"
+
+	defaultNotificationTime    = "09:00"
+	defaultQuizNotificationAt  = "08:00"
+	defaultSnoozeMinutes       = 30
+	defaultOverdueLookbackDays = 14
+)
+
+// NotificationConfig is the reminder daemon schedule, read from
+// ~/.openppl/daemon.json (or OPENPPL_DAEMON_PATH). Schedule maps a task
+// category to the local "HH:MM" its reminder fires; categories without an
+// entry use DefaultTime. An empty QuizTime disables the quiz nudge.
+type NotificationConfig struct {
+	Schedule      map[string]string `json:"schedule,omitempty"`
+	DefaultTime   string            `json:"default_time,omitempty"`
+	QuizTime      string            `json:"quiz_time"`
+	SnoozeMinutes int               `json:"snooze_minutes,omitempty"`
+	OverdueDays   int               `json:"overdue_days,omitempty"`
+}
+
+// DesktopNotification is one reminder ready to be shown.
+type DesktopNotification struct {
+	Key     string
+	Kind    string
+	Summary string
+	Body    string
+	TaskIDs []uint
+	Actions []string
+}
+
+func DefaultNotificationConfig() NotificationConfig {
+	return NotificationConfig{
+		Schedule: map[string]string{
+			"CFI Flights":  "07:00",
+			"Theory":       "09:00",
+			"Garmin 430":   "18:00",
+			"Chair Flying": "19:00",
+		},
+		DefaultTime:   defaultNotificationTime,
+		QuizTime:      defaultQuizNotificationAt,
+		SnoozeMinutes: defaultSnoozeMinutes,
+		OverdueDays:   defaultOverdueLookbackDays,
+	}
+}
+
+func LoadNotificationConfig() (NotificationConfig, error) {
+	path := strings.TrimSpace(os.Getenv("OPENPPL_DAEMON_PATH"))
+	if path == "" {
+		dir, err := motdDataDir()
+		if err != nil {
+			return NotificationConfig{}, err
+		}
+		path = filepath.Join(dir, "daemon.json")
+	}
+	return loadNotificationConfigFromPath(path)
+}
+
+func loadNotificationConfigFromPath(path string) (NotificationConfig, error) {
+	cfg := DefaultNotificationConfig()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("daemon: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return DefaultNotificationConfig(), fmt.Errorf("daemon: parse config: %w", err)
+	}
+
+	for category, clock := range cfg.Schedule {
+		if _, err := parseClock(clock); err != nil {
+			return DefaultNotificationConfig(), fmt.Errorf("daemon: schedule %q: %w", category, err)
+		}
+	}
+	if strings.TrimSpace(cfg.DefaultTime) == "" {
+		cfg.DefaultTime = defaultNotificationTime
+	}
+	if _, err := parseClock(cfg.DefaultTime); err != nil {
+		return DefaultNotificationConfig(), fmt.Errorf("daemon: default_time: %w", err)
+	}
+	if strings.TrimSpace(cfg.QuizTime) != "" {
+		if _, err := parseClock(cfg.QuizTime); err != nil {
+			return DefaultNotificationConfig(), fmt.Errorf("daemon: quiz_time: %w", err)
+		}
+	}
+	if cfg.SnoozeMinutes <= 0 {
+		cfg.SnoozeMinutes = defaultSnoozeMinutes
+	}
+	if cfg.OverdueDays <= 0 {
+		cfg.OverdueDays = defaultOverdueLookbackDays
+	}
+	return cfg, nil
+}
+
+// SnoozeDuration is how long a snoozed reminder stays quiet.
+func (c NotificationConfig) SnoozeDuration() time.Duration {
+	if c.SnoozeMinutes <= 0 {
+		return defaultSnoozeMinutes * time.Minute
+	}
+	return time.Duration(c.SnoozeMinutes) * time.Minute
+}
+
+// CategoryTime returns the local time of day a category's reminder fires.
+func (c NotificationConfig) CategoryTime(category string) time.Duration {
+	if clock, ok := c.Schedule[category]; ok {
+		if offset, err := parseClock(clock); err == nil {
+			return offset
+		}
+	}
+	if offset, err := parseClock(c.DefaultTime); err == nil {
+		return offset
+	}
+	offset, _ := parseClock(defaultNotificationTime)
+	return offset
+}
+
+// PlanDueNotifications returns the reminders that should be showing at now:
+// one per category whose scheduled time has passed and that has incomplete
+// tasks due today or overdue, plus the daily quiz nudge when it has not been
+// answered yet. Keys include the date, so each reminder fires once per day.
+func PlanDueNotifications(tasks []model.DailyTask, cfg NotificationConfig, now time.Time, quizAnswered bool) []DesktopNotification {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	oldest := today.AddDate(0, 0, -cfg.OverdueDays)
+	dateKey := today.Format("2006-01-02")
+
+	type bucket struct {
+		due     []model.DailyTask
+		overdue []model.DailyTask
+	}
+	buckets := map[string]*bucket{}
+	for _, task := range tasks {
+		if task.Completed {
+			continue
+		}
+		taskDay := time.Date(task.Date.Year(), task.Date.Month(), task.Date.Day(), 0, 0, 0, 0, now.Location())
+		if taskDay.After(today) || taskDay.Before(oldest) {
+			continue
+		}
+		category := strings.TrimSpace(task.Category)
+		if category == "" {
+			category = "Study"
+		}
+		b, ok := buckets[category]
+		if !ok {
+			b = &bucket{}
+			buckets[category] = b
+		}
+		if taskDay.Equal(today) {
+			b.due = append(b.due, task)
+		} else {
+			b.overdue = append(b.overdue, task)
+		}
+	}
+
+	categories := make([]string, 0, len(buckets))
+	for category := range buckets {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var notifications []DesktopNotification
+	elapsed := now.Sub(today)
+	for _, category := range categories {
+		if elapsed < cfg.CategoryTime(category) {
+			continue
+		}
+		b := buckets[category]
+		all := append(append([]model.DailyTask(nil), b.due...), b.overdue...)
+		ids := make([]uint, 0, len(all))
+		lines := make([]string, 0, len(all))
+		for _, task := range all {
+			ids = append(ids, task.ID)
+			line := "• " + taskICSSummary(task)
+			if !task.Date.IsZero() && task.Date.Before(today) {
+				line += fmt.Sprintf(" (overdue since %s)", task.Date.Format("Jan 2"))
+			}
+			lines = append(lines, line)
+		}
+
+		summary := fmt.Sprintf("%s: %d due today", category, len(b.due))
+		if len(b.overdue) > 0 {
+			summary = fmt.Sprintf("%s: %d due today, %d overdue", category, len(b.due), len(b.overdue))
+		}
+		notifications = append(notifications, DesktopNotification{
+			Key:     fmt.Sprintf("%s:%s:%s", NotificationKindTasks, CategorySlug(category), dateKey),
+			Kind:    NotificationKindTasks,
+			Summary: summary,
+			Body:    strings.Join(lines, "\n"),
+			TaskIDs: ids,
+			Actions: []string{NotificationActionDone, NotificationActionSnooze},
+		})
+	}
+
+	if !quizAnswered && strings.TrimSpace(cfg.QuizTime) != "" {
+		if quizAt, err := parseClock(cfg.QuizTime); err == nil && elapsed >= quizAt {
+			body := "Run: openppl motd quiz"
+			if entry, err := TodaysACSCode(now); err == nil {
+				body = fmt.Sprintf("ACS %s — %s\nRun: openppl motd quiz", entry.Code, entry.Title)
+			}
+			notifications = append(notifications, DesktopNotification{
+				Key:     fmt.Sprintf("%s:%s", NotificationKindQuiz, dateKey),
+				Kind:    NotificationKindQuiz,
+				Summary: "Daily ACS quiz is waiting",
+				Body:    body,
+				Actions: []string{NotificationActionSnooze},
+			})
+		}
+	}
+
+	return notifications
+}
+
+// CompleteNotificationTasks applies a "done" action by completing every task
+// the notification listed.
+func CompleteNotificationTasks(database *gorm.DB, n DesktopNotification) (int, error) {
+	completed := 0
+	for _, id := range n.TaskIDs {
+		task, err := SetTaskCompletion(database, id, true, "daemon")
+		if err != nil {
+			return completed, err
+		}
+		if task.Completed {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+// HasMOTDAttemptOn reports whether the daily quiz was answered or skipped on date.
+func HasMOTDAttemptOn(db *gorm.DB, date time.Time) (bool, error) {
+	var count int64
+	if err := db.Model(&MOTDAnswer{}).Where("date = ?", date.Format("2006-01-02")).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("motd: load attempt: %w", err)
+	}
+	return count > 0, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestPlanDueNotifications_FiresPerCategoryAfterScheduledTime(t *testing.T) {
+	cfg := DefaultNotificationConfig()
+	cfg.Schedule = map[string]string{"Theory": "09:00", "CFI Flights": "17:00"}
+	cfg.QuizTime = "08:00"
+
+	today := time.Date(2026, 5, 10, 0, 0, 0, 0, time.Local)
+	tasks := []model.DailyTask{
+		{ID: 1, Date: today, Category: "Theory", Title: "Airspace"},
+		{ID: 2, Date: today.AddDate(0, 0, -2), Category: "Theory", Title: "Weather"},
+		{ID: 3, Date: today, Category: "Theory", Title: "Done already", Completed: true},
+		{ID: 4, Date: today, Category: "CFI Flights", Title: "Pattern work"},
+		{ID: 5, Date: today.AddDate(0, 0, 1), Category: "Theory", Title: "Tomorrow"},
+		{ID: 6, Date: today.AddDate(0, 0, -30), Category: "Theory", Title: "Ancient"},
+	}
+
+	early := PlanDueNotifications(tasks, cfg, today.Add(7*time.Hour), false)
+	if len(early) != 0 {
+		t.Fatalf("expected nothing before 08:00, got %+v", early)
+	}
+
+	morning := PlanDueNotifications(tasks, cfg, today.Add(9*time.Hour+time.Minute), false)
+	if len(morning) != 2 {
+		t.Fatalf("expected theory + quiz reminders at 09:01, got %+v", morning)
+	}
+	theory := morning[0]
+	if theory.Key != "tasks:theory:2026-05-10" || theory.Summary != "Theory: 1 due today, 1 overdue" {
+		t.Fatalf("unexpected theory reminder: %+v", theory)
+	}
+	if len(theory.TaskIDs) != 2 || theory.TaskIDs[0] != 1 || theory.TaskIDs[1] != 2 {
+		t.Fatalf("unexpected task ids: %v", theory.TaskIDs)
+	}
+	if !strings.Contains(theory.Body, "Weather (overdue since May 8)") {
+		t.Fatalf("expected overdue marker in body: %q", theory.Body)
+	}
+	if morning[1].Kind != NotificationKindQuiz || len(morning[1].Actions) != 1 {
+		t.Fatalf("expected quiz reminder with snooze only, got %+v", morning[1])
+	}
+
+	evening := PlanDueNotifications(tasks, cfg, today.Add(17*time.Hour), true)
+	if len(evening) != 2 || evening[0].Key != "tasks:cfi-flights:2026-05-10" {
+		t.Fatalf("expected flights + theory reminders and no quiz after answering, got %+v", evening)
+	}
+}
+
+func TestLoadNotificationConfig_ValidatesClockValues(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(`{"schedule":{"Theory":"06:30"},"quiz_time":"","snooze_minutes":10}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	cfg, err := loadNotificationConfigFromPath(good)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.CategoryTime("Theory") != 6*time.Hour+30*time.Minute || cfg.CategoryTime("Other") != 9*time.Hour {
+		t.Fatalf("unexpected category times: %+v", cfg)
+	}
+	if cfg.QuizTime != "" || cfg.SnoozeDuration() != 10*time.Minute {
+		t.Fatalf("expected quiz disabled and 10m snooze, got %+v", cfg)
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"schedule":{"Theory":"25:99"}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := loadNotificationConfigFromPath(bad); err == nil {
+		t.Fatal("expected invalid clock error")
+	}
+}
+
+func TestCompleteNotificationTasks_MarksListedTasksDone(t *testing.T) {
+	db := setupEventsTestDB(t)
+	tasks := []model.DailyTask{{Title: "A", Date: time.Now()}, {Title: "B", Date: time.Now()}}
+	for i := range tasks {
+		db.Create(&tasks[i])
+	}
+
+	count, err := CompleteNotificationTasks(db, DesktopNotification{TaskIDs: []uint{tasks[0].ID, tasks[1].ID}})
+	if err != nil || count != 2 {
+		t.Fatalf("expected 2 completed, got %d (%v)", count, err)
+	}
+
+	var open int64
+	db.Model(&model.DailyTask{}).Where("completed = ?", false).Count(&open)
+	if open != 0 {
+		t.Fatalf("expected no open tasks, got %d", open)
+	}
+}
@@ -0,0 +1,424 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+//go:embed plan_template_default.json
+var defaultPlanTemplateJSON []byte
+
+const (
+	PlanTemplateVersion = 1
+
+	planTemplateAreaPlaceholder = "{area}"
+)
+
+// PlanTemplate is the curriculum the planner turns into daily tasks. Schools
+// can replace the embedded default with `openppl plan template use <file>`.
+type PlanTemplate struct {
+	Version     int                     `json:"version"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Categories  []PlanTemplateCategory  `json:"categories"`
+	Milestones  []PlanTemplateMilestone `json:"milestones,omitempty"`
+}
+
+// PlanTemplateCategory is one study track. A category is scheduled on days
+// where (days before checkride + OffsetDays) is a multiple of EveryDays, and
+// rotates through its tasks in order. Title and Description are defaults for
+// tasks that do not set their own; "{area}" is replaced with the task area.
+type PlanTemplateCategory struct {
+	Name            string             `json:"name"`
+	EveryDays       int                `json:"every_days"`
+	OffsetDays      int                `json:"offset_days,omitempty"`
+	DurationMinutes int                `json:"duration_minutes,omitempty"`
+	Title           string             `json:"title,omitempty"`
+	Description     string             `json:"description,omitempty"`
+	Tasks           []PlanTemplateTask `json:"tasks"`
+}
+
+// PlanTemplateTask is one rotating task. Prerequisites name other task IDs
+// (in any category) that must be scheduled on an earlier day first.
+type PlanTemplateTask struct {
+	ID              string   `json:"id"`
+	Area            string   `json:"area"`
+	Title           string   `json:"title,omitempty"`
+	Description     string   `json:"description,omitempty"`
+	ACS             []string `json:"acs,omitempty"`
+	DurationMinutes int      `json:"duration_minutes,omitempty"`
+	Prerequisites   []string `json:"prerequisites,omitempty"`
+}
+
+// PlanTemplateMilestone is a training gate such as first solo or the
+// knowledge test. It is targeted DaysBeforeCheckride days before the
+// checkride and is ready once its prerequisite tasks have each been completed
+// at least once, its checklist items (matched by title) are checked, and the
+// milestones it depends on are achieved. The planner schedules prerequisite
+// tasks ahead of the target date.
+type PlanTemplateMilestone struct {
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Description         string   `json:"description,omitempty"`
+	DaysBeforeCheckride int      `json:"days_before_checkride"`
+	Tasks               []string `json:"tasks,omitempty"`
+	Checklist           []string `json:"checklist,omitempty"`
+	Milestones          []string `json:"milestones,omitempty"`
+}
+
+// PlanTemplateError lists every problem found while validating a template.
+type PlanTemplateError struct {
+	Problems []string
+}
+
+func (e *PlanTemplateError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid plan template: " + e.Problems[0]
+	}
+	return fmt.Sprintf("invalid plan template (%d problems): %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+var (
+	planTemplateIDPattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	planTemplateACSPattern = regexp.MustCompile(`^PA\.[IVX]+(\.[A-Z](\.[KRS]\d+)?)?$`)
+)
+
+// DefaultPlanTemplate returns the embedded syllabus.
+func DefaultPlanTemplate() PlanTemplate {
+	tpl, err := ParsePlanTemplate(defaultPlanTemplateJSON)
+	if err != nil {
+		panic(fmt.Sprintf("embedded plan template: %v", err))
+	}
+	return tpl
+}
+
+// ParsePlanTemplate decodes and validates a JSON plan template.
+func ParsePlanTemplate(data []byte) (PlanTemplate, error) {
+	var tpl PlanTemplate
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&tpl); err != nil {
+		return PlanTemplate{}, &PlanTemplateError{Problems: []string{fmt.Sprintf("parse JSON: %v", err)}}
+	}
+	if problems := ValidatePlanTemplate(tpl); len(problems) > 0 {
+		return PlanTemplate{}, &PlanTemplateError{Problems: problems}
+	}
+	return tpl, nil
+}
+
+// ValidatePlanTemplate returns a human-readable problem for every invalid
+// field, unknown prerequisite, or prerequisite cycle.
+func ValidatePlanTemplate(tpl PlanTemplate) []string {
+	var problems []string
+	if tpl.Version != PlanTemplateVersion {
+		problems = append(problems, fmt.Sprintf("version must be %d, got %d", PlanTemplateVersion, tpl.Version))
+	}
+	if strings.TrimSpace(tpl.Name) == "" {
+		problems = append(problems, "name is required")
+	}
+	if len(tpl.Categories) == 0 {
+		problems = append(problems, "at least one category is required")
+	}
+
+	categories := map[string]bool{}
+	tasks := map[string]PlanTemplateTask{}
+	for ci, category := range tpl.Categories {
+		where := fmt.Sprintf("categories[%d]", ci)
+		name := strings.TrimSpace(category.Name)
+		if name == "" {
+			problems = append(problems, where+": name is required")
+		} else {
+			where = fmt.Sprintf("category %q", name)
+			if categories[strings.ToLower(name)] {
+				problems = append(problems, where+": duplicate category name")
+			}
+			categories[strings.ToLower(name)] = true
+		}
+		if category.EveryDays < 1 {
+			problems = append(problems, where+": every_days must be at least 1")
+		}
+		if category.OffsetDays < 0 {
+			problems = append(problems, where+": offset_days must not be negative")
+		}
+		if category.DurationMinutes < 0 {
+			problems = append(problems, where+": duration_minutes must not be negative")
+		}
+		if len(category.Tasks) == 0 {
+			problems = append(problems, where+": at least one task is required")
+		}
+
+		for ti, task := range category.Tasks {
+			taskWhere := fmt.Sprintf("%s tasks[%d]", where, ti)
+			if !planTemplateIDPattern.MatchString(task.ID) {
+				problems = append(problems, taskWhere+": id must be lowercase letters, digits and dashes")
+			} else {
+				taskWhere = fmt.Sprintf("task %q", task.ID)
+				if _, exists := tasks[task.ID]; exists {
+					problems = append(problems, taskWhere+": duplicate task id")
+				}
+				tasks[task.ID] = task
+			}
+			if strings.TrimSpace(task.Area) == "" && strings.TrimSpace(task.Title) == "" {
+				problems = append(problems, taskWhere+": area or title is required")
+			}
+			if task.DurationMinutes < 0 {
+				problems = append(problems, taskWhere+": duration_minutes must not be negative")
+			}
+			for _, code := range task.ACS {
+				if !planTemplateACSPattern.MatchString(code) {
+					problems = append(problems, fmt.Sprintf("%s: ACS reference %q is not like PA.I.C or PA.I.C.K1", taskWhere, code))
+				}
+			}
+		}
+	}
+
+	for _, category := range tpl.Categories {
+		for _, task := range category.Tasks {
+			for _, prereq := range task.Prerequisites {
+				if prereq == task.ID {
+					problems = append(problems, fmt.Sprintf("task %q: lists itself as a prerequisite", task.ID))
+				} else if _, ok := tasks[prereq]; !ok {
+					problems = append(problems, fmt.Sprintf("task %q: unknown prerequisite %q", task.ID, prereq))
+				}
+			}
+		}
+	}
+	if cycle := planTemplateCycle(tpl, tasks); cycle != "" {
+		problems = append(problems, "prerequisite cycle: "+cycle)
+	}
+	return append(problems, validatePlanTemplateMilestones(tpl, tasks)...)
+}
+
+// validatePlanTemplateMilestones checks milestone references. A milestone may
+// only depend on milestones listed before it, which also rules out cycles.
+func validatePlanTemplateMilestones(tpl PlanTemplate, tasks map[string]PlanTemplateTask) []string {
+	var problems []string
+	seen := map[string]bool{}
+	for mi, milestone := range tpl.Milestones {
+		where := fmt.Sprintf("milestones[%d]", mi)
+		if !planTemplateIDPattern.MatchString(milestone.ID) {
+			problems = append(problems, where+": id must be lowercase letters, digits and dashes")
+		} else {
+			where = fmt.Sprintf("milestone %q", milestone.ID)
+			if seen[milestone.ID] {
+				problems = append(problems, where+": duplicate milestone id")
+			}
+		}
+		if strings.TrimSpace(milestone.Name) == "" {
+			problems = append(problems, where+": name is required")
+		}
+		if milestone.DaysBeforeCheckride < 0 {
+			problems = append(problems, where+": days_before_checkride must not be negative")
+		}
+		for _, id := range milestone.Tasks {
+			if _, ok := tasks[id]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown task %q", where, id))
+			}
+		}
+		for _, title := range milestone.Checklist {
+			if strings.TrimSpace(title) == "" {
+				problems = append(problems, where+": checklist titles must not be empty")
+			}
+		}
+		for _, id := range milestone.Milestones {
+			if !seen[id] {
+				problems = append(problems, fmt.Sprintf("%s: milestone %q must be listed before it", where, id))
+			}
+		}
+		seen[milestone.ID] = true
+	}
+	return problems
+}
+
+// planTemplateCycle returns "a -> b -> a" for the first prerequisite cycle, if any.
+func planTemplateCycle(tpl PlanTemplate, tasks map[string]PlanTemplateTask) string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(id string) string
+	visit = func(id string) string {
+		switch state[id] {
+		case visiting:
+			for i, step := range path {
+				if step == id {
+					return strings.Join(append(append([]string(nil), path[i:]...), id), " -> ")
+				}
+			}
+		case done:
+			return ""
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, prereq := range tasks[id].Prerequisites {
+			if _, ok := tasks[prereq]; !ok || prereq == id {
+				continue
+			}
+			if cycle := visit(prereq); cycle != "" {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return ""
+	}
+
+	for _, category := range tpl.Categories {
+		for _, task := range category.Tasks {
+			if cycle := visit(task.ID); cycle != "" {
+				return cycle
+			}
+		}
+	}
+	return ""
+}
+
+// TaskTitle renders the task title, falling back to the category template.
+func (c PlanTemplateCategory) TaskTitle(task PlanTemplateTask) string {
+	return renderPlanTemplateText(firstNonEmpty(task.Title, c.Title, planTemplateAreaPlaceholder), task.Area)
+}
+
+// TaskDescription renders the task description, falling back to the category template.
+func (c PlanTemplateCategory) TaskDescription(task PlanTemplateTask) string {
+	text := firstNonEmpty(task.Description, c.Description)
+	if text == "" {
+		text = "Complete " + planTemplateAreaPlaceholder + " study materials."
+	}
+	return renderPlanTemplateText(text, task.Area)
+}
+
+// TaskDuration is the task's planned minutes, defaulting to the category's.
+func (c PlanTemplateCategory) TaskDuration(task PlanTemplateTask) int {
+	if task.DurationMinutes > 0 {
+		return task.DurationMinutes
+	}
+	return c.DurationMinutes
+}
+
+func renderPlanTemplateText(text string, area string) string {
+	return strings.TrimSpace(strings.ReplaceAll(text, planTemplateAreaPlaceholder, strings.TrimSpace(area)))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CategoryNames lists the template's categories in order.
+func (t PlanTemplate) CategoryNames() []string {
+	names := make([]string, 0, len(t.Categories))
+	for _, category := range t.Categories {
+		names = append(names, category.Name)
+	}
+	return names
+}
+
+// TaskCount is the number of rotating tasks across all categories.
+func (t PlanTemplate) TaskCount() int {
+	count := 0
+	for _, category := range t.Categories {
+		count += len(category.Tasks)
+	}
+	return count
+}
+
+// Milestone returns the milestone with the given id.
+func (t PlanTemplate) Milestone(id string) (PlanTemplateMilestone, bool) {
+	for _, milestone := range t.Milestones {
+		if milestone.ID == id {
+			return milestone, true
+		}
+	}
+	return PlanTemplateMilestone{}, false
+}
+
+// MarshalPlanTemplate encodes a template the way `plan template export` writes it.
+func MarshalPlanTemplate(tpl PlanTemplate) ([]byte, error) {
+	data, err := json.MarshalIndent(tpl, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// PlanTemplatePath is where the active custom template lives:
+// OPENPPL_PLAN_TEMPLATE_PATH or ~/.openppl/plan_template.json.
+func PlanTemplatePath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("OPENPPL_PLAN_TEMPLATE_PATH")); path != "" {
+		return path, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "plan_template.json"), nil
+}
+
+// LoadPlanTemplate returns the active template and where it came from
+// ("default" or the custom template path).
+func LoadPlanTemplate() (PlanTemplate, string, error) {
+	path, err := PlanTemplatePath()
+	if err != nil {
+		return DefaultPlanTemplate(), "default", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DefaultPlanTemplate(), "default", nil
+		}
+		return DefaultPlanTemplate(), "default", fmt.Errorf("plan template: read %s: %w", path, err)
+	}
+	tpl, err := ParsePlanTemplate(data)
+	if err != nil {
+		return DefaultPlanTemplate(), "default", fmt.Errorf("plan template %s: %w", path, err)
+	}
+	return tpl, path, nil
+}
+
+// UsePlanTemplate validates tpl and makes it the active template. Future
+// plan generation uses it; existing tasks are not touched.
+func UsePlanTemplate(tpl PlanTemplate) (string, error) {
+	if problems := ValidatePlanTemplate(tpl); len(problems) > 0 {
+		return "", &PlanTemplateError{Problems: problems}
+	}
+	path, err := PlanTemplatePath()
+	if err != nil {
+		return "", err
+	}
+	data, err := MarshalPlanTemplate(tpl)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("plan template: create dir: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("plan template: write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ResetPlanTemplate removes the custom template so the embedded default is used.
+func ResetPlanTemplate() error {
+	path, err := PlanTemplatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("plan template: remove %s: %w", path, err)
+	}
+	return nil
+}
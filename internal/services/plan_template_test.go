@@ -0,0 +1,143 @@
+package services
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultPlanTemplate_IsValidAndSchoolAppropriate(t *testing.T) {
+	tpl := DefaultPlanTemplate()
+	if got := strings.Join(tpl.CategoryNames(), ","); got != "Theory,Chair Flying,Garmin 430,CFI Flights" {
+		t.Fatalf("unexpected default categories: %s", got)
+	}
+	if tpl.TaskCount() != 16 {
+		t.Fatalf("expected 16 default tasks, got %d", tpl.TaskCount())
+	}
+	for _, category := range tpl.Categories {
+		for _, task := range category.Tasks {
+			if strings.Contains(strings.ToLower(category.TaskDescription(task)), "oceanic") {
+				t.Fatalf("task %q still mentions oceanic navigation", task.ID)
+			}
+			if len(task.ACS) == 0 {
+				t.Fatalf("task %q has no ACS reference", task.ID)
+			}
+		}
+	}
+}
+
+func TestValidatePlanTemplate_ReportsEveryProblem(t *testing.T) {
+	tpl := PlanTemplate{
+		Version: 1,
+		Name:    "Broken",
+		Categories: []PlanTemplateCategory{{
+			Name:      "Ground",
+			EveryDays: 0,
+			Tasks: []PlanTemplateTask{
+				{ID: "a", Area: "A", ACS: []string{"PA.I.C"}, Prerequisites: []string{"b"}},
+				{ID: "b", Area: "B", ACS: []string{"weather"}, Prerequisites: []string{"a"}},
+				{ID: "c", Area: "C", Prerequisites: []string{"missing"}},
+			},
+		}},
+	}
+
+	joined := strings.Join(ValidatePlanTemplate(tpl), "\n")
+	for _, want := range []string{
+		"every_days must be at least 1",
+		`ACS reference "weather"`,
+		`unknown prerequisite "missing"`,
+		"prerequisite cycle: a -> b -> a",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected problem %q in:\n%s", want, joined)
+		}
+	}
+
+	_, err := ParsePlanTemplate([]byte(`{"version":1,"name":"x","categoriez":[]}`))
+	var templateErr *PlanTemplateError
+	if !errors.As(err, &templateErr) || !strings.Contains(err.Error(), "categoriez") {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestGenerateStudyPlanFromTemplate_MatchesDefaultCadence(t *testing.T) {
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	checkride := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	tasks := GenerateStudyPlanFromTemplate(DefaultPlanTemplate(), checkride, 90, now)
+	if len(tasks) != 180 {
+		t.Fatalf("expected two tasks per day over 90 days, got %d", len(tasks))
+	}
+
+	last := tasks[len(tasks)-1]
+	if !last.Date.Equal(checkride) || last.DurationMinutes == 0 || last.ACSRefs == "" {
+		t.Fatalf("expected checkride-day task with duration and ACS refs, got %+v", last)
+	}
+	for _, task := range tasks {
+		if task.Date.Before(now.Truncate(24 * time.Hour)) {
+			t.Fatalf("task scheduled in the past: %+v", task)
+		}
+	}
+}
+
+func TestGenerateStudyPlanFromTemplate_HoldsTasksUntilPrerequisites(t *testing.T) {
+	tpl := PlanTemplate{
+		Version: 1,
+		Name:    "School",
+		Categories: []PlanTemplateCategory{
+			{Name: "Ground", EveryDays: 1, Title: "Ground: {area}", Tasks: []PlanTemplateTask{
+				{ID: "xc", Area: "Cross-country", Prerequisites: []string{"weather"}},
+				{ID: "weather", Area: "Weather"},
+			}},
+			{Name: "Flight", EveryDays: 3, Tasks: []PlanTemplateTask{
+				{ID: "solo-xc", Area: "Solo XC", Prerequisites: []string{"xc"}},
+			}},
+		},
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tasks := GenerateStudyPlanFromTemplate(tpl, now.AddDate(0, 0, 5), 6, now)
+
+	var titles []string
+	for _, task := range tasks {
+		titles = append(titles, task.Date.Format("02")+" "+task.Title)
+	}
+	got := strings.Join(titles, ", ")
+	want := "01 Ground: Weather, 02 Ground: Cross-country, 03 Ground: Weather, 03 Solo XC, 04 Ground: Cross-country, 05 Ground: Weather, 06 Ground: Cross-country, 06 Solo XC"
+	if got != want {
+		t.Fatalf("unexpected schedule:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUsePlanTemplate_ActivatesAndResets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan_template.json")
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", path)
+
+	custom := PlanTemplate{Version: 1, Name: "Our School", Categories: []PlanTemplateCategory{
+		{Name: "Ground School", EveryDays: 1, Tasks: []PlanTemplateTask{{ID: "airspace", Area: "Airspace", ACS: []string{"PA.I.E"}}}},
+	}}
+	if _, err := UsePlanTemplate(custom); err != nil {
+		t.Fatalf("UsePlanTemplate returned error: %v", err)
+	}
+
+	active, source, err := LoadPlanTemplate()
+	if err != nil || active.Name != "Our School" || source != path {
+		t.Fatalf("expected custom template from %s, got %q from %s (%v)", path, active.Name, source, err)
+	}
+	tasks := GenerateStudyPlan(time.Now().AddDate(0, 0, 2), 3)
+	if len(tasks) != 3 || tasks[0].Category != "Ground School" || tasks[0].Title != "Airspace" {
+		t.Fatalf("expected plan from custom template, got %+v", tasks)
+	}
+
+	if err := ResetPlanTemplate(); err != nil {
+		t.Fatalf("ResetPlanTemplate returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected custom template removed, stat err = %v", err)
+	}
+	if active, source, _ := LoadPlanTemplate(); source != "default" || active.Name != DefaultPlanTemplate().Name {
+		t.Fatalf("expected default template after reset, got %q from %s", active.Name, source)
+	}
+}
@@ -1,127 +1,142 @@
 package services
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"ppl-study-planner/internal/model"
 )
 
-// Task categories based on FAA ACS areas
-var Categories = []string{
-	"Theory",
-	"Chair Flying",
-	"Garmin 430",
-	"CFI Flights",
-}
+// Categories are the study tracks of the default plan template.
+var Categories = DefaultPlanTemplate().CategoryNames()
 
-// ACS Areas mapped to categories
-// Theory: Areas 1-4 (Aeronautical Knowledge)
-// Chair Flying: Areas 5-8 (Preflight Procedures, Airport Operations, Takeoffs/Landings, Fundamentals of Flight)
-// Garmin 430: Areas 9-12 (Navigation, Slow Flight, Stalls, Basic Instrument)
-// CFI Flights: Areas 13-16 (Complex Operations, Emergency Operations, Night, Post-flight)
-
-var ACSAreasByCategory = map[string][]string{
-	"Theory": {
-		"Area 1: Aerodynamics",
-		"Area 2: Regulations",
-		"Area 3: Weather",
-		"Area 4: Cross-Country Planning",
-	},
-	"Chair Flying": {
-		"Area 5: Preflight Procedures",
-		"Area 6: Airport Operations",
-		"Area 7: Takeoffs and Landings",
-		"Area 8: Fundamentals of Flight",
-	},
-	"Garmin 430": {
-		"Area 9: Navigation",
-		"Area 10: Slow Flight and Stalls",
-		"Area 11: Basic Instrument",
-		"Area 12: Attitude Instrument Flying",
-	},
-	"CFI Flights": {
-		"Area 13: Complex Aircraft Operations",
-		"Area 14: Emergency Operations",
-		"Area 15: Night Operations",
-		"Area 16: Post-Flight Procedures",
-	},
+// GenerateStudyPlan creates a backward-scheduled study plan from the active
+// plan template, falling back to the embedded default if it cannot be read.
+func GenerateStudyPlan(checkrideDate time.Time, totalDays int) []model.DailyTask {
+	tpl, _, err := LoadPlanTemplate()
+	if err != nil {
+		tpl = DefaultPlanTemplate()
+	}
+	return GenerateStudyPlanFromTemplate(tpl, checkrideDate, totalDays, time.Now())
 }
 
-// GenerateStudyPlan creates a backward-scheduled study plan
-func GenerateStudyPlan(checkrideDate time.Time, totalDays int) []model.DailyTask {
+// GenerateStudyPlanFromTemplate lays out totalDays of tasks ending on the
+// checkride date, skipping days before now. Each category rotates through
+// its tasks in order, holding back a task until its prerequisites were
+// scheduled on an earlier day. Tasks a milestone depends on are pulled ahead
+// of the rotation until they have been scheduled before its target date.
+func GenerateStudyPlanFromTemplate(tpl PlanTemplate, checkrideDate time.Time, totalDays int, now time.Time) []model.DailyTask {
 	var tasks []model.DailyTask
 
-	// Generate tasks for each day leading up to checkride
-	for i := 0; i < totalDays; i++ {
-		taskDate := checkrideDate.AddDate(0, 0, -i)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	cursors := make([]int, len(tpl.Categories))
+	scheduled := map[string]bool{}
+	gates := milestonesByTarget(tpl)
 
-		// Skip dates in the past (before today)
-		now := time.Now()
-		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// Walk forward in time so prerequisites land before the tasks that need them.
+	for i := totalDays - 1; i >= 0; i-- {
+		taskDate := checkrideDate.AddDate(0, 0, -i)
 		if taskDate.Before(today) {
 			continue
 		}
+		urgent := pendingGateTasks(gates, i, scheduled)
+
+		var scheduledToday []string
+		for catIndex, category := range tpl.Categories {
+			every := category.EveryDays
+			if every < 1 {
+				every = 1
+			}
+			if (i+category.OffsetDays)%every != 0 {
+				continue
+			}
 
-		// Assign 1-2 tasks per day from different categories
-		for catIndex, category := range Categories {
-			// Distribute: some days have multiple tasks, some have fewer
-			if (i+catIndex)%2 == 0 {
-				task := createTaskForCategory(taskDate, category, i, totalDays)
-				tasks = append(tasks, task)
+			next, ok := nextTemplateTask(category, cursors[catIndex], scheduled, urgent)
+			if !ok {
+				continue
 			}
+			cursors[catIndex] = next + 1
+			templateTask := category.Tasks[next]
+			tasks = append(tasks, createTaskFromTemplate(taskDate, category, templateTask))
+			scheduledToday = append(scheduledToday, templateTask.ID)
+			delete(urgent, templateTask.ID)
+		}
+		for _, id := range scheduledToday {
+			scheduled[id] = true
 		}
 	}
 
 	return tasks
 }
 
-// createTaskForCategory creates a single task for a given date and category
-func createTaskForCategory(date time.Time, category string, dayIndex, totalDays int) model.DailyTask {
-	areas := ACSAreasByCategory[category]
-	areaIndex := dayIndex % len(areas)
-	area := areas[areaIndex]
-
-	title := getTaskTitle(category, area)
-	description := getTaskDescription(category, area, dayIndex)
+// milestonesByTarget orders milestones by target date, earliest first.
+func milestonesByTarget(tpl PlanTemplate) []PlanTemplateMilestone {
+	gates := append([]PlanTemplateMilestone(nil), tpl.Milestones...)
+	sort.SliceStable(gates, func(i, j int) bool {
+		return gates[i].DaysBeforeCheckride > gates[j].DaysBeforeCheckride
+	})
+	return gates
+}
 
-	return model.DailyTask{
-		Date:        date,
-		Category:    category,
-		Title:       title,
-		Description: description,
-		Completed:   false,
+// pendingGateTasks returns the not-yet-scheduled tasks of milestones that are
+// still ahead of a day daysBefore days before the checkride.
+func pendingGateTasks(gates []PlanTemplateMilestone, daysBefore int, scheduled map[string]bool) map[string]bool {
+	urgent := map[string]bool{}
+	for _, gate := range gates {
+		if gate.DaysBeforeCheckride >= daysBefore {
+			continue
+		}
+		for _, id := range gate.Tasks {
+			if !scheduled[id] {
+				urgent[id] = true
+			}
+		}
 	}
+	return urgent
 }
 
-// getTaskTitle returns a task title for the category and area
-func getTaskTitle(category, area string) string {
-	switch category {
-	case "Theory":
-		return area + " - Knowledge Review"
-	case "Chair Flying":
-		return area + " - Oral Prep"
-	case "Garmin 430":
-		return area + " - GPS/FMS Practice"
-	case "CFI Flights":
-		return area + " - Flight Maneuver"
-	default:
-		return area
+// nextTemplateTask returns the index of the task to schedule next: the first
+// ready task a pending milestone needs, otherwise the first ready task at or
+// after cursor (wrapping around). A task is ready once its prerequisites have
+// all been scheduled.
+func nextTemplateTask(category PlanTemplateCategory, cursor int, scheduled map[string]bool, urgent map[string]bool) (int, bool) {
+	count := len(category.Tasks)
+	ready := func(index int) bool {
+		for _, prereq := range category.Tasks[index].Prerequisites {
+			if !scheduled[prereq] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for step := 0; step < count; step++ {
+		index := (cursor + step) % count
+		if urgent[category.Tasks[index].ID] && ready(index) {
+			return index, true
+		}
 	}
+	for step := 0; step < count; step++ {
+		index := (cursor + step) % count
+		if ready(index) {
+			return index, true
+		}
+	}
+	return 0, false
 }
 
-// getTaskDescription returns a detailed description for the task
-func getTaskDescription(category, area string, dayIndex int) string {
-	switch category {
-	case "Theory":
-		return "Review " + area + " concepts. Complete knowledge prep questions. Focus on FAA test prep."
-	case "Chair Flying":
-		return "Practice " + area + " procedures verbally. Visualize maneuvers. Review CFI teaching points."
-	case "Garmin 430":
-		return "Practice " + area + " on Garmin 430 simulator. Program flight plans. Review oceanic/continental navigation."
-	case "CFI Flights":
-		return "Prepare for " + area + " with CFI. Review ACS standards. Discuss common student errors."
-	default:
-		return "Complete " + area + " study materials."
+// createTaskFromTemplate creates a single task for a given date and template task
+func createTaskFromTemplate(date time.Time, category PlanTemplateCategory, task PlanTemplateTask) model.DailyTask {
+	return model.DailyTask{
+		Date:            date,
+		Category:        category.Name,
+		Title:           category.TaskTitle(task),
+		TemplateTaskID:  task.ID,
+		Description:     category.TaskDescription(task),
+		ACSRefs:         strings.Join(task.ACS, ","),
+		DurationMinutes: category.TaskDuration(task),
+		Completed:       false,
 	}
 }
 
@@ -165,18 +180,40 @@ func GetProgressByCategory(tasks []model.DailyTask) map[string]ProgressStats {
 	}
 
 	for _, task := range tasks {
-		if s, exists := stats[task.Category]; exists {
-			s.Total++
-			if task.Completed {
-				s.Completed++
-			}
-			stats[task.Category] = s
+		// Categories from a custom plan template get their own row too.
+		s, exists := stats[task.Category]
+		if !exists {
+			s = ProgressStats{Category: task.Category}
 		}
+		s.Total++
+		if task.Completed {
+			s.Completed++
+		}
+		stats[task.Category] = s
 	}
 
 	return stats
 }
 
+// ProgressCategoryOrder lists the categories in stats with the default
+// template's tracks first and any custom ones after them, alphabetically.
+func ProgressCategoryOrder(stats map[string]ProgressStats) []string {
+	order := make([]string, 0, len(stats))
+	known := map[string]bool{}
+	for _, category := range Categories {
+		known[category] = true
+		order = append(order, category)
+	}
+	var extra []string
+	for category := range stats {
+		if !known[category] {
+			extra = append(extra, category)
+		}
+	}
+	sort.Strings(extra)
+	return append(order, extra...)
+}
+
 // ProgressStats holds progress information for a category
 type ProgressStats struct {
 	Category   string
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	ReadinessFactorStudy        = "study"
+	ReadinessFactorKnowledge    = "knowledge"
+	ReadinessFactorChecklist    = "checklist"
+	ReadinessFactorFlightHours  = "flight_hours"
+	ReadinessFactorEndorsements = "endorsements"
+
+	// totalFlightHours is the aeronautical experience of 61.109(a).
+	totalFlightHours = 40
+	// dualFlightHours is the flight training of 61.109(a).
+	dualFlightHours = 20
+	// soloFlightHours is the solo flight time of 61.109(a)(5).
+	soloFlightHours = 10
+
+	// masteryAnswers is how many quiz answers an ACS area needs before its
+	// accuracy counts in full.
+	masteryAnswers = 3
+	// maxNextActions caps the recommended next actions.
+	maxNextActions = 5
+)
+
+// readinessWeights is how much each factor counts toward the overall score.
+var readinessWeights = map[string]float64{
+	ReadinessFactorStudy:        0.30,
+	ReadinessFactorKnowledge:    0.25,
+	ReadinessFactorChecklist:    0.15,
+	ReadinessFactorFlightHours:  0.20,
+	ReadinessFactorEndorsements: 0.10,
+}
+
+// checkrideEndorsements are the endorsements a student needs on the way to
+// the practical test. Repeatable ones (solo renewals, each solo
+// cross-country flight) are not counted.
+var checkrideEndorsements = []string{
+	EndorsementPreSoloKnowledge,
+	EndorsementSolo,
+	EndorsementSoloXC,
+	EndorsementKnowledgeTest,
+	EndorsementPracticalTest,
+}
+
+// ReadinessFactor is one signal of the readiness score, scored 0-100, with
+// what it is based on and what would raise it.
+type ReadinessFactor struct {
+	Key     string
+	Name    string
+	Weight  float64
+	Score   float64
+	Detail  string
+	Actions []string
+}
+
+// Gap is how many points of the overall score the factor is missing.
+func (f ReadinessFactor) Gap() float64 {
+	return f.Weight * (100 - f.Score)
+}
+
+// Readiness is the checkride readiness score: a weighted sum of the study
+// plan, quiz mastery, checklist, flight hour and endorsement factors.
+type Readiness struct {
+	Score       float64
+	Label       string
+	Factors     []ReadinessFactor
+	NextActions []string
+}
+
+// ReadinessInputs are the signals readiness is computed from.
+type ReadinessInputs struct {
+	Tasks        []model.DailyTask
+	Attempts     []MOTDAnswer
+	Checklist    []model.ChecklistItem
+	Logbook      []model.LogbookEntry
+	Endorsements []model.Endorsement
+}
+
+// LoadReadiness computes readiness from the latest study plan's tasks, the
+// rest of the study database and the given quiz attempts, which live in the
+// separate MOTD database. Sources that cannot be loaded count as empty.
+func LoadReadiness(database *gorm.DB, attempts []MOTDAnswer, now time.Time) Readiness {
+	inputs := ReadinessInputs{Attempts: attempts}
+	if tasks, err := LoadLatestPlanTasks(database); err == nil {
+		inputs.Tasks = tasks
+	}
+	if items, err := ListChecklistItems(database); err == nil {
+		inputs.Checklist = items
+	}
+	if entries, err := ListLogbook(database); err == nil {
+		inputs.Logbook = entries
+	}
+	if endorsements, err := ListEndorsements(database); err == nil {
+		inputs.Endorsements = endorsements
+	}
+	return ComputeReadiness(inputs, now)
+}
+
+// ExistingMOTDAttempts returns the quiz attempts from the MOTD database, or
+// none when the quiz has never been taken. Unlike InitMOTDDB it does not
+// create the database.
+func ExistingMOTDAttempts() []MOTDAnswer {
+	db, err := OpenExistingMOTDDB()
+	if err != nil || db == nil {
+		return nil
+	}
+	attempts, _ := LoadMOTDAttempts(db)
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+	return attempts
+}
+
+// ComputeReadiness scores each factor and combines them. The next actions
+// take the top action of each factor, biggest gap first, then the rest.
+func ComputeReadiness(inputs ReadinessInputs, now time.Time) Readiness {
+	factors := []ReadinessFactor{
+		studyFactor(inputs.Tasks, now),
+		knowledgeFactor(inputs.Attempts, now),
+		checklistFactor(inputs.Checklist),
+		flightHoursFactor(inputs.Logbook),
+		endorsementsFactor(inputs.Endorsements, now),
+	}
+
+	var readiness Readiness
+	for i := range factors {
+		factors[i].Weight = readinessWeights[factors[i].Key]
+		readiness.Score += factors[i].Weight * factors[i].Score
+	}
+	readiness.Score = math.Round(readiness.Score*10) / 10
+	readiness.Factors = factors
+	switch {
+	case readiness.Score >= 85:
+		readiness.Label = "Checkride ready"
+	case readiness.Score >= 60:
+		readiness.Label = "Getting close"
+	default:
+		readiness.Label = "Needs work"
+	}
+
+	byGap := append([]ReadinessFactor(nil), factors...)
+	sort.SliceStable(byGap, func(i, j int) bool { return byGap[i].Gap() > byGap[j].Gap() })
+	for round := 0; len(readiness.NextActions) < maxNextActions; round++ {
+		added := false
+		for _, factor := range byGap {
+			if round < len(factor.Actions) && len(readiness.NextActions) < maxNextActions {
+				readiness.NextActions = append(readiness.NextActions, factor.Actions[round])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return readiness
+}
+
+// studyFactor averages task completion over the plan's categories, so one
+// neglected category costs as much as it would on the checkride.
+func studyFactor(tasks []model.DailyTask, now time.Time) ReadinessFactor {
+	factor := ReadinessFactor{Key: ReadinessFactorStudy, Name: "Study plan"}
+	if len(tasks) == 0 {
+		factor.Detail = "no study plan yet"
+		factor.Actions = []string{"Generate a study plan with `openppl --configure`"}
+		return factor
+	}
+
+	stats := GetProgressByCategory(tasks)
+	var categories []ProgressStats
+	for _, category := range ProgressCategoryOrder(stats) {
+		if stats[category].Total > 0 {
+			categories = append(categories, stats[category])
+		}
+	}
+	parts := make([]string, 0, len(categories))
+	for _, category := range categories {
+		factor.Score += category.CalculatePercentage() / float64(len(categories))
+		parts = append(parts, fmt.Sprintf("%s %d/%d", category.Category, category.Completed, category.Total))
+	}
+	factor.Detail = strings.Join(parts, ", ")
+
+	today := dateOnlyUTC(now)
+	overdue := 0
+	for _, task := range tasks {
+		if !task.Completed && task.Date.Before(today) {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		factor.Actions = append(factor.Actions, fmt.Sprintf("Catch up on %d overdue study tasks", overdue))
+	}
+	weakest := categories[0]
+	for _, category := range categories[1:] {
+		if category.CalculatePercentage() < weakest.CalculatePercentage() {
+			weakest = category
+		}
+	}
+	if weakest.Completed < weakest.Total {
+		factor.Actions = append(factor.Actions, fmt.Sprintf("Work on %s: %d of %d tasks done", weakest.Category, weakest.Completed, weakest.Total))
+	}
+	return factor
+}
+
+// knowledgeFactor averages quiz mastery over every ACS area. An area's
+// mastery is its accuracy, scaled down until it has masteryAnswers answers;
+// areas never quizzed count as zero.
+func knowledgeFactor(attempts []MOTDAnswer, now time.Time) ReadinessFactor {
+	factor := ReadinessFactor{Key: ReadinessFactorKnowledge, Name: "ACS knowledge"}
+	areas := motdAreaOrder()
+	if len(areas) == 0 {
+		factor.Detail = "ACS dataset unavailable"
+		return factor
+	}
+
+	stats := ComputeMOTDReadiness(attempts, now)
+	byArea := map[string]MOTDReadinessArea{}
+	for _, area := range stats.Areas {
+		byArea[area.Area] = area
+	}
+
+	var unquizzed []string
+	weakest, weakestMastery := "", 101.0
+	for _, name := range areas {
+		area := byArea[name]
+		if area.Attempts == 0 {
+			unquizzed = append(unquizzed, name)
+			continue
+		}
+		mastery := area.Accuracy * math.Min(1, float64(area.Attempts)/masteryAnswers)
+		factor.Score += mastery / float64(len(areas))
+		if mastery < weakestMastery {
+			weakest, weakestMastery = name, mastery
+		}
+	}
+	factor.Detail = fmt.Sprintf("%d of %d ACS areas quizzed, %.0f%% accuracy on %d answers",
+		len(areas)-len(unquizzed), len(areas), stats.OverallAccuracy, stats.AnsweredAttempts)
+
+	if weakest != "" && weakestMastery < 80 {
+		area := byArea[weakest]
+		factor.Actions = append(factor.Actions, fmt.Sprintf("Review ACS area %s: %.0f%% on %d answers (`openppl motd weak`)", weakest, area.Accuracy, area.Attempts))
+	}
+	if len(unquizzed) > 0 {
+		factor.Actions = append(factor.Actions, fmt.Sprintf("Quiz ACS areas not answered yet: %s (`openppl motd quiz`)", strings.Join(unquizzed, ", ")))
+	}
+	return factor
+}
+
+// motdAreaOrder lists the ACS areas in the order the dataset first uses
+// them.
+func motdAreaOrder() []string {
+	seen := map[string]bool{}
+	var areas []string
+	for _, entry := range loadMOTDTasks() {
+		area := strings.TrimSpace(entry.Area)
+		if area != "" && !seen[area] {
+			seen[area] = true
+			areas = append(areas, area)
+		}
+	}
+	return areas
+}
+
+func checklistFactor(items []model.ChecklistItem) ReadinessFactor {
+	factor := ReadinessFactor{Key: ReadinessFactorChecklist, Name: "Checkride checklist"}
+	if len(items) == 0 {
+		factor.Detail = "no checklist items"
+		return factor
+	}
+	completed := 0
+	for _, item := range items {
+		if item.Completed {
+			completed++
+			continue
+		}
+		if len(factor.Actions) < 2 {
+			factor.Actions = append(factor.Actions, "Checklist: "+item.Title)
+		}
+	}
+	factor.Score = percent(completed, len(items))
+	factor.Detail = fmt.Sprintf("%d of %d items done", completed, len(items))
+	return factor
+}
+
+// flightHoursFactor averages progress toward the 61.109(a) minimums that
+// the logbook can tell apart: total time, dual, solo and the cross-country
+// requirements. Only flown entries count.
+func flightHoursFactor(entries []model.LogbookEntry) ReadinessFactor {
+	factor := ReadinessFactor{Key: ReadinessFactorFlightHours, Name: "Flight hours"}
+	flown := SummarizeFlightTime(entries)
+	xc := SummarizeCrossCountry(entries)
+
+	requirements := []struct{ have, need float64 }{
+		{flown.Total, totalFlightHours},
+		{flown.Dual, dualFlightHours},
+		{flown.Solo, soloFlightHours},
+		{xc.DualHours, dualCrossCountryHours},
+		{xc.SoloHours, soloCrossCountryHours},
+		{boolHours(xc.LongSoloXC), 1},
+	}
+	for _, requirement := range requirements {
+		factor.Score += 100 * math.Min(1, requirement.have/requirement.need) / float64(len(requirements))
+	}
+	deficits := append(flown.Deficits(), xc.Deficits()...)
+	factor.Detail = fmt.Sprintf("%.1f hours logged: %.1f dual, %.1f solo", flown.Total, flown.Dual, flown.Solo)
+	for _, deficit := range deficits {
+		factor.Actions = append(factor.Actions, "Flight hours: "+deficit)
+	}
+	return factor
+}
+
+func boolHours(done bool) float64 {
+	if done {
+		return 1
+	}
+	return 0
+}
+
+// endorsementsFactor counts the checkride endorsements on record. The
+// practical test endorsement only counts while it is current.
+func endorsementsFactor(endorsements []model.Endorsement, now time.Time) ReadinessFactor {
+	factor := ReadinessFactor{Key: ReadinessFactorEndorsements, Name: "Endorsements"}
+	recorded := map[string]bool{}
+	lapsed := map[string]bool{}
+	for _, e := range endorsements {
+		key := e.Kind
+		if key == EndorsementSoloRenewal {
+			key = EndorsementSolo
+		}
+		if key == EndorsementPracticalTest && !EndorsementCurrent(e, now) {
+			lapsed[key] = true
+			continue
+		}
+		recorded[key] = true
+	}
+
+	have := 0
+	for _, key := range checkrideEndorsements {
+		if recorded[key] {
+			have++
+			continue
+		}
+		kind, _ := LookupEndorsementKind(key)
+		if lapsed[key] {
+			factor.Actions = append(factor.Actions, fmt.Sprintf("Endorsement lapsed: %s (%s)", kind.Title, kind.Reference))
+		} else {
+			factor.Actions = append(factor.Actions, fmt.Sprintf("Endorsement needed: %s (%s)", kind.Title, kind.Reference))
+		}
+	}
+	factor.Score = percent(have, len(checkrideEndorsements))
+	factor.Detail = fmt.Sprintf("%d of %d checkride endorsements", have, len(checkrideEndorsements))
+	return factor
+}
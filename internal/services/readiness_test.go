@@ -0,0 +1,126 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestComputeReadiness_EmptyNeedsWork(t *testing.T) {
+	readiness := ComputeReadiness(ReadinessInputs{}, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+	if readiness.Score != 0 || readiness.Label != "Needs work" || len(readiness.Factors) != 5 {
+		t.Fatalf("unexpected readiness %+v", readiness)
+	}
+	if len(readiness.NextActions) == 0 || !strings.Contains(readiness.NextActions[0], "Generate a study plan") {
+		t.Fatalf("expected the study plan first, got %v", readiness.NextActions)
+	}
+}
+
+func TestComputeReadiness_CombinesFactors(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	flown := now.Add(-time.Hour)
+	lapsed := day(1)
+
+	inputs := ReadinessInputs{
+		Tasks: []model.DailyTask{
+			{Date: day(1), Category: "Theory", Title: "Airspace", Completed: true},
+			{Date: day(2), Category: "Theory", Title: "Weather", Completed: true},
+			{Date: day(3), Category: "Ground", Title: "Preflight"},
+			{Date: day(20), Category: "Ground", Title: "Radio"},
+		},
+		Attempts: []MOTDAnswer{
+			{Date: "2026-03-07", ACSCode: "PA.I.A.K1", IsCorrect: true},
+			{Date: "2026-03-08", ACSCode: "PA.I.A.K1", IsCorrect: true},
+			{Date: "2026-03-09", ACSCode: "PA.I.A.K1", IsCorrect: true},
+		},
+		Checklist: []model.ChecklistItem{
+			{Title: "Medical certificate", Completed: true},
+			{Title: "IACRA application"},
+		},
+		Logbook: []model.LogbookEntry{
+			{Hours: 25, FlownAt: &flown},
+			{Hours: 12, Solo: true, FlownAt: &flown},
+			{Hours: 3, CrossCountry: true, FlownAt: &flown},
+			{Hours: 5, Solo: true, CrossCountry: true, LongSoloXC: true, FlownAt: &flown},
+			{PlannedHours: 2, CrossCountry: true},
+		},
+		Endorsements: []model.Endorsement{
+			{Kind: EndorsementPreSoloKnowledge},
+			{Kind: EndorsementSoloRenewal},
+			{Kind: EndorsementPracticalTest, ExpiresOn: &lapsed},
+		},
+	}
+
+	readiness := ComputeReadiness(inputs, now)
+	scores := map[string]float64{}
+	for _, factor := range readiness.Factors {
+		scores[factor.Key] = factor.Score
+	}
+	want := map[string]float64{
+		ReadinessFactorStudy:        50,
+		ReadinessFactorKnowledge:    100.0 / 12,
+		ReadinessFactorChecklist:    50,
+		ReadinessFactorFlightHours:  100,
+		ReadinessFactorEndorsements: 40,
+	}
+	for key, score := range want {
+		if math.Abs(scores[key]-score) > 0.01 {
+			t.Fatalf("expected %s score %.2f, got %.2f", key, score, scores[key])
+		}
+	}
+	if readiness.Score != 48.6 || readiness.Label != "Needs work" {
+		t.Fatalf("expected 48.6 (Needs work), got %.1f (%s)", readiness.Score, readiness.Label)
+	}
+
+	wantActions := []string{
+		"Quiz ACS areas not answered yet",
+		"Catch up on 1 overdue study tasks",
+		"Checklist: IACRA application",
+		"Endorsement needed: Solo cross-country training",
+		"Work on Ground: 0 of 2 tasks done",
+	}
+	if len(readiness.NextActions) != len(wantActions) {
+		t.Fatalf("expected %d next actions, got %v", len(wantActions), readiness.NextActions)
+	}
+	for i, action := range wantActions {
+		if !strings.HasPrefix(readiness.NextActions[i], action) {
+			t.Fatalf("expected next action %d to start with %q, got %v", i, action, readiness.NextActions)
+		}
+	}
+
+	endorsements := readiness.Factors[4]
+	if !strings.Contains(strings.Join(endorsements.Actions, "\n"), "Endorsement lapsed: Practical test") {
+		t.Fatalf("expected the lapsed practical test endorsement, got %v", endorsements.Actions)
+	}
+}
+
+func TestLoadReadiness_ReadsStudyDatabase(t *testing.T) {
+	db := setupAutomationStatusTestDB(t)
+	if err := db.AutoMigrate(&model.LogbookEntry{}, &model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	old := model.StudyPlan{CheckrideDate: now.AddDate(0, 2, 0)}
+	current := model.StudyPlan{CheckrideDate: now.AddDate(0, 3, 0)}
+	db.Create(&old)
+	db.Create(&current)
+	// The old plan's open task would halve the study factor if it counted.
+	if err := db.Create(&model.DailyTask{StudyPlanID: old.ID, Date: now, Category: "Theory", Title: "Old plan review"}).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: now, Category: "Theory", Title: "Airspace", Completed: true}).Error; err != nil {
+		t.Fatalf("create task: %v", err)
+	}
+	if err := db.Create(&model.ChecklistItem{Category: model.CategoryFlight, Title: "Logbook reviewed", Completed: true}).Error; err != nil {
+		t.Fatalf("create checklist item: %v", err)
+	}
+
+	readiness := LoadReadiness(db, nil, now)
+	if readiness.Factors[0].Score != 100 || readiness.Factors[2].Score != 100 || readiness.Score != 45 {
+		t.Fatalf("unexpected readiness %+v", readiness)
+	}
+}
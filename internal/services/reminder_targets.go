@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	ReminderTargetApple       = "apple"
+	ReminderTargetTaskwarrior = "taskwarrior"
+	ReminderTargetTodoist     = "todoist"
+	ReminderTargetMSTodo      = "mstodo"
+
+	defaultTaskwarriorProject = "openppl"
+	defaultReminderTimeout    = 12 * time.Second
+)
+
+// ReminderTarget creates one reminder per study task in an external task
+// manager. Implementations share RemindersExportOptions and
+// RemindersExportResult so callers do not care which app is configured.
+type ReminderTarget interface {
+	Name() string
+	Export(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error)
+}
+
+// ReminderTargetConfig selects and configures the reminders backend. It is
+// read from ~/.openppl/reminders.json (or OPENPPL_REMINDERS_PATH).
+type ReminderTargetConfig struct {
+	Target      string                  `json:"target,omitempty"`
+	ListName    string                  `json:"list_name,omitempty"`
+	Taskwarrior TaskwarriorTargetConfig `json:"taskwarrior,omitempty"`
+	Todoist     TodoistTargetConfig     `json:"todoist,omitempty"`
+	MSTodo      MSTodoTargetConfig      `json:"mstodo,omitempty"`
+}
+
+type TaskwarriorTargetConfig struct {
+	Binary  string `json:"binary,omitempty"`
+	Project string `json:"project,omitempty"`
+}
+
+type TodoistTargetConfig struct {
+	Token     string `json:"token,omitempty"`
+	ProjectID string `json:"project_id,omitempty"`
+	BaseURL   string `json:"base_url,omitempty"`
+}
+
+type MSTodoTargetConfig struct {
+	AccessToken string `json:"access_token,omitempty"`
+	ListID      string `json:"list_id,omitempty"`
+	BaseURL     string `json:"base_url,omitempty"`
+}
+
+var (
+	loadReminderTargetConfig = LoadReminderTargetConfig
+	reminderCommandRunner    = defaultCommandRunner
+	reminderHTTPClient       = &http.Client{Timeout: 30 * time.Second}
+	reminderGOOS             = runtime.GOOS
+)
+
+// ExportReminders sends tasks to the configured reminder target.
+func ExportReminders(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error) {
+	cfg, err := loadReminderTargetConfig()
+	if err != nil {
+		return RemindersExportResult{}, &RemindersExportError{Kind: "config", Err: err}
+	}
+	target, err := NewReminderTarget(cfg)
+	if err != nil {
+		return RemindersExportResult{}, err
+	}
+	if strings.TrimSpace(opts.ListName) == "" {
+		opts.ListName = cfg.ListName
+	}
+	return target.Export(tasks, opts)
+}
+
+// NewReminderTarget builds the target named in cfg. Without an explicit
+// target, macOS uses Apple Reminders and everything else uses taskwarrior.
+func NewReminderTarget(cfg ReminderTargetConfig) (ReminderTarget, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Target))
+	if name == "" {
+		name = ReminderTargetTaskwarrior
+		if reminderGOOS == "darwin" {
+			name = ReminderTargetApple
+		}
+	}
+
+	switch name {
+	case ReminderTargetApple:
+		return appleRemindersTarget{run: reminderCommandRunner}, nil
+	case ReminderTargetTaskwarrior:
+		return newTaskwarriorTarget(cfg.Taskwarrior, reminderCommandRunner), nil
+	case ReminderTargetTodoist:
+		return newTodoistTarget(cfg.Todoist, reminderHTTPClient)
+	case ReminderTargetMSTodo, "microsoft", "microsoft-todo":
+		return newMSTodoTarget(cfg.MSTodo, reminderHTTPClient)
+	default:
+		return nil, &RemindersExportError{Kind: "config", Err: fmt.Errorf("unknown reminder target %q", cfg.Target)}
+	}
+}
+
+// LoadReminderTargetConfig reads the reminders config. TODOIST_API_TOKEN and
+// MSGRAPH_ACCESS_TOKEN override stored tokens so they can live outside the
+// file.
+func LoadReminderTargetConfig() (ReminderTargetConfig, error) {
+	path := strings.TrimSpace(os.Getenv("OPENPPL_REMINDERS_PATH"))
+	if path == "" {
+		dir, err := motdDataDir()
+		if err != nil {
+			return ReminderTargetConfig{}, err
+		}
+		path = filepath.Join(dir, "reminders.json")
+	}
+	return loadReminderTargetConfigFromPath(path)
+}
+
+func loadReminderTargetConfigFromPath(path string) (ReminderTargetConfig, error) {
+	var cfg ReminderTargetConfig
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return cfg, fmt.Errorf("reminders: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return ReminderTargetConfig{}, fmt.Errorf("reminders: parse config: %w", err)
+		}
+	}
+	if token := strings.TrimSpace(os.Getenv("TODOIST_API_TOKEN")); token != "" {
+		cfg.Todoist.Token = token
+	}
+	if token := strings.TrimSpace(os.Getenv("MSGRAPH_ACCESS_TOKEN")); token != "" {
+		cfg.MSTodo.AccessToken = token
+	}
+	return cfg, nil
+}
+
+type appleRemindersTarget struct {
+	run commandRunner
+}
+
+func (t appleRemindersTarget) Name() string { return ReminderTargetApple }
+
+func (t appleRemindersTarget) Export(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error) {
+	result, err := exportAppleRemindersWithRunner(tasks, opts, t.run)
+	result.Target = t.Name()
+	return result, err
+}
+
+// taskwarriorTarget adds one task per study task with the local `task` CLI.
+type taskwarriorTarget struct {
+	binary  string
+	project string
+	run     commandRunner
+}
+
+func newTaskwarriorTarget(cfg TaskwarriorTargetConfig, run commandRunner) taskwarriorTarget {
+	binary := strings.TrimSpace(cfg.Binary)
+	if binary == "" {
+		binary = "task"
+	}
+	return taskwarriorTarget{binary: binary, project: strings.TrimSpace(cfg.Project), run: run}
+}
+
+func (t taskwarriorTarget) Name() string { return ReminderTargetTaskwarrior }
+
+func (t taskwarriorTarget) Export(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error) {
+	if len(tasks) == 0 {
+		return RemindersExportResult{}, &RemindersExportError{Kind: "validation", Err: errors.New("no tasks available for reminders export")}
+	}
+	if t.run == nil {
+		return RemindersExportResult{}, &RemindersExportError{Kind: "validation", Err: errors.New("nil command runner")}
+	}
+
+	project := t.project
+	if project == "" {
+		project = taskwarriorProjectName(opts.ListName)
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultReminderTimeout
+	}
+
+	created := 0
+	for _, task := range tasks {
+		due := task.Date.UTC().Format("2006-01-02") + "T09:00"
+		args := []string{
+			"rc.confirmation=off",
+			"rc.verbose=new-id",
+			"add",
+			"project:" + project,
+			"due:" + due,
+			"+openppl",
+		}
+		if tag := taskwarriorProjectName(task.Category); tag != "" && tag != defaultTaskwarriorProject {
+			args = append(args, "+"+tag)
+		}
+		// Everything after "--" is description, so titles like "Area 1: ..."
+		// are never parsed as taskwarrior attributes.
+		args = append(args, "--", taskICSSummary(task))
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		output, err := t.run(ctx, t.binary, args...)
+		cancel()
+		if err != nil {
+			outputStr := string(output)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+				return RemindersExportResult{}, &RemindersExportError{Kind: "timeout", Output: outputStr, Err: err}
+			}
+			return RemindersExportResult{}, &RemindersExportError{Kind: "script_failure", Output: outputStr, Err: err}
+		}
+		created++
+	}
+
+	return RemindersExportResult{Target: t.Name(), ListName: project, Created: created}, nil
+}
+
+// taskwarriorProjectName turns a list or category name into a single
+// taskwarrior token ("CFI Flights" -> "cfi-flights").
+func taskwarriorProjectName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || name == defaultRemindersList {
+		return defaultTaskwarriorProject
+	}
+	return CategorySlug(name)
+}
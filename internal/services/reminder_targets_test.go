@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestNewReminderTarget_DefaultsByPlatform(t *testing.T) {
+	previous := reminderGOOS
+	t.Cleanup(func() { reminderGOOS = previous })
+
+	reminderGOOS = "linux"
+	target, err := NewReminderTarget(ReminderTargetConfig{})
+	if err != nil || target.Name() != ReminderTargetTaskwarrior {
+		t.Fatalf("expected taskwarrior on linux, got %v (%v)", target, err)
+	}
+
+	reminderGOOS = "darwin"
+	target, err = NewReminderTarget(ReminderTargetConfig{})
+	if err != nil || target.Name() != ReminderTargetApple {
+		t.Fatalf("expected apple on darwin, got %v (%v)", target, err)
+	}
+
+	_, err = NewReminderTarget(ReminderTargetConfig{Target: "todoist"})
+	var exportErr *RemindersExportError
+	if !errors.As(err, &exportErr) || exportErr.Kind != "config" {
+		t.Fatalf("expected config error for todoist without token, got %v", err)
+	}
+}
+
+func TestTaskwarriorTarget_BuildsArgvSafeCommand(t *testing.T) {
+	var calls [][]string
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		return []byte("Created task 1."), nil
+	}
+
+	target := newTaskwarriorTarget(TaskwarriorTargetConfig{}, runner)
+	tasks := []model.DailyTask{{Date: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Area 1: project:evil due:now"}}
+	result, err := target.Export(tasks, RemindersExportOptions{})
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if result.Created != 1 || result.ListName != "openppl" || result.Target != ReminderTargetTaskwarrior {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	got := calls[0]
+	want := []string{"task", "rc.confirmation=off", "rc.verbose=new-id", "add", "project:openppl", "due:2026-03-15T09:00", "+openppl", "+cfi-flights", "--", "Area 1: project:evil due:now"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("unexpected taskwarrior argv:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestTaskwarriorTarget_MapsFailure(t *testing.T) {
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return []byte("task: command not found"), errors.New("exit status 127")
+	}
+	_, err := newTaskwarriorTarget(TaskwarriorTargetConfig{}, runner).Export([]model.DailyTask{{Date: time.Now(), Title: "Task"}}, RemindersExportOptions{})
+
+	var exportErr *RemindersExportError
+	if !errors.As(err, &exportErr) || exportErr.Kind != "script_failure" {
+		t.Fatalf("expected script_failure, got %v", err)
+	}
+}
+
+func TestTodoistTarget_PostsTasksToStubServer(t *testing.T) {
+	var mu sync.Mutex
+	var received []todoistTaskRequest
+	var requestIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer todo-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.URL.Path != "/rest/v2/tasks" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body todoistTaskRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		received = append(received, body)
+		requestIDs = append(requestIDs, r.Header.Get("X-Request-Id"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"id":"123"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := newTodoistTarget(TodoistTargetConfig{Token: "todo-token", ProjectID: "p1", BaseURL: server.URL + "/rest/v2/"}, server.Client())
+	if err != nil {
+		t.Fatalf("newTodoistTarget returned error: %v", err)
+	}
+	tasks := []model.DailyTask{
+		{ID: 4, Date: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Airspace"},
+		{ID: 5, Date: time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Weather", Description: "METAR/TAF"},
+	}
+	result, err := target.Export(tasks, RemindersExportOptions{})
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if result.Created != 2 || result.Target != ReminderTargetTodoist {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if received[1].Content != "Weather" || received[1].Description != "METAR/TAF" || received[1].DueDatetime != "2026-03-16T09:00:00Z" || received[1].ProjectID != "p1" {
+		t.Fatalf("unexpected todoist payload: %+v", received[1])
+	}
+	if requestIDs[0] != "task-4-20260315@openppl" {
+		t.Fatalf("expected deterministic request id, got %q", requestIDs[0])
+	}
+
+	bad, _ := newTodoistTarget(TodoistTargetConfig{Token: "wrong", BaseURL: server.URL + "/rest/v2"}, server.Client())
+	_, err = bad.Export(tasks[:1], RemindersExportOptions{})
+	var exportErr *RemindersExportError
+	if !errors.As(err, &exportErr) || exportErr.Kind != "permission" {
+		t.Fatalf("expected permission error, got %v", err)
+	}
+}
+
+func TestMSTodoTarget_CreatesListAndTasksOnStubServer(t *testing.T) {
+	var mu sync.Mutex
+	var tasksPosted []msTodoTaskRequest
+	listCreated := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer graph-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1.0/me/todo/lists":
+			_, _ = w.Write([]byte(`{"value":[{"id":"tasks","displayName":"Tasks"}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.0/me/todo/lists":
+			var list msTodoList
+			_ = json.NewDecoder(r.Body).Decode(&list)
+			if list.DisplayName != "Flight Training" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			listCreated = true
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"list-42","displayName":"Flight Training"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v1.0/me/todo/lists/list-42/tasks":
+			var task msTodoTaskRequest
+			_ = json.NewDecoder(r.Body).Decode(&task)
+			tasksPosted = append(tasksPosted, task)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"t1"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	target, err := newMSTodoTarget(MSTodoTargetConfig{AccessToken: "graph-token", BaseURL: server.URL + "/v1.0"}, server.Client())
+	if err != nil {
+		t.Fatalf("newMSTodoTarget returned error: %v", err)
+	}
+	tasks := []model.DailyTask{{ID: 9, Date: time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC), Category: "Chair Flying", Title: "Engine failure flows"}}
+	result, err := target.Export(tasks, RemindersExportOptions{ListName: "Flight Training"})
+	if err != nil {
+		t.Fatalf("Export returned error: %v", err)
+	}
+	if !listCreated || result.Created != 1 || result.ListName != "Flight Training" {
+		t.Fatalf("unexpected result: %+v (list created=%v)", result, listCreated)
+	}
+	got := tasksPosted[0]
+	if got.Title != "Engine failure flows" || got.DueDateTime.DateTime != "2026-03-17T09:00:00" || got.DueDateTime.TimeZone != "UTC" || got.Linked[0].ExternalID != "task-9-20260317@openppl" {
+		t.Fatalf("unexpected To Do payload: %+v", got)
+	}
+}
+
+func TestExportReminders_UsesConfiguredTarget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reminders.json")
+	if err := os.WriteFile(path, []byte(`{"target":"taskwarrior","list_name":"PPL","taskwarrior":{"binary":"/usr/bin/task"}}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("OPENPPL_REMINDERS_PATH", path)
+
+	var gotBinary string
+	previousRunner := reminderCommandRunner
+	reminderCommandRunner = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		gotBinary = name
+		return nil, nil
+	}
+	t.Cleanup(func() { reminderCommandRunner = previousRunner })
+
+	result, err := ExportReminders([]model.DailyTask{{Date: time.Now(), Title: "Task"}}, RemindersExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportReminders returned error: %v", err)
+	}
+	if gotBinary != "/usr/bin/task" || result.ListName != "ppl" || result.Target != ReminderTargetTaskwarrior {
+		t.Fatalf("unexpected export: binary=%q result=%+v", gotBinary, result)
+	}
+}
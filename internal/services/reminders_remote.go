@@ -0,0 +1,266 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	defaultTodoistBaseURL = "https://api.todoist.com/rest/v2"
+	defaultMSGraphBaseURL = "https://graph.microsoft.com/v1.0"
+)
+
+// todoistTarget creates tasks through the Todoist REST API.
+type todoistTarget struct {
+	client    *http.Client
+	baseURL   string
+	token     string
+	projectID string
+}
+
+func newTodoistTarget(cfg TodoistTargetConfig, client *http.Client) (todoistTarget, error) {
+	token := strings.TrimSpace(cfg.Token)
+	if token == "" {
+		return todoistTarget{}, &RemindersExportError{Kind: "config", Err: errors.New("todoist token is not configured (set TODOIST_API_TOKEN)")}
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultTodoistBaseURL
+	}
+	return todoistTarget{client: client, baseURL: baseURL, token: token, projectID: strings.TrimSpace(cfg.ProjectID)}, nil
+}
+
+func (t todoistTarget) Name() string { return ReminderTargetTodoist }
+
+type todoistTaskRequest struct {
+	Content     string   `json:"content"`
+	Description string   `json:"description,omitempty"`
+	DueDatetime string   `json:"due_datetime,omitempty"`
+	ProjectID   string   `json:"project_id,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+func (t todoistTarget) Export(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error) {
+	if len(tasks) == 0 {
+		return RemindersExportResult{}, &RemindersExportError{Kind: "validation", Err: errors.New("no tasks available for reminders export")}
+	}
+
+	listName := "Todoist Inbox"
+	if t.projectID != "" {
+		listName = "Todoist project " + t.projectID
+	}
+
+	created := 0
+	for _, task := range tasks {
+		start, _ := taskWindowUTC(task.Date, opts.Location)
+		body := todoistTaskRequest{
+			Content:     taskICSSummary(task),
+			Description: taskICSDescription(task),
+			DueDatetime: start.Format(time.RFC3339),
+			ProjectID:   t.projectID,
+			Labels:      []string{"openppl"},
+		}
+		// Todoist deduplicates retried writes that share an X-Request-Id.
+		headers := map[string]string{"X-Request-Id": deterministicTaskUID(task)}
+		if err := postReminderJSON(t.client, t.baseURL+"/tasks", t.token, headers, body, nil, opts.Timeout); err != nil {
+			return RemindersExportResult{Target: t.Name(), ListName: listName, Created: created}, err
+		}
+		created++
+	}
+	return RemindersExportResult{Target: t.Name(), ListName: listName, Created: created}, nil
+}
+
+// msTodoTarget creates tasks in a Microsoft To Do list through Microsoft
+// Graph. The list is looked up by name, and created, unless ListID is set.
+type msTodoTarget struct {
+	client  *http.Client
+	baseURL string
+	token   string
+	listID  string
+}
+
+func newMSTodoTarget(cfg MSTodoTargetConfig, client *http.Client) (msTodoTarget, error) {
+	token := strings.TrimSpace(cfg.AccessToken)
+	if token == "" {
+		return msTodoTarget{}, &RemindersExportError{Kind: "config", Err: errors.New("microsoft graph access token is not configured (set MSGRAPH_ACCESS_TOKEN)")}
+	}
+	baseURL := strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if baseURL == "" {
+		baseURL = defaultMSGraphBaseURL
+	}
+	return msTodoTarget{client: client, baseURL: baseURL, token: token, listID: strings.TrimSpace(cfg.ListID)}, nil
+}
+
+func (t msTodoTarget) Name() string { return ReminderTargetMSTodo }
+
+type msTodoList struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+type msTodoTaskRequest struct {
+	Title       string            `json:"title"`
+	Body        msTodoItemBody    `json:"body"`
+	DueDateTime msTodoDateTime    `json:"dueDateTime"`
+	Categories  []string          `json:"categories,omitempty"`
+	Linked      []msTodoLinkedRes `json:"linkedResources,omitempty"`
+}
+
+type msTodoItemBody struct {
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+}
+
+type msTodoDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type msTodoLinkedRes struct {
+	ApplicationName string `json:"applicationName"`
+	DisplayName     string `json:"displayName"`
+	ExternalID      string `json:"externalId"`
+}
+
+func (t msTodoTarget) Export(tasks []model.DailyTask, opts RemindersExportOptions) (RemindersExportResult, error) {
+	if len(tasks) == 0 {
+		return RemindersExportResult{}, &RemindersExportError{Kind: "validation", Err: errors.New("no tasks available for reminders export")}
+	}
+
+	listName := strings.TrimSpace(opts.ListName)
+	if listName == "" {
+		listName = defaultRemindersList
+	}
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	listID := t.listID
+	if listID == "" {
+		var err error
+		listID, err = t.ensureList(listName, opts.Timeout)
+		if err != nil {
+			return RemindersExportResult{Target: t.Name(), ListName: listName}, err
+		}
+	}
+
+	created := 0
+	endpoint := fmt.Sprintf("%s/me/todo/lists/%s/tasks", t.baseURL, url.PathEscape(listID))
+	for _, task := range tasks {
+		start, _ := taskWindowUTC(task.Date, opts.Location)
+		body := msTodoTaskRequest{
+			Title:       taskICSSummary(task),
+			Body:        msTodoItemBody{Content: taskICSDescription(task), ContentType: "text"},
+			DueDateTime: msTodoDateTime{DateTime: start.In(loc).Format("2006-01-02T15:04:05"), TimeZone: loc.String()},
+			Linked: []msTodoLinkedRes{{
+				ApplicationName: "openppl",
+				DisplayName:     "openppl study task",
+				ExternalID:      deterministicTaskUID(task),
+			}},
+		}
+		if category := strings.TrimSpace(task.Category); category != "" {
+			body.Categories = []string{category}
+		}
+		if err := postReminderJSON(t.client, endpoint, t.token, nil, body, nil, opts.Timeout); err != nil {
+			return RemindersExportResult{Target: t.Name(), ListName: listName, Created: created}, err
+		}
+		created++
+	}
+	return RemindersExportResult{Target: t.Name(), ListName: listName, Created: created}, nil
+}
+
+func (t msTodoTarget) ensureList(name string, timeout time.Duration) (string, error) {
+	var lists struct {
+		Value []msTodoList `json:"value"`
+	}
+	if err := doReminderJSON(t.client, http.MethodGet, t.baseURL+"/me/todo/lists", t.token, nil, nil, &lists, timeout); err != nil {
+		return "", err
+	}
+	for _, list := range lists.Value {
+		if strings.EqualFold(strings.TrimSpace(list.DisplayName), name) {
+			return list.ID, nil
+		}
+	}
+
+	var created msTodoList
+	if err := postReminderJSON(t.client, t.baseURL+"/me/todo/lists", t.token, nil, msTodoList{DisplayName: name}, &created, timeout); err != nil {
+		return "", err
+	}
+	if created.ID == "" {
+		return "", &RemindersExportError{Kind: "script_failure", Err: errors.New("microsoft to do did not return a list id")}
+	}
+	return created.ID, nil
+}
+
+func postReminderJSON(client *http.Client, endpoint string, token string, headers map[string]string, body any, out any, timeout time.Duration) error {
+	return doReminderJSON(client, http.MethodPost, endpoint, token, headers, body, out, timeout)
+}
+
+// doReminderJSON performs one authenticated JSON request and maps HTTP
+// failures onto RemindersExportError kinds the UI already understands.
+func doReminderJSON(client *http.Client, method string, endpoint string, token string, headers map[string]string, body any, out any, timeout time.Duration) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if timeout <= 0 {
+		timeout = defaultReminderTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return &RemindersExportError{Kind: "validation", Err: err}
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return &RemindersExportError{Kind: "validation", Err: err}
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return &RemindersExportError{Kind: "timeout", Err: err}
+		}
+		return &RemindersExportError{Kind: "script_failure", Err: err}
+	}
+	defer resp.Body.Close()
+
+	payload, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return &RemindersExportError{Kind: "permission", Output: string(payload), Err: fmt.Errorf("%s %s returned %s", method, endpoint, resp.Status)}
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return &RemindersExportError{Kind: "script_failure", Output: string(payload), Err: fmt.Errorf("%s %s returned %s", method, endpoint, resp.Status)}
+	}
+
+	if out != nil && len(bytes.TrimSpace(payload)) > 0 {
+		if err := json.Unmarshal(payload, out); err != nil {
+			return &RemindersExportError{Kind: "script_failure", Err: fmt.Errorf("decode response: %w", err)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// rescheduleSearchDays is how far from a blocked flight day the rescheduler
+// looks for a ground task to trade with.
+const rescheduleSearchDays = 3
+
+// BlockedFlight is a pending flight task on a day the forecast puts below
+// personal minimums.
+type BlockedFlight struct {
+	Task    model.DailyTask
+	Reasons []string
+}
+
+// FlightSwap trades a blocked flight task's day with a nearby ground task's.
+type FlightSwap struct {
+	Flight  model.DailyTask
+	Ground  model.DailyTask
+	Reasons []string
+}
+
+// Describe is a one-line preview of the swap.
+func (s FlightSwap) Describe() string {
+	return fmt.Sprintf("%s %s -> %s, %s %s -> %s",
+		s.Flight.Title, s.Flight.Date.Format("Jan 2"), s.Ground.Date.Format("Jan 2"),
+		s.Ground.Title, s.Ground.Date.Format("Jan 2"), s.Flight.Date.Format("Jan 2"))
+}
+
+// RescheduleProposal is the rescheduler's suggestion for the forecast
+// period. Unresolved flights are blocked but have no ground task nearby.
+type RescheduleProposal struct {
+	Station    string
+	Swaps      []FlightSwap
+	Unresolved []BlockedFlight
+}
+
+// TaskRescheduledPayload describes a task moved to another day.
+type TaskRescheduledPayload struct {
+	TaskID      uint   `json:"task_id"`
+	StudyPlanID uint   `json:"study_plan_id"`
+	FromDate    string `json:"from_date"`
+	ToDate      string `json:"to_date"`
+	Category    string `json:"category"`
+	Title       string `json:"title"`
+	Reason      string `json:"reason"`
+	Source      string `json:"source"`
+}
+
+// ProposeWeatherReschedule checks the school airport's TAF against the
+// minimums and proposes swaps for the latest study plan's pending flight
+// tasks it blocks.
+func ProposeWeatherReschedule(ctx context.Context, database *gorm.DB, provider WeatherProvider, minimums PersonalMinimums, now time.Time) (RescheduleProposal, error) {
+	station, err := SchoolAirport(database)
+	if err != nil || station == "" {
+		return RescheduleProposal{}, err
+	}
+	taf, err := provider.TAF(ctx, station)
+	if err != nil {
+		return RescheduleProposal{Station: station}, err
+	}
+	tasks, err := LoadLatestPlanTasks(database)
+	if err != nil {
+		return RescheduleProposal{Station: station}, fmt.Errorf("load tasks: %w", err)
+	}
+	swaps, unresolved := PlanWeatherSwaps(tasks, taf, minimums.ForAirport(station), now)
+	return RescheduleProposal{Station: station, Swaps: swaps, Unresolved: unresolved}, nil
+}
+
+// PlanWeatherSwaps finds pending flight tasks whose flying window
+// (07:00-19:00 local) the TAF forecasts below the dual minimums (flight
+// tasks are lessons with an instructor), in any group, and
+// pairs each with a pending ground task up to three days away, today or
+// later. Days the forecast says are flyable are preferred over days it does
+// not reach yet, and nearer days over farther ones.
+func PlanWeatherSwaps(tasks []model.DailyTask, taf TAF, minimums PersonalMinimums, now time.Time) ([]FlightSwap, []BlockedFlight) {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	dayOf := func(task model.DailyTask) time.Time {
+		return time.Date(task.Date.Year(), task.Date.Month(), task.Date.Day(), 0, 0, 0, 0, loc)
+	}
+
+	type dayForecast struct {
+		reasons []string
+		covered bool
+	}
+	forecasts := map[time.Time]dayForecast{}
+	forecastFor := func(day time.Time) dayForecast {
+		if forecast, ok := forecasts[day]; ok {
+			return forecast
+		}
+		reasons, covered := forecastViolations(taf, minimums, false, day.Add(weatherFlyingWindowStart*time.Hour), day.Add(weatherFlyingWindowEnd*time.Hour))
+		forecasts[day] = dayForecast{reasons: reasons, covered: covered}
+		return forecasts[day]
+	}
+
+	groundByDay := map[time.Time][]model.DailyTask{}
+	for _, task := range tasks {
+		if !task.Completed && !IsFlightTask(task) {
+			groundByDay[dayOf(task)] = append(groundByDay[dayOf(task)], task)
+		}
+	}
+
+	var swaps []FlightSwap
+	var unresolved []BlockedFlight
+	used := map[uint]bool{}
+	for _, flight := range tasks {
+		day := dayOf(flight)
+		if flight.Completed || !IsFlightTask(flight) || day.Before(today) {
+			continue
+		}
+		forecast := forecastFor(day)
+		if len(forecast.reasons) == 0 {
+			continue
+		}
+
+		type candidate struct {
+			day      time.Time
+			distance int
+			flyable  bool
+		}
+		var candidates []candidate
+		for offset := 1; offset <= rescheduleSearchDays; offset++ {
+			for _, other := range []time.Time{day.AddDate(0, 0, offset), day.AddDate(0, 0, -offset)} {
+				if other.Before(today) {
+					continue
+				}
+				otherForecast := forecastFor(other)
+				if len(otherForecast.reasons) > 0 {
+					continue
+				}
+				candidates = append(candidates, candidate{day: other, distance: offset, flyable: otherForecast.covered})
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].flyable != candidates[j].flyable {
+				return candidates[i].flyable
+			}
+			return candidates[i].distance < candidates[j].distance
+		})
+
+		swapped := false
+		for _, c := range candidates {
+			for _, ground := range groundByDay[c.day] {
+				if used[ground.ID] {
+					continue
+				}
+				used[ground.ID] = true
+				swaps = append(swaps, FlightSwap{Flight: flight, Ground: ground, Reasons: forecast.reasons})
+				swapped = true
+				break
+			}
+			if swapped {
+				break
+			}
+		}
+		if !swapped {
+			unresolved = append(unresolved, BlockedFlight{Task: flight, Reasons: forecast.reasons})
+		}
+	}
+	return swaps, unresolved
+}
+
+// forecastViolations lists the distinct violations of the solo or dual
+// minimums in every TAF group overlapping [from, to), and whether the TAF
+// covers that time at all.
+func forecastViolations(taf TAF, minimums PersonalMinimums, solo bool, from, to time.Time) ([]string, bool) {
+	var reasons []string
+	seen := map[string]bool{}
+	covered := false
+	for _, period := range taf.Periods {
+		if !period.From.Before(to) || !period.To.After(from) {
+			continue
+		}
+		covered = true
+		for _, violation := range minimums.Violations(period.WeatherConditions, solo) {
+			if period.Change == "TEMPO" || strings.HasPrefix(period.Change, "PROB") {
+				violation = period.Change + " " + violation
+			}
+			if !seen[violation] {
+				seen[violation] = true
+				reasons = append(reasons, violation)
+			}
+		}
+	}
+	return reasons, covered
+}
+
+// ApplyWeatherReschedule swaps the days of each proposed pair and records a
+// task.rescheduled event per moved task. It refuses the whole proposal when
+// any task was completed or moved since it was made.
+func ApplyWeatherReschedule(database *gorm.DB, swaps []FlightSwap, source string) error {
+	return database.Transaction(func(tx *gorm.DB) error {
+		for _, swap := range swaps {
+			reason := "weather: " + strings.Join(swap.Reasons, ", ")
+			flight, err := loadUnchangedTask(tx, swap.Flight)
+			if err != nil {
+				return err
+			}
+			ground, err := loadUnchangedTask(tx, swap.Ground)
+			if err != nil {
+				return err
+			}
+			if err := moveTask(tx, flight, ground.Date, reason, source); err != nil {
+				return err
+			}
+			if err := moveTask(tx, ground, flight.Date, reason, source); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func loadUnchangedTask(tx *gorm.DB, proposed model.DailyTask) (model.DailyTask, error) {
+	var task model.DailyTask
+	if err := tx.First(&task, proposed.ID).Error; err != nil {
+		return model.DailyTask{}, fmt.Errorf("task %d not found", proposed.ID)
+	}
+	if task.Completed || !task.Date.Equal(proposed.Date) {
+		return model.DailyTask{}, fmt.Errorf("task %q changed since the proposal; refresh and try again", task.Title)
+	}
+	return task, nil
+}
+
+func moveTask(tx *gorm.DB, task model.DailyTask, to time.Time, reason string, source string) error {
+	from := task.Date
+	updates := map[string]interface{}{"date": to, "sequence": task.Sequence + 1}
+	if task.RescheduledFrom == nil {
+		updates["rescheduled_from"] = from
+	}
+	if err := tx.Model(&task).Updates(updates).Error; err != nil {
+		return fmt.Errorf("move task %q: %w", task.Title, err)
+	}
+	return RecordEvent(tx, EventTaskRescheduled, TaskRescheduledPayload{
+		TaskID:      task.ID,
+		StudyPlanID: task.StudyPlanID,
+		FromDate:    from.UTC().Format("2006-01-02"),
+		ToDate:      to.UTC().Format("2006-01-02"),
+		Category:    task.Category,
+		Title:       task.Title,
+		Reason:      reason,
+		Source:      source,
+	})
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestPersonalMinimums_Violations(t *testing.T) {
+	minimums := PersonalMinimums{
+		Dual:    MinimumsSet{CeilingFt: 2000, VisibilitySM: 3, MaxWindKt: 25, MaxCrosswindKt: 10, MaxGustSpreadKt: 10},
+		Solo:    MinimumsSet{CeilingFt: 3000, VisibilitySM: 5, MaxWindKt: 12, MaxCrosswindKt: 8, MaxGustSpreadKt: 5},
+		Runways: []string{"09", "27"},
+	}
+
+	conditions := WeatherConditions{
+		VisibilitySM: 2,
+		Clouds:       []CloudLayer{{Cover: "BKN", BaseFt: 800}},
+		Wind:         &Wind{DirDeg: 180, SpeedKt: 15, GustKt: 22},
+	}
+	want := "ceiling 800 ft below 2000 ft, visibility 2 SM below 3 SM, crosswind 22 kt on runway 09 above 10 kt"
+	if got := strings.Join(minimums.Violations(conditions, false), ", "); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	want = "ceiling 800 ft below 3000 ft, visibility 2 SM below 5 SM, wind 22 kt above 12 kt, gust spread 7 kt above 5 kt, crosswind 22 kt on runway 09 above 8 kt"
+	if got := strings.Join(minimums.Violations(conditions, true), ", "); got != want {
+		t.Fatalf("expected solo %q, got %q", want, got)
+	}
+
+	minimums.Runways = append(minimums.Runways, "18L")
+	conditions = WeatherConditions{VisibilitySM: 10, Wind: &Wind{DirDeg: 180, SpeedKt: 15, GustKt: 22}}
+	if got := minimums.Violations(conditions, false); len(got) != 0 {
+		t.Fatalf("expected runway 18L to be into the wind, got %v", got)
+	}
+	conditions.Wind = &Wind{DirDeg: -1, SpeedKt: 12}
+	if got := minimums.Violations(conditions, false); len(got) != 1 {
+		t.Fatalf("expected a variable 12 kt wind to count as crosswind, got %v", got)
+	}
+}
+
+func TestPersonalMinimums_LoadAndSave(t *testing.T) {
+	t.Setenv("OPENPPL_MINIMUMS_PATH", filepath.Join(t.TempDir(), "minimums.json"))
+
+	minimums, err := LoadPersonalMinimums()
+	if err != nil || minimums.Dual != DefaultPersonalMinimums().Dual || minimums.Solo != DefaultPersonalMinimums().Solo || len(minimums.Runways) != 0 {
+		t.Fatalf("expected defaults without a file, got %+v, %v", minimums, err)
+	}
+
+	minimums.Runways = []string{"08", "26"}
+	minimums.Solo.MaxCrosswindKt = 6
+	if err := SavePersonalMinimums(minimums); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := LoadPersonalMinimums()
+	if err != nil || loaded.Solo.MaxCrosswindKt != 6 || strings.Join(loaded.Runways, ",") != "08,26" {
+		t.Fatalf("expected saved minimums back, got %+v, %v", loaded, err)
+	}
+
+	// Limits missing from the file keep their defaults.
+	if err := os.WriteFile(os.Getenv("OPENPPL_MINIMUMS_PATH"), []byte(`{"solo": {"ceiling_ft": 4000}}`), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	loaded, err = LoadPersonalMinimums()
+	if err != nil || loaded.Solo.CeilingFt != 4000 || loaded.Solo.VisibilitySM != DefaultPersonalMinimums().Solo.VisibilitySM || loaded.Dual != DefaultPersonalMinimums().Dual {
+		t.Fatalf("expected a partial profile merged over the defaults, got %+v, %v", loaded, err)
+	}
+
+	minimums.Dual.MaxWindKt = -1
+	if err := SavePersonalMinimums(minimums); err == nil || !strings.Contains(err.Error(), "dual limits must not be negative") {
+		t.Fatalf("expected a negative limit error, got %v", err)
+	}
+	minimums.Dual.MaxWindKt = 20
+	minimums.Runways = []string{"45"}
+	if err := SavePersonalMinimums(minimums); err == nil || !strings.Contains(err.Error(), `invalid runway "45"`) {
+		t.Fatalf("expected an invalid runway error, got %v", err)
+	}
+}
+
+func rescheduleTestTAF(t *testing.T) TAF {
+	t.Helper()
+	taf, err := FileWeatherProvider{Dir: weatherFixtureDir}.TAF(context.Background(), "KFXE")
+	if err != nil {
+		t.Fatalf("TAF failed: %v", err)
+	}
+	return taf
+}
+
+func TestPlanWeatherSwaps_TradesBlockedFlightsForGroundTasks(t *testing.T) {
+	// The fixture TAF has TEMPO 2SM BKN008 on Jun 1 afternoon, BKN025 on the
+	// morning of Jun 2 and ends at 12Z Jun 2.
+	minimums := PersonalMinimums{Dual: MinimumsSet{CeilingFt: 2000, VisibilitySM: 3, MaxWindKt: 25, MaxCrosswindKt: 10, MaxGustSpreadKt: 10}, Runways: []string{"09", "27"}}
+	now := time.Date(2026, 6, 1, 6, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 6, d, 0, 0, 0, 0, time.UTC) }
+	tasks := []model.DailyTask{
+		{ID: 1, Date: day(1), Category: "CFI Flights", Title: "Pattern work"},
+		{ID: 2, Date: day(1), Category: "CFI Flights", Title: "Stalls"},
+		{ID: 3, Date: day(1), Category: "CFI Flights", Title: "Steep turns"},
+		{ID: 4, Date: day(1), Category: "Theory", Title: "Area 1"},
+		{ID: 5, Date: day(2), Category: "Theory", Title: "Area 2"},
+		{ID: 6, Date: day(3), Category: "Chair Flying", Title: "Area 3"},
+		{ID: 7, Date: time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Yesterday"},
+		{ID: 8, Date: day(2), Category: "CFI Flights", Title: "Slow flight"},
+	}
+
+	swaps, unresolved := PlanWeatherSwaps(tasks, rescheduleTestTAF(t), minimums, now)
+	if len(swaps) != 2 {
+		t.Fatalf("expected 2 swaps, got %+v", swaps)
+	}
+	// Jun 2 is forecast flyable, so it is tried before Jun 3, which the TAF
+	// does not reach.
+	if swaps[0].Flight.ID != 1 || swaps[0].Ground.ID != 5 || swaps[1].Flight.ID != 2 || swaps[1].Ground.ID != 6 {
+		t.Fatalf("unexpected pairing: %s / %s", swaps[0].Describe(), swaps[1].Describe())
+	}
+	if got := strings.Join(swaps[0].Reasons, ", "); got != "TEMPO ceiling 800 ft below 2000 ft, TEMPO visibility 2 SM below 3 SM" {
+		t.Fatalf("unexpected reasons %q", got)
+	}
+	if len(unresolved) != 1 || unresolved[0].Task.ID != 3 {
+		t.Fatalf("expected Steep turns unresolved, got %+v", unresolved)
+	}
+	if got := swaps[0].Describe(); got != "Pattern work Jun 1 -> Jun 2, Area 2 Jun 2 -> Jun 1" {
+		t.Fatalf("unexpected preview %q", got)
+	}
+}
+
+func TestApplyWeatherReschedule_SwapsDaysAndRecordsEvents(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.StudyPlan{}, &model.DailyTask{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+	oldPlan := model.StudyPlan{CheckrideDate: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)}
+	db.Create(&oldPlan)
+	db.Create(&model.DailyTask{StudyPlanID: oldPlan.ID, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Superseded pattern work"})
+	plan := model.StudyPlan{CheckrideDate: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)}
+	db.Create(&plan)
+	flight := model.DailyTask{StudyPlanID: plan.ID, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Pattern work"}
+	ground := model.DailyTask{StudyPlanID: plan.ID, Date: time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Area 2"}
+	db.Create(&flight)
+	db.Create(&ground)
+
+	minimums := PersonalMinimums{Dual: MinimumsSet{CeilingFt: 2000, VisibilitySM: 3, MaxWindKt: 25, MaxCrosswindKt: 10, MaxGustSpreadKt: 10}}
+	proposal, err := ProposeWeatherReschedule(context.Background(), db, FileWeatherProvider{Dir: weatherFixtureDir}, minimums, time.Date(2026, 6, 1, 6, 0, 0, 0, time.UTC))
+	if err != nil || proposal.Station != "KFXE" || len(proposal.Swaps) != 1 || len(proposal.Unresolved) != 0 {
+		t.Fatalf("expected one proposed swap and nothing from the old plan, got %+v, %v", proposal, err)
+	}
+
+	if err := ApplyWeatherReschedule(db, proposal.Swaps, "test"); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	var moved model.DailyTask
+	db.First(&moved, flight.ID)
+	if !moved.Date.Equal(ground.Date) || moved.Sequence != 1 {
+		t.Fatalf("expected the flight on Jun 2 with a bumped sequence, got %s seq %d", moved.Date, moved.Sequence)
+	}
+	var swapped model.DailyTask
+	db.First(&swapped, ground.ID)
+	if !swapped.Date.Equal(flight.Date) {
+		t.Fatalf("expected the ground task on Jun 1, got %s", swapped.Date)
+	}
+	var events []model.OutboxEvent
+	db.Where("event_type = ?", EventTaskRescheduled).Find(&events)
+	if len(events) != 2 || !strings.Contains(events[0].PayloadJSON, `"reason":"weather: TEMPO ceiling 800 ft below 2000 ft`) {
+		t.Fatalf("expected two task.rescheduled events, got %+v", events)
+	}
+
+	if err := ApplyWeatherReschedule(db, proposal.Swaps, "test"); err == nil || !strings.Contains(err.Error(), "changed since the proposal") {
+		t.Fatalf("expected a stale proposal to be refused, got %v", err)
+	}
+}
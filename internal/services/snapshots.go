@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	// velocityWindowDays is how far back ProjectCompletion measures the pace.
+	velocityWindowDays = 14
+
+	// The budget defaults match the web budget page.
+	defaultPlaneRate   = 150
+	defaultCFIRate     = 60
+	defaultBudgetLimit = 10000
+)
+
+// RecordProgressSnapshot stores today's progress on the latest plan,
+// readiness and spend, replacing an earlier snapshot from the same day so the
+// last run of the day wins.
+func RecordProgressSnapshot(database *gorm.DB, attempts []MOTDAnswer, now time.Time) (model.ProgressSnapshot, error) {
+	tasks, err := LoadLatestPlanTasks(database)
+	if err != nil {
+		return model.ProgressSnapshot{}, fmt.Errorf("snapshot: %w", err)
+	}
+
+	day := dateOnlyUTC(now)
+	snapshot := model.ProgressSnapshot{Date: day, TasksTotal: len(tasks)}
+	planned := 0
+	for _, task := range tasks {
+		if task.Completed {
+			snapshot.TasksCompleted++
+		}
+		if !dateOnlyUTC(task.Date).After(day) {
+			planned++
+		}
+	}
+	snapshot.ProgressPercent = percent(snapshot.TasksCompleted, snapshot.TasksTotal)
+	snapshot.PlannedPercent = percent(planned, snapshot.TasksTotal)
+	snapshot.ReadinessScore = LoadReadiness(database, attempts, now).Score
+	snapshot.SpentUSD, snapshot.BudgetUSD = estimateSpend(database)
+
+	var existing model.ProgressSnapshot
+	if err := database.Where("date = ?", day).Limit(1).Find(&existing).Error; err != nil {
+		return model.ProgressSnapshot{}, fmt.Errorf("snapshot: load %s: %w", day.Format("2006-01-02"), err)
+	}
+	snapshot.ID, snapshot.CreatedAt = existing.ID, existing.CreatedAt
+	if err := database.Save(&snapshot).Error; err != nil {
+		return model.ProgressSnapshot{}, fmt.Errorf("snapshot: save %s: %w", day.Format("2006-01-02"), err)
+	}
+	return snapshot, nil
+}
+
+// estimateSpend prices every flight flown in the logbook at the budget's
+// plane rate, plus the CFI rate for dual, and returns it with the budget
+// limit.
+func estimateSpend(database *gorm.DB) (spent, budget float64) {
+	planeRate := budgetAmount(database, model.BudgetPlaneRate, defaultPlaneRate)
+	cfiRate := budgetAmount(database, model.BudgetCfiRate, defaultCFIRate)
+	if entries, err := ListLogbook(database); err == nil {
+		flown := SummarizeFlightTime(entries)
+		spent = flown.Total*planeRate + flown.Dual*cfiRate
+	}
+	return spent, budgetAmount(database, model.BudgetLimit, defaultBudgetLimit)
+}
+
+func budgetAmount(database *gorm.DB, itemType model.BudgetItemType, fallback float64) float64 {
+	var budget model.Budget
+	if err := database.Where("item_type = ?", itemType).Order("id asc").Limit(1).Find(&budget).Error; err != nil || budget.ID == 0 {
+		return fallback
+	}
+	return budget.Amount
+}
+
+// ListProgressSnapshots returns the snapshots from since on, oldest first.
+// A zero since returns them all.
+func ListProgressSnapshots(database *gorm.DB, since time.Time) ([]model.ProgressSnapshot, error) {
+	query := database.Order("date asc")
+	if !since.IsZero() {
+		query = query.Where("date >= ?", dateOnlyUTC(since))
+	}
+	var snapshots []model.ProgressSnapshot
+	if err := query.Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("load progress snapshots: %w", err)
+	}
+	return snapshots, nil
+}
+
+// CompletionProjection extrapolates the recent pace of task completion to
+// the day the last task would be done. OK is false when there is no pace to
+// extrapolate: too little history or no tasks completed in the window.
+type CompletionProjection struct {
+	TasksPerDay float64
+	Remaining   int
+	Date        time.Time
+	OK          bool
+}
+
+// ProjectCompletion measures the pace between the latest snapshot and the
+// oldest one within velocityWindowDays of it.
+func ProjectCompletion(snapshots []model.ProgressSnapshot) CompletionProjection {
+	if len(snapshots) == 0 {
+		return CompletionProjection{}
+	}
+	last := snapshots[len(snapshots)-1]
+	projection := CompletionProjection{Remaining: last.TasksTotal - last.TasksCompleted}
+	if last.TasksTotal == 0 {
+		return projection
+	}
+	if projection.Remaining <= 0 {
+		projection.Remaining, projection.Date, projection.OK = 0, last.Date, true
+		return projection
+	}
+
+	first := last
+	for _, snapshot := range snapshots {
+		if last.Date.Sub(snapshot.Date) <= velocityWindowDays*24*time.Hour {
+			first = snapshot
+			break
+		}
+	}
+	days := last.Date.Sub(first.Date).Hours() / 24
+	if days < 1 {
+		return projection
+	}
+	projection.TasksPerDay = float64(last.TasksCompleted-first.TasksCompleted) / days
+	if projection.TasksPerDay <= 0 {
+		return projection
+	}
+	projection.Date = last.Date.AddDate(0, 0, int(math.Ceil(float64(projection.Remaining)/projection.TasksPerDay)))
+	projection.OK = true
+	return projection
+}
+
+// Describe is the projection in a sentence, compared to the checkride date
+// when one is set.
+func (p CompletionProjection) Describe(checkride time.Time) string {
+	switch {
+	case p.OK && p.Remaining == 0:
+		return "All study tasks are done"
+	case !p.OK:
+		return "No projected completion yet: it needs tasks completed across at least two days of snapshots"
+	}
+	text := fmt.Sprintf("Projected completion %s at %.1f tasks/day (%d to go)", p.Date.Format("Jan 2, 2006"), p.TasksPerDay, p.Remaining)
+	if checkride.IsZero() {
+		return text
+	}
+	days := int(math.Round(dateOnlyUTC(checkride).Sub(p.Date).Hours() / 24))
+	switch {
+	case days > 0:
+		return text + fmt.Sprintf(", %d days before the checkride", days)
+	case days < 0:
+		return text + fmt.Sprintf(", %d days after the checkride", -days)
+	default:
+		return text + ", on the checkride date"
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupSnapshotTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.ChecklistItem{}, &model.LogbookEntry{}, &model.Endorsement{}, &model.Budget{}, &model.ProgressSnapshot{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestRecordProgressSnapshot_OnePerDay(t *testing.T) {
+	db := setupSnapshotTestDB(t)
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	flown := now.Add(-48 * time.Hour)
+	old := model.StudyPlan{CheckrideDate: now.AddDate(0, 2, 0)}
+	current := model.StudyPlan{CheckrideDate: now.AddDate(0, 3, 0)}
+	db.Create(&old)
+	db.Create(&current)
+	db.Create(&model.DailyTask{StudyPlanID: old.ID, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Old plan review", Completed: true})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Airspace", Completed: true})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Weather"})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Charts"})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Regulations"})
+	db.Create(&model.LogbookEntry{Hours: 1.5, FlownAt: &flown})
+	if _, err := LogFlight(db, FlightInput{Date: flown, Hours: 1, Solo: true}); err != nil {
+		t.Fatalf("LogFlight failed: %v", err)
+	}
+	db.Create(&model.Budget{ItemType: model.BudgetPlaneRate, Amount: 180})
+
+	snapshot, err := RecordProgressSnapshot(db, nil, now)
+	if err != nil {
+		t.Fatalf("RecordProgressSnapshot failed: %v", err)
+	}
+	if snapshot.TasksTotal != 4 || snapshot.ProgressPercent != 25 || snapshot.PlannedPercent != 50 || snapshot.SpentUSD != 540 || snapshot.BudgetUSD != 10000 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.ReadinessScore <= 0 {
+		t.Fatalf("expected a readiness score, got %+v", snapshot)
+	}
+
+	db.Model(&model.DailyTask{}).Where("title = ?", "Weather").Update("completed", true)
+	if _, err := RecordProgressSnapshot(db, nil, now.Add(8*time.Hour)); err != nil {
+		t.Fatalf("second RecordProgressSnapshot failed: %v", err)
+	}
+	snapshots, err := ListProgressSnapshots(db, time.Time{})
+	if err != nil {
+		t.Fatalf("ListProgressSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].TasksCompleted != 2 {
+		t.Fatalf("expected the day's snapshot replaced, got %+v", snapshots)
+	}
+}
+
+func TestProjectCompletion(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	snapshots := []model.ProgressSnapshot{
+		{Date: day(1), TasksCompleted: 0, TasksTotal: 40},
+		{Date: day(6), TasksCompleted: 6, TasksTotal: 40},
+		{Date: day(15), TasksCompleted: 14, TasksTotal: 40},
+		{Date: day(20), TasksCompleted: 20, TasksTotal: 40},
+	}
+
+	projection := ProjectCompletion(snapshots)
+	if !projection.OK || projection.TasksPerDay != 1 || projection.Remaining != 20 || !projection.Date.Equal(day(20).AddDate(0, 0, 20)) {
+		t.Fatalf("unexpected projection %+v", projection)
+	}
+	if text := projection.Describe(time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC)); !strings.Contains(text, "Apr 9, 2026") || !strings.Contains(text, "6 days before the checkride") {
+		t.Fatalf("unexpected description %q", text)
+	}
+	if text := projection.Describe(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)); !strings.Contains(text, "8 days after the checkride") {
+		t.Fatalf("unexpected description %q", text)
+	}
+
+	if stalled := ProjectCompletion([]model.ProgressSnapshot{{Date: day(1), TasksCompleted: 3, TasksTotal: 10}, {Date: day(4), TasksCompleted: 3, TasksTotal: 10}}); stalled.OK {
+		t.Fatalf("expected no projection without progress, got %+v", stalled)
+	}
+	if done := ProjectCompletion([]model.ProgressSnapshot{{Date: day(4), TasksCompleted: 10, TasksTotal: 10}}); !done.OK || done.Describe(time.Time{}) != "All study tasks are done" {
+		t.Fatalf("expected a finished plan, got %+v", done)
+	}
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// StreakWeeks is how many weeks the streak heatmap covers by default.
+const StreakWeeks = 12
+
+// StreakDay is one day of study activity: the tasks completed that day and
+// whether the daily quiz was answered. Missed is set for days without
+// activity between the first active day and yesterday; today is never
+// missed yet.
+type StreakDay struct {
+	Date   time.Time
+	Tasks  int
+	Quiz   bool
+	Missed bool
+}
+
+// Active reports whether anything was studied that day.
+func (d StreakDay) Active() bool {
+	return d.Tasks > 0 || d.Quiz
+}
+
+// Level is the day's activity on a 0-4 scale for heatmaps: one point per
+// task and one for the quiz, capped at 4.
+func (d StreakDay) Level() int {
+	level := d.Tasks
+	if d.Quiz {
+		level++
+	}
+	return min(level, 4)
+}
+
+// StudyWeek counts the active days of a week that starts on Monday. Days
+// is how many of its days are tracked: from the first active day up to
+// today.
+type StudyWeek struct {
+	Start      time.Time
+	ActiveDays int
+	Days       int
+}
+
+// StudyStreaks combines task completions and quiz answers into daily study
+// streaks. Days covers whole weeks, Monday first, up to today.
+type StudyStreaks struct {
+	Current    int
+	Longest    int
+	Today      bool
+	MissedDays int
+	Days       []StreakDay
+	Weeks      []StudyWeek
+}
+
+// Consistency is the share of tracked days in the window with any study.
+func (s StudyStreaks) Consistency() float64 {
+	active, tracked := 0, 0
+	for _, week := range s.Weeks {
+		active += week.ActiveDays
+		tracked += week.Days
+	}
+	return percent(active, tracked)
+}
+
+// LoadStudyStreaks reads the task completion history and combines it with
+// the quiz attempts.
+func LoadStudyStreaks(database *gorm.DB, attempts []MOTDAnswer, now time.Time, weeks int) (StudyStreaks, error) {
+	entries, err := ListTaskProgress(database)
+	if err != nil {
+		return StudyStreaks{}, err
+	}
+	return ComputeStudyStreaks(entries, attempts, now, weeks), nil
+}
+
+// ComputeStudyStreaks counts a day as studied when a task was completed
+// (and not unchecked since) or the quiz was answered rather than skipped.
+// The streaks cover all history; Days and Weeks the last weeks weeks.
+func ComputeStudyStreaks(entries []model.Progress, attempts []MOTDAnswer, now time.Time, weeks int) StudyStreaks {
+	activity := map[time.Time]*StreakDay{}
+	day := func(date time.Time) *StreakDay {
+		if activity[date] == nil {
+			activity[date] = &StreakDay{Date: date}
+		}
+		return activity[date]
+	}
+	for _, entry := range latestCompletions(entries) {
+		day(dateOnlyUTC(entry.CompletedAt.In(now.Location()))).Tasks++
+	}
+	for _, attempt := range attempts {
+		if attempt.Skipped {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", attempt.Date); err == nil {
+			day(date).Quiz = true
+		}
+	}
+
+	var first time.Time
+	active := make([]time.Time, 0, len(activity))
+	for date := range activity {
+		active = append(active, date)
+		if first.IsZero() || date.Before(first) {
+			first = date
+		}
+	}
+	today := dateOnlyUTC(now)
+	streaks := StudyStreaks{Today: activity[today] != nil}
+	streaks.Current, streaks.Longest = dayStreaks(active, today)
+
+	weeks = max(weeks, 1)
+	start := today.AddDate(0, 0, -(int(today.Weekday())+6)%7-7*(weeks-1))
+	streaks.Weeks = make([]StudyWeek, weeks)
+	for date := start; !date.After(today); date = date.AddDate(0, 0, 1) {
+		current := StreakDay{Date: date}
+		if found := activity[date]; found != nil {
+			current = *found
+		}
+		week := &streaks.Weeks[int(date.Sub(start).Hours()/24)/7]
+		if week.Start.IsZero() {
+			week.Start = date
+		}
+		if !first.IsZero() && !date.Before(first) {
+			week.Days++
+			if current.Active() {
+				week.ActiveDays++
+			} else if date.Before(today) {
+				current.Missed = true
+				streaks.MissedDays++
+			}
+		}
+		streaks.Days = append(streaks.Days, current)
+	}
+	if !streaks.Today && streaks.Weeks[weeks-1].Days > 0 {
+		// Today is still open, so it does not count against the week.
+		streaks.Weeks[weeks-1].Days--
+	}
+	return streaks
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestComputeStudyStreaks_CombinesTasksAndQuiz(t *testing.T) {
+	// Wednesday; the two-week window starts on Monday Jun 1.
+	now := time.Date(2026, 6, 10, 18, 0, 0, 0, time.UTC)
+	at := func(day int) time.Time { return time.Date(2026, 6, day, 9, 0, 0, 0, time.UTC) }
+	entries := []model.Progress{
+		{DailyTaskID: 1, CompletedAt: at(3), Completed: true},
+		{DailyTaskID: 2, CompletedAt: at(3), Completed: true},
+		{DailyTaskID: 3, CompletedAt: at(6), Completed: true},
+		{DailyTaskID: 3, CompletedAt: at(6).Add(time.Hour), Completed: false},
+		{DailyTaskID: 4, CompletedAt: at(8), Completed: true},
+	}
+	attempts := []MOTDAnswer{
+		{Date: "2026-06-04", IsCorrect: true},
+		{Date: "2026-06-05", Skipped: true},
+		{Date: "2026-06-09"},
+	}
+
+	streaks := ComputeStudyStreaks(entries, attempts, now, 2)
+	if streaks.Current != 2 || streaks.Longest != 2 || streaks.Today {
+		t.Fatalf("expected a current and longest streak of 2 without today, got %+v", streaks)
+	}
+	if len(streaks.Days) != 10 || !streaks.Days[0].Date.Equal(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected Jun 1 to Jun 10 in the heatmap, got %d days from %v", len(streaks.Days), streaks.Days[0].Date)
+	}
+	if streaks.Days[1].Missed || streaks.Days[2].Level() != 2 || !streaks.Days[4].Missed || streaks.Days[9].Missed {
+		t.Fatalf("unexpected heatmap days: %+v", streaks.Days)
+	}
+	// Jun 5, 6 and 7 were missed; Jun 1 and 2 were before any study.
+	if streaks.MissedDays != 3 {
+		t.Fatalf("expected 3 missed days, got %d", streaks.MissedDays)
+	}
+	if streaks.Weeks[0].ActiveDays != 2 || streaks.Weeks[0].Days != 5 || streaks.Weeks[1].ActiveDays != 2 || streaks.Weeks[1].Days != 2 {
+		t.Fatalf("unexpected weeks: %+v", streaks.Weeks)
+	}
+	if got := streaks.Consistency(); got < 57 || got > 58 {
+		t.Fatalf("expected 4 of 7 tracked days, got %.1f%%", got)
+	}
+}
+
+func TestComputeStudyStreaks_Empty(t *testing.T) {
+	streaks := ComputeStudyStreaks(nil, nil, time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC), StreakWeeks)
+	if streaks.Current != 0 || streaks.MissedDays != 0 || streaks.Consistency() != 0 || len(streaks.Weeks) != StreakWeeks {
+		t.Fatalf("unexpected streaks without any history: %+v", streaks)
+	}
+}
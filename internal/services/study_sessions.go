@@ -0,0 +1,364 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	// PomodoroMinutes is the length of a Pomodoro focus block. A Pomodoro
+	// session counts at most this long, however late it is stopped.
+	PomodoroMinutes = 25
+	// PomodoroBreakMinutes is the break suggested after a focus block.
+	PomodoroBreakMinutes = 5
+
+	// minEstimateTasks is how many different tasks of a category need
+	// timed sessions before their actual effort replaces the template's
+	// duration for the whole category.
+	minEstimateTasks = 3
+)
+
+// ErrNoActiveSession is returned when pausing or stopping without a running
+// session.
+var ErrNoActiveSession = errors.New("no study session is running")
+
+// ActiveSessionError is returned when starting a session while another one
+// is still open.
+type ActiveSessionError struct {
+	Session model.StudySession
+}
+
+func (e *ActiveSessionError) Error() string {
+	return fmt.Sprintf("a study session for %q is already running; stop it first", e.Session.TaskTitle)
+}
+
+// SessionElapsed is the time worked in a session as of now, excluding
+// pauses and capped at PomodoroMinutes for a Pomodoro.
+func SessionElapsed(session model.StudySession, now time.Time) time.Duration {
+	elapsed := time.Duration(session.Seconds) * time.Second
+	if session.EndedAt == nil && session.PausedAt == nil && now.After(session.ResumedAt) {
+		elapsed += now.Sub(session.ResumedAt)
+	}
+	if session.Pomodoro {
+		elapsed = min(elapsed, PomodoroMinutes*time.Minute)
+	}
+	return elapsed
+}
+
+// PomodoroDone reports whether a Pomodoro session has used up its focus
+// block.
+func PomodoroDone(session model.StudySession, now time.Time) bool {
+	return session.Pomodoro && SessionElapsed(session, now) >= PomodoroMinutes*time.Minute
+}
+
+// ActiveStudySession returns the open (running or paused) session, if any.
+func ActiveStudySession(database *gorm.DB) (model.StudySession, bool, error) {
+	var session model.StudySession
+	if err := database.Where("ended_at IS NULL").Order("id desc").Limit(1).Find(&session).Error; err != nil {
+		return session, false, fmt.Errorf("load active study session: %w", err)
+	}
+	return session, session.ID != 0, nil
+}
+
+// StartStudySession starts timing taskID. Only one session can be open at a
+// time.
+func StartStudySession(database *gorm.DB, taskID uint, pomodoro bool, source string, now time.Time) (model.StudySession, error) {
+	var session model.StudySession
+	err := database.Transaction(func(tx *gorm.DB) error {
+		active, found, err := ActiveStudySession(tx)
+		if err != nil {
+			return err
+		}
+		if found {
+			return &ActiveSessionError{Session: active}
+		}
+		var task model.DailyTask
+		if err := tx.First(&task, taskID).Error; err != nil {
+			return fmt.Errorf("load task %d: %w", taskID, err)
+		}
+		session = model.StudySession{
+			DailyTaskID:    task.ID,
+			TaskTitle:      task.Title,
+			Category:       task.Category,
+			ACSRefs:        task.ACSRefs,
+			TemplateTaskID: task.TemplateTaskID,
+			PlannedMinutes: task.DurationMinutes,
+			Pomodoro:       pomodoro,
+			StartedAt:      now,
+			ResumedAt:      now,
+			Source:         source,
+		}
+		if err := tx.Create(&session).Error; err != nil {
+			return fmt.Errorf("save study session: %w", err)
+		}
+		return nil
+	})
+	return session, err
+}
+
+// PauseStudySession pauses the running session. Pausing a paused session
+// does nothing.
+func PauseStudySession(database *gorm.DB, now time.Time) (model.StudySession, error) {
+	return updateActiveSession(database, func(session *model.StudySession) {
+		if session.PausedAt != nil {
+			return
+		}
+		session.Seconds = int(SessionElapsed(*session, now).Seconds())
+		session.PausedAt = &now
+	})
+}
+
+// ResumeStudySession restarts the clock of a paused session.
+func ResumeStudySession(database *gorm.DB, now time.Time) (model.StudySession, error) {
+	return updateActiveSession(database, func(session *model.StudySession) {
+		if session.PausedAt == nil {
+			return
+		}
+		session.PausedAt = nil
+		session.ResumedAt = now
+	})
+}
+
+// StopStudySession ends the open session and stores the time worked.
+func StopStudySession(database *gorm.DB, now time.Time) (model.StudySession, error) {
+	return updateActiveSession(database, func(session *model.StudySession) {
+		session.Seconds = int(SessionElapsed(*session, now).Seconds())
+		session.PausedAt = nil
+		session.EndedAt = &now
+	})
+}
+
+func updateActiveSession(database *gorm.DB, update func(session *model.StudySession)) (model.StudySession, error) {
+	session, found, err := ActiveStudySession(database)
+	if err != nil {
+		return session, err
+	}
+	if !found {
+		return session, ErrNoActiveSession
+	}
+	update(&session)
+	if err := database.Save(&session).Error; err != nil {
+		return session, fmt.Errorf("save study session: %w", err)
+	}
+	return session, nil
+}
+
+// ListStudySessions returns the finished sessions, oldest first.
+func ListStudySessions(database *gorm.DB) ([]model.StudySession, error) {
+	var sessions []model.StudySession
+	if err := database.Where("ended_at IS NOT NULL").Order("started_at asc").Order("id asc").Find(&sessions).Error; err != nil {
+		return nil, fmt.Errorf("load study sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// StudyTimeRow is the timed effort for a category or ACS area. Planned sums
+// the planned minutes of the tasks that were timed, once per task, so it
+// compares like with like.
+type StudyTimeRow struct {
+	Name           string
+	Sessions       int
+	Tasks          int
+	ActualMinutes  float64
+	PlannedMinutes float64
+}
+
+// StudyTimeReport is the timed effort by category and by ACS area. A task
+// covering several areas splits its time evenly between them.
+type StudyTimeReport struct {
+	TotalMinutes float64
+	ByCategory   []StudyTimeRow
+	ByArea       []StudyTimeRow
+}
+
+// LoadStudyTimeReport summarises the finished study sessions.
+func LoadStudyTimeReport(database *gorm.DB) (StudyTimeReport, error) {
+	sessions, err := ListStudySessions(database)
+	if err != nil {
+		return StudyTimeReport{}, err
+	}
+	return SummarizeStudyTime(sessions), nil
+}
+
+// SummarizeStudyTime adds up finished sessions by category and ACS area.
+func SummarizeStudyTime(sessions []model.StudySession) StudyTimeReport {
+	var report StudyTimeReport
+	categories := map[string]*StudyTimeRow{}
+	areas := map[string]*StudyTimeRow{}
+	seen := map[uint]bool{}
+	row := func(rows map[string]*StudyTimeRow, name string) *StudyTimeRow {
+		if rows[name] == nil {
+			rows[name] = &StudyTimeRow{Name: name}
+		}
+		return rows[name]
+	}
+
+	for _, session := range sessions {
+		minutes := float64(session.Seconds) / 60
+		report.TotalMinutes += minutes
+		category := row(categories, session.Category)
+		category.Sessions++
+		category.ActualMinutes += minutes
+		firstForTask := !seen[session.DailyTaskID]
+		seen[session.DailyTaskID] = true
+		if firstForTask {
+			category.Tasks++
+			category.PlannedMinutes += float64(session.PlannedMinutes)
+		}
+
+		sessionAreas := acsAreas(session.ACSRefs)
+		for _, name := range sessionAreas {
+			area := row(areas, name)
+			area.Sessions++
+			area.ActualMinutes += minutes / float64(len(sessionAreas))
+			if firstForTask {
+				area.Tasks++
+				area.PlannedMinutes += float64(session.PlannedMinutes) / float64(len(sessionAreas))
+			}
+		}
+	}
+
+	for _, category := range categories {
+		report.ByCategory = append(report.ByCategory, *category)
+	}
+	sort.Slice(report.ByCategory, func(i, j int) bool {
+		if report.ByCategory[i].ActualMinutes != report.ByCategory[j].ActualMinutes {
+			return report.ByCategory[i].ActualMinutes > report.ByCategory[j].ActualMinutes
+		}
+		return report.ByCategory[i].Name < report.ByCategory[j].Name
+	})
+	position := map[string]int{}
+	for i, name := range motdAreaOrder() {
+		position[name] = i + 1
+	}
+	for _, area := range areas {
+		report.ByArea = append(report.ByArea, *area)
+	}
+	sort.Slice(report.ByArea, func(i, j int) bool {
+		a, b := position[report.ByArea[i].Name], position[report.ByArea[j].Name]
+		if a != b && a != 0 && b != 0 {
+			return a < b
+		}
+		if (a == 0) != (b == 0) {
+			return a != 0
+		}
+		return report.ByArea[i].Name < report.ByArea[j].Name
+	})
+	return report
+}
+
+// acsAreas returns the distinct ACS areas of comma separated references
+// such as "PA.I.A,PA.II.B": the second part of each code.
+func acsAreas(refs string) []string {
+	var areas []string
+	seen := map[string]bool{}
+	for _, ref := range strings.Split(refs, ",") {
+		parts := strings.Split(strings.TrimSpace(ref), ".")
+		if len(parts) < 2 || parts[1] == "" || seen[parts[1]] {
+			continue
+		}
+		seen[parts[1]] = true
+		areas = append(areas, parts[1])
+	}
+	return areas
+}
+
+// DurationEstimates are task durations learned from timed sessions: the
+// average minutes per timed task of each template task, and of each
+// category with at least minEstimateTasks timed tasks.
+type DurationEstimates struct {
+	ByTemplateTask map[string]int
+	ByCategory     map[string]int
+}
+
+// EstimateDurations averages the finished sessions per task, rounded to
+// five minutes.
+func EstimateDurations(sessions []model.StudySession) DurationEstimates {
+	type total struct {
+		minutes float64
+		tasks   map[uint]bool
+	}
+	add := func(totals map[string]*total, key string, session model.StudySession) {
+		if key == "" {
+			return
+		}
+		if totals[key] == nil {
+			totals[key] = &total{tasks: map[uint]bool{}}
+		}
+		totals[key].minutes += float64(session.Seconds) / 60
+		totals[key].tasks[session.DailyTaskID] = true
+	}
+	byTemplate, byCategory := map[string]*total{}, map[string]*total{}
+	for _, session := range sessions {
+		add(byTemplate, session.TemplateTaskID, session)
+		add(byCategory, session.Category, session)
+	}
+
+	average := func(t *total) int {
+		return max(5, int(math.Round(t.minutes/float64(len(t.tasks))/5))*5)
+	}
+	estimates := DurationEstimates{ByTemplateTask: map[string]int{}, ByCategory: map[string]int{}}
+	for key, t := range byTemplate {
+		estimates.ByTemplateTask[key] = average(t)
+	}
+	for key, t := range byCategory {
+		if len(t.tasks) >= minEstimateTasks {
+			estimates.ByCategory[key] = average(t)
+		}
+	}
+	return estimates
+}
+
+// Apply replaces the planned duration of tasks with the learned estimate,
+// preferring the template task's over the category's, and returns how many
+// tasks changed.
+func (e DurationEstimates) Apply(tasks []model.DailyTask) int {
+	changed := 0
+	for i := range tasks {
+		minutes, ok := e.ByTemplateTask[tasks[i].TemplateTaskID]
+		if !ok {
+			minutes, ok = e.ByCategory[tasks[i].Category]
+		}
+		if ok && minutes != tasks[i].DurationMinutes {
+			tasks[i].DurationMinutes = minutes
+			changed++
+		}
+	}
+	return changed
+}
+
+// ApplyActualDurations sets the duration of newly generated tasks from the
+// timed study sessions. Without sessions the template durations stay.
+func ApplyActualDurations(database *gorm.DB, tasks []model.DailyTask) error {
+	sessions, err := ListStudySessions(database)
+	if err != nil {
+		return err
+	}
+	EstimateDurations(sessions).Apply(tasks)
+	return nil
+}
+
+// NextStudyTask is the latest study plan's earliest incomplete task
+// scheduled for today or before, for starting a timer without picking a
+// task.
+func NextStudyTask(database *gorm.DB, now time.Time) (model.DailyTask, bool, error) {
+	tasks, err := LoadLatestPlanTasks(database)
+	if err != nil {
+		return model.DailyTask{}, false, fmt.Errorf("load next task: %w", err)
+	}
+	today := dateOnlyUTC(now)
+	for _, task := range tasks {
+		if !task.Completed && !dateOnlyUTC(task.Date).After(today) {
+			return task, true, nil
+		}
+	}
+	return model.DailyTask{}, false, nil
+}
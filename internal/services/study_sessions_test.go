@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupStudySessionsTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudySession{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return db
+}
+
+func TestStudySession_StartPauseResumeStop(t *testing.T) {
+	db := setupStudySessionsTestDB(t)
+	task := model.DailyTask{Category: "Theory", Title: "Airspace", ACSRefs: "PA.I.E", TemplateTaskID: "theory-airspace", DurationMinutes: 45}
+	db.Create(&task)
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	if _, err := StopStudySession(db, start); !errors.Is(err, ErrNoActiveSession) {
+		t.Fatalf("expected ErrNoActiveSession, got %v", err)
+	}
+	if _, err := StartStudySession(db, task.ID, false, "cli", start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	var active *ActiveSessionError
+	if _, err := StartStudySession(db, task.ID, false, "tui", start); !errors.As(err, &active) {
+		t.Fatalf("expected an ActiveSessionError, got %v", err)
+	}
+	if _, err := PauseStudySession(db, start.Add(20*time.Minute)); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if _, err := ResumeStudySession(db, start.Add(50*time.Minute)); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	session, err := StopStudySession(db, start.Add(60*time.Minute))
+	if err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	if session.Seconds != 30*60 || session.EndedAt == nil || session.Category != "Theory" || session.PlannedMinutes != 45 {
+		t.Fatalf("expected 30 minutes worked with the task copied, got %+v", session)
+	}
+	if _, found, _ := ActiveStudySession(db); found {
+		t.Fatal("expected no open session after stopping")
+	}
+}
+
+func TestStudySession_PomodoroIsCapped(t *testing.T) {
+	db := setupStudySessionsTestDB(t)
+	task := model.DailyTask{Category: "Chair Flying", Title: "Steep turns"}
+	db.Create(&task)
+	start := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	session, err := StartStudySession(db, task.ID, true, "tui", start)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if PomodoroDone(session, start.Add(24*time.Minute)) || !PomodoroDone(session, start.Add(25*time.Minute)) {
+		t.Fatal("expected the Pomodoro to finish after 25 minutes")
+	}
+	session, err = StopStudySession(db, start.Add(40*time.Minute))
+	if err != nil || session.Seconds != PomodoroMinutes*60 {
+		t.Fatalf("expected the Pomodoro capped at 25 minutes, got %+v (%v)", session, err)
+	}
+}
+
+func TestSummarizeStudyTimeAndEstimates(t *testing.T) {
+	sessions := []model.StudySession{
+		{DailyTaskID: 1, Category: "Theory", ACSRefs: "PA.I.A,PA.II.B", TemplateTaskID: "t1", PlannedMinutes: 30, Seconds: 40 * 60},
+		{DailyTaskID: 1, Category: "Theory", ACSRefs: "PA.I.A,PA.II.B", TemplateTaskID: "t1", PlannedMinutes: 30, Seconds: 20 * 60},
+		{DailyTaskID: 2, Category: "Theory", ACSRefs: "PA.I.C", TemplateTaskID: "t2", PlannedMinutes: 30, Seconds: 30 * 60},
+		{DailyTaskID: 3, Category: "Theory", TemplateTaskID: "t3", PlannedMinutes: 30, Seconds: 48 * 60},
+		{DailyTaskID: 4, Category: "CFI Flights", ACSRefs: "PA.IV.A", TemplateTaskID: "f1", PlannedMinutes: 90, Seconds: 100 * 60},
+	}
+
+	report := SummarizeStudyTime(sessions)
+	if report.TotalMinutes != 238 || len(report.ByCategory) != 2 {
+		t.Fatalf("unexpected totals: %+v", report)
+	}
+	theory := report.ByCategory[0]
+	if theory.Name != "Theory" || theory.Sessions != 4 || theory.Tasks != 3 || theory.ActualMinutes != 138 || theory.PlannedMinutes != 90 {
+		t.Fatalf("unexpected theory row: %+v", theory)
+	}
+	if len(report.ByArea) != 3 || report.ByArea[0].Name != "I" || report.ByArea[0].ActualMinutes != 60 || report.ByArea[1].Name != "II" || report.ByArea[2].Name != "IV" {
+		t.Fatalf("unexpected area rows: %+v", report.ByArea)
+	}
+
+	estimates := EstimateDurations(sessions)
+	if estimates.ByTemplateTask["t1"] != 60 || estimates.ByTemplateTask["t3"] != 50 || estimates.ByCategory["Theory"] != 45 {
+		t.Fatalf("unexpected estimates: %+v", estimates)
+	}
+	if _, ok := estimates.ByCategory["CFI Flights"]; ok {
+		t.Fatal("expected no category estimate from a single timed task")
+	}
+	tasks := []model.DailyTask{
+		{Category: "Theory", TemplateTaskID: "t1", DurationMinutes: 30},
+		{Category: "Theory", TemplateTaskID: "t9", DurationMinutes: 30},
+		{Category: "CFI Flights", TemplateTaskID: "f2", DurationMinutes: 90},
+	}
+	if changed := estimates.Apply(tasks); changed != 2 || tasks[0].DurationMinutes != 60 || tasks[1].DurationMinutes != 45 || tasks[2].DurationMinutes != 90 {
+		t.Fatalf("unexpected applied durations (%d changed): %+v", changed, tasks)
+	}
+}
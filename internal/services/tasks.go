@@ -0,0 +1,215 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// Task priorities. Generated tasks are normal priority.
+const (
+	TaskPriorityHigh   = "high"
+	TaskPriorityNormal = "normal"
+	TaskPriorityLow    = "low"
+)
+
+// TaskPriorities lists the task priorities, highest first.
+var TaskPriorities = []string{TaskPriorityHigh, TaskPriorityNormal, TaskPriorityLow}
+
+// ErrNoStudyPlan is returned when adding a task before a checkride date is
+// set.
+var ErrNoStudyPlan = errors.New("no study plan yet; set a checkride date first")
+
+// ParseTaskPriority accepts a priority or its first letter. Empty is
+// normal.
+func ParseTaskPriority(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return TaskPriorityNormal, nil
+	}
+	for _, priority := range TaskPriorities {
+		if value == priority || value == priority[:1] {
+			return priority, nil
+		}
+	}
+	return "", fmt.Errorf("unknown priority %q (want high, normal or low)", value)
+}
+
+// TaskInput holds the editable fields of a study task.
+type TaskInput struct {
+	Date            time.Time
+	Category        string
+	Title           string
+	Description     string
+	Notes           string
+	Priority        string
+	DurationMinutes int
+}
+
+// TaskInputFrom returns the editable fields of task, for prefilling forms.
+func TaskInputFrom(task model.DailyTask) TaskInput {
+	return TaskInput{
+		Date:            task.Date,
+		Category:        task.Category,
+		Title:           task.Title,
+		Description:     task.Description,
+		Notes:           task.Notes,
+		Priority:        task.Priority,
+		DurationMinutes: task.DurationMinutes,
+	}
+}
+
+func (in TaskInput) normalize() (TaskInput, error) {
+	in.Category = strings.TrimSpace(in.Category)
+	in.Title = strings.TrimSpace(in.Title)
+	in.Description = strings.TrimSpace(in.Description)
+	in.Notes = strings.TrimSpace(in.Notes)
+	switch {
+	case in.Title == "":
+		return in, errors.New("task title is required")
+	case in.Category == "":
+		return in, errors.New("task category is required")
+	case in.Date.IsZero():
+		return in, errors.New("task date is required")
+	case in.DurationMinutes < 0:
+		return in, errors.New("task duration cannot be negative")
+	}
+	priority, err := ParseTaskPriority(in.Priority)
+	if err != nil {
+		return in, err
+	}
+	in.Priority = priority
+	in.Date = dateOnlyUTC(in.Date)
+	return in, nil
+}
+
+// CreateTask adds a user-created task, such as a lesson the CFI assigned,
+// to the current study plan and records a task.created event.
+func CreateTask(database *gorm.DB, input TaskInput, source string) (model.DailyTask, error) {
+	input, err := input.normalize()
+	if err != nil {
+		return model.DailyTask{}, err
+	}
+	var plan model.StudyPlan
+	if err := database.Order("id desc").Limit(1).Find(&plan).Error; err != nil {
+		return model.DailyTask{}, fmt.Errorf("load study plan: %w", err)
+	}
+	if plan.ID == 0 {
+		return model.DailyTask{}, ErrNoStudyPlan
+	}
+	task := model.DailyTask{
+		StudyPlanID:     plan.ID,
+		Date:            input.Date,
+		Category:        input.Category,
+		Title:           input.Title,
+		Description:     input.Description,
+		Notes:           input.Notes,
+		Priority:        input.Priority,
+		DurationMinutes: input.DurationMinutes,
+		UserCreated:     true,
+	}
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&task).Error; err != nil {
+			return fmt.Errorf("add task: %w", err)
+		}
+		return RecordEvent(tx, EventTaskCreated, newTaskEventPayload(task, source))
+	})
+	if err != nil {
+		return model.DailyTask{}, err
+	}
+	return task, nil
+}
+
+// UpdateTask saves the edited fields of a task. A new date moves the task
+// like RescheduleTask does.
+func UpdateTask(database *gorm.DB, id uint, input TaskInput, source string) (model.DailyTask, error) {
+	input, err := input.normalize()
+	if err != nil {
+		return model.DailyTask{}, err
+	}
+	var task model.DailyTask
+	err = database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, id).Error; err != nil {
+			return fmt.Errorf("task %d not found", id)
+		}
+		moved := !dateOnlyUTC(task.Date).Equal(input.Date)
+		if moved {
+			if err := moveTask(tx, task, input.Date, "edited", source); err != nil {
+				return err
+			}
+			// moveTask already bumped the sequence for this edit.
+			if err := tx.First(&task, id).Error; err != nil {
+				return err
+			}
+		}
+		updates := map[string]interface{}{
+			"category":         input.Category,
+			"title":            input.Title,
+			"description":      input.Description,
+			"notes":            input.Notes,
+			"priority":         input.Priority,
+			"duration_minutes": input.DurationMinutes,
+		}
+		if !moved {
+			updates["sequence"] = task.Sequence + 1
+		}
+		if err := tx.Model(&task).Updates(updates).Error; err != nil {
+			return fmt.Errorf("update task %q: %w", task.Title, err)
+		}
+		return tx.First(&task, id).Error
+	})
+	return task, err
+}
+
+// RescheduleTask moves a task to another day and records a task.rescheduled
+// event. Moving it to the day it is on does nothing.
+func RescheduleTask(database *gorm.DB, id uint, date time.Time, source string) (model.DailyTask, error) {
+	var task model.DailyTask
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, id).Error; err != nil {
+			return fmt.Errorf("task %d not found", id)
+		}
+		if dateOnlyUTC(task.Date).Equal(dateOnlyUTC(date)) {
+			return nil
+		}
+		if err := moveTask(tx, task, dateOnlyUTC(date), "rescheduled", source); err != nil {
+			return err
+		}
+		return tx.First(&task, id).Error
+	})
+	return task, err
+}
+
+// DeleteTask removes a task and records a task.deleted event. Its
+// completion history and timed sessions stay, since they copy what they
+// need from the task.
+func DeleteTask(database *gorm.DB, id uint, source string) (model.DailyTask, error) {
+	var task model.DailyTask
+	err := database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&task, id).Error; err != nil {
+			return fmt.Errorf("task %d not found", id)
+		}
+		if err := tx.Delete(&task).Error; err != nil {
+			return fmt.Errorf("delete task %q: %w", task.Title, err)
+		}
+		return RecordEvent(tx, EventTaskDeleted, newTaskEventPayload(task, source))
+	})
+	if err != nil {
+		return model.DailyTask{}, err
+	}
+	return task, nil
+}
+
+// DeleteGeneratedTasks clears a plan's generated tasks before it is
+// regenerated. User-created tasks are kept.
+func DeleteGeneratedTasks(database *gorm.DB, planID uint) error {
+	if err := database.Where("study_plan_id = ? AND user_created = ?", planID, false).Delete(&model.DailyTask{}).Error; err != nil {
+		return fmt.Errorf("clear generated tasks: %w", err)
+	}
+	return nil
+}
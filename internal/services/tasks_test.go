@@ -0,0 +1,118 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func setupTasksTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	return db
+}
+
+func TestCreateTaskSurvivesRegeneration(t *testing.T) {
+	db := setupTasksTestDB(t)
+	lesson := TaskInput{Date: time.Date(2026, 6, 3, 15, 0, 0, 0, time.Local), Category: "CFI Flights", Title: "Steep turns with CFI", Priority: "h"}
+	if _, err := CreateTask(db, lesson, "tui"); !errors.Is(err, ErrNoStudyPlan) {
+		t.Fatalf("expected ErrNoStudyPlan without a plan, got %v", err)
+	}
+
+	plan := model.StudyPlan{CheckrideDate: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Generated"})
+	task, err := CreateTask(db, lesson, "tui")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if !task.UserCreated || task.Priority != TaskPriorityHigh || task.StudyPlanID != plan.ID || !task.Date.Equal(time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected task %+v", task)
+	}
+	if _, err := CreateTask(db, TaskInput{Date: lesson.Date, Category: "Theory"}, "tui"); err == nil {
+		t.Fatal("expected a missing title to be rejected")
+	}
+	var created []model.OutboxEvent
+	db.Where("event_type = ?", EventTaskCreated).Find(&created)
+	if len(created) != 1 || !strings.Contains(created[0].PayloadJSON, `"title":"Steep turns with CFI"`) {
+		t.Fatalf("expected one task.created event, got %+v", created)
+	}
+
+	if err := DeleteGeneratedTasks(db, plan.ID); err != nil {
+		t.Fatalf("delete generated: %v", err)
+	}
+	var remaining []model.DailyTask
+	db.Find(&remaining)
+	if len(remaining) != 1 || remaining[0].ID != task.ID {
+		t.Fatalf("expected only the user-created task to remain, got %+v", remaining)
+	}
+}
+
+func TestUpdateTaskReschedulesAndKeepsOriginalDate(t *testing.T) {
+	db := setupTasksTestDB(t)
+	original := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	task := model.DailyTask{Date: original, Category: "Theory", Title: "Airspace", DurationMinutes: 45}
+	db.Create(&task)
+
+	input := TaskInputFrom(task)
+	input.Date = original.AddDate(0, 0, 2)
+	input.Notes = "CFI wants class B transitions covered"
+	input.Priority = "low"
+	updated, err := UpdateTask(db, task.ID, input, "web")
+	if err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !updated.Date.Equal(input.Date) || updated.RescheduledFrom == nil || !updated.RescheduledFrom.Equal(original) {
+		t.Fatalf("expected a move from Jun 1 to Jun 3, got %+v", updated)
+	}
+	if updated.Notes != input.Notes || updated.Priority != TaskPriorityLow || updated.Sequence != 1 {
+		t.Fatalf("expected notes, priority and one sequence bump, got %+v", updated)
+	}
+
+	moved, err := RescheduleTask(db, task.ID, original.AddDate(0, 0, 5), "tui")
+	if err != nil {
+		t.Fatalf("reschedule: %v", err)
+	}
+	if !moved.RescheduledFrom.Equal(original) || !moved.Date.Equal(original.AddDate(0, 0, 5)) {
+		t.Fatalf("expected the first planned day to stay, got %+v", moved)
+	}
+	input = TaskInputFrom(moved)
+	input.Title = "Airspace and weather minimums"
+	edited, err := UpdateTask(db, task.ID, input, "web")
+	if err != nil {
+		t.Fatalf("edit: %v", err)
+	}
+	if edited.Title != input.Title || edited.Sequence != 3 {
+		t.Fatalf("expected the title edit to bump the sequence once, got %+v", edited)
+	}
+	var events []model.OutboxEvent
+	db.Where("event_type = ?", EventTaskRescheduled).Order("id asc").Find(&events)
+	if len(events) != 2 || !strings.Contains(events[0].PayloadJSON, `"from_date":"2026-06-01","to_date":"2026-06-03"`) {
+		t.Fatalf("expected two task.rescheduled events, got %+v", events)
+	}
+
+	if _, err := DeleteTask(db, task.ID, "web"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := DeleteTask(db, task.ID, "web"); err == nil {
+		t.Fatal("expected deleting a missing task to fail")
+	}
+	var deleted []model.OutboxEvent
+	db.Where("event_type = ?", EventTaskDeleted).Find(&deleted)
+	if len(deleted) != 1 || !strings.Contains(deleted[0].PayloadJSON, `"title":"Airspace and weather minimums"`) {
+		t.Fatalf("expected one task.deleted event, got %+v", deleted)
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// DashboardWarnings collects the warnings shown at the top of the TUI and web
+// dashboards and in automation status. Sources that cannot be
+// loaded are skipped.
+func DashboardWarnings(database *gorm.DB, now time.Time) []string {
+	var checkride time.Time
+	var plan model.StudyPlan
+	if err := database.Limit(1).Order("id desc").Find(&plan).Error; err == nil {
+		checkride = plan.CheckrideDate
+	}
+
+	var warnings []string
+	if documents, err := ListPilotDocuments(database); err == nil {
+		warnings = append(warnings, DocumentWarnings(documents, checkride, now)...)
+	}
+	if endorsements, err := ListEndorsements(database); err == nil {
+		warnings = append(warnings, EndorsementWarnings(endorsements, now)...)
+	}
+	if reports, err := LoadAircraftReports(database, now); err == nil {
+		warnings = append(warnings, AircraftWarnings(reports)...)
+	}
+	return warnings
+}
@@ -0,0 +1,748 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+// FlightCategory is the FAA flight category of an observation or forecast.
+type FlightCategory string
+
+const (
+	FlightCategoryVFR     FlightCategory = "VFR"
+	FlightCategoryMVFR    FlightCategory = "MVFR"
+	FlightCategoryIFR     FlightCategory = "IFR"
+	FlightCategoryLIFR    FlightCategory = "LIFR"
+	FlightCategoryUnknown FlightCategory = ""
+
+	defaultAviationWeatherURL = "https://aviationweather.gov/api/data"
+	defaultWeatherTimeout     = 10 * time.Second
+	defaultWeatherCacheTTL    = 10 * time.Minute
+
+	schoolAirportConfigKey = "school_airport"
+)
+
+// Flight tasks are checked against the forecast between these local hours.
+const (
+	weatherFlyingWindowStart = 7
+	weatherFlyingWindowEnd   = 19
+)
+
+// ErrNoWeatherData is returned when a station has no current METAR or TAF.
+var ErrNoWeatherData = errors.New("no weather data")
+
+var flightCategoryRank = map[FlightCategory]int{
+	FlightCategoryUnknown: 0,
+	FlightCategoryVFR:     1,
+	FlightCategoryMVFR:    2,
+	FlightCategoryIFR:     3,
+	FlightCategoryLIFR:    4,
+}
+
+// WorseThan reports whether c is a lower flight category than other.
+func (c FlightCategory) WorseThan(other FlightCategory) bool {
+	return flightCategoryRank[c] > flightCategoryRank[other]
+}
+
+// BelowVFR reports whether c is IFR or LIFR, where student training flights
+// are off.
+func (c FlightCategory) BelowVFR() bool {
+	return c == FlightCategoryIFR || c == FlightCategoryLIFR
+}
+
+// CloudLayer is one reported cloud layer, base in feet AGL.
+type CloudLayer struct {
+	Cover  string
+	BaseFt int
+}
+
+// Wind is a reported or forecast wind. DirDeg is true degrees, or -1 when
+// the direction is variable.
+type Wind struct {
+	DirDeg  int
+	SpeedKt int
+	GustKt  int
+}
+
+// CrosswindKt is the crosswind component on a runway heading, using the gust
+// when there is one. A variable wind counts as all crosswind.
+func (w Wind) CrosswindKt(runwayHeading int) int {
+	speed := w.SpeedKt
+	if w.GustKt > speed {
+		speed = w.GustKt
+	}
+	if w.DirDeg < 0 {
+		return speed
+	}
+	angle := float64(w.DirDeg-runwayHeading) * math.Pi / 180
+	return int(math.Round(math.Abs(math.Sin(angle)) * float64(speed)))
+}
+
+// WeatherConditions are the elements that decide the flight category, plus
+// the wind. VisibilitySM is negative and Wind nil when not reported.
+type WeatherConditions struct {
+	VisibilitySM float64
+	Clouds       []CloudLayer
+	Wind         *Wind
+}
+
+// CeilingFt returns the lowest broken, overcast or obscured layer.
+func (c WeatherConditions) CeilingFt() (int, bool) {
+	ceiling, found := 0, false
+	for _, layer := range c.Clouds {
+		switch layer.Cover {
+		case "BKN", "OVC", "OVX", "VV":
+			if !found || layer.BaseFt < ceiling {
+				ceiling, found = layer.BaseFt, true
+			}
+		}
+	}
+	return ceiling, found
+}
+
+// Category applies the FAA flight category limits: LIFR below 500 ft or 1 SM,
+// IFR below 1,000 ft or 3 SM, MVFR at or below 3,000 ft or 5 SM.
+func (c WeatherConditions) Category() FlightCategory {
+	ceiling, hasCeiling := c.CeilingFt()
+	if c.VisibilitySM < 0 && !hasCeiling && len(c.Clouds) == 0 {
+		return FlightCategoryUnknown
+	}
+	visibility := c.VisibilitySM
+	if visibility < 0 {
+		visibility = 10
+	}
+	if !hasCeiling {
+		ceiling = 100000
+	}
+	switch {
+	case ceiling < 500 || visibility < 1:
+		return FlightCategoryLIFR
+	case ceiling < 1000 || visibility < 3:
+		return FlightCategoryIFR
+	case ceiling <= 3000 || visibility <= 5:
+		return FlightCategoryMVFR
+	}
+	return FlightCategoryVFR
+}
+
+// METAR is a decoded routine weather observation.
+type METAR struct {
+	Station  string
+	Observed time.Time
+	Raw      string
+	Category FlightCategory
+	WeatherConditions
+}
+
+// TAFPeriod is one forecast group. Change is FM, BECMG, TEMPO or PROB, or
+// empty for the initial group.
+type TAFPeriod struct {
+	From     time.Time
+	To       time.Time
+	Change   string
+	Category FlightCategory
+	WeatherConditions
+}
+
+// TAF is a decoded terminal aerodrome forecast.
+type TAF struct {
+	Station   string
+	Issued    time.Time
+	ValidFrom time.Time
+	ValidTo   time.Time
+	Raw       string
+	Periods   []TAFPeriod
+}
+
+// WorstCategoryBetween returns the lowest category forecast in any group
+// (TEMPO and PROB included) overlapping [from, to), and false when the TAF
+// does not cover that time.
+func (t TAF) WorstCategoryBetween(from, to time.Time) (FlightCategory, bool) {
+	worst, covered := FlightCategoryUnknown, false
+	for _, period := range t.Periods {
+		if !period.From.Before(to) || !period.To.After(from) {
+			continue
+		}
+		covered = true
+		if period.Category.WorseThan(worst) {
+			worst = period.Category
+		}
+	}
+	return worst, covered
+}
+
+// WeatherProvider fetches current observations and forecasts by ICAO station.
+type WeatherProvider interface {
+	METAR(ctx context.Context, station string) (METAR, error)
+	TAF(ctx context.Context, station string) (TAF, error)
+}
+
+// DefaultWeatherProvider reads fixtures from OPENPPL_WEATHER_DIR when set
+// (for offline use and tests) and otherwise queries aviationweather.gov,
+// caching answers for 10 minutes.
+func DefaultWeatherProvider() WeatherProvider {
+	if dir := strings.TrimSpace(os.Getenv("OPENPPL_WEATHER_DIR")); dir != "" {
+		return FileWeatherProvider{Dir: dir}
+	}
+	return NewCachedWeatherProvider(NewAviationWeatherProvider(), defaultWeatherCacheTTL)
+}
+
+// AviationWeatherProvider reads METARs and TAFs from the aviationweather.gov
+// data API.
+type AviationWeatherProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewAviationWeatherProvider returns a provider for the public data API.
+func NewAviationWeatherProvider() *AviationWeatherProvider {
+	return &AviationWeatherProvider{
+		BaseURL: defaultAviationWeatherURL,
+		Client:  &http.Client{Timeout: defaultWeatherTimeout},
+	}
+}
+
+func (p *AviationWeatherProvider) METAR(ctx context.Context, station string) (METAR, error) {
+	body, err := p.get(ctx, "metar", station)
+	if err != nil {
+		return METAR{}, err
+	}
+	return decodeMETAR(station, body)
+}
+
+func (p *AviationWeatherProvider) TAF(ctx context.Context, station string) (TAF, error) {
+	body, err := p.get(ctx, "taf", station)
+	if err != nil {
+		return TAF{}, err
+	}
+	return decodeTAF(station, body)
+}
+
+func (p *AviationWeatherProvider) get(ctx context.Context, product string, station string) ([]byte, error) {
+	station = NormalizeStation(station)
+	if station == "" {
+		return nil, errors.New("weather: station is required")
+	}
+	endpoint := strings.TrimRight(p.BaseURL, "/") + "/" + product + "?" + url.Values{"ids": {station}, "format": {"json"}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("weather: build request: %w", err)
+	}
+	req.Header.Set("User-Agent", "openppl")
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultWeatherTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("weather: fetch %s for %s: %w", product, station, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, fmt.Errorf("weather: %s for %s: %w", product, station, ErrNoWeatherData)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("weather: fetch %s for %s: unexpected status %d", product, station, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("weather: read %s for %s: %w", product, station, err)
+	}
+	return body, nil
+}
+
+// FileWeatherProvider reads <STATION>-metar.json and <STATION>-taf.json from
+// Dir, in the same JSON format the aviationweather.gov API returns.
+type FileWeatherProvider struct {
+	Dir string
+}
+
+func (p FileWeatherProvider) METAR(_ context.Context, station string) (METAR, error) {
+	body, err := p.read(station, "metar")
+	if err != nil {
+		return METAR{}, err
+	}
+	return decodeMETAR(station, body)
+}
+
+func (p FileWeatherProvider) TAF(_ context.Context, station string) (TAF, error) {
+	body, err := p.read(station, "taf")
+	if err != nil {
+		return TAF{}, err
+	}
+	return decodeTAF(station, body)
+}
+
+func (p FileWeatherProvider) read(station string, product string) ([]byte, error) {
+	station = NormalizeStation(station)
+	body, err := os.ReadFile(filepath.Join(p.Dir, station+"-"+product+".json"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("weather: %s for %s: %w", product, station, ErrNoWeatherData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("weather: read %s for %s: %w", product, station, err)
+	}
+	return body, nil
+}
+
+// CachedWeatherProvider remembers answers from another provider for a while,
+// so redrawing a dashboard does not refetch.
+type CachedWeatherProvider struct {
+	provider WeatherProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	metars map[string]cachedMETAR
+	tafs   map[string]cachedTAF
+}
+
+type cachedMETAR struct {
+	at    time.Time
+	metar METAR
+	err   error
+}
+
+type cachedTAF struct {
+	at  time.Time
+	taf TAF
+	err error
+}
+
+// NewCachedWeatherProvider wraps provider with a ttl cache per station.
+func NewCachedWeatherProvider(provider WeatherProvider, ttl time.Duration) *CachedWeatherProvider {
+	return &CachedWeatherProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+		metars:   map[string]cachedMETAR{},
+		tafs:     map[string]cachedTAF{},
+	}
+}
+
+func (p *CachedWeatherProvider) METAR(ctx context.Context, station string) (METAR, error) {
+	station = NormalizeStation(station)
+	p.mu.Lock()
+	entry, ok := p.metars[station]
+	p.mu.Unlock()
+	if ok && p.now().Sub(entry.at) < p.ttl {
+		return entry.metar, entry.err
+	}
+	metar, err := p.provider.METAR(ctx, station)
+	if ctx.Err() == nil {
+		p.mu.Lock()
+		p.metars[station] = cachedMETAR{at: p.now(), metar: metar, err: err}
+		p.mu.Unlock()
+	}
+	return metar, err
+}
+
+func (p *CachedWeatherProvider) TAF(ctx context.Context, station string) (TAF, error) {
+	station = NormalizeStation(station)
+	p.mu.Lock()
+	entry, ok := p.tafs[station]
+	p.mu.Unlock()
+	if ok && p.now().Sub(entry.at) < p.ttl {
+		return entry.taf, entry.err
+	}
+	taf, err := p.provider.TAF(ctx, station)
+	if ctx.Err() == nil {
+		p.mu.Lock()
+		p.tafs[station] = cachedTAF{at: p.now(), taf: taf, err: err}
+		p.mu.Unlock()
+	}
+	return taf, err
+}
+
+// NormalizeStation upper-cases and trims an ICAO station identifier.
+func NormalizeStation(station string) string {
+	return strings.ToUpper(strings.TrimSpace(station))
+}
+
+// aviationweather.gov JSON. Times come as unix seconds or ISO strings and
+// visibility as a number or a string such as "10+".
+type awMETAR struct {
+	ICAO       string       `json:"icaoId"`
+	ObsTime    awTime       `json:"obsTime"`
+	ReportTime awTime       `json:"reportTime"`
+	Raw        string       `json:"rawOb"`
+	Visibility awVisibility `json:"visib"`
+	Clouds     []awCloud    `json:"clouds"`
+	VertVis    *int         `json:"vertVis"`
+	FltCat     string       `json:"fltCat"`
+	awWind
+}
+
+type awTAF struct {
+	ICAO      string          `json:"icaoId"`
+	IssueTime awTime          `json:"issueTime"`
+	ValidFrom awTime          `json:"validTimeFrom"`
+	ValidTo   awTime          `json:"validTimeTo"`
+	Raw       string          `json:"rawTAF"`
+	Forecasts []awTAFForecast `json:"fcsts"`
+}
+
+type awTAFForecast struct {
+	TimeFrom   awTime       `json:"timeFrom"`
+	TimeTo     awTime       `json:"timeTo"`
+	Change     string       `json:"fcstChange"`
+	Visibility awVisibility `json:"visib"`
+	Clouds     []awCloud    `json:"clouds"`
+	VertVis    *int         `json:"vertVis"`
+	awWind
+}
+
+type awWind struct {
+	WindDir   *awWindDir `json:"wdir"`
+	WindSpeed *int       `json:"wspd"`
+	WindGust  *int       `json:"wgst"`
+}
+
+func (w awWind) wind() *Wind {
+	if w.WindSpeed == nil {
+		return nil
+	}
+	wind := &Wind{DirDeg: -1, SpeedKt: *w.WindSpeed}
+	if w.WindDir != nil {
+		wind.DirDeg = int(*w.WindDir)
+	}
+	if w.WindGust != nil {
+		wind.GustKt = *w.WindGust
+	}
+	return wind
+}
+
+// awWindDir is degrees, or -1 for "VRB".
+type awWindDir int
+
+func (d *awWindDir) UnmarshalJSON(data []byte) error {
+	var degrees int
+	if err := json.Unmarshal(data, &degrees); err == nil {
+		*d = awWindDir(degrees)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid wind direction %s", data)
+	}
+	if degrees, err := strconv.Atoi(strings.TrimSpace(text)); err == nil {
+		*d = awWindDir(degrees)
+		return nil
+	}
+	*d = -1
+	return nil
+}
+
+type awCloud struct {
+	Cover string `json:"cover"`
+	Base  *int   `json:"base"`
+}
+
+type awTime struct{ time.Time }
+
+func (t *awTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var seconds int64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		t.Time = time.Unix(seconds, 0).UTC()
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid time %s", data)
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05.000Z", "2006-01-02 15:04:05"} {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time %q", text)
+}
+
+// awVisibility is statute miles, or -1 when not reported.
+type awVisibility float64
+
+func (v *awVisibility) UnmarshalJSON(data []byte) error {
+	*v = -1
+	if string(data) == "null" {
+		return nil
+	}
+	var miles float64
+	if err := json.Unmarshal(data, &miles); err == nil {
+		*v = awVisibility(miles)
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid visibility %s", data)
+	}
+	parsed, err := parseVisibility(text)
+	if err != nil {
+		return err
+	}
+	*v = awVisibility(parsed)
+	return nil
+}
+
+// parseVisibility reads "10+", "P6SM", "1 1/2" or "3/4SM".
+func parseVisibility(text string) (float64, error) {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(text), "P"), "SM"))
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSuffix(text, "+"), "M"))
+	total := 0.0
+	for _, part := range strings.Fields(text) {
+		if numerator, denominator, ok := strings.Cut(part, "/"); ok {
+			n, errN := strconv.ParseFloat(numerator, 64)
+			d, errD := strconv.ParseFloat(denominator, 64)
+			if errN != nil || errD != nil || d == 0 {
+				return 0, fmt.Errorf("invalid visibility %q", text)
+			}
+			total += n / d
+			continue
+		}
+		whole, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid visibility %q", text)
+		}
+		total += whole
+	}
+	if text == "" {
+		return -1, nil
+	}
+	return total, nil
+}
+
+func awLayers(clouds []awCloud, vertVis *int) []CloudLayer {
+	layers := make([]CloudLayer, 0, len(clouds)+1)
+	for _, cloud := range clouds {
+		layer := CloudLayer{Cover: strings.ToUpper(cloud.Cover)}
+		if cloud.Base != nil {
+			layer.BaseFt = *cloud.Base
+		}
+		layers = append(layers, layer)
+	}
+	if vertVis != nil {
+		layers = append(layers, CloudLayer{Cover: "VV", BaseFt: *vertVis})
+	}
+	return layers
+}
+
+func decodeMETAR(station string, body []byte) (METAR, error) {
+	var reports []awMETAR
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return METAR{}, fmt.Errorf("weather: decode metar for %s: %w", NormalizeStation(station), err)
+	}
+	if len(reports) == 0 {
+		return METAR{}, fmt.Errorf("weather: metar for %s: %w", NormalizeStation(station), ErrNoWeatherData)
+	}
+	report := reports[0]
+	metar := METAR{
+		Station:  report.ICAO,
+		Observed: report.ObsTime.Time,
+		Raw:      report.Raw,
+		WeatherConditions: WeatherConditions{
+			VisibilitySM: float64(report.Visibility),
+			Clouds:       awLayers(report.Clouds, report.VertVis),
+			Wind:         report.wind(),
+		},
+	}
+	if metar.Observed.IsZero() {
+		metar.Observed = report.ReportTime.Time
+	}
+	metar.Category = FlightCategory(strings.ToUpper(report.FltCat))
+	if _, ok := flightCategoryRank[metar.Category]; !ok || metar.Category == FlightCategoryUnknown {
+		metar.Category = metar.WeatherConditions.Category()
+	}
+	return metar, nil
+}
+
+// decodeTAF decodes the first TAF. TEMPO, PROB and BECMG groups only list
+// what changes, so missing elements are carried over from the prevailing
+// group before the category is computed.
+func decodeTAF(station string, body []byte) (TAF, error) {
+	var reports []awTAF
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return TAF{}, fmt.Errorf("weather: decode taf for %s: %w", NormalizeStation(station), err)
+	}
+	if len(reports) == 0 {
+		return TAF{}, fmt.Errorf("weather: taf for %s: %w", NormalizeStation(station), ErrNoWeatherData)
+	}
+	report := reports[0]
+	taf := TAF{
+		Station:   report.ICAO,
+		Issued:    report.IssueTime.Time,
+		ValidFrom: report.ValidFrom.Time,
+		ValidTo:   report.ValidTo.Time,
+		Raw:       report.Raw,
+	}
+	prevailing := WeatherConditions{VisibilitySM: -1}
+	for _, forecast := range report.Forecasts {
+		conditions := WeatherConditions{
+			VisibilitySM: float64(forecast.Visibility),
+			Clouds:       awLayers(forecast.Clouds, forecast.VertVis),
+			Wind:         forecast.wind(),
+		}
+		change := strings.ToUpper(strings.TrimSpace(forecast.Change))
+		if change != "" && change != "FM" {
+			if conditions.VisibilitySM < 0 {
+				conditions.VisibilitySM = prevailing.VisibilitySM
+			}
+			if len(conditions.Clouds) == 0 {
+				conditions.Clouds = prevailing.Clouds
+			}
+			if conditions.Wind == nil {
+				conditions.Wind = prevailing.Wind
+			}
+		}
+		if change != "TEMPO" && !strings.HasPrefix(change, "PROB") {
+			prevailing = conditions
+		}
+		taf.Periods = append(taf.Periods, TAFPeriod{
+			From:              forecast.TimeFrom.Time,
+			To:                forecast.TimeTo.Time,
+			Change:            change,
+			Category:          conditions.Category(),
+			WeatherConditions: conditions,
+		})
+	}
+	return taf, nil
+}
+
+// SchoolAirport returns the school airport chosen during onboarding, or ""
+// when none is set.
+func SchoolAirport(database *gorm.DB) (string, error) {
+	var cfg model.AppConfig
+	if err := database.Where("key = ?", schoolAirportConfigKey).Limit(1).Find(&cfg).Error; err != nil {
+		return "", fmt.Errorf("load school airport: %w", err)
+	}
+	return NormalizeStation(cfg.Value), nil
+}
+
+// IsFlightTask reports whether a task is flown with a CFI (the "CFI Flights"
+// category of the default plan template) and so depends on the weather.
+func IsFlightTask(task model.DailyTask) bool {
+	return strings.Contains(strings.ToLower(task.Category), "flight")
+}
+
+// WeatherTaskFlag is a day with flight tasks that the TAF forecasts below VFR.
+type WeatherTaskFlag struct {
+	Date     time.Time
+	Category FlightCategory
+	Tasks    []model.DailyTask
+}
+
+// FlagFlightTasks returns the days whose pending flight tasks fall on a
+// forecast-IFR or LIFR flying window (07:00-19:00 local).
+func FlagFlightTasks(tasks []model.DailyTask, taf TAF, loc *time.Location) []WeatherTaskFlag {
+	var flags []WeatherTaskFlag
+	byDay := map[string]int{}
+	for _, task := range tasks {
+		if task.Completed || !IsFlightTask(task) {
+			continue
+		}
+		day := time.Date(task.Date.Year(), task.Date.Month(), task.Date.Day(), 0, 0, 0, 0, loc)
+		category, covered := taf.WorstCategoryBetween(
+			day.Add(weatherFlyingWindowStart*time.Hour),
+			day.Add(weatherFlyingWindowEnd*time.Hour),
+		)
+		if !covered || !category.BelowVFR() {
+			continue
+		}
+		key := day.Format("2006-01-02")
+		if i, ok := byDay[key]; ok {
+			flags[i].Tasks = append(flags[i].Tasks, task)
+			continue
+		}
+		byDay[key] = len(flags)
+		flags = append(flags, WeatherTaskFlag{Date: day, Category: category, Tasks: []model.DailyTask{task}})
+	}
+	return flags
+}
+
+// WeatherBrief is the school airport's current weather and the flight tasks
+// its forecast puts at risk. METAR and TAF are nil when unavailable, with the
+// reason in the matching error.
+type WeatherBrief struct {
+	Station  string
+	METAR    *METAR
+	TAF      *TAF
+	Flags    []WeatherTaskFlag
+	METARErr error
+	TAFErr   error
+}
+
+// LoadWeatherBrief fetches weather for the school airport. It returns an
+// empty brief when no airport is configured.
+func LoadWeatherBrief(ctx context.Context, database *gorm.DB, provider WeatherProvider, now time.Time) (WeatherBrief, error) {
+	station, err := SchoolAirport(database)
+	if err != nil || station == "" {
+		return WeatherBrief{}, err
+	}
+	brief := WeatherBrief{Station: station}
+	if metar, err := provider.METAR(ctx, station); err != nil {
+		brief.METARErr = err
+	} else {
+		brief.METAR = &metar
+	}
+	taf, err := provider.TAF(ctx, station)
+	if err != nil {
+		brief.TAFErr = err
+		return brief, nil
+	}
+	brief.TAF = &taf
+
+	// Only days the TAF covers (about a day ahead) can be flagged.
+	var tasks []model.DailyTask
+	if err := database.Where("completed = ?", false).Order("date asc, id asc").Find(&tasks).Error; err != nil {
+		return brief, fmt.Errorf("load flight tasks: %w", err)
+	}
+	brief.Flags = FlagFlightTasks(tasks, taf, now.Location())
+	return brief, nil
+}
+
+// Summary is a one-line current conditions report, e.g.
+// "KFXE VFR (observed 14:53)".
+func (b WeatherBrief) Summary() string {
+	if b.Station == "" {
+		return ""
+	}
+	if b.METAR == nil {
+		return b.Station + " weather unavailable"
+	}
+	category := string(b.METAR.Category)
+	if category == "" {
+		category = "category unknown"
+	}
+	return fmt.Sprintf("%s %s (observed %s)", b.Station, category, b.METAR.Observed.Local().Format("15:04"))
+}
+
+// Warnings lists flight tasks on forecast-IFR days.
+func (b WeatherBrief) Warnings() []string {
+	var warnings []string
+	for _, flag := range b.Flags {
+		titles := make([]string, 0, len(flag.Tasks))
+		for _, task := range flag.Tasks {
+			titles = append(titles, task.Title)
+		}
+		warnings = append(warnings, fmt.Sprintf("%s forecast %s on %s: %s", b.Station, flag.Category, flag.Date.Format("Mon Jan 2"), strings.Join(titles, ", ")))
+	}
+	return warnings
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const weatherFixtureDir = "testdata/weather"
+
+func TestWeatherConditionsCategory(t *testing.T) {
+	cases := []struct {
+		name       string
+		conditions WeatherConditions
+		want       FlightCategory
+	}{
+		{"clear", WeatherConditions{VisibilitySM: 10, Clouds: []CloudLayer{{Cover: "CLR"}}}, FlightCategoryVFR},
+		{"scattered low", WeatherConditions{VisibilitySM: 10, Clouds: []CloudLayer{{Cover: "SCT", BaseFt: 800}}}, FlightCategoryVFR},
+		{"ceiling 3000", WeatherConditions{VisibilitySM: 10, Clouds: []CloudLayer{{Cover: "BKN", BaseFt: 3000}}}, FlightCategoryMVFR},
+		{"visibility 5", WeatherConditions{VisibilitySM: 5}, FlightCategoryMVFR},
+		{"ceiling 900", WeatherConditions{VisibilitySM: 10, Clouds: []CloudLayer{{Cover: "FEW", BaseFt: 400}, {Cover: "OVC", BaseFt: 900}}}, FlightCategoryIFR},
+		{"visibility 2.5", WeatherConditions{VisibilitySM: 2.5, Clouds: []CloudLayer{{Cover: "SCT", BaseFt: 5000}}}, FlightCategoryIFR},
+		{"vertical visibility", WeatherConditions{VisibilitySM: 0.25, Clouds: []CloudLayer{{Cover: "VV", BaseFt: 200}}}, FlightCategoryLIFR},
+		{"nothing reported", WeatherConditions{VisibilitySM: -1}, FlightCategoryUnknown},
+	}
+	for _, tc := range cases {
+		if got := tc.conditions.Category(); got != tc.want {
+			t.Fatalf("%s: expected %s, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestParseVisibility(t *testing.T) {
+	for text, want := range map[string]float64{"10+": 10, "6+": 6, "P6SM": 6, "1 1/2": 1.5, "3/4SM": 0.75, "M1/4": 0.25} {
+		got, err := parseVisibility(text)
+		if err != nil || got != want {
+			t.Fatalf("parseVisibility(%q) = %v, %v; want %v", text, got, err, want)
+		}
+	}
+	if _, err := parseVisibility("lots"); err == nil {
+		t.Fatal("expected an error for unparseable visibility")
+	}
+}
+
+func TestFileWeatherProvider_DecodesFixtures(t *testing.T) {
+	provider := FileWeatherProvider{Dir: weatherFixtureDir}
+
+	metar, err := provider.METAR(context.Background(), "kfxe")
+	if err != nil {
+		t.Fatalf("METAR failed: %v", err)
+	}
+	if metar.Category != FlightCategoryVFR || metar.VisibilitySM != 10 || !metar.Observed.Equal(time.Date(2026, 6, 1, 11, 53, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected METAR: %+v", metar)
+	}
+
+	taf, err := provider.TAF(context.Background(), "KFXE")
+	if err != nil {
+		t.Fatalf("TAF failed: %v", err)
+	}
+	var categories []string
+	for _, period := range taf.Periods {
+		categories = append(categories, string(period.Category))
+	}
+	if got := strings.Join(categories, ","); got != "VFR,IFR,MVFR" {
+		t.Fatalf("expected VFR,IFR,MVFR periods, got %s", got)
+	}
+	if tempo := taf.Periods[1]; tempo.Change != "TEMPO" || tempo.VisibilitySM != 2 {
+		t.Fatalf("unexpected TEMPO group: %+v", tempo)
+	}
+
+	if _, err := provider.METAR(context.Background(), "KXXX"); !errors.Is(err, ErrNoWeatherData) {
+		t.Fatalf("expected ErrNoWeatherData for a missing station, got %v", err)
+	}
+}
+
+func TestAviationWeatherProvider_QueriesDataAPI(t *testing.T) {
+	fixture, err := os.ReadFile(weatherFixtureDir + "/KFXE-metar.json")
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metar" || r.URL.Query().Get("ids") != "KFXE" || r.URL.Query().Get("format") != "json" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.URL.Query().Get("ids") == "KFXE" {
+			_, _ = w.Write(fixture)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	provider := &AviationWeatherProvider{BaseURL: server.URL, Client: server.Client()}
+	metar, err := provider.METAR(context.Background(), " kfxe ")
+	if err != nil {
+		t.Fatalf("METAR failed: %v", err)
+	}
+	if metar.Station != "KFXE" || !strings.HasPrefix(metar.Raw, "KFXE 011153Z") {
+		t.Fatalf("unexpected METAR: %+v", metar)
+	}
+}
+
+type countingWeatherProvider struct {
+	WeatherProvider
+	calls int
+}
+
+func (p *countingWeatherProvider) METAR(ctx context.Context, station string) (METAR, error) {
+	p.calls++
+	return p.WeatherProvider.METAR(ctx, station)
+}
+
+func TestCachedWeatherProvider_ReusesAnswersWithinTTL(t *testing.T) {
+	counting := &countingWeatherProvider{WeatherProvider: FileWeatherProvider{Dir: weatherFixtureDir}}
+	cached := NewCachedWeatherProvider(counting, 10*time.Minute)
+	clock := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	cached.now = func() time.Time { return clock }
+
+	for i := 0; i < 3; i++ {
+		if _, err := cached.METAR(context.Background(), "KFXE"); err != nil {
+			t.Fatalf("METAR failed: %v", err)
+		}
+	}
+	if counting.calls != 1 {
+		t.Fatalf("expected one upstream call within the TTL, got %d", counting.calls)
+	}
+	clock = clock.Add(11 * time.Minute)
+	_, _ = cached.METAR(context.Background(), "KFXE")
+	if counting.calls != 2 {
+		t.Fatalf("expected a refetch after the TTL, got %d calls", counting.calls)
+	}
+}
+
+func TestFlagFlightTasks_FlagsForecastIFRDays(t *testing.T) {
+	taf, err := FileWeatherProvider{Dir: weatherFixtureDir}.TAF(context.Background(), "KFXE")
+	if err != nil {
+		t.Fatalf("TAF failed: %v", err)
+	}
+	tasks := []model.DailyTask{
+		{ID: 1, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Pattern work"},
+		{ID: 2, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Weather theory"},
+		{ID: 3, Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Stalls", Completed: true},
+		{ID: 4, Date: time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Steep turns"},
+		{ID: 5, Date: time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Slow flight"},
+	}
+
+	flags := FlagFlightTasks(tasks, taf, time.UTC)
+	if len(flags) != 1 || flags[0].Category != FlightCategoryIFR || len(flags[0].Tasks) != 1 || flags[0].Tasks[0].ID != 1 {
+		t.Fatalf("expected only Jun 1 pattern work flagged IFR (TEMPO), got %+v", flags)
+	}
+}
+
+func TestLoadWeatherBrief_UsesSchoolAirport(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.DailyTask{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	provider := FileWeatherProvider{Dir: weatherFixtureDir}
+	now := time.Date(2026, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	brief, err := LoadWeatherBrief(context.Background(), db, provider, now)
+	if err != nil || brief.Station != "" || brief.Summary() != "" {
+		t.Fatalf("expected an empty brief without a school airport, got %+v, %v", brief, err)
+	}
+
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+	db.Create(&model.DailyTask{Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Pattern work"})
+	brief, err = LoadWeatherBrief(context.Background(), db, provider, now)
+	if err != nil {
+		t.Fatalf("LoadWeatherBrief failed: %v", err)
+	}
+	if !strings.HasPrefix(brief.Summary(), "KFXE VFR") {
+		t.Fatalf("unexpected summary %q", brief.Summary())
+	}
+	warnings := brief.Warnings()
+	if len(warnings) != 1 || warnings[0] != "KFXE forecast IFR on Mon Jun 1: Pattern work" {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+const (
+	defaultWebhookMaxAttempts    = 8
+	defaultWebhookInitialBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff     = 6 * time.Hour
+	defaultWebhookBatchSize      = 50
+	defaultWebhookTimeout        = 10 * time.Second
+
+	WebhookSignatureHeader = "X-OpenPPL-Signature"
+	WebhookTimestampHeader = "X-OpenPPL-Timestamp"
+	WebhookEventHeader     = "X-OpenPPL-Event"
+	WebhookDeliveryHeader  = "X-OpenPPL-Delivery"
+)
+
+// WebhookEndpoint is a single HTTP receiver for outbox events.
+type WebhookEndpoint struct {
+	Name   string   `json:"name,omitempty"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookConfig lists configured receivers and delivery limits.
+type WebhookConfig struct {
+	Endpoints   []WebhookEndpoint `json:"endpoints"`
+	MaxAttempts int               `json:"max_attempts,omitempty"`
+}
+
+// WebhookDeliveryResult summarizes one outbox flush.
+type WebhookDeliveryResult struct {
+	Attempted int
+	Delivered int
+	Retrying  int
+	Failed    int
+}
+
+// WebhookOutboxStats counts outbox events by delivery status.
+type WebhookOutboxStats struct {
+	Pending   int64
+	Delivered int64
+	Failed    int64
+}
+
+type webhookEnvelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Subscribes reports whether the endpoint wants the given event type.
+// An empty event list subscribes to everything.
+func (e WebhookEndpoint) Subscribes(eventType string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, candidate := range e.Events {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func LoadWebhookConfig() (WebhookConfig, error) {
+	path, err := webhookConfigPath()
+	if err != nil {
+		return WebhookConfig{}, err
+	}
+	return loadWebhookConfigFromPath(path)
+}
+
+func webhookConfigPath() (string, error) {
+	if override := strings.TrimSpace(os.Getenv("OPENPPL_WEBHOOKS_PATH")); override != "" {
+		return override, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "webhooks.json"), nil
+}
+
+func loadWebhookConfigFromPath(path string) (WebhookConfig, error) {
+	var cfg WebhookConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("webhooks: read config: %w", err)
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return cfg, nil
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return WebhookConfig{}, fmt.Errorf("webhooks: parse config: %w", err)
+	}
+	for i, endpoint := range cfg.Endpoints {
+		if strings.TrimSpace(endpoint.URL) == "" {
+			return WebhookConfig{}, fmt.Errorf("webhooks: endpoint %d has no url", i+1)
+		}
+		if strings.TrimSpace(endpoint.Secret) == "" {
+			return WebhookConfig{}, fmt.Errorf("webhooks: endpoint %q has no secret", endpoint.URL)
+		}
+	}
+	return cfg, nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers recompute it with their shared secret to authenticate deliveries.
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDispatcher delivers pending outbox events with retries and backoff.
+type WebhookDispatcher struct {
+	db             *gorm.DB
+	cfg            WebhookConfig
+	client         *http.Client
+	now            func() time.Time
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func NewWebhookDispatcher(database *gorm.DB, cfg WebhookConfig) *WebhookDispatcher {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	return &WebhookDispatcher{
+		db:             database,
+		cfg:            cfg,
+		client:         &http.Client{Timeout: defaultWebhookTimeout},
+		now:            time.Now,
+		initialBackoff: defaultWebhookInitialBackoff,
+		maxBackoff:     defaultWebhookMaxBackoff,
+	}
+}
+
+func (d *WebhookDispatcher) WithHTTPClient(client *http.Client) *WebhookDispatcher {
+	if client != nil {
+		d.client = client
+	}
+	return d
+}
+
+func (d *WebhookDispatcher) WithClock(clock func() time.Time) *WebhookDispatcher {
+	if clock != nil {
+		d.now = clock
+	}
+	return d
+}
+
+// DeliverPending sends every due pending event to its subscribed endpoints.
+// An event is marked delivered only once all subscribed endpoints accept it;
+// receivers should dedupe on the delivery ID header.
+func (d *WebhookDispatcher) DeliverPending(ctx context.Context) (WebhookDeliveryResult, error) {
+	var result WebhookDeliveryResult
+	if d == nil || d.db == nil {
+		return result, errors.New("database is required")
+	}
+	if len(d.cfg.Endpoints) == 0 {
+		return result, nil
+	}
+
+	now := d.now().UTC()
+	var events []model.OutboxEvent
+	if err := d.db.Where("status = ? AND next_attempt_at <= ?", OutboxStatusPending, now).
+		Order("id asc").
+		Limit(defaultWebhookBatchSize).
+		Find(&events).Error; err != nil {
+		return result, fmt.Errorf("query outbox: %w", err)
+	}
+
+	for _, event := range events {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Attempted++
+		statusCode, deliverErr := d.deliverEvent(ctx, event)
+
+		event.Attempts++
+		event.LastStatusCode = statusCode
+		if deliverErr == nil {
+			delivered := d.now().UTC()
+			event.Status = OutboxStatusDelivered
+			event.DeliveredAt = &delivered
+			event.LastError = ""
+			result.Delivered++
+		} else {
+			event.LastError = deliverErr.Error()
+			if event.Attempts >= d.cfg.MaxAttempts {
+				event.Status = OutboxStatusFailed
+				result.Failed++
+			} else {
+				event.NextAttemptAt = now.Add(d.backoff(event.Attempts))
+				result.Retrying++
+			}
+		}
+
+		if err := d.db.Save(&event).Error; err != nil {
+			return result, fmt.Errorf("update outbox event %d: %w", event.ID, err)
+		}
+	}
+
+	return result, nil
+}
+
+func (d *WebhookDispatcher) deliverEvent(ctx context.Context, event model.OutboxEvent) (int, error) {
+	body, err := json.Marshal(webhookEnvelope{
+		ID:        webhookDeliveryID(event),
+		Type:      event.EventType,
+		CreatedAt: event.CreatedAt.UTC().Format(time.RFC3339),
+		Data:      json.RawMessage(event.PayloadJSON),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("encode envelope: %w", err)
+	}
+
+	lastStatus := 0
+	var failures []string
+	for _, endpoint := range d.cfg.Endpoints {
+		if !endpoint.Subscribes(event.EventType) {
+			continue
+		}
+		statusCode, err := d.post(ctx, endpoint, event, body)
+		if statusCode != 0 {
+			lastStatus = statusCode
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", endpoint.URL, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return lastStatus, errors.New(strings.Join(failures, "; "))
+	}
+	return lastStatus, nil
+}
+
+func (d *WebhookDispatcher) post(ctx context.Context, endpoint WebhookEndpoint, event model.OutboxEvent, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(d.now().UTC().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "openppl-webhooks/1")
+	req.Header.Set(WebhookEventHeader, event.EventType)
+	req.Header.Set(WebhookDeliveryHeader, webhookDeliveryID(event))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(endpoint.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := d.initialBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.maxBackoff {
+			return d.maxBackoff
+		}
+	}
+	return delay
+}
+
+func webhookDeliveryID(event model.OutboxEvent) string {
+	return fmt.Sprintf("evt-%d", event.ID)
+}
+
+// DeliverPendingWebhooks loads the user's webhook config and flushes due events.
+func DeliverPendingWebhooks(ctx context.Context, database *gorm.DB) (WebhookDeliveryResult, error) {
+	cfg, err := LoadWebhookConfig()
+	if err != nil {
+		return WebhookDeliveryResult{}, err
+	}
+	return NewWebhookDispatcher(database, cfg).DeliverPending(ctx)
+}
+
+// LoadWebhookOutboxStats counts outbox events by status.
+func LoadWebhookOutboxStats(database *gorm.DB) (WebhookOutboxStats, error) {
+	var stats WebhookOutboxStats
+	if database == nil {
+		return stats, errors.New("database is required")
+	}
+	counts := []struct {
+		status string
+		target *int64
+	}{
+		{OutboxStatusPending, &stats.Pending},
+		{OutboxStatusDelivered, &stats.Delivered},
+		{OutboxStatusFailed, &stats.Failed},
+	}
+	for _, c := range counts {
+		if err := database.Model(&model.OutboxEvent{}).Where("status = ?", c.status).Count(c.target).Error; err != nil {
+			return stats, fmt.Errorf("count %s events: %w", c.status, err)
+		}
+	}
+	return stats, nil
+}
+
+// RetryFailedWebhooks moves failed events back to pending so the next flush retries them.
+func RetryFailedWebhooks(database *gorm.DB, now time.Time) (int64, error) {
+	if database == nil {
+		return 0, errors.New("database is required")
+	}
+	res := database.Model(&model.OutboxEvent{}).
+		Where("status = ?", OutboxStatusFailed).
+		Updates(map[string]any{"status": OutboxStatusPending, "attempts": 0, "next_attempt_at": now.UTC()})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestWebhookDispatcher_DeliversSignedEvents(t *testing.T) {
+	db := setupEventsTestDB(t)
+	now := time.Now().UTC().Add(time.Minute)
+
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := RecordEvent(db, EventBudgetChanged, BudgetEventPayload{ItemType: "plane_rate", Amount: 165, Source: "web"}); err != nil {
+		t.Fatalf("RecordEvent: %v", err)
+	}
+
+	cfg := WebhookConfig{Endpoints: []WebhookEndpoint{{URL: server.URL, Secret: "s3cret"}}}
+	result, err := NewWebhookDispatcher(db, cfg).WithClock(func() time.Time { return now }).DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("DeliverPending returned error: %v", err)
+	}
+	if result.Attempted != 1 || result.Delivered != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if len(received) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(received))
+	}
+	req := received[0]
+	if req.Header.Get(WebhookEventHeader) != EventBudgetChanged {
+		t.Fatalf("unexpected event header %q", req.Header.Get(WebhookEventHeader))
+	}
+	timestamp := req.Header.Get(WebhookTimestampHeader)
+	want := "sha256=" + SignWebhookPayload("s3cret", timestamp, bodies[0])
+	if got := req.Header.Get(WebhookSignatureHeader); got != want {
+		t.Fatalf("signature mismatch: got %q want %q", got, want)
+	}
+
+	var envelope struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(bodies[0], &envelope); err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	if envelope.ID != req.Header.Get(WebhookDeliveryHeader) || !strings.Contains(string(envelope.Data), `"plane_rate"`) {
+		t.Fatalf("unexpected envelope: %s", bodies[0])
+	}
+
+	var event model.OutboxEvent
+	db.First(&event)
+	if event.Status != OutboxStatusDelivered || event.DeliveredAt == nil {
+		t.Fatalf("expected delivered event, got status=%q", event.Status)
+	}
+}
+
+func TestWebhookDispatcher_RetriesWithBackoffThenFails(t *testing.T) {
+	db := setupEventsTestDB(t)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	if err := RecordEvent(db, EventTaskCompleted, TaskEventPayload{TaskID: 1, Completed: true}); err != nil {
+		t.Fatalf("RecordEvent: %v", err)
+	}
+	db.Model(&model.OutboxEvent{}).Where("1 = 1").Update("next_attempt_at", now)
+
+	cfg := WebhookConfig{MaxAttempts: 2, Endpoints: []WebhookEndpoint{{URL: server.URL, Secret: "k"}}}
+	dispatcher := NewWebhookDispatcher(db, cfg).WithClock(func() time.Time { return now })
+
+	first, err := dispatcher.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("first flush: %v", err)
+	}
+	if first.Retrying != 1 {
+		t.Fatalf("expected retrying result, got %+v", first)
+	}
+
+	var event model.OutboxEvent
+	db.First(&event)
+	if !event.NextAttemptAt.Equal(now.Add(defaultWebhookInitialBackoff)) {
+		t.Fatalf("expected backoff to %v, got %v", now.Add(defaultWebhookInitialBackoff), event.NextAttemptAt)
+	}
+	if event.LastStatusCode != http.StatusBadGateway {
+		t.Fatalf("expected last status 502, got %d", event.LastStatusCode)
+	}
+
+	again, _ := dispatcher.DeliverPending(context.Background())
+	if again.Attempted != 0 {
+		t.Fatalf("expected event to wait for backoff, got %+v", again)
+	}
+
+	now = now.Add(time.Hour)
+	final, err := dispatcher.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatalf("final flush: %v", err)
+	}
+	if final.Failed != 1 || calls != 2 {
+		t.Fatalf("expected permanent failure after 2 calls, got %+v calls=%d", final, calls)
+	}
+
+	requeued, err := RetryFailedWebhooks(db, now)
+	if err != nil || requeued != 1 {
+		t.Fatalf("RetryFailedWebhooks = %d, %v", requeued, err)
+	}
+}
+
+func TestWebhookEndpoint_Subscribes(t *testing.T) {
+	all := WebhookEndpoint{}
+	if !all.Subscribes(EventQuizAnswered) {
+		t.Fatal("empty event list should subscribe to everything")
+	}
+	tasksOnly := WebhookEndpoint{Events: []string{EventTaskCompleted}}
+	if tasksOnly.Subscribes(EventBudgetChanged) {
+		t.Fatal("endpoint should not receive unsubscribed events")
+	}
+}
+
+func TestLoadWebhookConfig_ValidatesEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	missing, err := loadWebhookConfigFromPath(filepath.Join(dir, "missing.json"))
+	if err != nil || len(missing.Endpoints) != 0 {
+		t.Fatalf("expected empty config for missing file, got %+v, %v", missing, err)
+	}
+
+	path := filepath.Join(dir, "webhooks.json")
+	if err := os.WriteFile(path, []byte(`{"endpoints":[{"url":"https://example.test/hook"}]}`), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if _, err := loadWebhookConfigFromPath(path); err == nil {
+		t.Fatal("expected error for endpoint without secret")
+	}
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// avgasLbPerGal is the standard weight of 100LL used in weight and balance.
+const avgasLbPerGal = 6.0
+
+//go:embed wb_profiles_default.json
+var defaultWBProfilesJSON []byte
+
+// WBStation is a loading station: seats, baggage areas and the like.
+// MaxLb is the structural limit, zero when the POH gives none.
+type WBStation struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	ArmIn float64 `json:"arm_in"`
+	MaxLb float64 `json:"max_lb,omitempty"`
+}
+
+// WBPoint is a corner of the CG envelope.
+type WBPoint struct {
+	ArmIn    float64 `json:"arm_in"`
+	WeightLb float64 `json:"weight_lb"`
+}
+
+// WBProfile is an aircraft's weight and balance data: the empty weight and
+// arm from its weight and balance record, the loading stations, usable fuel
+// and the normal category CG envelope, as a polygon in arm/weight space.
+//
+// Profiles in the user's file may name a Base profile and only list what
+// differs, typically the empty weight and arm of their own airplane.
+type WBProfile struct {
+	ID              string      `json:"id"`
+	Name            string      `json:"name"`
+	Base            string      `json:"base,omitempty"`
+	EmptyWeightLb   float64     `json:"empty_weight_lb"`
+	EmptyArmIn      float64     `json:"empty_arm_in"`
+	MaxTakeoffLb    float64     `json:"max_takeoff_lb"`
+	MaxLandingLb    float64     `json:"max_landing_lb,omitempty"`
+	FuelCapacityGal float64     `json:"fuel_capacity_gal"`
+	FuelArmIn       float64     `json:"fuel_arm_in"`
+	FuelLbPerGal    float64     `json:"fuel_lb_per_gal,omitempty"`
+	Stations        []WBStation `json:"stations"`
+	Envelope        []WBPoint   `json:"envelope"`
+}
+
+// Station finds a loading station by id.
+func (p WBProfile) Station(id string) (WBStation, bool) {
+	for _, station := range p.Stations {
+		if strings.EqualFold(station.ID, strings.TrimSpace(id)) {
+			return station, true
+		}
+	}
+	return WBStation{}, false
+}
+
+// Validate checks that the profile can be computed with.
+func (p WBProfile) Validate() error {
+	if strings.TrimSpace(p.ID) == "" {
+		return errors.New("profile id is required")
+	}
+	if p.EmptyWeightLb <= 0 || p.EmptyArmIn <= 0 {
+		return fmt.Errorf("%s: empty weight and arm must be positive", p.ID)
+	}
+	if p.MaxTakeoffLb <= p.EmptyWeightLb {
+		return fmt.Errorf("%s: max takeoff weight must be above the empty weight", p.ID)
+	}
+	if p.FuelCapacityGal < 0 || (p.FuelCapacityGal > 0 && p.FuelArmIn <= 0) {
+		return fmt.Errorf("%s: fuel needs a capacity and an arm", p.ID)
+	}
+	if len(p.Stations) == 0 {
+		return fmt.Errorf("%s: at least one station is required", p.ID)
+	}
+	seen := map[string]bool{}
+	for _, station := range p.Stations {
+		key := strings.ToLower(strings.TrimSpace(station.ID))
+		if key == "" || seen[key] {
+			return fmt.Errorf("%s: station ids must be set and unique", p.ID)
+		}
+		if station.ArmIn <= 0 || station.MaxLb < 0 {
+			return fmt.Errorf("%s: station %s needs a positive arm", p.ID, station.ID)
+		}
+		seen[key] = true
+	}
+	if len(p.Envelope) < 3 {
+		return fmt.Errorf("%s: the CG envelope needs at least three points", p.ID)
+	}
+	return nil
+}
+
+// EnvelopeLimits returns the forward and aft CG limits at a weight, read
+// off the envelope polygon. ok is false when the weight is outside the
+// envelope altogether.
+func (p WBProfile) EnvelopeLimits(weightLb float64) (forwardIn, aftIn float64, ok bool) {
+	forwardIn, aftIn = math.Inf(1), math.Inf(-1)
+	for i, from := range p.Envelope {
+		to := p.Envelope[(i+1)%len(p.Envelope)]
+		low, high := math.Min(from.WeightLb, to.WeightLb), math.Max(from.WeightLb, to.WeightLb)
+		if weightLb < low || weightLb > high {
+			continue
+		}
+		arms := []float64{from.ArmIn, to.ArmIn}
+		if from.WeightLb != to.WeightLb {
+			fraction := (weightLb - from.WeightLb) / (to.WeightLb - from.WeightLb)
+			arms = []float64{from.ArmIn + fraction*(to.ArmIn-from.ArmIn)}
+		}
+		for _, arm := range arms {
+			forwardIn, aftIn = math.Min(forwardIn, arm), math.Max(aftIn, arm)
+		}
+	}
+	return forwardIn, aftIn, !math.IsInf(forwardIn, 1)
+}
+
+func wbProfilesPath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("OPENPPL_WB_PROFILES_PATH")); path != "" {
+		return path, nil
+	}
+	dir, err := motdDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "wb_profiles.json"), nil
+}
+
+// LoadWBProfiles returns the embedded C172S and PA-28-161 profiles together
+// with the user's own from ~/.openppl/wb_profiles.json (or
+// OPENPPL_WB_PROFILES_PATH), a JSON array of profiles. A user profile with
+// the id of an embedded one replaces it. When the user file cannot be used
+// the embedded profiles are returned with the error.
+func LoadWBProfiles() ([]WBProfile, error) {
+	var profiles []WBProfile
+	if err := json.Unmarshal(defaultWBProfilesJSON, &profiles); err != nil {
+		return nil, fmt.Errorf("weight and balance: embedded profiles: %w", err)
+	}
+
+	path, err := wbProfilesPath()
+	if err != nil {
+		return profiles, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return profiles, fmt.Errorf("weight and balance: read %s: %w", path, err)
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return profiles, fmt.Errorf("weight and balance: parse %s: %w", path, err)
+	}
+
+	byID := map[string]WBProfile{}
+	for _, profile := range profiles {
+		byID[strings.ToLower(profile.ID)] = profile
+	}
+	for _, entry := range entries {
+		var header struct {
+			ID   string `json:"id"`
+			Base string `json:"base"`
+		}
+		if err := json.Unmarshal(entry, &header); err != nil {
+			return profiles, fmt.Errorf("weight and balance: parse %s: %w", path, err)
+		}
+		var profile WBProfile
+		if header.Base != "" {
+			base, ok := byID[strings.ToLower(header.Base)]
+			if !ok {
+				return profiles, fmt.Errorf("weight and balance: %s: unknown base profile %q", header.ID, header.Base)
+			}
+			// Copy the slices so decoding over them cannot change the base.
+			profile = base
+			profile.Stations = append([]WBStation(nil), base.Stations...)
+			profile.Envelope = append([]WBPoint(nil), base.Envelope...)
+		}
+		if err := json.Unmarshal(entry, &profile); err != nil {
+			return profiles, fmt.Errorf("weight and balance: parse %s: %w", path, err)
+		}
+		if err := profile.Validate(); err != nil {
+			return profiles, fmt.Errorf("weight and balance: %s: %w", path, err)
+		}
+		byID[strings.ToLower(profile.ID)] = profile
+	}
+
+	merged := make([]WBProfile, 0, len(byID))
+	for _, profile := range byID {
+		merged = append(merged, profile)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].ID < merged[j].ID })
+	return merged, nil
+}
+
+// FindWBProfile looks a profile up by id, ignoring case.
+func FindWBProfile(profiles []WBProfile, id string) (WBProfile, bool) {
+	for _, profile := range profiles {
+		if strings.EqualFold(profile.ID, strings.TrimSpace(id)) {
+			return profile, true
+		}
+	}
+	return WBProfile{}, false
+}
+
+// WBLoading is what is put in the airplane: pounds by station id, fuel at
+// takeoff and the fuel burned by landing, in gallons.
+type WBLoading struct {
+	Stations map[string]float64
+	FuelGal  float64
+	BurnGal  float64
+}
+
+// WBLine is one line of the loading table.
+type WBLine struct {
+	Name     string
+	WeightLb float64
+	ArmIn    float64
+}
+
+// MomentLbIn is weight times arm.
+func (l WBLine) MomentLbIn() float64 {
+	return l.WeightLb * l.ArmIn
+}
+
+// WBCondition is the weight and CG at takeoff or landing, with the envelope
+// limits at that weight.
+type WBCondition struct {
+	WeightLb       float64
+	MomentLbIn     float64
+	CGIn           float64
+	ForwardLimitIn float64
+	AftLimitIn     float64
+	InEnvelope     bool
+}
+
+// WBResult is a computed weight and balance.
+type WBResult struct {
+	Profile  WBProfile
+	Loading  WBLoading
+	Lines    []WBLine
+	Takeoff  WBCondition
+	Landing  WBCondition
+	Problems []string
+}
+
+// WithinLimits reports whether every weight and CG check passed.
+func (r WBResult) WithinLimits() bool {
+	return len(r.Problems) == 0
+}
+
+// ComputeWeightBalance works out the takeoff and landing weight and CG and
+// checks them against the profile's limits. Loadings the profile cannot
+// hold, such as unknown stations or more fuel than the tanks take, are
+// errors; weights and CGs outside the limits are Problems.
+func ComputeWeightBalance(profile WBProfile, loading WBLoading) (WBResult, error) {
+	fuelLbPerGal := profile.FuelLbPerGal
+	if fuelLbPerGal == 0 {
+		fuelLbPerGal = avgasLbPerGal
+	}
+	if loading.FuelGal < 0 || loading.BurnGal < 0 {
+		return WBResult{}, errors.New("fuel cannot be negative")
+	}
+	if loading.FuelGal > profile.FuelCapacityGal {
+		return WBResult{}, fmt.Errorf("%.1f gal is more than the %.0f gal usable fuel of the %s", loading.FuelGal, profile.FuelCapacityGal, profile.Name)
+	}
+	if loading.BurnGal > loading.FuelGal {
+		return WBResult{}, fmt.Errorf("fuel burn %.1f gal is more than the %.1f gal on board", loading.BurnGal, loading.FuelGal)
+	}
+	for id, weight := range loading.Stations {
+		if _, ok := profile.Station(id); !ok {
+			ids := make([]string, 0, len(profile.Stations))
+			for _, station := range profile.Stations {
+				ids = append(ids, station.ID)
+			}
+			return WBResult{}, fmt.Errorf("unknown station %q for %s (stations: %s)", id, profile.ID, strings.Join(ids, ", "))
+		}
+		if weight < 0 {
+			return WBResult{}, fmt.Errorf("station %s cannot be negative", id)
+		}
+	}
+
+	result := WBResult{Profile: profile, Loading: loading}
+	result.Lines = append(result.Lines, WBLine{Name: "Empty weight", WeightLb: profile.EmptyWeightLb, ArmIn: profile.EmptyArmIn})
+	for _, station := range profile.Stations {
+		weight := 0.0
+		for id, pounds := range loading.Stations {
+			if strings.EqualFold(id, station.ID) {
+				weight += pounds
+			}
+		}
+		result.Lines = append(result.Lines, WBLine{Name: station.Name, WeightLb: weight, ArmIn: station.ArmIn})
+		if station.MaxLb > 0 && weight > station.MaxLb {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s %.0f lb is over its %.0f lb limit", station.Name, weight, station.MaxLb))
+		}
+	}
+	fuel := WBLine{Name: fmt.Sprintf("Fuel %.1f gal", loading.FuelGal), WeightLb: loading.FuelGal * fuelLbPerGal, ArmIn: profile.FuelArmIn}
+	result.Lines = append(result.Lines, fuel)
+
+	for _, line := range result.Lines {
+		result.Takeoff.WeightLb += line.WeightLb
+		result.Takeoff.MomentLbIn += line.MomentLbIn()
+	}
+	burn := WBLine{WeightLb: loading.BurnGal * fuelLbPerGal, ArmIn: profile.FuelArmIn}
+	result.Landing.WeightLb = result.Takeoff.WeightLb - burn.WeightLb
+	result.Landing.MomentLbIn = result.Takeoff.MomentLbIn - burn.MomentLbIn()
+
+	maxLanding := profile.MaxLandingLb
+	if maxLanding == 0 {
+		maxLanding = profile.MaxTakeoffLb
+	}
+	for _, check := range []struct {
+		name      string
+		condition *WBCondition
+		maxLb     float64
+	}{
+		{"takeoff", &result.Takeoff, profile.MaxTakeoffLb},
+		{"landing", &result.Landing, maxLanding},
+	} {
+		c := check.condition
+		c.CGIn = c.MomentLbIn / c.WeightLb
+		forward, aft, ok := profile.EnvelopeLimits(c.WeightLb)
+		c.ForwardLimitIn, c.AftLimitIn = forward, aft
+		c.InEnvelope = ok && c.CGIn >= forward && c.CGIn <= aft
+		switch {
+		case c.WeightLb > check.maxLb:
+			result.Problems = append(result.Problems, fmt.Sprintf("%s weight %.0f lb is over the %.0f lb maximum", check.name, c.WeightLb, check.maxLb))
+		case !ok:
+			result.Problems = append(result.Problems, fmt.Sprintf("%s weight %.0f lb is outside the CG envelope", check.name, c.WeightLb))
+		case c.CGIn < forward:
+			result.Problems = append(result.Problems, fmt.Sprintf("%s CG %.2f in is forward of the %.2f in limit", check.name, c.CGIn, forward))
+		case c.CGIn > aft:
+			result.Problems = append(result.Problems, fmt.Sprintf("%s CG %.2f in is aft of the %.2f in limit", check.name, c.CGIn, aft))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// wbChartBounds is the arm/weight area a chart covers: the envelope and
+// both loading points, with some margin.
+type wbChartBounds struct {
+	minArm, maxArm       float64
+	minWeight, maxWeight float64
+}
+
+func (r WBResult) chartBounds() wbChartBounds {
+	b := wbChartBounds{minArm: math.Inf(1), maxArm: math.Inf(-1), minWeight: math.Inf(1), maxWeight: math.Inf(-1)}
+	include := func(arm, weight float64) {
+		b.minArm, b.maxArm = math.Min(b.minArm, arm), math.Max(b.maxArm, arm)
+		b.minWeight, b.maxWeight = math.Min(b.minWeight, weight), math.Max(b.maxWeight, weight)
+	}
+	for _, point := range r.Profile.Envelope {
+		include(point.ArmIn, point.WeightLb)
+	}
+	include(r.Takeoff.CGIn, r.Takeoff.WeightLb)
+	include(r.Landing.CGIn, r.Landing.WeightLb)
+	armMargin, weightMargin := (b.maxArm-b.minArm)*0.05, (b.maxWeight-b.minWeight)*0.05
+	b.minArm, b.maxArm = b.minArm-armMargin, b.maxArm+armMargin
+	b.minWeight, b.maxWeight = b.minWeight-weightMargin, b.maxWeight+weightMargin
+	return b
+}
+
+// ChartLines draws the CG envelope as ASCII art, with the takeoff (T) and
+// landing (L) points, arm across and weight up.
+func (r WBResult) ChartLines(width, height int) []string {
+	b := r.chartBounds()
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", width))
+	}
+	cell := func(arm, weight float64) (int, int) {
+		col := int(math.Round((arm - b.minArm) / (b.maxArm - b.minArm) * float64(width-1)))
+		row := int(math.Round((b.maxWeight - weight) / (b.maxWeight - b.minWeight) * float64(height-1)))
+		return min(max(col, 0), width-1), min(max(row, 0), height-1)
+	}
+
+	envelope := r.Profile.Envelope
+	steps := 2 * (width + height)
+	for i, from := range envelope {
+		to := envelope[(i+1)%len(envelope)]
+		for step := 0; step <= steps; step++ {
+			fraction := float64(step) / float64(steps)
+			col, row := cell(from.ArmIn+fraction*(to.ArmIn-from.ArmIn), from.WeightLb+fraction*(to.WeightLb-from.WeightLb))
+			grid[row][col] = '.'
+		}
+	}
+	col, row := cell(r.Landing.CGIn, r.Landing.WeightLb)
+	grid[row][col] = 'L'
+	col, row = cell(r.Takeoff.CGIn, r.Takeoff.WeightLb)
+	grid[row][col] = 'T'
+
+	lines := make([]string, 0, height+2)
+	for row, cells := range grid {
+		label := ""
+		if row == 0 || row == height-1 || row == height/2 {
+			label = fmt.Sprintf("%.0f", b.maxWeight-float64(row)/float64(height-1)*(b.maxWeight-b.minWeight))
+		}
+		lines = append(lines, fmt.Sprintf("%6s |%s", label, strings.TrimRight(string(cells), " ")))
+	}
+	lines = append(lines, "       +"+strings.Repeat("-", width))
+	left, right := fmt.Sprintf("%.1f in", b.minArm), fmt.Sprintf("%.1f in", b.maxArm)
+	lines = append(lines, "        "+left+strings.Repeat(" ", max(width-len(left)-len(right), 1))+right)
+	return lines
+}
+
+// ChartSVG draws the CG envelope as an SVG image, with the takeoff and
+// landing points joined by the fuel burn line. Points outside the limits
+// are drawn in red.
+func (r WBResult) ChartSVG() string {
+	const (
+		width, height = 480, 320
+		left, bottom  = 56, 36
+		top, right    = 12, 12
+	)
+	b := r.chartBounds()
+	x := func(arm float64) float64 {
+		return left + (arm-b.minArm)/(b.maxArm-b.minArm)*(width-left-right)
+	}
+	y := func(weight float64) float64 {
+		return top + (b.maxWeight-weight)/(b.maxWeight-b.minWeight)*(height-top-bottom)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="11">`, width, height, width, height)
+	fmt.Fprintf(&svg, `<title>%s CG envelope</title>`, html.EscapeString(r.Profile.Name))
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`, left, height-bottom, width-right, height-bottom)
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`, left, top, left, height-bottom)
+	for _, weight := range []float64{b.minWeight, (b.minWeight + b.maxWeight) / 2, b.maxWeight} {
+		fmt.Fprintf(&svg, `<text x="%d" y="%.1f" text-anchor="end">%.0f</text>`, left-4, y(weight)+4, weight)
+	}
+	for _, arm := range []float64{b.minArm, (b.minArm + b.maxArm) / 2, b.maxArm} {
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%d" text-anchor="middle">%.1f</text>`, x(arm), height-bottom+14, arm)
+	}
+	fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="middle">CG (in aft of datum)</text>`, (left+width-right)/2, height-4)
+
+	points := make([]string, 0, len(r.Profile.Envelope))
+	for _, point := range r.Profile.Envelope {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x(point.ArmIn), y(point.WeightLb)))
+	}
+	fmt.Fprintf(&svg, `<polygon points="%s" fill="#e6f2ff" stroke="#1f6fb2" stroke-width="2"/>`, strings.Join(points, " "))
+	fmt.Fprintf(&svg, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#555" stroke-dasharray="4 3"/>`,
+		x(r.Takeoff.CGIn), y(r.Takeoff.WeightLb), x(r.Landing.CGIn), y(r.Landing.WeightLb))
+	for _, point := range []struct {
+		label     string
+		condition WBCondition
+	}{{"Takeoff", r.Takeoff}, {"Landing", r.Landing}} {
+		color := "#2e7d32"
+		if !point.condition.InEnvelope {
+			color = "#c62828"
+		}
+		fmt.Fprintf(&svg, `<circle cx="%.1f" cy="%.1f" r="5" fill="%s"><title>%s %.0f lb at %.2f in</title></circle>`,
+			x(point.condition.CGIn), y(point.condition.WeightLb), color, point.label, point.condition.WeightLb, point.condition.CGIn)
+		fmt.Fprintf(&svg, `<text x="%.1f" y="%.1f">%s</text>`, x(point.condition.CGIn)+8, y(point.condition.WeightLb)+4, point.label)
+	}
+	svg.WriteString("</svg>")
+	return svg.String()
+}
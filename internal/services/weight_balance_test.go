@@ -0,0 +1,158 @@
+package services
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadTestWBProfiles(t *testing.T) []WBProfile {
+	t.Helper()
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+	profiles, err := LoadWBProfiles()
+	if err != nil {
+		t.Fatalf("LoadWBProfiles failed: %v", err)
+	}
+	return profiles
+}
+
+func testWBProfile(t *testing.T, id string) WBProfile {
+	t.Helper()
+	profile, ok := FindWBProfile(loadTestWBProfiles(t), id)
+	if !ok {
+		t.Fatalf("expected embedded profile %s", id)
+	}
+	return profile
+}
+
+func TestLoadWBProfiles_EmbeddedProfiles(t *testing.T) {
+	profiles := loadTestWBProfiles(t)
+	if len(profiles) != 2 || profiles[0].ID != "c172s" || profiles[1].ID != "pa28-161" {
+		t.Fatalf("unexpected profiles %+v", profiles)
+	}
+	if _, ok := FindWBProfile(profiles, "PA28-161"); !ok {
+		t.Fatal("expected profile lookup to ignore case")
+	}
+}
+
+func TestLoadWBProfiles_UserProfileInheritsBase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wb_profiles.json")
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", path)
+	data := `[{"id": "n12345", "base": "c172s", "name": "N12345 (C172S)", "empty_weight_lb": 1702.4, "empty_arm_in": 41.1}]`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	profiles, err := LoadWBProfiles()
+	if err != nil {
+		t.Fatalf("LoadWBProfiles failed: %v", err)
+	}
+	profile, ok := FindWBProfile(profiles, "n12345")
+	if !ok || len(profiles) != 3 {
+		t.Fatalf("expected the user profile next to the embedded ones, got %+v", profiles)
+	}
+	if profile.EmptyWeightLb != 1702.4 || profile.MaxTakeoffLb != 2550 || len(profile.Stations) != 4 || len(profile.Envelope) != 5 {
+		t.Fatalf("expected base values with the user's empty weight, got %+v", profile)
+	}
+}
+
+func TestLoadWBProfiles_InvalidUserFileKeepsEmbedded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wb_profiles.json")
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", path)
+	if err := os.WriteFile(path, []byte(`[{"id": "n1", "base": "c150"}]`), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	profiles, err := LoadWBProfiles()
+	if err == nil || !strings.Contains(err.Error(), "unknown base profile") {
+		t.Fatalf("expected unknown base error, got %v", err)
+	}
+	if len(profiles) != 2 {
+		t.Fatalf("expected embedded profiles alongside the error, got %+v", profiles)
+	}
+}
+
+func TestEnvelopeLimits(t *testing.T) {
+	profile := testWBProfile(t, "c172s")
+	forward, aft, ok := profile.EnvelopeLimits(2250)
+	if !ok || math.Abs(forward-37.25) > 0.01 || math.Abs(aft-47.3) > 0.01 {
+		t.Fatalf("unexpected limits at 2250 lb: %.2f-%.2f (%v)", forward, aft, ok)
+	}
+	if _, _, ok := profile.EnvelopeLimits(2600); ok {
+		t.Fatal("expected no limits above the envelope")
+	}
+}
+
+func TestComputeWeightBalance_WithinLimits(t *testing.T) {
+	profile := testWBProfile(t, "c172s")
+	result, err := ComputeWeightBalance(profile, WBLoading{Stations: map[string]float64{"front": 340}, FuelGal: 53, BurnGal: 10})
+	if err != nil {
+		t.Fatalf("ComputeWeightBalance failed: %v", err)
+	}
+	if result.Takeoff.WeightLb != 2338 || math.Abs(result.Takeoff.CGIn-41.01) > 0.01 {
+		t.Fatalf("unexpected takeoff %+v", result.Takeoff)
+	}
+	if result.Landing.WeightLb != 2278 || !result.Landing.InEnvelope {
+		t.Fatalf("unexpected landing %+v", result.Landing)
+	}
+	if !result.WithinLimits() {
+		t.Fatalf("expected within limits, got %v", result.Problems)
+	}
+}
+
+func TestComputeWeightBalance_ReportsProblems(t *testing.T) {
+	profile := testWBProfile(t, "c172s")
+	result, err := ComputeWeightBalance(profile, WBLoading{
+		Stations: map[string]float64{"front": 170, "rear": 400, "baggage1": 120, "baggage2": 60},
+		FuelGal:  20,
+	})
+	if err != nil {
+		t.Fatalf("ComputeWeightBalance failed: %v", err)
+	}
+	problems := strings.Join(result.Problems, "\n")
+	for _, want := range []string{"Baggage area 2 60 lb is over its 50 lb limit", "takeoff CG 50.22 in is aft of the 47.30 in limit"} {
+		if !strings.Contains(problems, want) {
+			t.Fatalf("expected %q in problems:\n%s", want, problems)
+		}
+	}
+
+	result, err = ComputeWeightBalance(profile, WBLoading{Stations: map[string]float64{"front": 400, "rear": 340}, FuelGal: 53})
+	if err != nil {
+		t.Fatalf("ComputeWeightBalance failed: %v", err)
+	}
+	if result.WithinLimits() || !strings.Contains(strings.Join(result.Problems, "\n"), "over the 2550 lb maximum") {
+		t.Fatalf("expected over gross weight, got %v", result.Problems)
+	}
+}
+
+func TestComputeWeightBalance_RejectsBadLoading(t *testing.T) {
+	profile := testWBProfile(t, "pa28-161")
+	for _, loading := range []WBLoading{
+		{FuelGal: 60},
+		{FuelGal: 10, BurnGal: 12},
+		{Stations: map[string]float64{"baggage1": 20}, FuelGal: 10},
+		{Stations: map[string]float64{"front": -10}, FuelGal: 10},
+	} {
+		if _, err := ComputeWeightBalance(profile, loading); err == nil {
+			t.Fatalf("expected an error for %+v", loading)
+		}
+	}
+}
+
+func TestWBResultCharts(t *testing.T) {
+	profile := testWBProfile(t, "pa28-161")
+	result, err := ComputeWeightBalance(profile, WBLoading{Stations: map[string]float64{"front": 340}, FuelGal: 48, BurnGal: 8})
+	if err != nil {
+		t.Fatalf("ComputeWeightBalance failed: %v", err)
+	}
+	lines := result.ChartLines(40, 10)
+	chart := strings.Join(lines, "\n")
+	if len(lines) != 12 || !strings.Contains(chart, "T") || !strings.Contains(chart, "L") {
+		t.Fatalf("unexpected chart:\n%s", chart)
+	}
+	if svg := result.ChartSVG(); !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, "<polygon") || strings.Contains(svg, "#c62828") {
+		t.Fatalf("unexpected SVG %s", svg)
+	}
+}
@@ -75,12 +75,13 @@ var (
 )
 
 // Screen titles
-var ScreenTitle = [5]string{
+var ScreenTitle = [6]string{
 	"Dashboard",
 	"Study Plan",
 	"Progress",
 	"Budget",
 	"Checklist",
+	"Weight & Balance",
 }
 
 // Category colors for study tasks
@@ -0,0 +1,183 @@
+package timer
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl timer [status]
+  openppl timer start [<task-id>] [--pomodoro]   Time a task; defaults to the next task due
+  openppl timer pause|resume
+  openppl timer stop
+  openppl timer report                           Actual vs planned hours by category and ACS area`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl timer [subcommand]`. Sessions are
+// shared with the TUI Study screen, so a timer started here shows there.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "status":
+		return runStatus(database, stdout)
+	case "start":
+		return runStart(database, args[1:], stdout)
+	case "pause":
+		session, err := services.PauseStudySession(database, now())
+		return printUpdate(stdout, "Paused", session, err)
+	case "resume":
+		session, err := services.ResumeStudySession(database, now())
+		return printUpdate(stdout, "Resumed", session, err)
+	case "stop":
+		session, err := services.StopStudySession(database, now())
+		return printUpdate(stdout, "Logged", session, err)
+	case "report":
+		return runReport(database, stdout)
+	case "help", "-h", "--help":
+		fmt.Fprintln(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+func runStart(database *gorm.DB, args []string, stdout io.Writer) int {
+	var positional []string
+	for len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		positional, args = append(positional, args[0]), args[1:]
+	}
+	flags := flag.NewFlagSet("timer start", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	pomodoro := flags.Bool("pomodoro", false, fmt.Sprintf("stop counting after %d minutes", services.PomodoroMinutes))
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	positional = append(positional, flags.Args()...)
+
+	var taskID uint
+	switch len(positional) {
+	case 0:
+		task, found, err := services.NextStudyTask(database, now())
+		if err != nil {
+			fmt.Fprintf(stdout, "Could not find a task: %v\n", err)
+			return 1
+		}
+		if !found {
+			fmt.Fprintln(stdout, "No task is due; pass a task id.")
+			return 1
+		}
+		taskID = task.ID
+	case 1:
+		id, err := strconv.ParseUint(positional[0], 10, 64)
+		if err != nil {
+			fmt.Fprintf(stdout, "Invalid task id %q\n", positional[0])
+			return 1
+		}
+		taskID = uint(id)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+
+	session, err := services.StartStudySession(database, taskID, *pomodoro, "cli", now())
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	kind := "Timer"
+	if session.Pomodoro {
+		kind = fmt.Sprintf("%d minute Pomodoro", services.PomodoroMinutes)
+	}
+	fmt.Fprintf(stdout, "%s started on #%d %s (%s, planned %d min)\n", kind, session.DailyTaskID, session.TaskTitle, session.Category, session.PlannedMinutes)
+	return 0
+}
+
+func runStatus(database *gorm.DB, stdout io.Writer) int {
+	session, found, err := services.ActiveStudySession(database)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	if !found {
+		fmt.Fprintln(stdout, "No timer running. Start one with `openppl timer start`.")
+		return 0
+	}
+	printSession(stdout, "Running", session)
+	return 0
+}
+
+// printUpdate prints the outcome of a pause, resume or stop.
+func printUpdate(stdout io.Writer, verb string, session model.StudySession, err error) int {
+	if errors.Is(err, services.ErrNoActiveSession) {
+		fmt.Fprintln(stdout, "No timer running.")
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	printSession(stdout, verb, session)
+	return 0
+}
+
+func printSession(stdout io.Writer, verb string, session model.StudySession) {
+	state := ""
+	if session.EndedAt == nil && session.PausedAt != nil && verb == "Running" {
+		state = " (paused)"
+	}
+	if session.Pomodoro {
+		state += fmt.Sprintf(" [Pomodoro %d min]", services.PomodoroMinutes)
+	}
+	fmt.Fprintf(stdout, "%s %s on #%d %s%s\n", verb, formatDuration(services.SessionElapsed(session, now())), session.DailyTaskID, session.TaskTitle, state)
+	if session.EndedAt != nil && services.PomodoroDone(session, now()) {
+		fmt.Fprintf(stdout, "Pomodoro complete. Take a %d minute break.\n", services.PomodoroBreakMinutes)
+	}
+}
+
+func runReport(database *gorm.DB, stdout io.Writer) int {
+	timeReport, err := services.LoadStudyTimeReport(database)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	if len(timeReport.ByCategory) == 0 {
+		fmt.Fprintln(stdout, "No timed study sessions yet. Start one with `openppl timer start`.")
+		return 0
+	}
+	fmt.Fprintf(stdout, "Timed study: %.1f h\n", timeReport.TotalMinutes/60)
+	printRows(stdout, "Category", timeReport.ByCategory)
+	if len(timeReport.ByArea) > 0 {
+		printRows(stdout, "ACS area", timeReport.ByArea)
+	}
+	return 0
+}
+
+func printRows(stdout io.Writer, heading string, rows []services.StudyTimeRow) {
+	fmt.Fprintf(stdout, "  %-24s %8s %8s %6s\n", heading, "Actual", "Planned", "Tasks")
+	for _, row := range rows {
+		fmt.Fprintf(stdout, "  %-24s %7.1fh %7.1fh %6d\n", row.Name, row.ActualMinutes/60, row.PlannedMinutes/60, row.Tasks)
+	}
+}
+
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Minutes())
+	if minutes >= 60 {
+		return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+	}
+	return fmt.Sprintf("%d min", minutes)
+}
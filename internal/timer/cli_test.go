@@ -0,0 +1,133 @@
+package timer
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func run(t *testing.T, db *gorm.DB, wantCode int, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	if code := Execute(db, args, &out); code != wantCode {
+		t.Fatalf("timer %s = %d, want %d; output %q", strings.Join(args, " "), code, wantCode, out.String())
+	}
+	return out.String()
+}
+
+func TestStart_NoDueTaskExitsOne(t *testing.T) {
+	db := setupTimerCLITestDB(t)
+	clock := stopClock(t, time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC))
+	oldPlan := model.StudyPlan{CheckrideDate: clock.AddDate(0, 1, 0)}
+	db.Create(&oldPlan)
+	db.Create(&model.DailyTask{StudyPlanID: oldPlan.ID, Date: clock.AddDate(0, 0, -1), Category: "Theory", Title: "Superseded airspace", DurationMinutes: 60})
+	plan := model.StudyPlan{CheckrideDate: clock.AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: clock.AddDate(0, 0, 1), Category: "Theory", Title: "Tomorrow's airspace", DurationMinutes: 60})
+
+	if out := run(t, db, 1, "start"); !strings.Contains(out, "No task is due; pass a task id.") {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if out := run(t, db, 1, "start", "abc"); !strings.Contains(out, `Invalid task id "abc"`) {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if out := run(t, db, 1, "start", "99"); !strings.Contains(out, "load task 99") {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if out := run(t, db, 0, "status"); !strings.Contains(out, "No timer running.") {
+		t.Fatalf("unexpected status %q", out)
+	}
+}
+
+func TestStartPauseResumeStopAndReport(t *testing.T) {
+	db := setupTimerCLITestDB(t)
+	clock := stopClock(t, time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC))
+	plan := model.StudyPlan{CheckrideDate: clock.AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: clock.AddDate(0, 0, 1), Category: "Theory", Title: "Tomorrow's airspace", DurationMinutes: 60})
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: *clock, Category: "Theory", Title: "Weather", DurationMinutes: 60})
+
+	if out := run(t, db, 0, "start"); !strings.Contains(out, "Timer started on #2 Weather (Theory, planned 60 min)") {
+		t.Fatalf("expected the task due today, got %q", out)
+	}
+	if out := run(t, db, 1, "start", "1"); !strings.Contains(out, "already running") {
+		t.Fatalf("expected a second timer to be refused, got %q", out)
+	}
+
+	*clock = clock.Add(30 * time.Minute)
+	if out := run(t, db, 0, "pause"); !strings.Contains(out, "Paused 30 min on #2 Weather") {
+		t.Fatalf("unexpected pause output %q", out)
+	}
+	*clock = clock.Add(time.Hour)
+	if out := run(t, db, 0, "status"); !strings.Contains(out, "Running 30 min on #2 Weather (paused)") {
+		t.Fatalf("expected the pause not to count, got %q", out)
+	}
+	run(t, db, 0, "resume")
+	*clock = clock.Add(45 * time.Minute)
+	if out := run(t, db, 0, "stop"); !strings.Contains(out, "Logged 1h 15m on #2 Weather") {
+		t.Fatalf("unexpected stop output %q", out)
+	}
+	if out := run(t, db, 1, "stop"); !strings.Contains(out, "No timer running.") {
+		t.Fatalf("unexpected second stop output %q", out)
+	}
+
+	out := run(t, db, 0, "report")
+	if !strings.Contains(out, "Timed study: 1.2 h") || !strings.Contains(out, "Theory") {
+		t.Fatalf("unexpected report %q", out)
+	}
+}
+
+func TestPomodoroStopsCountingAtTwentyFiveMinutes(t *testing.T) {
+	db := setupTimerCLITestDB(t)
+	clock := stopClock(t, time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC))
+	db.Create(&model.DailyTask{Date: *clock, Category: "CFI Flights", Title: "Stalls", DurationMinutes: 90})
+
+	if out := run(t, db, 0, "start", "1", "--pomodoro"); !strings.Contains(out, "25 minute Pomodoro started on #1 Stalls") {
+		t.Fatalf("unexpected start output %q", out)
+	}
+	*clock = clock.Add(40 * time.Minute)
+	out := run(t, db, 0, "stop")
+	if !strings.Contains(out, "Logged 25 min on #1 Stalls [Pomodoro 25 min]") || !strings.Contains(out, "Take a 5 minute break.") {
+		t.Fatalf("unexpected stop output %q", out)
+	}
+}
+
+func TestUnknownSubcommandAndFlagsExitOne(t *testing.T) {
+	db := setupTimerCLITestDB(t)
+	run(t, db, 1, "lap")
+	run(t, db, 1, "start", "--bogus")
+	run(t, db, 1, "start", "1", "2")
+	run(t, db, 1, "pause")
+	if out := run(t, db, 0, "report"); !strings.Contains(out, "No timed study sessions yet.") {
+		t.Fatalf("unexpected empty report %q", out)
+	}
+}
+
+func setupTimerCLITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.StudySession{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// stopClock makes now return the time behind the returned pointer, so a
+// test can move it forward between commands.
+func stopClock(t *testing.T, at time.Time) *time.Time {
+	t.Helper()
+	clock := at
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+	return &clock
+}
@@ -90,9 +90,14 @@ func TestHelpOutputIncludesStudyActions(t *testing.T) {
 		"Global Navigation",
 		"Study Actions",
 		"Export ICS",
-		"Export Apple Reminders",
+		"Export reminders",
 		"Sync Google Calendar",
+		"Sync CalDAV calendar",
 		"Export OpenCode bot tasks",
+		"Dashboard Actions",
+		"Mark next ready milestone achieved",
+		"Checklist Actions",
+		"Add custom checklist item",
 		"? / F1",
 	}
 
@@ -15,20 +15,40 @@ type HelpSection struct {
 }
 
 var shortcutRegistry = []Shortcut{
-	{Keys: "1-5", Action: "Switch screens (Dashboard, Study, Progress, Budget, Checklist)", Section: "Global Navigation", Footer: true},
+	{Keys: "1-6", Action: "Switch screens (Dashboard, Study, Progress, Budget, Checklist, W&B)", Section: "Global Navigation", Footer: true},
 	{Keys: "up/down", Action: "Move selection", Section: "Global Navigation", Footer: false},
 	{Keys: "enter", Action: "Select or toggle focused item", Section: "Global Navigation", Footer: false},
 	{Keys: "q", Action: "Quit app", Section: "App Controls", Footer: true},
 	{Keys: "ctrl+c", Action: "Force quit", Section: "App Controls", Footer: false},
 	{Keys: "? / F1", Action: "Toggle help", Section: "App Controls", Footer: true},
+	{Keys: "m", Action: "Mark next ready milestone achieved (Dashboard)", Section: "Dashboard Actions", Footer: false},
+	{Keys: "M", Action: "Reopen last achieved milestone (Dashboard)", Section: "Dashboard Actions", Footer: false},
+	{Keys: "w", Action: "Apply weather swaps for flights below minimums (Dashboard)", Section: "Dashboard Actions", Footer: false},
 	{Keys: "/", Action: "Set or edit checkride date", Section: "Study Actions", Footer: false},
 	{Keys: "tab", Action: "Cycle study category filter", Section: "Study Actions", Footer: false},
 	{Keys: "1-5", Action: "Filter study categories", Section: "Study Actions", Footer: false},
 	{Keys: "e", Action: "Export ICS", Section: "Study Actions", Footer: false},
-	{Keys: "r", Action: "Export Apple Reminders", Section: "Study Actions", Footer: false},
+	{Keys: "E", Action: "Export ICS to-dos", Section: "Study Actions", Footer: false},
+	{Keys: "r", Action: "Export reminders (Apple, taskwarrior, Todoist, To Do)", Section: "Study Actions", Footer: false},
 	{Keys: "g", Action: "Sync Google Calendar", Section: "Study Actions", Footer: false},
+	{Keys: "c", Action: "Sync CalDAV calendar", Section: "Study Actions", Footer: false},
 	{Keys: "o", Action: "Export OpenCode bot tasks", Section: "Study Actions", Footer: false},
 	{Keys: "up/down + enter", Action: "Toggle study task completion", Section: "Study Actions", Footer: false},
+	{Keys: "t", Action: "Start or stop the focus timer on the selected task", Section: "Study Actions", Footer: false},
+	{Keys: "T", Action: "Start a 25 minute Pomodoro on the selected task", Section: "Study Actions", Footer: false},
+	{Keys: "p", Action: "Pause or resume the focus timer", Section: "Study Actions", Footer: false},
+	{Keys: "a", Action: "Add a study task, such as a lesson the CFI assigned", Section: "Study Actions", Footer: false},
+	{Keys: "m", Action: "Edit the selected task: title, date, category, priority, minutes and notes", Section: "Study Actions", Footer: false},
+	{Keys: "x", Action: "Delete the selected task (asks first)", Section: "Study Actions", Footer: false},
+	{Keys: "[ / ]", Action: "Move the selected task a day earlier or later", Section: "Study Actions", Footer: false},
+	{Keys: "a", Action: "Add custom checklist item (Checklist)", Section: "Checklist Actions", Footer: false},
+	{Keys: "e", Action: "Edit selected custom checklist item (Checklist)", Section: "Checklist Actions", Footer: false},
+	{Keys: "d", Action: "Delete selected custom checklist item (Checklist)", Section: "Checklist Actions", Footer: false},
+	{Keys: "tab", Action: "Cycle checklist category (filter, or new item while typing)", Section: "Checklist Actions", Footer: false},
+	{Keys: "tab", Action: "Switch aircraft profile (W&B)", Section: "Weight & Balance Actions", Footer: false},
+	{Keys: "left/right", Action: "Change the selected load by 10 lb or 1 gal (W&B)", Section: "Weight & Balance Actions", Footer: false},
+	{Keys: "H / L", Action: "Change the selected load by 1 lb or 0.1 gal (W&B)", Section: "Weight & Balance Actions", Footer: false},
+	{Keys: "x", Action: "Clear the selected load (W&B)", Section: "Weight & Balance Actions", Footer: false},
 }
 
 // AllShortcuts returns all shortcut definitions.
@@ -54,7 +74,10 @@ func FooterShortcuts() []Shortcut {
 func HelpSections(_ Screen) []HelpSection {
 	globalNavigation := make([]Shortcut, 0)
 	appControls := make([]Shortcut, 0)
+	dashboardActions := make([]Shortcut, 0)
 	studyActions := make([]Shortcut, 0)
+	checklistActions := make([]Shortcut, 0)
+	wbActions := make([]Shortcut, 0)
 
 	for _, shortcut := range shortcutRegistry {
 		switch shortcut.Section {
@@ -62,14 +85,23 @@ func HelpSections(_ Screen) []HelpSection {
 			globalNavigation = append(globalNavigation, shortcut)
 		case "App Controls":
 			appControls = append(appControls, shortcut)
+		case "Dashboard Actions":
+			dashboardActions = append(dashboardActions, shortcut)
 		case "Study Actions":
 			studyActions = append(studyActions, shortcut)
+		case "Checklist Actions":
+			checklistActions = append(checklistActions, shortcut)
+		case "Weight & Balance Actions":
+			wbActions = append(wbActions, shortcut)
 		}
 	}
 
 	return []HelpSection{
 		{Title: "Global Navigation", Shortcuts: globalNavigation},
 		{Title: "App Controls", Shortcuts: appControls},
+		{Title: "Dashboard Actions", Shortcuts: dashboardActions},
 		{Title: "Study Actions", Shortcuts: studyActions},
+		{Title: "Checklist Actions", Shortcuts: checklistActions},
+		{Title: "Weight & Balance Actions", Shortcuts: wbActions},
 	}
 }
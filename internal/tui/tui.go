@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"ppl-study-planner/internal/db"
-	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
 	"ppl-study-planner/internal/styles"
 	"ppl-study-planner/internal/view"
 
@@ -24,6 +25,7 @@ const (
 	ScreenProgress
 	ScreenBudget
 	ScreenChecklist
+	ScreenWeightBalance
 )
 
 // MainModel contains all application state
@@ -38,6 +40,7 @@ type MainModel struct {
 	dashboardView *view.DashboardView
 	checklistView *view.ChecklistView
 	budgetView    *view.BudgetView
+	wbView        *view.WeightBalanceView
 }
 
 // New creates a new TUI model
@@ -57,44 +60,51 @@ func New() (*MainModel, error) {
 		dashboardView: view.NewDashboardView(database),
 		checklistView: view.NewChecklistView(database),
 		budgetView:    view.NewBudgetView(database),
+		wbView:        view.NewWeightBalanceView(),
 	}, nil
 }
 
 // Init implements tea.Model
 func (m MainModel) Init() tea.Cmd {
-	// Seed checklist items if empty
-	var count int64
-	m.db.Model(&model.ChecklistItem{}).Count(&count)
-	if count == 0 {
-		checklistItems := []model.ChecklistItem{
-			{Category: model.CategoryDocuments, Title: "Pilot certificate (Airplane category)"},
-			{Category: model.CategoryDocuments, Title: "Medical certificate (Class 3 or higher)"},
-			{Category: model.CategoryDocuments, Title: "Logbook with required entries"},
-			{Category: model.CategoryDocuments, Title: "Form AC 61-91 (if using simulator)"},
-			{Category: model.CategoryAircraft, Title: "Airworthiness certificate"},
-			{Category: model.CategoryAircraft, Title: "Registration certificate"},
-			{Category: model.CategoryAircraft, Title: "Operating limitations"},
-			{Category: model.CategoryAircraft, Title: "Weight and balance report"},
-			{Category: model.CategoryAircraft, Title: "Maintenance logs"},
-			{Category: model.CategoryGround, Title: "Charts and publications"},
-			{Category: model.CategoryGround, Title: "Flight planner"},
-			{Category: model.CategoryGround, Title: "Weather briefing documentation"},
-			{Category: model.CategoryFlight, Title: "Pre-solo knowledge test passed"},
-			{Category: model.CategoryFlight, Title: "Solo endorsements (3 takeoffs/landings)"},
-			{Category: model.CategoryFlight, Title: "Cross-country endorsements"},
-			{Category: model.CategoryFlight, Title: "Night endorsement"},
-			{Category: model.CategoryFlight, Title: "Instrument proficiency"},
-		}
-		m.db.Create(&checklistItems)
+	// Seed the default checklist on first start and after template upgrades.
+	_ = services.SeedChecklist(m.db)
+
+	// Endorsements and inspections lapse with time, so refresh the items
+	// they drive on start.
+	_ = services.RefreshChecklist(m.db, time.Now())
+	_, _ = services.RecordProgressSnapshot(m.db, services.ExistingMOTDAttempts(), time.Now())
+	if m.checklistView != nil {
+		m.checklistView.Init()
 	}
 
-	return nil
+	var cmds []tea.Cmd
+	if m.dashboardView != nil {
+		cmds = append(cmds, m.dashboardView.Init())
+	}
+	if m.studyView != nil {
+		cmds = append(cmds, m.studyView.Init())
+	}
+	return tea.Batch(cmds...)
 }
 
 // Update implements tea.Model
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// While a checklist item is being typed, keys are text, not shortcuts.
+		if m.currentScreen == ScreenChecklist && m.checklistView != nil && m.checklistView.Editing() && msg.String() != "ctrl+c" {
+			updated, cmd := m.checklistView.Update(msg)
+			m.checklistView = updated.(*view.ChecklistView)
+			return m, cmd
+		}
+
+		// Likewise while the checkride date or a task is being typed on the Study screen.
+		if m.currentScreen == ScreenStudyPlan && m.studyView != nil && m.studyView.Editing() && msg.String() != "ctrl+c" {
+			updated, cmd := m.studyView.Update(msg)
+			m.studyView = updated.(*view.StudyView)
+			return m, cmd
+		}
+
 		if msg.String() == "?" || msg.String() == "f1" {
 			m.helpVisible = !m.helpVisible
 			return m, nil
@@ -123,6 +133,8 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.currentScreen = ScreenBudget
 		case "5":
 			m.currentScreen = ScreenChecklist
+		case "6":
+			m.currentScreen = ScreenWeightBalance
 		}
 
 		// Route messages to dashboard view when on dashboard screen
@@ -159,6 +171,29 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		// Route messages to the weight and balance worksheet
+		if m.currentScreen == ScreenWeightBalance && m.wbView != nil {
+			updated, cmd := m.wbView.Update(msg)
+			m.wbView = updated.(*view.WeightBalanceView)
+			return m, cmd
+		}
+
+	case view.WeatherMsg:
+		// Weather refreshes in the background whichever screen is showing.
+		if m.dashboardView != nil {
+			updated, cmd := m.dashboardView.Update(msg)
+			m.dashboardView = updated.(*view.DashboardView)
+			return m, cmd
+		}
+
+	case view.TimerTickMsg:
+		// The focus timer keeps running, and a Pomodoro ends, on any screen.
+		if m.studyView != nil {
+			updated, cmd := m.studyView.Update(msg)
+			m.studyView = updated.(*view.StudyView)
+			return m, cmd
+		}
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -171,7 +206,7 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m MainModel) View() string {
 	header := renderHeader(m.currentScreen)
 	footer := renderFooter()
-	content := renderContent(m.currentScreen, m.width, m.height, m.dashboardView, m.studyView, m.progressView, m.budgetView, m.checklistView)
+	content := renderContent(m.currentScreen, m.width, m.height, m.dashboardView, m.studyView, m.progressView, m.budgetView, m.checklistView, m.wbView)
 	if m.helpVisible {
 		content = renderHelpOverlay(m.currentScreen)
 	}
@@ -216,7 +251,7 @@ func renderHelpOverlay(screen Screen) string {
 	return styles.HighlightBox.Width(76).Render(b.String())
 }
 
-func renderContent(screen Screen, width, height int, dashboardView *view.DashboardView, studyView *view.StudyView, progressView *view.ProgressView, budgetView *view.BudgetView, checklistView *view.ChecklistView) string {
+func renderContent(screen Screen, width, height int, dashboardView *view.DashboardView, studyView *view.StudyView, progressView *view.ProgressView, budgetView *view.BudgetView, checklistView *view.ChecklistView, wbView *view.WeightBalanceView) string {
 	contentWidth := width - 4
 	contentHeight := height - 4
 
@@ -246,6 +281,11 @@ func renderContent(screen Screen, width, height int, dashboardView *view.Dashboa
 			return checklistView.View()
 		}
 		return renderChecklist(contentWidth, contentHeight)
+	case ScreenWeightBalance:
+		if wbView != nil {
+			return wbView.View()
+		}
+		return ""
 	default:
 		return ""
 	}
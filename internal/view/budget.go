@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 
 	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
 	"ppl-study-planner/internal/styles"
 )
 
@@ -38,6 +39,7 @@ type BudgetView struct {
 	width         int
 	height        int
 	db            interface{}
+	saveErr       error
 }
 
 // BudgetField represents a field in the budget form
@@ -144,6 +146,9 @@ func (v *BudgetView) View() string {
   Reduce flight hours or living costs to stay within budget.`,
 			-costs.Remaining))
 	}
+	if v.saveErr != nil {
+		warning += styles.ErrorStyle.Render("\n  Budget not saved: " + v.saveErr.Error())
+	}
 
 	// Help
 	help := styles.Dim.Render(" [↑↓] Navigate fields | [←→] Adjust value | [Tab] Next field")
@@ -397,24 +402,22 @@ func (v *BudgetView) saveBudget() {
 		return
 	}
 
-	// Save plane rate
-	gormDb.Save(&model.Budget{
-		ItemType: model.BudgetPlaneRate,
-		Amount:   v.PlaneRate,
-	})
-
-	// Save CFI rate
-	gormDb.Save(&model.Budget{
-		ItemType: model.BudgetCfiRate,
-		Amount:   v.CfiRate,
-	})
-
-	// Save living costs (as total for now)
+	// Living costs are saved as a total for now.
 	livingTotal := v.TravelCost + v.RentCost + v.FoodCost + v.CarCost
-	gormDb.Save(&model.Budget{
-		ItemType: model.BudgetLiving,
-		Amount:   livingTotal,
-	})
+	v.saveErr = nil
+	for _, item := range []struct {
+		itemType model.BudgetItemType
+		amount   float64
+	}{
+		{model.BudgetPlaneRate, v.PlaneRate},
+		{model.BudgetCfiRate, v.CfiRate},
+		{model.BudgetLiving, livingTotal},
+	} {
+		if err := services.SaveBudgetItem(gormDb, item.itemType, item.amount, "tui"); err != nil {
+			v.saveErr = err
+			return
+		}
+	}
 }
 
 // SetRates sets the flight rates
@@ -8,6 +8,7 @@ import (
 	"gorm.io/gorm"
 
 	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
 	"ppl-study-planner/internal/styles"
 )
 
@@ -19,6 +20,13 @@ type ChecklistView struct {
 	width         int
 	height        int
 	db            interface{}
+
+	// Custom item editing: editMode is "", "add", "edit" or "delete".
+	editMode      string
+	editID        uint
+	input         string
+	inputCategory int
+	status        string
 }
 
 // categories represents the filter categories
@@ -38,17 +46,40 @@ func NewChecklistView(db interface{}) *ChecklistView {
 
 // Init loads the checklist items from database
 func (v *ChecklistView) Init() tea.Cmd {
-	// Load items from database
-	if gormDb, ok := v.db.(*gorm.DB); ok {
-		gormDb.Find(&v.items)
-	}
+	v.reload()
 	return nil
 }
 
+// Editing reports whether the view is capturing typed text, so global
+// shortcuts should be passed through to it.
+func (v *ChecklistView) Editing() bool {
+	return v.editMode != ""
+}
+
+func (v *ChecklistView) reload() {
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
+		return
+	}
+	items, err := services.ListChecklistItems(gormDb)
+	if err != nil {
+		v.status = err.Error()
+		return
+	}
+	v.items = items
+	if filtered := v.getFilteredItems(); v.selectedIndex >= len(filtered) {
+		v.selectedIndex = max(len(filtered)-1, 0)
+	}
+}
+
 // Update handles checklist updates
 func (v *ChecklistView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if v.editMode != "" {
+			return v.handleEdit(msg)
+		}
+		v.status = ""
 		switch msg.String() {
 		case "up", "k":
 			if v.selectedIndex > 0 {
@@ -64,6 +95,26 @@ func (v *ChecklistView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "tab":
 			v.categoryIndex = (v.categoryIndex + 1) % len(categories)
 			v.selectedIndex = 0
+		case "a":
+			v.editMode = "add"
+			v.input = ""
+			v.inputCategory = 0
+			if v.categoryIndex > 0 {
+				v.inputCategory = v.categoryIndex - 1
+			}
+		case "e":
+			if item, ok := v.selectedCustomItem(); ok {
+				v.editMode = "edit"
+				v.editID = item.ID
+				v.input = item.Title
+				v.inputCategory = checklistCategoryIndex(item.Category)
+			}
+		case "d", "x":
+			if item, ok := v.selectedCustomItem(); ok {
+				v.editMode = "delete"
+				v.editID = item.ID
+				v.input = item.Title
+			}
 		}
 	case tea.WindowSizeMsg:
 		v.width = msg.Width
@@ -108,7 +159,20 @@ func (v *ChecklistView) View() string {
 	items := v.renderItems(filtered)
 
 	// Footer help
-	footer := styles.Dim.Render(" [↑↓] Navigate | [Enter/Space] Toggle | [Tab] Filter by category")
+	footer := styles.Dim.Render(" [↑↓] Navigate | [Enter/Space] Toggle | [Tab] Filter by category | [a] Add | [e] Edit | [d] Delete custom item")
+	switch {
+	case v.editMode == "delete":
+		footer = styles.WarningStyle.Render(fmt.Sprintf(" Delete %q? [y] Yes | [n/Esc] No", v.input))
+	case v.editMode != "":
+		label := "New item"
+		if v.editMode == "edit" {
+			label = "Edit item"
+		}
+		footer = fmt.Sprintf(" %s [%s]: %s\n%s", label, services.ChecklistCategories[v.inputCategory], v.input+"_",
+			styles.Dim.Render(" [Enter] Save | [Tab] Change category | [Esc] Cancel"))
+	case v.status != "":
+		footer = styles.WarningStyle.Render(" "+v.status) + "\n" + footer
+	}
 
 	content := fmt.Sprintf(`%s
 
@@ -166,9 +230,11 @@ func (v *ChecklistView) renderItems(items []model.ChecklistItem) string {
 		}
 
 		line := fmt.Sprintf("  %s %s", checkbox, item.Title)
+		if item.TemplateKey == "" {
+			line += styles.Dim.Render(" (custom)")
+		}
 
-		if i == v.selectedIndex && v.categoryIndex > 0 ||
-			(v.categoryIndex == 0 && isSelectedCategory(item.Category, items, v.selectedIndex)) {
+		if i == v.selectedIndex {
 			line = styles.Selected.Render("▶ " + strings.TrimPrefix(line, "  "))
 		}
 
@@ -182,20 +248,6 @@ func (v *ChecklistView) renderItems(items []model.ChecklistItem) string {
 	return strings.Join(lines, "\n")
 }
 
-func isSelectedCategory(cat model.ChecklistCategory, items []model.ChecklistItem, selectedIdx int) bool {
-	// Find the actual index considering category headers
-	idx := 0
-	for _, item := range items {
-		if idx == selectedIdx {
-			return item.Category == cat
-		}
-		if item.Category == cat {
-			idx++
-		}
-	}
-	return false
-}
-
 func (v *ChecklistView) getFilteredItems() []model.ChecklistItem {
 	if v.categoryIndex == 0 {
 		return v.items
@@ -213,10 +265,106 @@ func (v *ChecklistView) getFilteredItems() []model.ChecklistItem {
 
 func (v *ChecklistView) toggleItem() {
 	filtered := v.getFilteredItems()
-	if v.selectedIndex >= 0 && v.selectedIndex < len(filtered) {
+	if v.selectedIndex < 0 || v.selectedIndex >= len(filtered) {
+		return
+	}
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
 		filtered[v.selectedIndex].Completed = !filtered[v.selectedIndex].Completed
-		// Save to database: v.db.Save(&filtered[v.selectedIndex])
+		return
+	}
+	if _, err := services.ToggleChecklistItem(gormDb, filtered[v.selectedIndex].ID); err != nil {
+		v.status = err.Error()
+	}
+	v.reload()
+}
+
+func (v *ChecklistView) selectedCustomItem() (model.ChecklistItem, bool) {
+	filtered := v.getFilteredItems()
+	if v.selectedIndex < 0 || v.selectedIndex >= len(filtered) {
+		return model.ChecklistItem{}, false
+	}
+	item := filtered[v.selectedIndex]
+	if item.TemplateKey != "" {
+		v.status = "Default checklist items can only be toggled; add a custom item with [a]."
+		return model.ChecklistItem{}, false
+	}
+	return item, true
+}
+
+// handleEdit handles typing while adding, editing or deleting a custom item.
+func (v *ChecklistView) handleEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if v.editMode == "delete" {
+		switch msg.String() {
+		case "y", "Y":
+			v.saveEdit()
+		case "n", "N", "esc":
+			v.cancelEdit()
+		}
+		return v, nil
+	}
+
+	switch msg.String() {
+	case "enter":
+		v.saveEdit()
+	case "esc":
+		v.cancelEdit()
+	case "tab":
+		v.inputCategory = (v.inputCategory + 1) % len(services.ChecklistCategories)
+	case "backspace":
+		if runes := []rune(v.input); len(runes) > 0 {
+			v.input = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			v.input += string(msg.Runes)
+		}
+	}
+	return v, nil
+}
+
+func (v *ChecklistView) saveEdit() {
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
+		v.cancelEdit()
+		return
+	}
+
+	category := string(services.ChecklistCategories[v.inputCategory])
+	var err error
+	switch v.editMode {
+	case "add":
+		_, err = services.AddChecklistItem(gormDb, category, v.input)
+	case "edit":
+		_, err = services.UpdateChecklistItem(gormDb, v.editID, category, v.input)
+	case "delete":
+		err = services.DeleteChecklistItem(gormDb, v.editID)
+	}
+	if err != nil && v.editMode != "delete" {
+		// Keep the input so the title can be fixed.
+		v.status = err.Error()
+		return
+	}
+	v.cancelEdit()
+	if err != nil {
+		v.status = err.Error()
+	}
+	v.reload()
+}
+
+func (v *ChecklistView) cancelEdit() {
+	v.editMode = ""
+	v.editID = 0
+	v.input = ""
+}
+
+func checklistCategoryIndex(category model.ChecklistCategory) int {
+	for i, c := range services.ChecklistCategories {
+		if c == category {
+			return i
+		}
 	}
+	return 0
 }
 
 type categoryStats struct {
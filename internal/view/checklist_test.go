@@ -0,0 +1,75 @@
+package view
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestChecklistViewAddsEditsAndDeletesCustomItems(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ChecklistItem{}, &model.AppConfig{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if err := services.SeedChecklist(db); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	v := NewChecklistView(db)
+	v.Init()
+	press := func(keys ...string) {
+		for _, key := range keys {
+			var msg tea.KeyMsg
+			switch key {
+			case "enter", "tab", "backspace", "esc":
+				msg = tea.KeyMsg{Type: map[string]tea.KeyType{"enter": tea.KeyEnter, "tab": tea.KeyTab, "backspace": tea.KeyBackspace, "esc": tea.KeyEsc}[key]}
+			default:
+				msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+			}
+			v.Update(msg)
+		}
+	}
+
+	// Filter to Flight, then add "Squawk 1200" there.
+	press("tab", "tab", "tab", "tab", "a")
+	if !v.Editing() {
+		t.Fatal("expected a to start adding an item")
+	}
+	press("S", "q", "u", "a", "w", "k", " ", "1", "2", "0", "0", "enter")
+	if v.Editing() {
+		t.Fatalf("expected enter to save, status %q", v.status)
+	}
+	var item model.ChecklistItem
+	if err := db.Where("title = ?", "Squawk 1200").First(&item).Error; err != nil || item.Category != model.CategoryFlight {
+		t.Fatalf("expected custom Flight item, got %+v (%v)", item, err)
+	}
+
+	// Default items cannot be edited.
+	v.selectedIndex = 0
+	press("e")
+	if v.Editing() || v.status == "" {
+		t.Fatal("expected editing a default item to be refused with a status message")
+	}
+
+	filtered := v.getFilteredItems()
+	v.selectedIndex = len(filtered) - 1
+	press("e", "backspace", "backspace", "backspace", "backspace", "7", "7", "0", "0", "enter")
+	db.First(&item, item.ID)
+	if item.Title != "Squawk 7700" {
+		t.Fatalf("expected edited title, got %q", item.Title)
+	}
+
+	press("d", "y")
+	if err := db.First(&model.ChecklistItem{}, item.ID).Error; err == nil {
+		t.Fatal("expected custom item to be deleted")
+	}
+}
@@ -1,13 +1,16 @@
 package view
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"gorm.io/gorm"
 
 	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
 	"ppl-study-planner/internal/styles"
 )
 
@@ -16,13 +19,53 @@ type DashboardView struct {
 	db            interface{}
 	checkrideDate time.Time
 	stats         DashboardStats
+	milestones    []services.MilestoneStatus
+	readiness     services.Readiness
+	quizAttempts  []services.MOTDAnswer
+	quizLoaded    bool
+	warnings      []string
+	weather       services.WeatherProvider
+	weatherBrief  services.WeatherBrief
+	proposal      services.RescheduleProposal
+	message       string
 	width         int
 	height        int
 }
 
+// weatherRefreshInterval is how often the dashboard refetches the school
+// airport's METAR and TAF.
+const weatherRefreshInterval = 10 * time.Minute
+
+// WeatherMsg carries a freshly loaded weather brief and reschedule proposal
+// to the dashboard.
+type WeatherMsg struct {
+	Brief    services.WeatherBrief
+	Proposal services.RescheduleProposal
+
+	// scheduled marks loads from the refresh loop, which schedule the next.
+	scheduled bool
+}
+
 // Init implements tea.Model
 func (v *DashboardView) Init() tea.Cmd {
-	return nil
+	return v.weatherCmd(true)
+}
+
+// weatherCmd fetches the school airport weather off the UI goroutine and
+// checks pending flights against the personal minimums.
+func (v *DashboardView) weatherCmd(scheduled bool) tea.Cmd {
+	return func() tea.Msg {
+		gormDb, ok := v.db.(*gorm.DB)
+		if !ok || v.weather == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		brief, _ := services.LoadWeatherBrief(ctx, gormDb, v.weather, time.Now())
+		minimums, _ := services.LoadPersonalMinimums()
+		proposal, _ := services.ProposeWeatherReschedule(ctx, gormDb, v.weather, minimums, time.Now())
+		return WeatherMsg{Brief: brief, Proposal: proposal, scheduled: scheduled}
+	}
 }
 
 // DashboardStats holds the dashboard statistics
@@ -39,16 +82,33 @@ type DashboardStats struct {
 // NewDashboardView creates a new dashboard view
 func NewDashboardView(db interface{}) *DashboardView {
 	return &DashboardView{
-		db:     db,
-		stats:  DashboardStats{WeekTasks: make(map[string]int)},
-		width:  80,
-		height: 24,
+		db:      db,
+		stats:   DashboardStats{WeekTasks: make(map[string]int)},
+		weather: services.DefaultWeatherProvider(),
+		width:   80,
+		height:  24,
 	}
 }
 
 // Update handles dashboard updates
 func (v *DashboardView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "m":
+			v.markNextMilestone()
+		case "M":
+			v.reopenLastMilestone()
+		case "w":
+			return v, v.applyReschedule()
+		}
+	case WeatherMsg:
+		v.weatherBrief = msg.Brief
+		v.proposal = msg.Proposal
+		if !msg.scheduled {
+			return v, nil
+		}
+		return v, tea.Tick(weatherRefreshInterval, func(time.Time) tea.Msg { return v.weatherCmd(true)() })
 	case tea.WindowSizeMsg:
 		v.width = msg.Width
 		v.height = msg.Height
@@ -56,6 +116,74 @@ func (v *DashboardView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return v, nil
 }
 
+// markNextMilestone marks the first ready milestone achieved.
+func (v *DashboardView) markNextMilestone() {
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
+		return
+	}
+	for _, milestone := range v.milestones {
+		if milestone.State != services.MilestoneStateReady {
+			continue
+		}
+		if err := services.SetMilestoneAchieved(gormDb, milestone.ID, true, "tui", time.Now()); err != nil {
+			v.message = fmt.Sprintf("Could not update milestone: %v", err)
+			return
+		}
+		v.message = fmt.Sprintf("Milestone achieved: %s", milestone.Name)
+		return
+	}
+	if next, ok := services.NextMilestone(v.milestones); ok {
+		v.message = fmt.Sprintf("Next milestone %q is not ready: %s", next.Name, next.Blockers())
+		return
+	}
+	v.message = "All milestones achieved"
+}
+
+// applyReschedule applies the previewed weather swaps, then reloads the
+// proposal.
+func (v *DashboardView) applyReschedule() tea.Cmd {
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
+		return nil
+	}
+	if len(v.proposal.Swaps) == 0 {
+		v.message = "No weather swaps to apply"
+		return nil
+	}
+	if err := services.ApplyWeatherReschedule(gormDb, v.proposal.Swaps, "tui"); err != nil {
+		v.message = fmt.Sprintf("Could not reschedule: %v", err)
+		return v.weatherCmd(false)
+	}
+	v.message = fmt.Sprintf("Rescheduled %d flight(s) around the weather", len(v.proposal.Swaps))
+	v.proposal = services.RescheduleProposal{}
+	return v.weatherCmd(false)
+}
+
+// reopenLastMilestone undoes the most recently achieved milestone.
+func (v *DashboardView) reopenLastMilestone() {
+	gormDb, ok := v.db.(*gorm.DB)
+	if !ok {
+		return
+	}
+	var last *services.MilestoneStatus
+	for i := range v.milestones {
+		milestone := &v.milestones[i]
+		if milestone.AchievedAt != nil && (last == nil || milestone.AchievedAt.After(*last.AchievedAt)) {
+			last = milestone
+		}
+	}
+	if last == nil {
+		v.message = "No achieved milestones to reopen"
+		return
+	}
+	if err := services.SetMilestoneAchieved(gormDb, last.ID, false, "tui", time.Now()); err != nil {
+		v.message = fmt.Sprintf("Could not update milestone: %v", err)
+		return
+	}
+	v.message = fmt.Sprintf("Milestone reopened: %s", last.Name)
+}
+
 // View renders the dashboard
 func (v *DashboardView) View() string {
 	// Query stats from database if we have a DB connection
@@ -95,24 +223,36 @@ func (v *DashboardView) View() string {
 	content := fmt.Sprintf(`%s
 
 %s Days until checkride: %s
+%s%s
 
 %s Overall Progress
 %s
 
+%s Checkride Readiness
+%s
+
 %s Quick Stats
 %s
 
 %s Upcoming Week
+%s
+%s Milestones
 %s`,
 		styles.Title.Render("Dashboard"),
 		daysStyle.Render("📅"),
 		daysStyle.Render(fmt.Sprintf("%d days", daysUntil)),
+		v.renderWeather()+v.renderReschedule(),
+		v.renderWarnings(),
 		styles.Normal.Render("Progress"),
 		progressBar,
+		styles.Normal.Render("🎯"),
+		v.renderReadiness(),
 		styles.Normal.Render("Quick Stats"),
 		stats,
 		styles.Normal.Render("Upcoming Week"),
 		weekTasks,
+		styles.Normal.Render("🏁"),
+		v.renderMilestones(),
 	)
 
 	return box.Render(content)
@@ -134,6 +274,33 @@ func (v *DashboardView) renderProgressBar(percent float64) string {
 	return fmt.Sprintf("  [%s] %d%%", bar, int(percent))
 }
 
+// renderReadiness shows the combined readiness score, each factor's score
+// and the top next actions.
+func (v *DashboardView) renderReadiness() string {
+	readiness := v.readiness
+	scoreStyle := styles.ErrorStyle
+	switch {
+	case readiness.Score >= 85:
+		scoreStyle = styles.Success
+	case readiness.Score >= 60:
+		scoreStyle = styles.Normal
+	}
+	result := "  " + scoreStyle.Render(fmt.Sprintf("%.0f/100 %s", readiness.Score, readiness.Label))
+
+	parts := make([]string, 0, len(readiness.Factors))
+	for _, factor := range readiness.Factors {
+		parts = append(parts, fmt.Sprintf("%s %.0f", factor.Name, factor.Score))
+	}
+	result += "\n" + styles.Dim.Render("  "+strings.Join(parts, " · "))
+	for i, action := range readiness.NextActions {
+		if i == 3 {
+			break
+		}
+		result += "\n" + styles.Normal.Render("  → "+action)
+	}
+	return result
+}
+
 func (v *DashboardView) renderWeekTasks() string {
 	if len(v.stats.WeekTasks) == 0 {
 		return styles.Dim.Render("  No tasks scheduled for this week")
@@ -160,6 +327,90 @@ func (v *DashboardView) renderWeekTasks() string {
 	return result
 }
 
+func (v *DashboardView) renderWeather() string {
+	summary := v.weatherBrief.Summary()
+	if summary == "" {
+		return ""
+	}
+	line := "\n  ☁ " + summary
+	if v.weatherBrief.METAR == nil {
+		return styles.Dim.Render(line)
+	}
+	switch v.weatherBrief.METAR.Category {
+	case services.FlightCategoryVFR:
+		return styles.Success.Render(line)
+	case services.FlightCategoryMVFR:
+		return styles.WarningStyle.Render(line)
+	case services.FlightCategoryIFR, services.FlightCategoryLIFR:
+		return styles.ErrorStyle.Render(line)
+	}
+	return styles.Normal.Render(line)
+}
+
+// renderReschedule previews the proposed weather swaps.
+func (v *DashboardView) renderReschedule() string {
+	if len(v.proposal.Swaps) == 0 && len(v.proposal.Unresolved) == 0 {
+		return ""
+	}
+	result := "\n" + styles.WarningStyle.Render("  ↻ Below your minimums at "+v.proposal.Station+":")
+	for _, swap := range v.proposal.Swaps {
+		result += "\n" + styles.Normal.Render("    "+swap.Describe()) +
+			styles.Dim.Render(" ("+strings.Join(swap.Reasons, ", ")+")")
+	}
+	for _, blocked := range v.proposal.Unresolved {
+		result += "\n" + styles.ErrorStyle.Render(fmt.Sprintf("    %s %s: no ground task nearby to swap", blocked.Task.Title, blocked.Task.Date.Format("Jan 2"))) +
+			styles.Dim.Render(" ("+strings.Join(blocked.Reasons, ", ")+")")
+	}
+	if len(v.proposal.Swaps) > 0 {
+		result += "\n" + styles.Dim.Render("  [w] apply swaps")
+	}
+	return result
+}
+
+func (v *DashboardView) renderWarnings() string {
+	result := ""
+	for _, warning := range append(append([]string{}, v.warnings...), v.weatherBrief.Warnings()...) {
+		result += "\n" + styles.ErrorStyle.Render("  ⚠ "+warning)
+	}
+	return result
+}
+
+func (v *DashboardView) renderMilestones() string {
+	if len(v.milestones) == 0 {
+		return styles.Dim.Render("  No milestones in the plan template")
+	}
+
+	result := ""
+	for _, milestone := range v.milestones {
+		target := ""
+		if !milestone.TargetDate.IsZero() {
+			target = " · target " + milestone.TargetDate.Format("Jan 2")
+		}
+
+		var line string
+		switch milestone.State {
+		case services.MilestoneStateAchieved:
+			line = styles.Success.Render(fmt.Sprintf("  ✓ %s", milestone.Name)) +
+				styles.Dim.Render(" · achieved "+milestone.AchievedAt.Local().Format("Jan 2"))
+		case services.MilestoneStateReady:
+			line = styles.Normal.Render(fmt.Sprintf("  ● %s", milestone.Name)) +
+				styles.Success.Render(" · ready") + styles.Dim.Render(target)
+		default:
+			line = styles.Normal.Render(fmt.Sprintf("  ○ %s", milestone.Name)) +
+				styles.Dim.Render(" · "+milestone.Blockers()+target)
+		}
+		if milestone.Overdue {
+			line += styles.ErrorStyle.Render(" · overdue")
+		}
+		result += line + "\n"
+	}
+	result += styles.Dim.Render("  [m] mark next ready milestone achieved  [M] reopen last")
+	if v.message != "" {
+		result += "\n  " + styles.Normal.Render(v.message)
+	}
+	return result
+}
+
 func (v *DashboardView) refreshStats(db interface{}) {
 	// Type assert to GORM DB
 	gormDb, ok := db.(*gorm.DB)
@@ -220,6 +471,19 @@ func (v *DashboardView) refreshStats(db interface{}) {
 		dateKey := task.Date.Format("01/02")
 		v.stats.WeekTasks[dateKey] = int(task.Count)
 	}
+
+	if milestones, err := services.LoadMilestoneStatuses(gormDb, time.Now()); err == nil {
+		v.milestones = milestones
+	}
+	v.warnings = services.DashboardWarnings(gormDb, time.Now())
+
+	// Quiz answers live in the MOTD database and only change from the
+	// command line, so they are read once.
+	if !v.quizLoaded {
+		v.quizAttempts = services.ExistingMOTDAttempts()
+		v.quizLoaded = true
+	}
+	v.readiness = services.LoadReadiness(gormDb, v.quizAttempts, time.Now())
 }
 
 // SetCheckrideDate sets the checkride date for the dashboard
@@ -0,0 +1,71 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestDashboardPreviewsAndAppliesWeatherSwaps(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	flight := model.DailyTask{Date: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Pattern work"}
+	ground := model.DailyTask{Date: time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Area 2"}
+	db.Create(&flight)
+	db.Create(&ground)
+
+	v := NewDashboardView(db)
+	v.weather = nil
+	v.Update(WeatherMsg{Proposal: services.RescheduleProposal{
+		Station: "KFXE",
+		Swaps:   []services.FlightSwap{{Flight: flight, Ground: ground, Reasons: []string{"TEMPO ceiling 800 ft below 3000 ft"}}},
+	}})
+	if preview := v.renderReschedule(); !strings.Contains(preview, "Pattern work Jun 1 -> Jun 2") || !strings.Contains(preview, "[w] apply swaps") {
+		t.Fatalf("expected a swap preview, got %q", preview)
+	}
+
+	v.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	var moved model.DailyTask
+	db.First(&moved, flight.ID)
+	if !moved.Date.Equal(ground.Date) {
+		t.Fatalf("expected the flight moved to Jun 2, got %s", moved.Date)
+	}
+	if !strings.Contains(v.message, "Rescheduled 1 flight") || v.renderReschedule() != "" {
+		t.Fatalf("expected the proposal cleared after applying, got %q / %q", v.message, v.renderReschedule())
+	}
+}
+
+func TestDashboardShowsReadiness(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudyPlan{}, &model.ChecklistItem{}, &model.LogbookEntry{}, &model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	plan := model.StudyPlan{CheckrideDate: time.Now().AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Now().AddDate(0, 0, 1), Category: "Theory", Title: "Airspace", Completed: true})
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Now().AddDate(0, 0, 2), Category: "Theory", Title: "Weather"})
+
+	v := NewDashboardView(db)
+	v.quizLoaded = true
+	v.refreshStats(db)
+	readiness := v.renderReadiness()
+	if !strings.Contains(readiness, "15/100 Needs work") || !strings.Contains(readiness, "Study plan 50") || !strings.Contains(readiness, "→ Work on Theory: 1 of 2 tasks done") {
+		t.Fatalf("expected the readiness breakdown, got %q", readiness)
+	}
+}
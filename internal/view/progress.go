@@ -20,8 +20,25 @@ type ProgressView struct {
 	tasks          []model.DailyTask
 	overallPercent float64
 	byCategory     map[string]services.ProgressStats
+	checkrideDate  time.Time
+	snapshots      []model.ProgressSnapshot
+	history        services.CompletionHistory
+	entries        []model.Progress
+	streaks        services.StudyStreaks
+	quizAttempts   []services.MOTDAnswer
+	quizLoaded     bool
+	studyTime      services.StudyTimeReport
 }
 
+// trendDays is how much snapshot history the trend sparklines cover.
+const trendDays = 30
+
+// sparkBlocks are the sparkline levels, lowest first.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// heatBlocks are the streak heatmap cells for activity levels 1-4.
+var heatBlocks = []string{"░", "▒", "▓", "█"}
+
 // NewProgressView creates a new progress view
 func NewProgressView(db *gorm.DB) *ProgressView {
 	pv := &ProgressView{db: db}
@@ -36,6 +53,22 @@ func (pv *ProgressView) loadData() {
 		pv.tasks = plan.DailyTasks
 		_, _, pv.overallPercent = services.CalculateProgress(plan.DailyTasks)
 		pv.byCategory = services.GetProgressByCategory(plan.DailyTasks)
+		pv.checkrideDate = plan.CheckrideDate
+	}
+	if snapshots, err := services.ListProgressSnapshots(pv.db, time.Now().AddDate(0, 0, -trendDays)); err == nil {
+		pv.snapshots = snapshots
+	}
+	if entries, err := services.ListTaskProgress(pv.db); err == nil {
+		pv.entries = entries
+		pv.history = services.SummarizeCompletionHistory(entries, time.Now())
+	}
+	if !pv.quizLoaded {
+		pv.quizAttempts = services.ExistingMOTDAttempts()
+		pv.quizLoaded = true
+	}
+	pv.streaks = services.ComputeStudyStreaks(pv.entries, pv.quizAttempts, time.Now(), services.StreakWeeks)
+	if report, err := services.LoadStudyTimeReport(pv.db); err == nil {
+		pv.studyTime = report
 	}
 }
 
@@ -71,7 +104,7 @@ func (pv *ProgressView) View() string {
 	b.WriteString(styles.Normal.Render("By Category:"))
 	b.WriteString("\n")
 
-	cats := []string{"Theory", "Chair Flying", "Garmin 430", "CFI Flights"}
+	cats := services.ProgressCategoryOrder(pv.byCategory)
 	for i, cat := range cats {
 		stats := pv.byCategory[cat]
 		percent := stats.CalculatePercentage()
@@ -81,6 +114,14 @@ func (pv *ProgressView) View() string {
 		b.WriteString(fmt.Sprintf(" %d/%d (%.0f%%)\n", stats.Completed, stats.Total, percent))
 	}
 
+	b.WriteString("\n")
+	b.WriteString(pv.renderTrend())
+	b.WriteString("\n")
+	b.WriteString(pv.renderStreaks())
+	b.WriteString("\n")
+	b.WriteString(pv.renderHabits())
+	b.WriteString("\n")
+	b.WriteString(pv.renderStudyTime())
 	b.WriteString("\n")
 
 	// Today's tasks
@@ -129,6 +170,158 @@ func (pv *ProgressView) renderProgressBar(percent float64) string {
 	return styles.ProgressBar.Render(bar[:filled]) + styles.ProgressBarEmpty.Render(bar[filled:])
 }
 
+// renderTrend shows sparklines of the daily snapshots and the projected
+// completion date.
+func (pv *ProgressView) renderTrend() string {
+	var b strings.Builder
+	b.WriteString(styles.Normal.Render(fmt.Sprintf("Trend (last %d days):", trendDays)))
+	b.WriteString("\n")
+	if len(pv.snapshots) < 2 {
+		b.WriteString(styles.Dim.Render("  The trend builds up from a daily snapshot each day openppl runs"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	first, last := pv.snapshots[0], pv.snapshots[len(pv.snapshots)-1]
+	progress := make([]float64, len(pv.snapshots))
+	readiness := make([]float64, len(pv.snapshots))
+	spent := make([]float64, len(pv.snapshots))
+	for i, snapshot := range pv.snapshots {
+		progress[i], readiness[i], spent[i] = snapshot.ProgressPercent, snapshot.ReadinessScore, snapshot.SpentUSD
+	}
+	b.WriteString(fmt.Sprintf("  Progress   %s %3.0f%% (%+.0f pts)\n", sparkline(progress, 0, 100), last.ProgressPercent, last.ProgressPercent-first.ProgressPercent))
+	b.WriteString(fmt.Sprintf("  Readiness  %s %3.0f (%+.0f pts)\n", sparkline(readiness, 0, 100), last.ReadinessScore, last.ReadinessScore-first.ReadinessScore))
+	b.WriteString(fmt.Sprintf("  Spend      %s $%.0f of $%.0f\n", sparkline(spent, 0, max(last.BudgetUSD, last.SpentUSD)), last.SpentUSD, last.BudgetUSD))
+
+	projection := services.ProjectCompletion(pv.snapshots)
+	text := projection.Describe(pv.checkrideDate)
+	if projection.OK && !pv.checkrideDate.IsZero() && projection.Date.After(pv.checkrideDate) {
+		b.WriteString(styles.ErrorStyle.Render("  " + text))
+	} else {
+		b.WriteString(styles.Dim.Render("  " + text))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderStreaks shows the study streak over tasks and quiz answers, the
+// weekly consistency and a heatmap of the last weeks, one column per week.
+func (pv *ProgressView) renderStreaks() string {
+	var b strings.Builder
+	st := pv.streaks
+	b.WriteString(styles.Normal.Render(fmt.Sprintf("Study Streak: %d days (longest %d)", st.Current, st.Longest)))
+	switch {
+	case st.Today:
+		b.WriteString(styles.Success.Render("  ✓ studied today"))
+	case st.Current > 0:
+		b.WriteString(styles.Dim.Render("  complete a task or answer the quiz today to keep it"))
+	}
+	b.WriteString("\n")
+
+	weekly := make([]float64, len(st.Weeks))
+	for i, week := range st.Weeks {
+		if week.Days > 0 {
+			weekly[i] = float64(week.ActiveDays) / float64(week.Days)
+		}
+	}
+	b.WriteString(fmt.Sprintf("  Weekly     %s %.0f%% of days, %d missed\n", sparkline(weekly, 0, 1), st.Consistency(), st.MissedDays))
+
+	for weekday, label := range []string{"Mon", "", "Wed", "", "Fri", "", "Sun"} {
+		b.WriteString(fmt.Sprintf("  %-4s", label))
+		for week := range st.Weeks {
+			i := week*7 + weekday
+			switch {
+			case i >= len(st.Days):
+				b.WriteString("  ")
+			case st.Days[i].Active():
+				b.WriteString(styles.ProgressBar.Render(heatBlocks[st.Days[i].Level()-1]) + " ")
+			case st.Days[i].Missed:
+				b.WriteString(styles.ErrorStyle.Render("·") + " ")
+			default:
+				b.WriteString(styles.Dim.Render("·") + " ")
+			}
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Dim.Render("      ░▒▓█ tasks + quiz   red · missed day"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderHabits shows on-time versus late completions and when in the day
+// tasks get done, from the completion history.
+func (pv *ProgressView) renderHabits() string {
+	var b strings.Builder
+	h := pv.history
+	if h.Completions == 0 {
+		b.WriteString(styles.Normal.Render("Study Habits:"))
+		b.WriteString("\n")
+		b.WriteString(styles.Dim.Render("  Completion history starts with the next task you check off"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(styles.Normal.Render(fmt.Sprintf("Study Habits (since %s):", h.Since.Format("Jan 2"))))
+	b.WriteString("\n")
+	timing := fmt.Sprintf("  On time    %d of %d", h.OnTime, h.Completions)
+	if h.Late > 0 {
+		timing += fmt.Sprintf(", %d late by %.1f days on average", h.Late, h.AverageDaysLate())
+	}
+	b.WriteString(timing + "\n")
+	for _, part := range services.TimesOfDay {
+		count := h.ByTimeOfDay[part]
+		width := count * 20 / h.Completions
+		b.WriteString(fmt.Sprintf("  %-10s %s %d\n", part, styles.ProgressBar.Render(strings.Repeat("█", width))+strings.Repeat(" ", 20-width), count))
+	}
+	if hour := h.BusiestHour(); hour >= 0 {
+		b.WriteString(styles.Dim.Render(fmt.Sprintf("  Most tasks get done around %02d:00", hour)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// renderStudyTime compares the hours timed on the Study screen with the
+// hours planned for the same tasks, by category and by ACS area.
+func (pv *ProgressView) renderStudyTime() string {
+	var b strings.Builder
+	report := pv.studyTime
+	if len(report.ByCategory) == 0 {
+		b.WriteString(styles.Normal.Render("Study Time:"))
+		b.WriteString("\n")
+		b.WriteString(styles.Dim.Render("  Time a task with [t] on the Study screen to compare actual and planned hours"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	b.WriteString(styles.Normal.Render(fmt.Sprintf("Study Time (%.1f h timed):", report.TotalMinutes/60)))
+	b.WriteString("\n")
+	for _, row := range report.ByCategory {
+		b.WriteString(fmt.Sprintf("  %-10s %5.1fh of %.1fh planned, %d tasks\n", row.Name, row.ActualMinutes/60, row.PlannedMinutes/60, row.Tasks))
+	}
+	if len(report.ByArea) > 0 {
+		areas := make([]string, len(report.ByArea))
+		for i, row := range report.ByArea {
+			areas[i] = fmt.Sprintf("%s %.1fh", row.Name, row.ActualMinutes/60)
+		}
+		b.WriteString(styles.Dim.Render("  ACS areas: " + strings.Join(areas, " · ")))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// sparkline draws values between low and high as one block per value.
+func sparkline(values []float64, low, high float64) string {
+	var b strings.Builder
+	for _, value := range values {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[min(max(level, 0), len(sparkBlocks)-1)])
+	}
+	return b.String()
+}
+
 // getTodayTasks returns tasks scheduled for today
 func (pv *ProgressView) getTodayTasks() []model.DailyTask {
 	now := time.Now()
@@ -144,16 +337,36 @@ func (pv *ProgressView) getTodayTasks() []model.DailyTask {
 	return result
 }
 
-// getRecentCompletions returns recently completed tasks
+// getRecentCompletions returns the last completed tasks, newest first, from
+// the completion history. Before there is any history it lists completed
+// tasks in plan order.
 func (pv *ProgressView) getRecentCompletions() []model.DailyTask {
 	var result []model.DailyTask
+	if len(pv.entries) == 0 {
+		for _, t := range pv.tasks {
+			if t.Completed {
+				result = append(result, t)
+				if len(result) >= 7 {
+					break
+				}
+			}
+		}
+		return result
+	}
+
+	byID := make(map[uint]model.DailyTask, len(pv.tasks))
 	for _, t := range pv.tasks {
-		if t.Completed {
-			// Check if completed recently (for now just show completed tasks)
+		byID[t.ID] = t
+	}
+	seen := map[uint]bool{}
+	for i := len(pv.entries) - 1; i >= 0 && len(result) < 7; i-- {
+		entry := pv.entries[i]
+		if seen[entry.DailyTaskID] {
+			continue
+		}
+		seen[entry.DailyTaskID] = true
+		if t, ok := byID[entry.DailyTaskID]; ok && entry.Completed && t.Completed {
 			result = append(result, t)
-			if len(result) >= 7 {
-				break
-			}
 		}
 	}
 	return result
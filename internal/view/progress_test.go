@@ -0,0 +1,121 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestSparklineScalesBetweenBounds(t *testing.T) {
+	if got := sparkline([]float64{0, 50, 100, 120}, 0, 100); got != "▁▄██" {
+		t.Fatalf("unexpected sparkline %q", got)
+	}
+}
+
+func TestProgressViewShowsTrendAndProjection(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.ProgressSnapshot{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	db.Create(&model.StudyPlan{CheckrideDate: today.AddDate(0, 0, 5)})
+	for i, completed := range []int{2, 4, 6} {
+		db.Create(&model.ProgressSnapshot{
+			Date:            today.AddDate(0, 0, i-2),
+			TasksCompleted:  completed,
+			TasksTotal:      20,
+			ProgressPercent: float64(completed) * 5,
+			ReadinessScore:  float64(20 + 5*i),
+			SpentUSD:        float64(1000 * i),
+			BudgetUSD:       10000,
+		})
+	}
+
+	pv := NewProgressView(db)
+	trend := pv.renderTrend()
+	for _, want := range []string{"Progress   ▁▂▃  30% (+20 pts)", "Readiness  ▂▂▃  30 (+10 pts)", "$2000 of $10000", "at 2.0 tasks/day (14 to go), 2 days after the checkride"} {
+		if !strings.Contains(trend, want) {
+			t.Fatalf("expected %q in trend:\n%s", want, trend)
+		}
+	}
+}
+
+func TestProgressViewShowsStudyHabits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.Progress{}, &model.OutboxEvent{}, &model.ProgressSnapshot{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	plan := model.StudyPlan{CheckrideDate: time.Now().AddDate(0, 1, 0)}
+	db.Create(&plan)
+	task := model.DailyTask{StudyPlanID: plan.ID, Date: time.Now().AddDate(0, 0, -2), Category: "Theory", Title: "Weather services"}
+	db.Create(&task)
+
+	if !strings.Contains(NewProgressView(db).renderHabits(), "Completion history starts") {
+		t.Fatal("expected an empty history hint before any completion")
+	}
+	if _, err := services.ToggleTaskCompletion(db, task.ID, "tui"); err != nil {
+		t.Fatalf("toggle: %v", err)
+	}
+	pv := NewProgressView(db)
+	habits := pv.renderHabits()
+	for _, want := range []string{"On time    0 of 1, 1 late by 2.0 days on average", "Most tasks get done around"} {
+		if !strings.Contains(habits, want) {
+			t.Fatalf("expected %q in habits:\n%s", want, habits)
+		}
+	}
+	if recent := pv.getRecentCompletions(); len(recent) != 1 || recent[0].ID != task.ID {
+		t.Fatalf("expected the toggled task as the recent completion, got %+v", recent)
+	}
+	streaks := pv.renderStreaks()
+	for _, want := range []string{"Study Streak: 1 days (longest 1)", "✓ studied today", "Mon ", "░"} {
+		if !strings.Contains(streaks, want) {
+			t.Fatalf("expected %q in streaks:\n%s", want, streaks)
+		}
+	}
+}
+
+func TestProgressViewShowsStudyTime(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.Progress{}, &model.ProgressSnapshot{}, &model.StudySession{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if !strings.Contains(NewProgressView(db).renderStudyTime(), "Time a task with [t]") {
+		t.Fatal("expected a hint before any timed session")
+	}
+
+	task := model.DailyTask{Date: time.Now(), Category: "Theory", Title: "Airspace", DurationMinutes: 60, ACSRefs: "PA.I.E,PA.III.A"}
+	db.Create(&task)
+	start := time.Now().Add(-2 * time.Hour)
+	if _, err := services.StartStudySession(db, task.ID, false, "tui", start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := services.StopStudySession(db, start.Add(90*time.Minute)); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	studyTime := NewProgressView(db).renderStudyTime()
+	for _, want := range []string{"Study Time (1.5 h timed)", "Theory       1.5h of 1.0h planned, 1 tasks", "ACS areas: I 0.8h · III 0.8h"} {
+		if !strings.Contains(studyTime, want) {
+			t.Fatalf("expected %q in study time:\n%s", want, studyTime)
+		}
+	}
+}
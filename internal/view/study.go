@@ -2,6 +2,7 @@ package view
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -29,6 +30,19 @@ type StudyView struct {
 	category      string
 	status        studyStatus
 	operation     studyOperationState
+	session       model.StudySession
+	timing        bool
+	ticking       bool
+	form          *taskForm
+	deleteID      uint
+}
+
+// TimerTickMsg advances the Study screen focus timer once a second while a
+// session runs.
+type TimerTickMsg time.Time
+
+func timerTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return TimerTickMsg(t) })
 }
 
 type studyOperationState struct {
@@ -51,6 +65,11 @@ type googleSyncDoneMsg struct {
 	err    error
 }
 
+type caldavSyncDoneMsg struct {
+	result services.CalDAVSyncResult
+	err    error
+}
+
 type opencodeExportDoneMsg struct {
 	result services.OpenCodeBotExportResult
 	err    error
@@ -69,11 +88,15 @@ func NewStudyView(db *gorm.DB) *StudyView {
 // loadData loads study plan data from database
 func (sv *StudyView) loadData() {
 	var plan model.StudyPlan
-	if err := sv.db.Preload("DailyTasks").Last(&plan).Error; err == nil {
+	byDate := func(db *gorm.DB) *gorm.DB { return db.Order("date asc, id asc") }
+	if err := sv.db.Preload("DailyTasks", byDate).Last(&plan).Error; err == nil {
 		sv.checkrideDate = plan.CheckrideDate
 		sv.hasCheckride = true
 		sv.tasks = plan.DailyTasks
 	}
+	if session, found, err := services.ActiveStudySession(sv.db); err == nil {
+		sv.session, sv.timing = session, found
+	}
 	sv.applyFilter()
 }
 
@@ -94,9 +117,18 @@ func (sv *StudyView) applyFilter() {
 	}
 }
 
-// Init implements tea.Model
+// Init implements tea.Model. It picks up the ticks of a session that is
+// already running, for example one started with `openppl timer start`.
 func (sv *StudyView) Init() tea.Cmd {
-	return nil
+	return sv.startTicking()
+}
+
+func (sv *StudyView) startTicking() tea.Cmd {
+	if !sv.timing || sv.session.PausedAt != nil || sv.ticking {
+		return nil
+	}
+	sv.ticking = true
+	return timerTick()
 }
 
 // Update implements tea.Model
@@ -106,24 +138,45 @@ func (sv *StudyView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			sv.finishOperation(newStudyStatusFromError("ICS export", msg.err))
 		} else {
-			sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("ICS export complete: %s (%d events)", msg.result.Path, msg.result.EventCount)))
+			noun := "events"
+			if msg.result.Mode == services.ICSExportModeTodos {
+				noun = "to-dos"
+			}
+			sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("ICS export complete: %s (%d %s)", msg.result.Path, msg.result.EventCount, noun)))
 		}
 		return sv, nil
 	case remindersExportDoneMsg:
 		if msg.err != nil {
 			sv.finishOperation(newStudyStatusFromError("Reminders export", msg.err))
 		} else {
-			sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("Reminders export complete: %d created in '%s'", msg.result.Created, msg.result.ListName)))
+			sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("Reminders export complete: %d created in '%s' (%s)", msg.result.Created, msg.result.ListName, msg.result.Target)))
 		}
 		return sv, nil
 	case googleSyncDoneMsg:
 		if msg.err != nil {
 			sv.finishOperation(newStudyStatusFromError("Google sync", msg.err))
 		} else {
+			counts := fmt.Sprintf("%d created, %d updated, %d deleted, %d unchanged", msg.result.Created, msg.result.Updated, msg.result.Deleted, msg.result.Unchanged)
+			if len(msg.result.CompletedTaskIDs) > 0 {
+				sv.loadData()
+				counts += fmt.Sprintf(", %d completed from calendar", len(msg.result.CompletedTaskIDs))
+			}
+			if msg.result.Failed > 0 {
+				sv.finishOperation(newStudyStatusWarning(fmt.Sprintf("Google sync completed with issues: %s, %d failed (%s)", counts, msg.result.Failed, msg.result.CalendarID)))
+			} else {
+				sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("Google sync complete: %s (%s)", counts, msg.result.CalendarID)))
+			}
+		}
+		return sv, nil
+	case caldavSyncDoneMsg:
+		if msg.err != nil {
+			sv.finishOperation(newStudyStatusFromError("CalDAV sync", msg.err))
+		} else {
+			counts := fmt.Sprintf("%d created, %d updated, %d deleted, %d unchanged", msg.result.Created, msg.result.Updated, msg.result.Deleted, msg.result.Unchanged)
 			if msg.result.Failed > 0 {
-				sv.finishOperation(newStudyStatusWarning(fmt.Sprintf("Google sync completed with issues: %d created, %d failed (%s)", msg.result.Created, msg.result.Failed, msg.result.CalendarID)))
+				sv.finishOperation(newStudyStatusWarning(fmt.Sprintf("CalDAV sync completed with issues: %s, %d failed (%s)", counts, msg.result.Failed, msg.result.CollectionURL)))
 			} else {
-				sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("Google sync complete: %d created (%s)", msg.result.Created, msg.result.CalendarID)))
+				sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("CalDAV sync complete: %s (%s)", counts, msg.result.CollectionURL)))
 			}
 		}
 		return sv, nil
@@ -134,10 +187,23 @@ func (sv *StudyView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			sv.finishOperation(newStudyStatusSuccess(fmt.Sprintf("OpenCode export complete: %d tasks -> %s", msg.result.TaskCount, msg.result.Path)))
 		}
 		return sv, nil
+	case TimerTickMsg:
+		sv.ticking = false
+		if !sv.timing || sv.session.PausedAt != nil {
+			return sv, nil
+		}
+		if services.PomodoroDone(sv.session, time.Time(msg)) {
+			sv.stopTimer(time.Time(msg))
+			return sv, nil
+		}
+		return sv, sv.startTicking()
 	case tea.KeyMsg:
 		if sv.inputMode {
 			return sv.handleInput(msg)
 		}
+		if sv.form != nil || sv.deleteID != 0 {
+			return sv.handleTaskForm(msg)
+		}
 		return sv.handleNav(msg)
 	}
 	return sv, nil
@@ -154,7 +220,15 @@ func (sv *StudyView) handleInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				layout = "01/02/06"
 			}
 			if d, err := time.Parse(layout, sv.dateInput); err == nil {
-				sv.saveStudyPlan(d)
+				var blocked *services.CheckrideBlockedError
+				if err := services.CheckCheckrideDate(sv.db, d); errors.As(err, &blocked) {
+					sv.status = newStudyStatusWarning(fmt.Sprintf("Cannot schedule: %v. Pick a date on or before %s.", blocked, blocked.LatestCheckride().Format("01/02/2006")))
+					return sv, nil
+				}
+				if err := sv.saveStudyPlan(d); err != nil {
+					sv.status = newStudyStatusFromError("Save plan", err)
+					return sv, nil
+				}
 				sv.status = newStudyStatusSuccess("Checkride date saved.")
 				sv.inputMode = false
 				sv.dateInput = ""
@@ -219,18 +293,119 @@ func (sv *StudyView) handleNav(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		sv.category = "CFI Flights"
 		sv.applyFilter()
 	case "e":
-		return sv, sv.exportICS()
+		return sv, sv.exportICS(services.ICSExportModeEvents)
+	case "E":
+		return sv, sv.exportICS(services.ICSExportModeTodos)
 	case "r":
 		return sv, sv.exportReminders()
 	case "g":
 		return sv, sv.syncGoogleCalendar()
+	case "c":
+		return sv, sv.syncCalDAV()
 	case "o":
 		return sv, sv.exportOpenCodeBot()
+	case "t":
+		if sv.timing {
+			sv.stopTimer(time.Now())
+			return sv, nil
+		}
+		return sv, sv.startTimer(false)
+	case "T":
+		return sv, sv.startTimer(true)
+	case "p":
+		sv.togglePause()
+		return sv, sv.startTicking()
+	case "a":
+		sv.openTaskForm(false)
+	case "m":
+		sv.openTaskForm(true)
+	case "x":
+		sv.confirmDelete()
+	case "[":
+		sv.moveTask(-1)
+	case "]":
+		sv.moveTask(1)
 	}
 	return sv, nil
 }
 
-func (sv *StudyView) exportICS() tea.Cmd {
+// startTimer starts a focus session on the selected task, as a Pomodoro
+// when pomodoro is set.
+func (sv *StudyView) startTimer(pomodoro bool) tea.Cmd {
+	if len(sv.filteredTasks) == 0 {
+		return nil
+	}
+	session, err := services.StartStudySession(sv.db, sv.filteredTasks[sv.selectedIdx].ID, pomodoro, "tui", time.Now())
+	if err != nil {
+		sv.status = newStudyStatusWarning(fmt.Sprintf("Timer not started: %v", err))
+		return nil
+	}
+	sv.session, sv.timing = session, true
+	if pomodoro {
+		sv.status = newStudyStatusInfo(fmt.Sprintf("Pomodoro started: %d minutes on %s", services.PomodoroMinutes, session.TaskTitle))
+	} else {
+		sv.status = newStudyStatusInfo("Timer started: " + session.TaskTitle)
+	}
+	return sv.startTicking()
+}
+
+func (sv *StudyView) stopTimer(now time.Time) {
+	session, err := services.StopStudySession(sv.db, now)
+	if err != nil {
+		sv.status = newStudyStatusFromError("Timer", err)
+		return
+	}
+	sv.session, sv.timing = model.StudySession{}, false
+	worked := formatTimer(time.Duration(session.Seconds) * time.Second)
+	if session.Pomodoro && session.Seconds >= services.PomodoroMinutes*60 {
+		sv.status = newStudyStatusSuccess(fmt.Sprintf("Pomodoro complete: %s on %s. Take a %d minute break.", worked, session.TaskTitle, services.PomodoroBreakMinutes))
+		return
+	}
+	sv.status = newStudyStatusSuccess(fmt.Sprintf("Logged %s on %s", worked, session.TaskTitle))
+}
+
+func (sv *StudyView) togglePause() {
+	if !sv.timing {
+		sv.status = newStudyStatusWarning("No timer running. Press t to start one on the selected task.")
+		return
+	}
+	var err error
+	if sv.session.PausedAt != nil {
+		sv.session, err = services.ResumeStudySession(sv.db, time.Now())
+	} else {
+		sv.session, err = services.PauseStudySession(sv.db, time.Now())
+	}
+	if err != nil {
+		sv.status = newStudyStatusFromError("Timer", err)
+	}
+}
+
+// renderTimer shows the open focus session, if any.
+func (sv *StudyView) renderTimer() string {
+	if !sv.timing {
+		return ""
+	}
+	elapsed := formatTimer(services.SessionElapsed(sv.session, time.Now()))
+	if sv.session.Pomodoro {
+		elapsed = fmt.Sprintf("Pomodoro %s of %d:00", elapsed, services.PomodoroMinutes)
+	}
+	line := fmt.Sprintf("⏱ %s  %s (%s)", elapsed, sv.session.TaskTitle, sv.session.Category)
+	if sv.session.PausedAt != nil {
+		return styles.WarningStyle.Render(line+"  paused") + "\n\n"
+	}
+	return styles.Success.Render(line) + "\n\n"
+}
+
+// formatTimer formats a duration as m:ss, or h:mm:ss from an hour.
+func formatTimer(d time.Duration) string {
+	seconds := int(d.Seconds())
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+func (sv *StudyView) exportICS(mode string) tea.Cmd {
 	if sv.operation.loading {
 		sv.status = newStudyStatusWarning(fmt.Sprintf("%s already in progress. Please wait for it to finish.", sv.operation.label))
 		return nil
@@ -244,12 +419,22 @@ func (sv *StudyView) exportICS() tea.Cmd {
 	sv.startOperation("ICS export", "Exporting ICS file...")
 
 	tasks := append([]model.DailyTask(nil), sv.tasks...)
+	loc := sv.exportLocation()
 	return func() tea.Msg {
-		result, err := services.ExportICS(tasks, "exports")
+		result, err := services.ExportICS(tasks, services.ICSExportOptions{OutputDir: "exports", Mode: mode, Location: loc})
 		return icsExportDoneMsg{result: result, err: err}
 	}
 }
 
+// exportLocation is the school airport's time zone, which exports schedule
+// tasks in.
+func (sv *StudyView) exportLocation() *time.Location {
+	if sv.db == nil {
+		return nil
+	}
+	return services.SchoolAirportLocation(sv.db)
+}
+
 func (sv *StudyView) exportReminders() tea.Cmd {
 	if sv.operation.loading {
 		sv.status = newStudyStatusWarning(fmt.Sprintf("%s already in progress. Please wait for it to finish.", sv.operation.label))
@@ -261,11 +446,12 @@ func (sv *StudyView) exportReminders() tea.Cmd {
 		return nil
 	}
 
-	sv.startOperation("Reminders export", "Exporting Apple Reminders...")
+	sv.startOperation("Reminders export", "Exporting reminders...")
 
 	tasks := append([]model.DailyTask(nil), sv.tasks...)
+	loc := sv.exportLocation()
 	return func() tea.Msg {
-		result, err := services.ExportAppleReminders(tasks, services.RemindersExportOptions{})
+		result, err := services.ExportReminders(tasks, services.RemindersExportOptions{Location: loc})
 		return remindersExportDoneMsg{result: result, err: err}
 	}
 }
@@ -284,12 +470,43 @@ func (sv *StudyView) syncGoogleCalendar() tea.Cmd {
 	sv.startOperation("Google sync", "Syncing tasks to Google Calendar...")
 
 	tasks := append([]model.DailyTask(nil), sv.tasks...)
+	loc := sv.exportLocation()
 	return func() tea.Msg {
-		result, err := services.SyncTasksToGoogleCalendar(context.Background(), tasks, services.GoogleCalendarSyncOptions{})
+		opts := services.GoogleCalendarSyncOptions{PullCompletion: true, Location: loc}
+		if sv.db != nil {
+			opts.LastLocalChange, _ = services.LastCompletionChanges(sv.db)
+		}
+		result, err := services.SyncTasksToGoogleCalendar(context.Background(), tasks, opts)
+		if err == nil && sv.db != nil {
+			for _, id := range result.CompletedTaskIDs {
+				_, _ = services.SetTaskCompletion(sv.db, id, true, "google")
+			}
+		}
 		return googleSyncDoneMsg{result: result, err: err}
 	}
 }
 
+func (sv *StudyView) syncCalDAV() tea.Cmd {
+	if sv.operation.loading {
+		sv.status = newStudyStatusWarning(fmt.Sprintf("%s already in progress. Please wait for it to finish.", sv.operation.label))
+		return nil
+	}
+
+	if len(sv.tasks) == 0 {
+		sv.status = newStudyStatusWarning("CalDAV sync skipped: no tasks available to sync.")
+		return nil
+	}
+
+	sv.startOperation("CalDAV sync", "Syncing tasks to CalDAV calendar...")
+
+	tasks := append([]model.DailyTask(nil), sv.tasks...)
+	loc := sv.exportLocation()
+	return func() tea.Msg {
+		result, err := services.SyncTasksToCalDAV(context.Background(), tasks, services.CalDAVSyncOptions{Location: loc})
+		return caldavSyncDoneMsg{result: result, err: err}
+	}
+}
+
 func (sv *StudyView) exportOpenCodeBot() tea.Cmd {
 	if sv.operation.loading {
 		sv.status = newStudyStatusWarning(fmt.Sprintf("%s already in progress. Please wait for it to finish.", sv.operation.label))
@@ -337,38 +554,61 @@ func (sv *StudyView) cycleCategory() {
 // toggleTask toggles task completion in database
 func (sv *StudyView) toggleTask(id uint) tea.Cmd {
 	return func() tea.Msg {
-		var task model.DailyTask
-		if err := sv.db.First(&task, id).Error; err == nil {
-			task.Completed = !task.Completed
-			sv.db.Save(&task)
+		if _, err := services.ToggleTaskCompletion(sv.db, id, "tui"); err == nil {
 			sv.loadData()
+			sv.flushWebhooks()
 		}
 		return nil
 	}
 }
 
-// saveStudyPlan saves the checkride date and generates tasks
-func (sv *StudyView) saveStudyPlan(date time.Time) {
-	var plan model.StudyPlan
-	if err := sv.db.Last(&plan).Error; err != nil {
-		plan = model.StudyPlan{CheckrideDate: date}
-		sv.db.Create(&plan)
-	} else {
+// saveStudyPlan saves the checkride date and regenerates the tasks in one
+// transaction, so the plan.regenerated event only goes out for a full save.
+func (sv *StudyView) saveStudyPlan(date time.Time) error {
+	err := sv.db.Transaction(func(tx *gorm.DB) error {
+		var plan model.StudyPlan
+		if err := tx.Order("id desc").Limit(1).Find(&plan).Error; err != nil {
+			return err
+		}
 		plan.CheckrideDate = date
-		sv.db.Save(&plan)
-	}
+		if err := tx.Save(&plan).Error; err != nil {
+			return err
+		}
 
-	// Clear old tasks
-	sv.db.Where("study_plan_id = ?", plan.ID).Delete(&model.DailyTask{})
+		// Clear old tasks, keeping the ones added by hand
+		if err := services.DeleteGeneratedTasks(tx, plan.ID); err != nil {
+			return err
+		}
 
-	// Generate new tasks
-	tasks := services.GenerateStudyPlan(date, 90)
-	for i := range tasks {
-		tasks[i].StudyPlanID = plan.ID
+		tasks := services.GenerateStudyPlan(date, 90)
+		if err := services.ApplyActualDurations(tx, tasks); err != nil {
+			return err
+		}
+		for i := range tasks {
+			tasks[i].StudyPlanID = plan.ID
+		}
+		if len(tasks) > 0 {
+			if err := tx.Create(&tasks).Error; err != nil {
+				return err
+			}
+		}
+		return services.RecordPlanRegenerated(tx, plan, len(tasks), "tui")
+	})
+	if err != nil {
+		return err
 	}
-	sv.db.Create(&tasks)
 
 	sv.loadData()
+	go sv.flushWebhooks()
+	return nil
+}
+
+// flushWebhooks makes a best-effort attempt to deliver outbox events right away.
+// Anything left pending is picked up by the next flush or `openppl webhooks deliver`.
+func (sv *StudyView) flushWebhooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _ = services.DeliverPendingWebhooks(ctx, sv.db)
 }
 
 // View implements tea.Model
@@ -390,6 +630,10 @@ func (sv *StudyView) View() string {
 		b.WriteString(styles.Dim.Render("Press / to set checkride date"))
 	}
 	b.WriteString("\n\n")
+	b.WriteString(sv.renderTimer())
+	if sv.form != nil {
+		b.WriteString(sv.renderTaskForm())
+	}
 
 	// Category filters
 	b.WriteString("Filter: ")
@@ -424,11 +668,11 @@ func (sv *StudyView) View() string {
 	}
 
 	// Help
-	b.WriteString(styles.Dim.Render("\n[↑↓] Navigate  [Enter] Toggle  [/] Date  [Tab/1-5] Filter  [e] Export ICS  [r] Reminders  [g] Google Sync  [o] OpenCode"))
+	b.WriteString(styles.Dim.Render("\n[↑↓] Navigate  [Enter] Toggle  [/] Date  [Tab/1-5] Filter  [e/E] Export ICS events/to-dos  [r] Reminders  [g] Google Sync  [c] CalDAV  [o] OpenCode  [t] Timer start/stop  [T] Pomodoro  [p] Pause  [a] Add  [m] Edit  [x] Delete  [[/]] Move a day"))
 
 	if sv.operation.loading {
 		b.WriteString("\n")
-		b.WriteString(styles.Dim.Render(fmt.Sprintf("Operation in progress: %s (repeat e/r/g/o is ignored)", sv.operation.label)))
+		b.WriteString(styles.Dim.Render(fmt.Sprintf("Operation in progress: %s (repeat e/r/g/c/o is ignored)", sv.operation.label)))
 		b.WriteString("\n")
 		b.WriteString(styles.WarningStyle.Render(fmt.Sprintf("[%s] %s", currentLoadingMarker(), sv.status.message)))
 	}
@@ -495,10 +739,31 @@ func (sv *StudyView) renderTask(t model.DailyTask, selected bool) string {
 		check = "[x]"
 	}
 	catStyle := sv.categoryStyle(t.Category)
+	title := t.Title
+	switch t.Priority {
+	case services.TaskPriorityHigh:
+		title = "! " + title
+	case services.TaskPriorityLow:
+		title = styles.Dim.Render(title)
+	}
+	var extras []string
+	if t.UserCreated {
+		extras = append(extras, "added")
+	}
+	if t.RescheduledFrom != nil {
+		extras = append(extras, "moved from "+t.RescheduledFrom.Format("Jan 2"))
+	}
+	if len(extras) > 0 {
+		title += styles.Dim.Render(" (" + strings.Join(extras, ", ") + ")")
+	}
 	if selected {
-		return styles.SelectedTask.Render(fmt.Sprintf(" > %s %s - %s", check, catStyle.Render(t.Category), t.Title)) + "\n"
+		line := styles.SelectedTask.Render(fmt.Sprintf(" > %s %s - %s", check, catStyle.Render(t.Category), title)) + "\n"
+		if t.Notes != "" {
+			line += styles.Dim.Render("       Notes: "+t.Notes) + "\n"
+		}
+		return line
 	}
-	return fmt.Sprintf("   %s %s - %s\n", check, catStyle.Render(t.Category), t.Title)
+	return fmt.Sprintf("   %s %s - %s\n", check, catStyle.Render(t.Category), title)
 }
 
 // categoryStyle returns the style for a category
@@ -21,7 +21,7 @@ func TestStudyLoadingStateTransitions(t *testing.T) {
 		},
 	}
 
-	cmd := sv.exportICS()
+	cmd := sv.exportICS(services.ICSExportModeEvents)
 	if cmd == nil {
 		t.Fatal("expected exportICS to return async command")
 	}
@@ -58,10 +58,10 @@ func TestStudyLoadingIndicatorRendering(t *testing.T) {
 	if !strings.Contains(renderedLoading, "Operation in progress: Reminders export") {
 		t.Fatalf("expected loading hint in view, got %q", renderedLoading)
 	}
-	if !strings.Contains(renderedLoading, "repeat e/r/g/o is ignored") {
+	if !strings.Contains(renderedLoading, "repeat e/r/g/c/o is ignored") {
 		t.Fatalf("expected duplicate-key hint in view, got %q", renderedLoading)
 	}
-	if !strings.Contains(renderedLoading, "Exporting Apple Reminders") {
+	if !strings.Contains(renderedLoading, "Exporting reminders") {
 		t.Fatalf("expected loading status text in view, got %q", renderedLoading)
 	}
 
@@ -80,7 +80,7 @@ func TestStudyDuplicateOperationSuppression(t *testing.T) {
 		tasks: []model.DailyTask{{ID: 1, Category: "Theory", Title: "Review regulations"}},
 	}
 
-	firstCmd := sv.exportICS()
+	firstCmd := sv.exportICS(services.ICSExportModeEvents)
 	if firstCmd == nil {
 		t.Fatal("expected first command to start operation")
 	}
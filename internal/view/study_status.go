@@ -64,11 +64,27 @@ func newStudyStatusFromError(operation string, err error) studyStatus {
 		}
 	}
 
+	var caldavErr *services.CalDAVError
+	if errors.As(err, &caldavErr) {
+		switch caldavErr.Kind {
+		case services.CalDAVErrorConfig:
+			return newStudyStatusError("CalDAV is not configured. Add your calendar URL to ~/.openppl/caldav.json and retry.")
+		case services.CalDAVErrorAuth:
+			return newStudyStatusError("CalDAV server rejected the credentials. Check username/password or token and retry.")
+		case services.CalDAVErrorValidation:
+			return newStudyStatusError("CalDAV sync request is invalid. Refresh tasks and try again.")
+		default:
+			return newStudyStatusError("CalDAV sync failed. Check the calendar URL and try again.")
+		}
+	}
+
 	var remindersErr *services.RemindersExportError
 	if errors.As(err, &remindersErr) {
 		switch remindersErr.Kind {
 		case "validation":
 			return newStudyStatusError("Reminders export request is invalid. Refresh tasks and try again.")
+		case "config":
+			return newStudyStatusError("Reminders target is not configured. Check ~/.openppl/reminders.json and retry.")
 		case "timeout":
 			return newStudyStatusError("Reminders export timed out. Open Reminders and try again.")
 		case "permission":
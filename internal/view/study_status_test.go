@@ -2,8 +2,10 @@ package view
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"gorm.io/driver/sqlite"
@@ -41,6 +43,12 @@ func TestStudyStatusTranslations(t *testing.T) {
 			expected:  "valid OAuth client file",
 			operation: "Google sync",
 		},
+		{
+			name:      "caldav missing config",
+			err:       &services.CalDAVError{Kind: services.CalDAVErrorConfig, Err: errors.New("calendar url is not configured")},
+			expected:  "caldav.json",
+			operation: "CalDAV sync",
+		},
 		{
 			name:      "reminders permission",
 			err:       &services.RemindersExportError{Kind: "permission", Err: errors.New("denied")},
@@ -113,7 +121,7 @@ func TestStudyInputValidation(t *testing.T) {
 func TestStudyStatusRendering(t *testing.T) {
 	sv := &StudyView{}
 
-	_ = sv.exportICS()
+	_ = sv.exportICS(services.ICSExportModeEvents)
 	if sv.status.severity != studyStatusSeverityWarning {
 		t.Fatalf("expected ICS no-task warning, got %q", sv.status.severity)
 	}
@@ -151,10 +159,47 @@ func newStudyViewForTest(t *testing.T) *StudyView {
 		t.Fatalf("open sqlite: %v", err)
 	}
 
-	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}); err != nil {
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.StudySession{}, &model.OutboxEvent{}); err != nil {
 		t.Fatalf("automigrate: %v", err)
 	}
 
 	sv := NewStudyView(db)
 	return sv
 }
+
+func TestSaveStudyPlanRollsBackWhenTheEventFails(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// No outbox table, so recording plan.regenerated fails.
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.StudySession{}); err != nil {
+		t.Fatalf("automigrate: %v", err)
+	}
+	sv := NewStudyView(db)
+	checkride := time.Now().AddDate(0, 2, 0)
+
+	if err := sv.saveStudyPlan(checkride); err == nil {
+		t.Fatal("expected the save to fail without an outbox")
+	}
+	var plans, tasks int64
+	db.Model(&model.StudyPlan{}).Count(&plans)
+	db.Model(&model.DailyTask{}).Count(&tasks)
+	if plans != 0 || tasks != 0 {
+		t.Fatalf("expected nothing saved, got %d plans and %d tasks", plans, tasks)
+	}
+
+	if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+		t.Fatalf("automigrate outbox: %v", err)
+	}
+	if err := sv.saveStudyPlan(checkride); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	var events int64
+	db.Model(&model.OutboxEvent{}).Where("event_type = ?", services.EventPlanRegenerated).Count(&events)
+	db.Model(&model.DailyTask{}).Count(&tasks)
+	if events != 1 || tasks == 0 {
+		t.Fatalf("expected one plan.regenerated event with tasks, got %d events and %d tasks", events, tasks)
+	}
+}
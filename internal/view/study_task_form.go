@@ -0,0 +1,301 @@
+package view
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+	"ppl-study-planner/internal/styles"
+)
+
+// Task form fields, in tab order.
+const (
+	taskFieldTitle = iota
+	taskFieldDate
+	taskFieldCategory
+	taskFieldPriority
+	taskFieldMinutes
+	taskFieldNotes
+	taskFieldCount
+)
+
+var taskFieldLabels = []string{"Title", "Date", "Category", "Priority", "Minutes", "Notes"}
+
+// taskFormDateLayout matches the checkride date input.
+const taskFormDateLayout = "01/02/2006"
+
+// taskForm adds or edits a study task on the Study screen. Category and
+// priority are picked with left/right; the other fields are typed. The
+// description is not shown but kept, so editing does not clear it.
+type taskForm struct {
+	taskID      uint // 0 adds a new task
+	values      [taskFieldCount]string
+	focus       int
+	categories  []string
+	description string
+}
+
+func newTaskForm(task model.DailyTask) *taskForm {
+	form := &taskForm{taskID: task.ID, categories: append([]string(nil), services.Categories...), description: task.Description}
+	if task.Category != "" && !containsString(form.categories, task.Category) {
+		form.categories = append(form.categories, task.Category)
+	}
+	priority := task.Priority
+	if priority == "" {
+		priority = services.TaskPriorityNormal
+	}
+	form.values[taskFieldTitle] = task.Title
+	form.values[taskFieldDate] = task.Date.Format(taskFormDateLayout)
+	form.values[taskFieldCategory] = task.Category
+	form.values[taskFieldPriority] = priority
+	form.values[taskFieldNotes] = task.Notes
+	if task.DurationMinutes > 0 {
+		form.values[taskFieldMinutes] = strconv.Itoa(task.DurationMinutes)
+	}
+	return form
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// cycle steps a choice field through its options.
+func (f *taskForm) cycle(step int) {
+	var options []string
+	switch f.focus {
+	case taskFieldCategory:
+		options = f.categories
+	case taskFieldPriority:
+		options = services.TaskPriorities
+	default:
+		return
+	}
+	index := 0
+	for i, option := range options {
+		if option == f.values[f.focus] {
+			index = i
+			break
+		}
+	}
+	f.values[f.focus] = options[(index+step+len(options))%len(options)]
+}
+
+func (f *taskForm) input() (services.TaskInput, error) {
+	date, err := time.Parse(taskFormDateLayout, strings.TrimSpace(f.values[taskFieldDate]))
+	if err != nil {
+		return services.TaskInput{}, fmt.Errorf("invalid date %q, use MM/DD/YYYY", f.values[taskFieldDate])
+	}
+	minutes := 0
+	if value := strings.TrimSpace(f.values[taskFieldMinutes]); value != "" {
+		if minutes, err = strconv.Atoi(value); err != nil {
+			return services.TaskInput{}, fmt.Errorf("invalid minutes %q", value)
+		}
+	}
+	return services.TaskInput{
+		Date:            date,
+		Category:        f.values[taskFieldCategory],
+		Title:           f.values[taskFieldTitle],
+		Description:     f.description,
+		Notes:           f.values[taskFieldNotes],
+		Priority:        f.values[taskFieldPriority],
+		DurationMinutes: minutes,
+	}, nil
+}
+
+// Editing reports whether the view is capturing typed text, so global
+// shortcuts should be passed through to it.
+func (sv *StudyView) Editing() bool {
+	return sv.inputMode || sv.form != nil || sv.deleteID != 0
+}
+
+// openTaskForm starts adding a task, on the selected task's day and in the
+// filtered category, or editing the selected task.
+func (sv *StudyView) openTaskForm(edit bool) {
+	if !sv.hasCheckride {
+		sv.status = newStudyStatusWarning("Set a checkride date with / before adding tasks.")
+		return
+	}
+	if edit {
+		if task, ok := sv.selectedTask(); ok {
+			sv.form = newTaskForm(task)
+		}
+		return
+	}
+	draft := model.DailyTask{Date: time.Now(), Category: sv.category}
+	if task, ok := sv.selectedTask(); ok {
+		draft.Date = task.Date
+	}
+	if draft.Category == "" {
+		draft.Category = services.Categories[0]
+	}
+	sv.form = newTaskForm(draft)
+}
+
+func (sv *StudyView) selectedTask() (model.DailyTask, bool) {
+	if sv.selectedIdx < 0 || sv.selectedIdx >= len(sv.filteredTasks) {
+		return model.DailyTask{}, false
+	}
+	return sv.filteredTasks[sv.selectedIdx], true
+}
+
+// selectTask moves the selection to id after a reload, when it is shown.
+func (sv *StudyView) selectTask(id uint) {
+	if idx := sv.findIndex(id); idx >= 0 {
+		sv.selectedIdx = idx
+	}
+}
+
+// handleTaskForm handles typing in the task form and the delete prompt.
+func (sv *StudyView) handleTaskForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if sv.deleteID != 0 {
+		switch msg.String() {
+		case "y", "Y":
+			sv.deleteTask()
+		case "n", "N", "esc":
+			sv.deleteID = 0
+			sv.status = studyStatus{}
+		}
+		return sv, nil
+	}
+
+	form := sv.form
+	switch msg.String() {
+	case "enter":
+		sv.saveTaskForm()
+	case "esc":
+		sv.form = nil
+		sv.status = studyStatus{}
+	case "tab", "down":
+		form.focus = (form.focus + 1) % taskFieldCount
+	case "shift+tab", "up":
+		form.focus = (form.focus + taskFieldCount - 1) % taskFieldCount
+	case "left":
+		form.cycle(-1)
+	case "right":
+		form.cycle(1)
+	case "backspace":
+		if form.focus == taskFieldCategory || form.focus == taskFieldPriority {
+			return sv, nil
+		}
+		if runes := []rune(form.values[form.focus]); len(runes) > 0 {
+			form.values[form.focus] = string(runes[:len(runes)-1])
+		}
+	default:
+		if form.focus == taskFieldCategory || form.focus == taskFieldPriority {
+			return sv, nil
+		}
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			form.values[form.focus] += string(msg.Runes)
+		}
+	}
+	return sv, nil
+}
+
+func (sv *StudyView) saveTaskForm() {
+	input, err := sv.form.input()
+	if err != nil {
+		sv.status = newStudyStatusWarning("Task not saved: " + err.Error())
+		return
+	}
+	var task model.DailyTask
+	verb := "Added"
+	if sv.form.taskID == 0 {
+		task, err = services.CreateTask(sv.db, input, "tui")
+	} else {
+		verb = "Saved"
+		task, err = services.UpdateTask(sv.db, sv.form.taskID, input, "tui")
+	}
+	if err != nil {
+		// Keep the form open so the input can be fixed.
+		sv.status = newStudyStatusWarning("Task not saved: " + err.Error())
+		return
+	}
+	sv.form = nil
+	sv.loadData()
+	sv.selectTask(task.ID)
+	sv.status = newStudyStatusSuccess(fmt.Sprintf("%s %s on %s", verb, task.Title, task.Date.Format("Mon Jan 2")))
+	go sv.flushWebhooks()
+}
+
+// confirmDelete asks before deleting the selected task.
+func (sv *StudyView) confirmDelete() {
+	task, ok := sv.selectedTask()
+	if !ok {
+		return
+	}
+	sv.deleteID = task.ID
+	note := ""
+	if !task.UserCreated {
+		note = " It comes back when the plan is regenerated."
+	}
+	sv.status = newStudyStatusWarning(fmt.Sprintf("Delete %q? [y/n]%s", task.Title, note))
+}
+
+func (sv *StudyView) deleteTask() {
+	task, err := services.DeleteTask(sv.db, sv.deleteID, "tui")
+	sv.deleteID = 0
+	if err != nil {
+		sv.status = newStudyStatusFromError("Delete", err)
+		return
+	}
+	sv.loadData()
+	sv.status = newStudyStatusSuccess("Deleted " + task.Title)
+}
+
+// moveTask reschedules the selected task by days.
+func (sv *StudyView) moveTask(days int) {
+	task, ok := sv.selectedTask()
+	if !ok {
+		return
+	}
+	moved, err := services.RescheduleTask(sv.db, task.ID, task.Date.AddDate(0, 0, days), "tui")
+	if err != nil {
+		sv.status = newStudyStatusFromError("Reschedule", err)
+		return
+	}
+	sv.loadData()
+	sv.selectTask(moved.ID)
+	sv.status = newStudyStatusSuccess(fmt.Sprintf("Moved %s to %s", moved.Title, moved.Date.Format("Mon Jan 2")))
+	go sv.flushWebhooks()
+}
+
+// renderTaskForm draws the open add or edit form.
+func (sv *StudyView) renderTaskForm() string {
+	form := sv.form
+	var b strings.Builder
+	title := "Add task"
+	if form.taskID != 0 {
+		title = "Edit task"
+	}
+	b.WriteString(styles.Subtitle.Render(title))
+	b.WriteString("\n")
+	for field, label := range taskFieldLabels {
+		value := form.values[field]
+		if field == taskFieldCategory || field == taskFieldPriority {
+			value = "◂ " + value + " ▸"
+		}
+		line := fmt.Sprintf("%-9s %s", label+":", value)
+		if field == form.focus {
+			if field != taskFieldCategory && field != taskFieldPriority {
+				line += "_"
+			}
+			b.WriteString(styles.SelectedTask.Render(" > " + line))
+		} else {
+			b.WriteString("   " + line)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(styles.Dim.Render("[Tab/↑↓] Field  [←→] Choose  [Enter] Save  [Esc] Cancel"))
+	b.WriteString("\n\n")
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func typeKeys(sv *StudyView, keys ...string) {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "right":
+			msg = tea.KeyMsg{Type: tea.KeyRight}
+		case "backspace":
+			msg = tea.KeyMsg{Type: tea.KeyBackspace}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		sv.Update(msg)
+	}
+}
+
+func TestStudyViewTaskFormAddsEditsAndDeletes(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.OutboxEvent{}, &model.StudySession{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	plan := model.StudyPlan{CheckrideDate: day.AddDate(0, 1, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: day, Category: "Theory", Title: "Airspace"})
+
+	sv := NewStudyView(db)
+	typeKeys(sv, "a")
+	if !sv.Editing() || !strings.Contains(sv.View(), "Add task") {
+		t.Fatalf("expected the add form to open:\n%s", sv.View())
+	}
+	typeKeys(sv, "Steep turns", "tab", "tab", "right", "right", "right", "tab", "right", "tab", "9", "0", "tab", "Bring the POH", "enter")
+	if sv.form != nil {
+		t.Fatalf("expected the form to close, status %q", sv.status.message)
+	}
+	var added model.DailyTask
+	db.Where("title = ?", "Steep turns").First(&added)
+	if !added.UserCreated || added.Category != "CFI Flights" || added.Priority != services.TaskPriorityLow || added.DurationMinutes != 90 || added.Notes != "Bring the POH" || !added.Date.Equal(day) {
+		t.Fatalf("unexpected task %+v", added)
+	}
+
+	typeKeys(sv, "m", "tab")
+	for range "06/01/2026" {
+		typeKeys(sv, "backspace")
+	}
+	typeKeys(sv, "13/45/2026", "enter")
+	if sv.form == nil || !strings.Contains(sv.status.message, "invalid date") {
+		t.Fatalf("expected a bad date to keep the form open, got %q", sv.status.message)
+	}
+	for range "13/45/2026" {
+		typeKeys(sv, "backspace")
+	}
+	typeKeys(sv, "06/03/2026", "enter")
+	var moved model.DailyTask
+	db.First(&moved, added.ID)
+	if !moved.Date.Equal(day.AddDate(0, 0, 2)) || moved.RescheduledFrom == nil {
+		t.Fatalf("expected the task moved to Jun 3, got %+v (status %q)", moved, sv.status.message)
+	}
+	if !strings.Contains(sv.View(), "(added, moved from Jun 1)") {
+		t.Fatalf("expected the task to show it was added and moved:\n%s", sv.View())
+	}
+
+	typeKeys(sv, "x", "y")
+	var count int64
+	db.Model(&model.DailyTask{}).Count(&count)
+	if count != 1 || sv.Editing() {
+		t.Fatalf("expected the task deleted, %d tasks left", count)
+	}
+}
@@ -0,0 +1,50 @@
+package view
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestStudyViewTimerLogsSession(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.StudySession{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	plan := model.StudyPlan{CheckrideDate: time.Now().AddDate(0, 1, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Now(), Category: "Theory", Title: "Weather services", DurationMinutes: 30})
+
+	sv := NewStudyView(db)
+	if cmd := sv.startTimer(true); cmd == nil {
+		t.Fatal("expected the timer to start ticking")
+	}
+	if !strings.Contains(sv.renderTimer(), "Pomodoro 0:00 of 25:00  Weather services (Theory)") {
+		t.Fatalf("unexpected timer line %q", sv.renderTimer())
+	}
+	if _, found, _ := services.ActiveStudySession(db); !found {
+		t.Fatal("expected the session to be stored")
+	}
+
+	sv.stopTimer(sv.session.StartedAt.Add(40 * time.Minute))
+	if sv.timing || sv.renderTimer() != "" {
+		t.Fatal("expected the timer to stop")
+	}
+	if !strings.Contains(sv.status.message, "Pomodoro complete: 25:00 on Weather services") {
+		t.Fatalf("unexpected status %q", sv.status.message)
+	}
+	sessions, err := services.ListStudySessions(db)
+	if err != nil || len(sessions) != 1 || sessions[0].Seconds != services.PomodoroMinutes*60 {
+		t.Fatalf("expected one capped session, got %+v (%v)", sessions, err)
+	}
+}
@@ -0,0 +1,176 @@
+package view
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ppl-study-planner/internal/services"
+	"ppl-study-planner/internal/styles"
+)
+
+// WeightBalanceView is a weight and balance worksheet for the embedded and
+// user aircraft profiles. Rows are the profile's stations, then fuel at
+// takeoff and fuel burned.
+type WeightBalanceView struct {
+	profiles     []services.WBProfile
+	profileIndex int
+	selected     int
+	loadings     map[string]*services.WBLoading
+	status       string
+}
+
+// NewWeightBalanceView loads the profiles and starts each with a pilot and
+// instructor up front and full tanks.
+func NewWeightBalanceView() *WeightBalanceView {
+	v := &WeightBalanceView{loadings: map[string]*services.WBLoading{}}
+	profiles, err := services.LoadWBProfiles()
+	if err != nil {
+		v.status = err.Error()
+	}
+	v.profiles = profiles
+	return v
+}
+
+func (v *WeightBalanceView) loading() *services.WBLoading {
+	profile := v.profiles[v.profileIndex]
+	loading, ok := v.loadings[profile.ID]
+	if !ok {
+		loading = &services.WBLoading{Stations: map[string]float64{}, FuelGal: profile.FuelCapacityGal}
+		loading.Stations[profile.Stations[0].ID] = 340
+		v.loadings[profile.ID] = loading
+	}
+	return loading
+}
+
+// Init implements tea.Model
+func (v *WeightBalanceView) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (v *WeightBalanceView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || len(v.profiles) == 0 {
+		return v, nil
+	}
+	rows := len(v.profiles[v.profileIndex].Stations) + 2
+	switch key.String() {
+	case "tab":
+		v.profileIndex = (v.profileIndex + 1) % len(v.profiles)
+		v.selected = 0
+	case "up", "k":
+		if v.selected > 0 {
+			v.selected--
+		}
+	case "down", "j":
+		if v.selected < rows-1 {
+			v.selected++
+		}
+	case "right", "l", "+":
+		v.adjust(10)
+	case "left", "h", "-":
+		v.adjust(-10)
+	case "shift+right", "L":
+		v.adjust(1)
+	case "shift+left", "H":
+		v.adjust(-1)
+	case "x":
+		v.adjust(math.Inf(-1))
+	}
+	return v, nil
+}
+
+// adjust changes the selected row by delta pounds, or a tenth of delta in
+// gallons for the fuel rows, keeping it within what the profile allows.
+func (v *WeightBalanceView) adjust(delta float64) {
+	profile := v.profiles[v.profileIndex]
+	loading := v.loading()
+	stations := len(profile.Stations)
+	switch {
+	case v.selected < stations:
+		id := profile.Stations[v.selected].ID
+		loading.Stations[id] = math.Max(0, loading.Stations[id]+delta)
+	case v.selected == stations:
+		loading.FuelGal = math.Min(math.Max(0, loading.FuelGal+delta/10), profile.FuelCapacityGal)
+		loading.BurnGal = math.Min(loading.BurnGal, loading.FuelGal)
+	default:
+		loading.BurnGal = math.Min(math.Max(0, loading.BurnGal+delta/10), loading.FuelGal)
+	}
+}
+
+// View implements tea.Model
+func (v *WeightBalanceView) View() string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Weight & Balance"))
+	b.WriteString("\n\n")
+	if len(v.profiles) == 0 {
+		b.WriteString(styles.ErrorStyle.Render("No aircraft profiles: " + v.status))
+		return b.String()
+	}
+
+	profile := v.profiles[v.profileIndex]
+	loading := v.loading()
+	b.WriteString(styles.Normal.Render(fmt.Sprintf("%s (%s)", profile.Name, profile.ID)))
+	b.WriteString(styles.Dim.Render(fmt.Sprintf("  [tab] profile %d/%d", v.profileIndex+1, len(v.profiles))))
+	b.WriteString("\n\n")
+
+	rows := make([]string, 0, len(profile.Stations)+2)
+	for _, station := range profile.Stations {
+		limit := ""
+		if station.MaxLb > 0 {
+			limit = fmt.Sprintf(" (max %.0f)", station.MaxLb)
+		}
+		rows = append(rows, fmt.Sprintf("%-28s %6.0f lb%s", station.Name, loading.Stations[station.ID], limit))
+	}
+	rows = append(rows,
+		fmt.Sprintf("%-28s %6.1f gal (of %.0f)", "Fuel at takeoff", loading.FuelGal, profile.FuelCapacityGal),
+		fmt.Sprintf("%-28s %6.1f gal", "Fuel burned", loading.BurnGal),
+	)
+	for i, row := range rows {
+		if i == v.selected {
+			b.WriteString(styles.Selected.Render("> " + row))
+		} else {
+			b.WriteString("  " + row)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	result, err := services.ComputeWeightBalance(profile, *loading)
+	if err != nil {
+		b.WriteString(styles.ErrorStyle.Render(err.Error()))
+		return b.String()
+	}
+	for _, condition := range []struct {
+		name string
+		services.WBCondition
+	}{{"Takeoff", result.Takeoff}, {"Landing", result.Landing}} {
+		line := fmt.Sprintf("%-8s %6.0f lb  CG %6.2f in", condition.name, condition.WeightLb, condition.CGIn)
+		if condition.InEnvelope {
+			b.WriteString(styles.Success.Render(line))
+		} else {
+			b.WriteString(styles.ErrorStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(strings.Join(result.ChartLines(56, 12), "\n"))
+	b.WriteString("\n")
+	b.WriteString(styles.Dim.Render("        T takeoff, L landing"))
+	b.WriteString("\n\n")
+
+	if result.WithinLimits() {
+		b.WriteString(styles.Success.Render("Within limits at takeoff and landing"))
+	} else {
+		for _, problem := range result.Problems {
+			b.WriteString(styles.ErrorStyle.Render("⚠ " + problem))
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Dim.Render("[↑/↓] Row  [←/→] ±10 lb or ±1 gal  [H/L] ±1 lb or ±0.1 gal  [x] Clear row"))
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package wb
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl wb [profiles]
+  openppl wb show <profile>
+  openppl wb <profile> [<station>=<lb> ...] [--fuel GAL] [--burn GAL] [--svg FILE]`
+
+// Execute is the dispatcher for `openppl wb [subcommand]`. A computation
+// exits 1 when the loading is out of limits so it can gate scripts.
+func Execute(args []string, stdout io.Writer) int {
+	profiles, err := services.LoadWBProfiles()
+	if err != nil {
+		fmt.Fprintf(stdout, "Warning: %v\n", err)
+		if profiles == nil {
+			return 1
+		}
+	}
+
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	switch sub {
+	case "", "profiles", "list":
+		for _, profile := range profiles {
+			fmt.Fprintf(stdout, "  %-10s %s\n", profile.ID, profile.Name)
+		}
+		fmt.Fprintln(stdout, "Compute with `openppl wb <profile> front=340 rear=0 --fuel 40 --burn 10`.")
+		return 0
+	case "show":
+		if len(args) < 2 {
+			fmt.Fprintln(stdout, usage)
+			return 1
+		}
+		profile, ok := services.FindWBProfile(profiles, args[1])
+		if !ok {
+			fmt.Fprintf(stdout, "Unknown profile %q; see `openppl wb profiles`.\n", args[1])
+			return 1
+		}
+		printProfile(profile, stdout)
+		return 0
+	case "help", "-h", "--help":
+		fmt.Fprintln(stdout, usage)
+		return 0
+	default:
+		profile, ok := services.FindWBProfile(profiles, sub)
+		if !ok {
+			fmt.Fprintf(stdout, "Unknown profile %q; see `openppl wb profiles`.\n", sub)
+			fmt.Fprintln(stdout, usage)
+			return 1
+		}
+		return runCompute(profile, args[1:], stdout)
+	}
+}
+
+func printProfile(profile services.WBProfile, stdout io.Writer) {
+	fmt.Fprintf(stdout, "%s (%s)\n", profile.Name, profile.ID)
+	fmt.Fprintf(stdout, "  Empty:       %.1f lb at %.2f in\n", profile.EmptyWeightLb, profile.EmptyArmIn)
+	fmt.Fprintf(stdout, "  Max takeoff: %.0f lb\n", profile.MaxTakeoffLb)
+	if profile.MaxLandingLb > 0 {
+		fmt.Fprintf(stdout, "  Max landing: %.0f lb\n", profile.MaxLandingLb)
+	}
+	fmt.Fprintf(stdout, "  Fuel:        %.0f gal usable at %.2f in\n", profile.FuelCapacityGal, profile.FuelArmIn)
+	fmt.Fprintln(stdout, "  Stations:")
+	for _, station := range profile.Stations {
+		limit := ""
+		if station.MaxLb > 0 {
+			limit = fmt.Sprintf(", max %.0f lb", station.MaxLb)
+		}
+		fmt.Fprintf(stdout, "    %-10s %s at %.2f in%s\n", station.ID, station.Name, station.ArmIn, limit)
+	}
+	fmt.Fprintln(stdout, "  Envelope:")
+	for _, point := range profile.Envelope {
+		fmt.Fprintf(stdout, "    %.2f in at %.0f lb\n", point.ArmIn, point.WeightLb)
+	}
+}
+
+// runCompute takes station weights as station=lb arguments before or after
+// the flags. Fuel defaults to full tanks.
+func runCompute(profile services.WBProfile, args []string, stdout io.Writer) int {
+	var assignments []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		assignments, args = append(assignments, args[0]), args[1:]
+	}
+	flags := flag.NewFlagSet("wb", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	fuel := flags.Float64("fuel", profile.FuelCapacityGal, "fuel at takeoff in gallons (default: full)")
+	burn := flags.Float64("burn", 0, "fuel burned by landing in gallons")
+	svgPath := flags.String("svg", "", "also write the envelope chart as SVG to FILE")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	assignments = append(assignments, flags.Args()...)
+
+	loading := services.WBLoading{Stations: map[string]float64{}, FuelGal: *fuel, BurnGal: *burn}
+	for _, assignment := range assignments {
+		id, value, found := strings.Cut(assignment, "=")
+		pounds, err := strconv.ParseFloat(value, 64)
+		if !found || err != nil {
+			fmt.Fprintf(stdout, "Invalid station weight %q, want <station>=<lb> such as front=340\n", assignment)
+			return 1
+		}
+		loading.Stations[id] += pounds
+	}
+
+	result, err := services.ComputeWeightBalance(profile, loading)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	printResult(result, stdout)
+
+	if *svgPath != "" {
+		if err := os.WriteFile(*svgPath, []byte(result.ChartSVG()), 0644); err != nil {
+			fmt.Fprintf(stdout, "Could not write %s: %v\n", *svgPath, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Wrote %s\n", *svgPath)
+	}
+	if !result.WithinLimits() {
+		return 1
+	}
+	return 0
+}
+
+func printResult(result services.WBResult, stdout io.Writer) {
+	fmt.Fprintf(stdout, "Weight and balance: %s\n", result.Profile.Name)
+	fmt.Fprintf(stdout, "  %-28s %8s %8s %10s\n", "Item", "Weight", "Arm", "Moment")
+	for _, line := range result.Lines {
+		fmt.Fprintf(stdout, "  %-28s %8.1f %8.2f %10.0f\n", line.Name, line.WeightLb, line.ArmIn, line.MomentLbIn())
+	}
+	for _, condition := range []struct {
+		name string
+		services.WBCondition
+	}{{"Takeoff", result.Takeoff}, {"Landing", result.Landing}} {
+		limits := "outside the envelope"
+		if !math.IsInf(condition.ForwardLimitIn, 0) {
+			limits = fmt.Sprintf("limits %.2f-%.2f", condition.ForwardLimitIn, condition.AftLimitIn)
+		}
+		fmt.Fprintf(stdout, "  %-28s %8.1f %8.2f %10.0f  %s\n", condition.name, condition.WeightLb, condition.CGIn, condition.MomentLbIn, limits)
+	}
+	fmt.Fprintln(stdout)
+	for _, line := range result.ChartLines(60, 16) {
+		fmt.Fprintln(stdout, line)
+	}
+	fmt.Fprintln(stdout, "        T takeoff, L landing")
+	fmt.Fprintln(stdout)
+	if result.WithinLimits() {
+		fmt.Fprintln(stdout, "Within limits at takeoff and landing.")
+		return
+	}
+	fmt.Fprintln(stdout, "OUT OF LIMITS:")
+	for _, problem := range result.Problems {
+		fmt.Fprintf(stdout, "  - %s\n", problem)
+	}
+}
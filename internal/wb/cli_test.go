@@ -0,0 +1,76 @@
+package wb
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompute_WithinLimitsExitsZero(t *testing.T) {
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+	svgPath := filepath.Join(t.TempDir(), "wb.svg")
+
+	var out bytes.Buffer
+	if code := Execute([]string{"c172s", "front=340", "--fuel", "53", "--burn", "10", "--svg", svgPath}, &out); code != 0 {
+		t.Fatalf("wb c172s = %d; output %q", code, out.String())
+	}
+	for _, want := range []string{"Weight and balance: Cessna 172S", "Takeoff", "2338.0", "Within limits at takeoff and landing.", "Wrote " + svgPath} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected output to contain %q, got %q", want, out.String())
+		}
+	}
+	if data, err := os.ReadFile(svgPath); err != nil || !strings.Contains(string(data), "<svg") {
+		t.Fatalf("expected an SVG chart, got %v", err)
+	}
+}
+
+func TestCompute_OutOfLimitsExitsOne(t *testing.T) {
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+
+	var out bytes.Buffer
+	// Station weights may follow the flags and repeat; repeats add up.
+	if code := Execute([]string{"c172s", "--fuel", "53", "front=200", "front=200", "rear=340"}, &out); code != 1 {
+		t.Fatalf("wb overweight = %d, want 1; output %q", code, out.String())
+	}
+	if !strings.Contains(out.String(), "OUT OF LIMITS:") || !strings.Contains(out.String(), "over the 2550 lb maximum") {
+		t.Fatalf("expected the overweight problem, got %q", out.String())
+	}
+}
+
+func TestProfilesAndShow(t *testing.T) {
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+
+	var out bytes.Buffer
+	if code := Execute(nil, &out); code != 0 || !strings.Contains(out.String(), "c172s") || !strings.Contains(out.String(), "pa28-161") {
+		t.Fatalf("wb = %d; output %q", code, out.String())
+	}
+	out.Reset()
+	if code := Execute([]string{"show", "c172s"}, &out); code != 0 || !strings.Contains(out.String(), "baggage1") || !strings.Contains(out.String(), "max 120 lb") {
+		t.Fatalf("wb show = %d; output %q", code, out.String())
+	}
+}
+
+func TestInvalidInputExitsOne(t *testing.T) {
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+	for args, want := range map[string]string{
+		"show":                      "usage:",
+		"show c150":                 `Unknown profile "c150"`,
+		"c150 front=340":            `Unknown profile "c150"`,
+		"c172s front":               `Invalid station weight "front"`,
+		"c172s front=heavy":         `Invalid station weight "front=heavy"`,
+		"c172s wing=10":             `unknown station "wing"`,
+		"c172s --fuel 80":           "more than the 53 gal usable fuel",
+		"c172s --fuel 10 --burn 20": "fuel burn 20.0 gal is more than the 10.0 gal on board",
+		"c172s --fuel lots":         "invalid value",
+	} {
+		var out bytes.Buffer
+		if code := Execute(strings.Fields(args), &out); code != 1 {
+			t.Fatalf("wb %s = %d, want 1; output %q", args, code, out.String())
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("wb %s: expected %q in output, got %q", args, want, out.String())
+		}
+	}
+}
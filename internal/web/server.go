@@ -1,10 +1,13 @@
 package web
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,8 +16,22 @@ import (
 	"ppl-study-planner/internal/services"
 )
 
+const (
+	webhookFlushInterval = 30 * time.Second
+	snapshotInterval     = time.Hour
+	weatherTimeout       = 5 * time.Second
+)
+
 type server struct {
-	db *gorm.DB
+	db        *gorm.DB
+	feedToken string
+	weather   services.WeatherProvider
+	// motdAttempts reads the quiz answers for the readiness score; nil
+	// means no quiz data.
+	motdAttempts func() []services.MOTDAnswer
+	// openMOTDDB opens the quiz database whose outbox holds quiz events; a
+	// nil database means the quiz has not been taken yet.
+	openMOTDDB func() (*gorm.DB, error)
 }
 
 type pageData struct {
@@ -23,21 +40,45 @@ type pageData struct {
 }
 
 func Run(db *gorm.DB, host string, port int) error {
-	s := &server{db: db}
+	s := &server{db: db, weather: services.DefaultWeatherProvider(), motdAttempts: services.ExistingMOTDAttempts, openMOTDDB: services.OpenExistingMOTDDB}
+	if token, err := services.LoadOrCreateCalendarFeedToken(); err != nil {
+		fmt.Printf("Calendar feed disabled: %v\n", err)
+	} else {
+		s.feedToken = token
+	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.dashboard)
 	mux.HandleFunc("/study", s.study)
 	mux.HandleFunc("/study/toggle", s.studyToggle)
+	mux.HandleFunc("/study/edit", s.studyEdit)
+	mux.HandleFunc("/study/delete", s.studyDelete)
 	mux.HandleFunc("/budget", s.budget)
 	mux.HandleFunc("/budget/update", s.budgetUpdate)
 	mux.HandleFunc("/checklist", s.checklist)
 	mux.HandleFunc("/checklist/toggle", s.checklistToggle)
+	mux.HandleFunc("/checklist/add", s.checklistAdd)
+	mux.HandleFunc("/checklist/edit", s.checklistEdit)
+	mux.HandleFunc("/checklist/delete", s.checklistDelete)
+	mux.HandleFunc("/milestones/toggle", s.milestoneToggle)
+	mux.HandleFunc("/endorsements", s.endorsements)
+	mux.HandleFunc("/endorsements/add", s.endorsementAdd)
+	mux.HandleFunc("/endorsements/delete", s.endorsementDelete)
+	mux.HandleFunc("/wb", s.weightBalance)
+	mux.HandleFunc("/api/progress/history", s.progressHistory)
+	mux.HandleFunc("/calendar.ics", s.calendarFeed)
+	mux.HandleFunc("/calendar/", s.calendarFeed)
+
+	go s.deliverWebhooksLoop(context.Background(), webhookFlushInterval)
+	go s.recordSnapshotsLoop(context.Background(), snapshotInterval)
 
 	bindAddr := fmt.Sprintf("%s:%d", host, port)
 	url := browserURL(host, port)
 	fmt.Printf("Web UI starting on http://%s\n", bindAddr)
 	fmt.Printf("Opening browser at %s\n", url)
+	if s.feedToken != "" {
+		fmt.Printf("Calendar feed: %s\n", s.feedURL(url, ""))
+	}
 	go func() {
 		time.Sleep(350 * time.Millisecond)
 		if err := openBrowser(url); err != nil {
@@ -54,31 +95,126 @@ func (s *server) dashboard(w http.ResponseWriter, r *http.Request) {
 	s.db.Find(&tasks)
 	completed, total, percentage := services.CalculateProgress(tasks)
 	body := template.HTML(fmt.Sprintf(`
-<p><strong>Progress:</strong> %d/%d completed (%.1f%%)</p>
+%s%s<p><strong>Progress:</strong> %d/%d completed (%.1f%%)</p>
+%s
+%s
+%s
+%s
 <ul>
   <li><a href="/study">Study tasks</a></li>
   <li><a href="/budget">Budget planner</a></li>
   <li><a href="/checklist">Checkride checklist</a></li>
+  <li><a href="/endorsements">Endorsements</a></li>
 </ul>
-`, completed, total, percentage))
+%s
+%s`, s.weatherBlock(r.Context()), s.warningsList(), completed, total, percentage, s.readinessBlock(), s.streakBlock(), s.trendBlock(), s.studyTimeBlock(), s.milestonesTable(), s.feedLinks(tasks)))
 	renderPage(w, pageData{Title: "Dashboard", Body: body})
 }
 
+// readinessBlock shows the checkride readiness score with its factors and
+// next actions.
+func (s *server) readinessBlock() string {
+	var attempts []services.MOTDAnswer
+	if s.motdAttempts != nil {
+		attempts = s.motdAttempts()
+	}
+	readiness := services.LoadReadiness(s.db, attempts, time.Now())
+	body := fmt.Sprintf(`<h3>Checkride readiness: %.0f/100 (%s)</h3><table><tr><th>Factor</th><th>Score</th><th>Weight</th><th>Based on</th></tr>`,
+		readiness.Score, template.HTMLEscapeString(readiness.Label))
+	for _, factor := range readiness.Factors {
+		body += fmt.Sprintf("<tr><td>%s</td><td>%.0f</td><td>%.0f%%</td><td>%s</td></tr>",
+			template.HTMLEscapeString(factor.Name), factor.Score, factor.Weight*100, template.HTMLEscapeString(factor.Detail))
+	}
+	body += "</table>"
+	if len(readiness.NextActions) > 0 {
+		body += "<p><strong>Next actions:</strong></p><ol>"
+		for _, action := range readiness.NextActions {
+			body += "<li>" + template.HTMLEscapeString(action) + "</li>"
+		}
+		body += "</ol>"
+	}
+	return body
+}
+
+func (s *server) milestonesTable() string {
+	milestones, err := services.LoadMilestoneStatuses(s.db, time.Now())
+	if err != nil || len(milestones) == 0 {
+		return ""
+	}
+
+	body := "<h3>Milestones</h3><table><tr><th>Milestone</th><th>Target</th><th>Status</th><th>Still needed</th><th></th></tr>"
+	for _, m := range milestones {
+		target := ""
+		if !m.TargetDate.IsZero() {
+			target = m.TargetDate.Format("2006-01-02")
+		}
+		status := m.State
+		if m.State == services.MilestoneStateAchieved {
+			status = "achieved " + m.AchievedAt.Local().Format("2006-01-02")
+		}
+		if m.Overdue {
+			status += " (overdue)"
+		}
+		var needed []string
+		needed = append(needed, m.MissingTasks...)
+		needed = append(needed, m.MissingChecklist...)
+		for _, name := range m.MissingMilestones {
+			needed = append(needed, "Milestone: "+name)
+		}
+		action, label := "achieve", "Mark achieved"
+		if m.State == services.MilestoneStateAchieved {
+			action, label = "reopen", "Reopen"
+		}
+		body += fmt.Sprintf(`<tr><td title="%s">%s</td><td>%s</td><td>%s</td><td>%s</td><td>
+<form method="POST" action="/milestones/toggle"><input type="hidden" name="id" value="%s"><input type="hidden" name="action" value="%s"><button type="submit">%s</button></form>
+</td></tr>`, template.HTMLEscapeString(m.Description), template.HTMLEscapeString(m.Name), target, template.HTMLEscapeString(status),
+			template.HTMLEscapeString(strings.Join(needed, ", ")), template.HTMLEscapeString(m.ID), action, label)
+	}
+	return body + "</table>"
+}
+
+func (s *server) milestoneToggle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+	if id := strings.TrimSpace(r.FormValue("id")); id != "" {
+		_ = services.SetMilestoneAchieved(s.db, id, r.FormValue("action") != "reopen", "web", time.Now())
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
 func (s *server) study(w http.ResponseWriter, r *http.Request) {
 	var tasks []model.DailyTask
 	s.db.Order("date asc, id asc").Find(&tasks)
 
-	body := "<h3>Study Tasks</h3><table><tr><th>Date</th><th>Code</th><th>Description</th><th>Done</th><th></th></tr>"
+	body := `<h3>Study Tasks</h3><p><a href="/study/edit">Add a task</a></p><table><tr><th>Date</th><th>Code</th><th>Description</th><th>Priority</th><th>Notes</th><th>Done</th><th></th></tr>`
 	for _, t := range tasks {
 		checked := ""
 		if t.Completed {
 			checked = "checked"
 		}
-		body += fmt.Sprintf(`<tr><td>%s</td><td>%s</td><td>%s</td><td><input type="checkbox" disabled %s></td><td>
+		var extras []string
+		if t.UserCreated {
+			extras = append(extras, "added")
+		}
+		if t.RescheduledFrom != nil {
+			extras = append(extras, "moved from "+t.RescheduledFrom.Format("2006-01-02"))
+		}
+		date := t.Date.Format("2006-01-02")
+		if len(extras) > 0 {
+			date += " <small>(" + strings.Join(extras, ", ") + ")</small>"
+		}
+		body += fmt.Sprintf(`<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td><input type="checkbox" disabled %s></td><td>
 <form method="POST" action="/study/toggle"><input type="hidden" name="id" value="%d"><button type="submit">Toggle</button></form>
-</td></tr>`, t.Date.Format("2006-01-02"), template.HTMLEscapeString(extractCode(t.Title)), template.HTMLEscapeString(extractDesc(t.Title)), checked, t.ID)
+<a href="/study/edit?id=%d">Edit</a>
+</td></tr>`, date, template.HTMLEscapeString(extractCode(t.Title)), template.HTMLEscapeString(extractDesc(t.Title)),
+			template.HTMLEscapeString(t.Priority), template.HTMLEscapeString(t.Notes), checked, t.ID, t.ID)
 	}
 	body += "</table>"
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		body = `<p><strong>Could not update the task:</strong> ` + template.HTMLEscapeString(msg) + "</p>" + body
+	}
 	renderPage(w, pageData{Title: "Study", Body: template.HTML(body)})
 }
 
@@ -89,11 +225,7 @@ func (s *server) studyToggle(w http.ResponseWriter, r *http.Request) {
 	}
 	id, _ := strconv.Atoi(r.FormValue("id"))
 	if id > 0 {
-		var task model.DailyTask
-		if err := s.db.First(&task, id).Error; err == nil {
-			task.Completed = !task.Completed
-			s.db.Save(&task)
-		}
+		_, _ = services.ToggleTaskCompletion(s.db, uint(id), "web")
 	}
 	http.Redirect(w, r, "/study", http.StatusSeeOther)
 }
@@ -123,39 +255,76 @@ func (s *server) budgetUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if v, err := strconv.ParseFloat(r.FormValue("plane_rate"), 64); err == nil {
-		upsertBudget(s.db, model.BudgetPlaneRate, v)
+	fields := []struct {
+		name     string
+		itemType model.BudgetItemType
+	}{
+		{"plane_rate", model.BudgetPlaneRate},
+		{"cfi_rate", model.BudgetCfiRate},
+		{"living", model.BudgetLiving},
+		{"budget_limit", model.BudgetLimit},
 	}
-	if v, err := strconv.ParseFloat(r.FormValue("cfi_rate"), 64); err == nil {
-		upsertBudget(s.db, model.BudgetCfiRate, v)
-	}
-	if v, err := strconv.ParseFloat(r.FormValue("living"), 64); err == nil {
-		upsertBudget(s.db, model.BudgetLiving, v)
-	}
-	if v, err := strconv.ParseFloat(r.FormValue("budget_limit"), 64); err == nil {
-		upsertBudget(s.db, model.BudgetLimit, v)
+	for _, field := range fields {
+		v, err := strconv.ParseFloat(r.FormValue(field.name), 64)
+		if err != nil {
+			continue
+		}
+		if err := services.SaveBudgetItem(s.db, field.itemType, v, "web"); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 	}
 
 	http.Redirect(w, r, "/budget", http.StatusSeeOther)
 }
 
 func (s *server) checklist(w http.ResponseWriter, r *http.Request) {
-	var items []model.ChecklistItem
-	s.db.Order("id asc").Find(&items)
+	_ = services.SeedChecklist(s.db)
+	_ = services.RefreshChecklist(s.db, time.Now())
+	items, _ := services.ListChecklistItems(s.db)
 	body := "<h3>Checkride Checklist</h3><table><tr><th>Category</th><th>Item</th><th>Done</th><th></th></tr>"
 	for _, item := range items {
 		checked := ""
 		if item.Completed {
 			checked = "checked"
 		}
+		title := template.HTMLEscapeString(item.Title)
+		actions := fmt.Sprintf(`<form method="POST" action="/checklist/toggle"><input type="hidden" name="id" value="%d"><button type="submit">Toggle</button></form>`, item.ID)
+		if item.TemplateKey == "" {
+			title = fmt.Sprintf(`<form method="POST" action="/checklist/edit"><input type="hidden" name="id" value="%d"><select name="category">%s</select>
+<input name="title" value="%s"><button type="submit">Save</button></form>`, item.ID, checklistCategoryOptions(item.Category), title)
+			actions += fmt.Sprintf(`<form method="POST" action="/checklist/delete"><input type="hidden" name="id" value="%d"><button type="submit">Delete</button></form>`, item.ID)
+		}
 		body += fmt.Sprintf(`<tr><td>%s</td><td>%s</td><td><input type="checkbox" disabled %s></td><td>
-<form method="POST" action="/checklist/toggle"><input type="hidden" name="id" value="%d"><button type="submit">Toggle</button></form>
-</td></tr>`, template.HTMLEscapeString(string(item.Category)), template.HTMLEscapeString(item.Title), checked, item.ID)
+%s
+</td></tr>`, template.HTMLEscapeString(string(item.Category)), title, checked, actions)
 	}
 	body += "</table>"
+	body += fmt.Sprintf(`
+<h3>Add custom item</h3>
+<form method="POST" action="/checklist/add">
+  <label>Category: <select name="category">%s</select></label>
+  <label>Item: <input name="title"></label>
+  <button type="submit">Add</button>
+</form>`, checklistCategoryOptions(model.CategoryDocuments))
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		body = `<p><strong>Could not update checklist:</strong> ` + template.HTMLEscapeString(msg) + "</p>" + body
+	}
 	renderPage(w, pageData{Title: "Checklist", Body: template.HTML(body)})
 }
 
+func checklistCategoryOptions(selected model.ChecklistCategory) string {
+	options := ""
+	for _, category := range services.ChecklistCategories {
+		attr := ""
+		if category == selected {
+			attr = " selected"
+		}
+		options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, category, attr, category)
+	}
+	return options
+}
+
 func (s *server) checklistToggle(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Redirect(w, r, "/checklist", http.StatusSeeOther)
@@ -163,15 +332,312 @@ func (s *server) checklistToggle(w http.ResponseWriter, r *http.Request) {
 	}
 	id, _ := strconv.Atoi(r.FormValue("id"))
 	if id > 0 {
-		var item model.ChecklistItem
-		if err := s.db.First(&item, id).Error; err == nil {
-			item.Completed = !item.Completed
-			s.db.Save(&item)
-		}
+		_, _ = services.ToggleChecklistItem(s.db, uint(id))
+	}
+	http.Redirect(w, r, "/checklist", http.StatusSeeOther)
+}
+
+func (s *server) checklistAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/checklist", http.StatusSeeOther)
+		return
+	}
+	_, err := services.AddChecklistItem(s.db, r.FormValue("category"), r.FormValue("title"))
+	redirectChecklist(w, r, err)
+}
+
+func (s *server) checklistEdit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/checklist", http.StatusSeeOther)
+		return
+	}
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	_, err := services.UpdateChecklistItem(s.db, uint(id), r.FormValue("category"), r.FormValue("title"))
+	redirectChecklist(w, r, err)
+}
+
+func (s *server) checklistDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/checklist", http.StatusSeeOther)
+		return
+	}
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	redirectChecklist(w, r, services.DeleteChecklistItem(s.db, uint(id)))
+}
+
+func redirectChecklist(w http.ResponseWriter, r *http.Request, err error) {
+	if err != nil {
+		http.Redirect(w, r, "/checklist?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
 	}
 	http.Redirect(w, r, "/checklist", http.StatusSeeOther)
 }
 
+// weatherBlock shows the school airport's flight category and the flight
+// tasks its TAF puts on IFR days.
+func (s *server) weatherBlock(ctx context.Context) string {
+	if s.weather == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(ctx, weatherTimeout)
+	defer cancel()
+	brief, err := services.LoadWeatherBrief(ctx, s.db, s.weather, time.Now())
+	if err != nil || brief.Station == "" {
+		return ""
+	}
+	category := "unknown"
+	if brief.METAR != nil && brief.METAR.Category != services.FlightCategoryUnknown {
+		category = string(brief.METAR.Category)
+	}
+	body := fmt.Sprintf(`<p class="weather weather-%s"><strong>Weather:</strong> %s</p>`,
+		strings.ToLower(category), template.HTMLEscapeString(brief.Summary()))
+	if brief.METAR != nil && brief.METAR.Raw != "" {
+		body += "<pre>" + template.HTMLEscapeString(brief.METAR.Raw) + "</pre>"
+	}
+	warnings := brief.Warnings()
+	if len(warnings) == 0 {
+		return body
+	}
+	body += `<ul class="warnings">`
+	for _, warning := range warnings {
+		body += "<li>⚠ " + template.HTMLEscapeString(warning) + "</li>"
+	}
+	return body + "</ul>"
+}
+
+func (s *server) warningsList() string {
+	warnings := services.DashboardWarnings(s.db, time.Now())
+	if len(warnings) == 0 {
+		return ""
+	}
+	body := `<ul class="warnings">`
+	for _, warning := range warnings {
+		body += "<li>⚠ " + template.HTMLEscapeString(warning) + "</li>"
+	}
+	return body + "</ul>"
+}
+
+func (s *server) endorsements(w http.ResponseWriter, r *http.Request) {
+	endorsements, _ := services.ListEndorsements(s.db)
+	now := time.Now()
+
+	body := s.warningsList() + "<h3>Endorsements</h3><table><tr><th>Issued</th><th>Endorsement</th><th>CFI</th><th>Valid through</th><th>Notes</th><th></th></tr>"
+	for _, e := range endorsements {
+		kind, _ := services.LookupEndorsementKind(e.Kind)
+		validity := "—"
+		if e.ExpiresOn != nil {
+			validity = e.ExpiresOn.Format("2006-01-02")
+			if !services.EndorsementCurrent(e, now) {
+				validity += " (lapsed)"
+			}
+		}
+		cfi := e.CFIName + ", " + e.CFICertificate
+		if e.CFIExpiresOn != nil {
+			cfi += " exp " + e.CFIExpiresOn.Format("2006-01-02")
+		}
+		body += fmt.Sprintf(`<tr><td>%s</td><td>%s <small>%s</small></td><td>%s</td><td>%s</td><td>%s</td><td>
+<form method="POST" action="/endorsements/delete"><input type="hidden" name="id" value="%d"><button type="submit">Delete</button></form>
+</td></tr>`, e.IssuedOn.Format("2006-01-02"), template.HTMLEscapeString(kind.Title), template.HTMLEscapeString(kind.Reference),
+			template.HTMLEscapeString(cfi), validity, template.HTMLEscapeString(e.Notes), e.ID)
+	}
+	body += "</table>"
+
+	options := ""
+	for _, kind := range services.EndorsementKinds {
+		options += fmt.Sprintf(`<option value="%s">%s (%s)</option>`, kind.Key, template.HTMLEscapeString(kind.Title), template.HTMLEscapeString(kind.Reference))
+	}
+	body += fmt.Sprintf(`
+<h3>Record endorsement</h3>
+<form method="POST" action="/endorsements/add">
+  <label>Kind: <select name="kind">%s</select></label><br>
+  <label>Issued: <input type="date" name="issued_on" value="%s"></label><br>
+  <label>CFI name: <input name="cfi_name"></label><br>
+  <label>CFI certificate: <input name="cfi_certificate"></label><br>
+  <label>CFI certificate expires: <input type="date" name="cfi_expires_on"></label><br>
+  <label>Notes (route for solo XC flights): <input name="notes"></label><br>
+  <button type="submit">Save</button>
+</form>`, options, now.Format("2006-01-02"))
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		body = `<p><strong>Could not save endorsement:</strong> ` + template.HTMLEscapeString(msg) + "</p>" + body
+	}
+	renderPage(w, pageData{Title: "Endorsements", Body: template.HTML(body)})
+}
+
+func (s *server) endorsementAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/endorsements", http.StatusSeeOther)
+		return
+	}
+	input := services.EndorsementInput{
+		Kind:           r.FormValue("kind"),
+		CFIName:        r.FormValue("cfi_name"),
+		CFICertificate: r.FormValue("cfi_certificate"),
+		Notes:          r.FormValue("notes"),
+	}
+	input.IssuedOn, _ = time.Parse("2006-01-02", r.FormValue("issued_on"))
+	input.CFIExpiresOn, _ = time.Parse("2006-01-02", r.FormValue("cfi_expires_on"))
+	if _, err := services.AddEndorsement(s.db, input, time.Now()); err != nil {
+		http.Redirect(w, r, "/endorsements?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/endorsements", http.StatusSeeOther)
+}
+
+func (s *server) endorsementDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/endorsements", http.StatusSeeOther)
+		return
+	}
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	if id > 0 {
+		_ = services.DeleteEndorsement(s.db, uint(id), time.Now())
+	}
+	http.Redirect(w, r, "/endorsements", http.StatusSeeOther)
+}
+
+// weightBalance is a GET form: the profile, station weights and fuel come in
+// as query parameters so a loading can be bookmarked or shared.
+func (s *server) weightBalance(w http.ResponseWriter, r *http.Request) {
+	profiles, err := services.LoadWBProfiles()
+	body := ""
+	if err != nil {
+		body += `<p><strong>Warning:</strong> ` + template.HTMLEscapeString(err.Error()) + "</p>"
+	}
+	if len(profiles) == 0 {
+		renderPage(w, pageData{Title: "Weight & Balance", Body: template.HTML(body + "<p>No aircraft profiles.</p>")})
+		return
+	}
+	query := r.URL.Query()
+	profile, ok := services.FindWBProfile(profiles, query.Get("profile"))
+	if !ok {
+		profile = profiles[0]
+	}
+
+	loading := services.WBLoading{Stations: map[string]float64{}, FuelGal: profile.FuelCapacityGal}
+	if value, err := strconv.ParseFloat(query.Get("fuel"), 64); err == nil {
+		loading.FuelGal = value
+	}
+	loading.BurnGal, _ = strconv.ParseFloat(query.Get("burn"), 64)
+	for _, station := range profile.Stations {
+		loading.Stations[station.ID], _ = strconv.ParseFloat(query.Get(station.ID), 64)
+	}
+
+	options := ""
+	for _, p := range profiles {
+		selected := ""
+		if p.ID == profile.ID {
+			selected = " selected"
+		}
+		options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, template.HTMLEscapeString(p.ID), selected, template.HTMLEscapeString(p.Name))
+	}
+	body += fmt.Sprintf(`<h3>Weight &amp; Balance</h3>
+<form method="GET" action="/wb">
+  <label>Aircraft: <select name="profile" onchange="this.form.submit()">%s</select></label><br>`, options)
+	for _, station := range profile.Stations {
+		limit := ""
+		if station.MaxLb > 0 {
+			limit = fmt.Sprintf(" (max %.0f lb)", station.MaxLb)
+		}
+		body += fmt.Sprintf(`
+  <label>%s%s: <input type="number" step="any" min="0" name="%s" value="%g"> lb</label><br>`,
+			template.HTMLEscapeString(station.Name), limit, template.HTMLEscapeString(station.ID), loading.Stations[station.ID])
+	}
+	body += fmt.Sprintf(`
+  <label>Fuel at takeoff: <input type="number" step="any" min="0" name="fuel" value="%g"> gal (of %.0f)</label><br>
+  <label>Fuel burned: <input type="number" step="any" min="0" name="burn" value="%g"> gal</label><br>
+  <button type="submit">Compute</button>
+</form>`, loading.FuelGal, profile.FuelCapacityGal, loading.BurnGal)
+
+	result, err := services.ComputeWeightBalance(profile, loading)
+	if err != nil {
+		body += `<p><strong>Could not compute:</strong> ` + template.HTMLEscapeString(err.Error()) + "</p>"
+		renderPage(w, pageData{Title: "Weight & Balance", Body: template.HTML(body)})
+		return
+	}
+	body += "<table><tr><th>Item</th><th>Weight (lb)</th><th>Arm (in)</th><th>Moment (lb-in)</th></tr>"
+	for _, line := range result.Lines {
+		body += fmt.Sprintf("<tr><td>%s</td><td>%.1f</td><td>%.2f</td><td>%.0f</td></tr>", template.HTMLEscapeString(line.Name), line.WeightLb, line.ArmIn, line.MomentLbIn())
+	}
+	for _, condition := range []struct {
+		name string
+		services.WBCondition
+	}{{"Takeoff", result.Takeoff}, {"Landing", result.Landing}} {
+		body += fmt.Sprintf("<tr><th>%s</th><th>%.1f</th><th>%.2f</th><th>%.0f</th></tr>", condition.name, condition.WeightLb, condition.CGIn, condition.MomentLbIn)
+	}
+	body += "</table>"
+	if result.WithinLimits() {
+		body += "<p><strong>Within limits at takeoff and landing.</strong></p>"
+	} else {
+		body += "<p><strong>Out of limits:</strong></p><ul>"
+		for _, problem := range result.Problems {
+			body += "<li>" + template.HTMLEscapeString(problem) + "</li>"
+		}
+		body += "</ul>"
+	}
+	body += result.ChartSVG()
+	renderPage(w, pageData{Title: "Weight & Balance", Body: template.HTML(body)})
+}
+
+// calendarFeed serves /calendar.ics and /calendar/<category>.ics for calendar
+// subscriptions. The secret token in the query string is the only auth, so
+// unknown or missing tokens get a plain 404.
+func (s *server) calendarFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if !services.CalendarFeedTokenMatches(s.feedToken, r.URL.Query().Get("token")) {
+		http.NotFound(w, r)
+		return
+	}
+
+	name := "openppl study plan"
+	tasks, err := services.LoadLatestPlanTasks(s.db)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if r.URL.Path != "/calendar.ics" {
+		slug, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/calendar/"), ".ics")
+		if !ok || slug == "" || strings.Contains(slug, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		tasks = services.FilterTasksByCategorySlug(tasks, slug)
+		if len(tasks) > 0 {
+			name = fmt.Sprintf("openppl %s", tasks[0].Category)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	_, _ = w.Write([]byte(services.BuildCalendarFeed(tasks, services.CalendarFeedOptions{Name: name, Location: services.SchoolAirportLocation(s.db)})))
+}
+
+func (s *server) feedURL(base string, category string) string {
+	path := "/calendar.ics"
+	if category != "" {
+		path = "/calendar/" + services.CategorySlug(category) + ".ics"
+	}
+	return base + path + "?token=" + s.feedToken
+}
+
+func (s *server) feedLinks(tasks []model.DailyTask) string {
+	if s.feedToken == "" {
+		return ""
+	}
+	seen := map[string]bool{}
+	links := fmt.Sprintf(`<p><strong>Calendar feed:</strong> <a href="%s">all tasks</a>`, template.HTMLEscapeString(s.feedURL("", "")))
+	for _, task := range tasks {
+		if task.Category == "" || seen[task.Category] {
+			continue
+		}
+		seen[task.Category] = true
+		links += fmt.Sprintf(` · <a href="%s">%s</a>`, template.HTMLEscapeString(s.feedURL("", task.Category)), template.HTMLEscapeString(task.Category))
+	}
+	return links + "<br><small>Subscribe with the full URL (including the token) in your calendar app.</small></p>"
+}
+
 func renderPage(w http.ResponseWriter, p pageData) {
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	layout := `<!doctype html>
@@ -180,7 +646,7 @@ func renderPage(w http.ResponseWriter, p pageData) {
 <style>body{font-family:ui-sans-serif,system-ui;padding:18px;max-width:1100px;margin:0 auto}nav a{margin-right:12px}table{border-collapse:collapse;width:100%}th,td{border:1px solid #ddd;padding:8px;text-align:left}button{padding:4px 8px}</style>
 </head><body>
 <h1>openppl web</h1>
-<nav><a href="/">Dashboard</a><a href="/study">Study</a><a href="/budget">Budget</a><a href="/checklist">Checklist</a></nav>
+<nav><a href="/">Dashboard</a><a href="/study">Study</a><a href="/budget">Budget</a><a href="/checklist">Checklist</a><a href="/endorsements">Endorsements</a><a href="/wb">W&amp;B</a></nav>
 <hr>
 {{.Body}}
 </body></html>`
@@ -199,17 +665,59 @@ func readBudget(db *gorm.DB, itemType model.BudgetItemType, fallback float64) fl
 	return budget.Amount
 }
 
-func upsertBudget(db *gorm.DB, itemType model.BudgetItemType, amount float64) {
-	var budget model.Budget
-	if err := db.Where("item_type = ?", itemType).Order("id asc").Limit(1).Find(&budget).Error; err != nil {
-		return
+// deliverWebhooksLoop periodically flushes the plan and quiz outboxes while
+// the web server runs. The quiz database is opened once it exists and kept
+// open until the loop stops.
+func (s *server) deliverWebhooksLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var motdDB *gorm.DB
+	defer func() {
+		if motdDB != nil {
+			if sqlDB, err := motdDB.DB(); err == nil {
+				sqlDB.Close()
+			}
+		}
+	}()
+	for {
+		if _, err := services.DeliverPendingWebhooks(ctx, s.db); err != nil {
+			fmt.Printf("Webhook delivery failed: %v\n", err)
+		}
+		if motdDB == nil && s.openMOTDDB != nil {
+			motdDB, _ = s.openMOTDDB()
+		}
+		if motdDB != nil {
+			if _, err := services.DeliverPendingWebhooks(ctx, motdDB); err != nil {
+				fmt.Printf("Quiz webhook delivery failed: %v\n", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	if budget.ID == 0 {
-		_ = db.Create(&model.Budget{ItemType: itemType, Amount: amount}).Error
-		return
+}
+
+// recordSnapshotsLoop records today's progress snapshot on start and then
+// every interval, so a server left running keeps the trend history going.
+func (s *server) recordSnapshotsLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		var attempts []services.MOTDAnswer
+		if s.motdAttempts != nil {
+			attempts = s.motdAttempts()
+		}
+		if _, err := services.RecordProgressSnapshot(s.db, attempts, time.Now()); err != nil {
+			fmt.Printf("Could not record progress snapshot: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
-	budget.Amount = amount
-	_ = db.Save(&budget).Error
 }
 
 func extractCode(title string) string {
@@ -0,0 +1,471 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+func TestCalendarFeedRequiresTokenAndFiltersByCategory(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	old := model.StudyPlan{CheckrideDate: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}
+	current := model.StudyPlan{CheckrideDate: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)}
+	db.Create(&old)
+	db.Create(&current)
+	db.Create(&model.DailyTask{StudyPlanID: old.ID, Date: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Old plan review"})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Category: "Theory", Title: "Area 1 Review"})
+	db.Create(&model.DailyTask{StudyPlanID: current.ID, Date: time.Date(2026, 7, 2, 0, 0, 0, 0, time.UTC), Category: "CFI Flights", Title: "Pattern work"})
+
+	s := &server{db: db, feedToken: "secret"}
+
+	tests := []struct {
+		path     string
+		status   int
+		contains string
+		excludes string
+	}{
+		{path: "/calendar.ics", status: http.StatusNotFound},
+		{path: "/calendar.ics?token=wrong", status: http.StatusNotFound},
+		{path: "/calendar.ics?token=secret", status: http.StatusOK, contains: "Pattern work", excludes: "Old plan review"},
+		{path: "/calendar/cfi-flights.ics?token=secret", status: http.StatusOK, contains: "Pattern work", excludes: "Area 1 Review"},
+		{path: "/calendar/cfi-flights?token=secret", status: http.StatusNotFound},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		s.calendarFeed(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Fatalf("%s: expected status %d, got %d", tc.path, tc.status, rec.Code)
+		}
+		body := rec.Body.String()
+		if tc.contains != "" && !strings.Contains(body, tc.contains) {
+			t.Fatalf("%s: expected body to contain %q", tc.path, tc.contains)
+		}
+		if tc.excludes != "" && strings.Contains(body, tc.excludes) {
+			t.Fatalf("%s: expected body to exclude %q", tc.path, tc.excludes)
+		}
+		if tc.status == http.StatusOK && !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/calendar") {
+			t.Fatalf("%s: unexpected content type %q", tc.path, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestMilestoneToggleMarksAchievedAndDashboardShowsIt(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.ChecklistItem{}, &model.Milestone{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", t.TempDir()+"/plan_template.json")
+	s := &server{db: db}
+
+	form := strings.NewReader("id=pre-solo-knowledge-test&action=achieve")
+	req := httptest.NewRequest(http.MethodPost, "/milestones/toggle", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.milestoneToggle(rec, req)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("expected redirect, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.dashboard(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "<h3>Milestones</h3>") || !strings.Contains(body, "Pre-solo knowledge test") || !strings.Contains(body, "Reopen") {
+		t.Fatalf("expected achieved milestone on dashboard, got:\n%s", body)
+	}
+}
+
+func TestEndorsementAddShowsOnEndorsementsPage(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ChecklistItem{}, &model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	s := &server{db: db}
+
+	issued := time.Now().AddDate(0, 0, -85).Format("2006-01-02")
+	form := strings.NewReader("kind=solo&issued_on=" + issued + "&cfi_name=Jane+Doe&cfi_certificate=1234567CFI")
+	req := httptest.NewRequest(http.MethodPost, "/endorsements/add", form)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	s.endorsementAdd(rec, req)
+	if location := rec.Header().Get("Location"); location != "/endorsements" {
+		t.Fatalf("expected redirect to /endorsements, got %q", location)
+	}
+
+	rec = httptest.NewRecorder()
+	s.endorsements(rec, httptest.NewRequest(http.MethodGet, "/endorsements", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "Jane Doe, 1234567CFI") || !strings.Contains(body, "lapses in 5 days") {
+		t.Fatalf("expected endorsement and lapse warning, got:\n%s", body)
+	}
+}
+
+func TestChecklistCustomItemsCanBeAddedAndRemoved(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.ChecklistItem{}, &model.AppConfig{}, &model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	s := &server{db: db}
+	post := func(handler http.HandlerFunc, form string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/checklist", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := httptest.NewRecorder()
+	s.checklist(rec, httptest.NewRequest(http.MethodGet, "/checklist", nil))
+	if !strings.Contains(rec.Body.String(), "IACRA application") {
+		t.Fatalf("expected the default checklist to be seeded, got:\n%s", rec.Body.String())
+	}
+
+	if rec := post(s.checklistAdd, "category=Aircraft&title=Fuel+receipt"); rec.Header().Get("Location") != "/checklist" {
+		t.Fatalf("unexpected add redirect %q", rec.Header().Get("Location"))
+	}
+	var item model.ChecklistItem
+	if err := db.Where("title = ?", "Fuel receipt").First(&item).Error; err != nil {
+		t.Fatalf("expected custom item: %v", err)
+	}
+	post(s.checklistEdit, fmt.Sprintf("id=%d&category=Ground&title=Fuel+receipt+copy", item.ID))
+	db.First(&item, item.ID)
+	if item.Title != "Fuel receipt copy" || item.Category != model.CategoryGround {
+		t.Fatalf("expected edited item, got %+v", item)
+	}
+
+	var seeded model.ChecklistItem
+	db.Where("template_key = ?", "iacra").First(&seeded)
+	if rec := post(s.checklistDelete, fmt.Sprintf("id=%d", seeded.ID)); !strings.Contains(rec.Header().Get("Location"), "error=") {
+		t.Fatal("expected deleting a default item to redirect with an error")
+	}
+	post(s.checklistDelete, fmt.Sprintf("id=%d", item.ID))
+	if err := db.First(&model.ChecklistItem{}, item.ID).Error; err == nil {
+		t.Fatal("expected custom item to be deleted")
+	}
+}
+
+func TestDashboardShowsSchoolAirportFlightCategory(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.DailyTask{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.AppConfig{Key: "school_airport", Value: "KFXE"})
+
+	dir := t.TempDir()
+	metar := `[{"icaoId":"KFXE","obsTime":1780314780,"visib":2,"rawOb":"KFXE 011153Z 00000KT 2SM BR OVC008 24/23 A3004","clouds":[{"cover":"OVC","base":800}]}]`
+	if err := os.WriteFile(filepath.Join(dir, "KFXE-metar.json"), []byte(metar), 0o600); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	s := &server{db: db, weather: services.FileWeatherProvider{Dir: dir}}
+
+	body := s.weatherBlock(context.Background())
+	if !strings.Contains(body, "KFXE IFR") || !strings.Contains(body, "OVC008") {
+		t.Fatalf("expected IFR weather for KFXE, got %s", body)
+	}
+}
+
+func TestWeightBalancePageComputesFromQuery(t *testing.T) {
+	t.Setenv("OPENPPL_WB_PROFILES_PATH", filepath.Join(t.TempDir(), "wb_profiles.json"))
+	s := &server{}
+
+	rec := httptest.NewRecorder()
+	s.weightBalance(rec, httptest.NewRequest(http.MethodGet, "/wb?profile=c172s&front=340&fuel=53&burn=10", nil))
+	body := rec.Body.String()
+	if !strings.Contains(body, "Within limits at takeoff and landing") || !strings.Contains(body, "<svg") {
+		t.Fatalf("expected a within-limits result with a chart, got:\n%s", body)
+	}
+
+	rec = httptest.NewRecorder()
+	s.weightBalance(rec, httptest.NewRequest(http.MethodGet, "/wb?profile=c172s&front=400&rear=340", nil))
+	if body := rec.Body.String(); !strings.Contains(body, "Out of limits") || !strings.Contains(body, "over the 2550 lb maximum") {
+		t.Fatalf("expected an over-weight result, got:\n%s", body)
+	}
+}
+
+func TestDashboardShowsReadinessBreakdown(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudyPlan{}, &model.ChecklistItem{}, &model.Milestone{}, &model.LogbookEntry{}, &model.Endorsement{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", filepath.Join(t.TempDir(), "plan_template.json"))
+	plan := model.StudyPlan{CheckrideDate: time.Now().AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Now(), Category: "Theory", Title: "Airspace", Completed: true})
+	s := &server{db: db, motdAttempts: func() []services.MOTDAnswer {
+		return []services.MOTDAnswer{{Date: "2026-03-01", ACSCode: "PA.I.A.K1", IsCorrect: true}}
+	}}
+
+	rec := httptest.NewRecorder()
+	s.dashboard(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+	for _, want := range []string{"Checkride readiness: 31/100 (Needs work)", "<td>Study plan</td><td>100</td><td>30%</td>", "1 of 12 ACS areas quizzed", "Endorsement needed: Pre-solo aeronautical knowledge"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q on the dashboard, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestProgressHistoryAndDashboardTrends(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudyPlan{}, &model.ChecklistItem{}, &model.Milestone{}, &model.LogbookEntry{}, &model.Endorsement{}, &model.ProgressSnapshot{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", filepath.Join(t.TempDir(), "plan_template.json"))
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	for i, completed := range []int{4, 8} {
+		db.Create(&model.ProgressSnapshot{Date: today.AddDate(0, 0, i-1), TasksCompleted: completed, TasksTotal: 12, ProgressPercent: float64(completed) / 12 * 100, BudgetUSD: 10000})
+	}
+	s := &server{db: db}
+
+	rec := httptest.NewRecorder()
+	s.progressHistory(rec, httptest.NewRequest(http.MethodGet, "/api/progress/history?days=30", nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, want := range []string{`"tasks_completed":8`, `"remaining":4`, `"tasks_per_day":4`, `"date":"` + today.AddDate(0, 0, 1).Format("2006-01-02") + `"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %s in history, got:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	s.progressHistory(rec, httptest.NewRequest(http.MethodGet, "/api/progress/history?days=soon", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a bad request for an invalid days, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.dashboard(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body = rec.Body.String()
+	for _, want := range []string{"<h3>Trends</h3>", "Projected completion", "<title>Progress (%)</title>", "<title>Spend (USD)</title>"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q on the dashboard, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestDashboardShowsStudyStreakHeatmap(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.Progress{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	task := model.DailyTask{Date: time.Now(), Category: "Theory", Title: "Airspace"}
+	db.Create(&task)
+	if _, err := services.ToggleTaskCompletion(db, task.ID, "web"); err != nil {
+		t.Fatalf("toggle: %v", err)
+	}
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+	s := &server{db: db, motdAttempts: func() []services.MOTDAnswer {
+		return []services.MOTDAnswer{{Date: yesterday, IsCorrect: true}}
+	}}
+
+	body := s.streakBlock()
+	for _, want := range []string{"Study streak: 2 days (longest 2)", "Studied today.", "<title>Study heatmap</title>", "1 tasks</title>", ": quiz</title>"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in the streak block, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestDashboardShowsStudyTime(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudySession{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	s := &server{db: db}
+	if body := s.studyTimeBlock(); body != "" {
+		t.Fatalf("expected no study time block without sessions, got %q", body)
+	}
+
+	task := model.DailyTask{Date: time.Now(), Category: "Theory", Title: "Airspace", DurationMinutes: 60, ACSRefs: "PA.I.E"}
+	db.Create(&task)
+	start := time.Now().Add(-time.Hour)
+	if _, err := services.StartStudySession(db, task.ID, false, "web", start); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if _, err := services.StopStudySession(db, start.Add(45*time.Minute)); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	body := s.studyTimeBlock()
+	for _, want := range []string{"Study time: 0.8 h timed", "<tr><td>Theory</td><td>0.8 h</td><td>1.0 h</td><td>1</td><td>1</td></tr>", "<th>ACS area</th>", "<tr><td>I</td>"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in the study time block, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestStudyTasksCanBeAddedEditedAndDeleted(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.StudyPlan{}, &model.DailyTask{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	db.Create(&model.StudyPlan{CheckrideDate: time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC)})
+	s := &server{db: db}
+	post := func(handler http.HandlerFunc, form string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/study/edit", strings.NewReader(form))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	if rec := post(s.studyEdit, "date=2026-06-01&category=CFI+Flights&title=&priority=high"); !strings.Contains(rec.Header().Get("Location"), "/study/edit?error=task+title+is+required") {
+		t.Fatalf("expected a missing title to return to the form, got %q", rec.Header().Get("Location"))
+	}
+	if rec := post(s.studyEdit, "date=2026-06-01&category=CFI+Flights&title=PA.V.A+Steep+turns&priority=high&minutes=90&notes=Bring+the+POH"); rec.Header().Get("Location") != "/study" {
+		t.Fatalf("unexpected add redirect %q", rec.Header().Get("Location"))
+	}
+	var task model.DailyTask
+	if err := db.Where("title = ?", "PA.V.A Steep turns").First(&task).Error; err != nil || !task.UserCreated || task.Priority != "high" || task.DurationMinutes != 90 {
+		t.Fatalf("expected the user-created task, got %+v (%v)", task, err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.studyEdit(rec, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/study/edit?id=%d", task.ID), nil))
+	for _, want := range []string{"Edit study task", `value="2026-06-01"`, `<option value="high" selected>`, "Bring the POH</textarea>", `action="/study/delete"`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Fatalf("expected %q in the edit form, got:\n%s", want, rec.Body.String())
+		}
+	}
+
+	post(s.studyEdit, fmt.Sprintf("id=%d&date=2026-06-04&category=CFI+Flights&title=PA.V.A+Steep+turns&priority=normal&notes=Bring+the+POH", task.ID))
+	rec = httptest.NewRecorder()
+	s.study(rec, httptest.NewRequest(http.MethodGet, "/study", nil))
+	if !strings.Contains(rec.Body.String(), "2026-06-04 <small>(added, moved from 2026-06-01)</small>") {
+		t.Fatalf("expected the moved task on the study page, got:\n%s", rec.Body.String())
+	}
+
+	post(s.studyDelete, fmt.Sprintf("id=%d", task.ID))
+	if err := db.First(&model.DailyTask{}, task.ID).Error; err == nil {
+		t.Fatal("expected the task to be deleted")
+	}
+}
+
+func TestDeliverWebhooksLoopFlushesQuizOutbox(t *testing.T) {
+	delivered := make(chan string, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- r.Header.Get(services.WebhookEventHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	configPath := filepath.Join(t.TempDir(), "webhooks.json")
+	config := fmt.Sprintf(`{"endpoints":[{"url":%q,"secret":"s3cret"}]}`, receiver.URL)
+	if err := os.WriteFile(configPath, []byte(config), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("OPENPPL_WEBHOOKS_PATH", configPath)
+
+	open := func(name string) *gorm.DB {
+		db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s_%s?mode=memory&cache=shared", t.Name(), name)), &gorm.Config{})
+		if err != nil {
+			t.Fatalf("open db: %v", err)
+		}
+		if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+		return db
+	}
+	planDB, quizDB := open("plan"), open("quiz")
+	if err := services.RecordEvent(quizDB, services.EventQuizAnswered, map[string]string{"date": "2026-03-01"}); err != nil {
+		t.Fatalf("record quiz event: %v", err)
+	}
+	opened := 0
+	s := &server{db: planDB, openMOTDDB: func() (*gorm.DB, error) {
+		opened++
+		return quizDB, nil
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.deliverWebhooksLoop(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	select {
+	case event := <-delivered:
+		if event != services.EventQuizAnswered {
+			t.Fatalf("expected a quiz event, got %q", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("quiz outbox was not flushed")
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	<-done
+	if opened != 1 {
+		t.Fatalf("expected the quiz database to be opened once and reused, got %d opens", opened)
+	}
+}
+
+func TestRecordSnapshotsLoopRecordsOnStart(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.DailyTask{}, &model.StudyPlan{}, &model.ChecklistItem{}, &model.Milestone{}, &model.LogbookEntry{}, &model.Endorsement{}, &model.Budget{}, &model.ProgressSnapshot{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Setenv("OPENPPL_PLAN_TEMPLATE_PATH", filepath.Join(t.TempDir(), "plan_template.json"))
+	plan := model.StudyPlan{CheckrideDate: time.Now().AddDate(0, 2, 0)}
+	db.Create(&plan)
+	db.Create(&model.DailyTask{StudyPlanID: plan.ID, Date: time.Now(), Category: "Theory", Title: "Airspace", Completed: true})
+	s := &server{db: db}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.recordSnapshotsLoop(ctx, time.Hour)
+
+	snapshots, err := services.ListProgressSnapshots(db, time.Time{})
+	if err != nil {
+		t.Fatalf("ListProgressSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].TasksCompleted != 1 || snapshots[0].TasksTotal != 1 {
+		t.Fatalf("expected today's snapshot on start, got %+v", snapshots)
+	}
+}
@@ -0,0 +1,149 @@
+package web
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+// studyEdit shows the add or edit form for a study task on GET, with ?id=
+// selecting the task to edit, and saves it on POST.
+func (s *server) studyEdit(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	if r.Method == http.MethodPost {
+		s.studySave(w, r, uint(id))
+		return
+	}
+
+	task := model.DailyTask{Date: time.Now(), Category: services.Categories[0], Priority: services.TaskPriorityNormal}
+	title := "Add study task"
+	if id > 0 {
+		if err := s.db.First(&task, id).Error; err != nil {
+			http.Redirect(w, r, "/study?error="+url.QueryEscape(fmt.Sprintf("task %d not found", id)), http.StatusSeeOther)
+			return
+		}
+		title = "Edit study task"
+	}
+	body := "<h3>" + title + "</h3>"
+	if msg := r.URL.Query().Get("error"); msg != "" {
+		body += `<p><strong>Could not save the task:</strong> ` + template.HTMLEscapeString(msg) + "</p>"
+	}
+	body += taskFormHTML(task)
+	if task.ID != 0 {
+		body += fmt.Sprintf(`<form method="POST" action="/study/delete"><input type="hidden" name="id" value="%d"><button type="submit">Delete task</button></form>`, task.ID)
+	}
+	renderPage(w, pageData{Title: "Study", Body: template.HTML(body)})
+}
+
+func (s *server) studySave(w http.ResponseWriter, r *http.Request, id uint) {
+	input := services.TaskInput{
+		Category:    r.FormValue("category"),
+		Title:       r.FormValue("title"),
+		Description: r.FormValue("description"),
+		Notes:       r.FormValue("notes"),
+		Priority:    r.FormValue("priority"),
+	}
+	var err error
+	if input.Date, err = time.Parse("2006-01-02", r.FormValue("date")); err != nil {
+		redirectStudyEdit(w, r, id, fmt.Errorf("invalid date %q", r.FormValue("date")))
+		return
+	}
+	if value := strings.TrimSpace(r.FormValue("minutes")); value != "" {
+		if input.DurationMinutes, err = strconv.Atoi(value); err != nil {
+			redirectStudyEdit(w, r, id, fmt.Errorf("invalid minutes %q", value))
+			return
+		}
+	}
+	if id == 0 {
+		_, err = services.CreateTask(s.db, input, "web")
+	} else {
+		_, err = services.UpdateTask(s.db, id, input, "web")
+	}
+	if err != nil {
+		redirectStudyEdit(w, r, id, err)
+		return
+	}
+	http.Redirect(w, r, "/study", http.StatusSeeOther)
+}
+
+func (s *server) studyDelete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Redirect(w, r, "/study", http.StatusSeeOther)
+		return
+	}
+	id, _ := strconv.Atoi(r.FormValue("id"))
+	if _, err := services.DeleteTask(s.db, uint(id), "web"); err != nil {
+		http.Redirect(w, r, "/study?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+	http.Redirect(w, r, "/study", http.StatusSeeOther)
+}
+
+func redirectStudyEdit(w http.ResponseWriter, r *http.Request, id uint, err error) {
+	target := "/study/edit?error=" + url.QueryEscape(err.Error())
+	if id != 0 {
+		target += fmt.Sprintf("&id=%d", id)
+	}
+	http.Redirect(w, r, target, http.StatusSeeOther)
+}
+
+func taskFormHTML(task model.DailyTask) string {
+	minutes := ""
+	if task.DurationMinutes > 0 {
+		minutes = strconv.Itoa(task.DurationMinutes)
+	}
+	return fmt.Sprintf(`<form method="POST" action="/study/edit">
+  <input type="hidden" name="id" value="%d">
+  <label>Date: <input type="date" name="date" value="%s"></label><br>
+  <label>Category: <select name="category">%s</select></label><br>
+  <label>Title: <input name="title" value="%s" size="60"></label><br>
+  <label>Description: <input name="description" value="%s" size="60"></label><br>
+  <label>Priority: <select name="priority">%s</select></label><br>
+  <label>Minutes: <input type="number" name="minutes" min="0" value="%s"></label><br>
+  <label>Notes:<br><textarea name="notes" rows="4" cols="60">%s</textarea></label><br>
+  <button type="submit">Save</button> <a href="/study">Cancel</a>
+</form>`, task.ID, task.Date.Format("2006-01-02"), taskCategoryOptions(task.Category), template.HTMLEscapeString(task.Title),
+		template.HTMLEscapeString(task.Description), taskPriorityOptions(task.Priority), minutes, template.HTMLEscapeString(task.Notes))
+}
+
+func taskCategoryOptions(selected string) string {
+	categories := append([]string(nil), services.Categories...)
+	found := false
+	for _, category := range categories {
+		found = found || category == selected
+	}
+	if !found && selected != "" {
+		categories = append(categories, selected)
+	}
+	options := ""
+	for _, category := range categories {
+		attr := ""
+		if category == selected {
+			attr = " selected"
+		}
+		options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, template.HTMLEscapeString(category), attr, template.HTMLEscapeString(category))
+	}
+	return options
+}
+
+func taskPriorityOptions(selected string) string {
+	if selected == "" {
+		selected = services.TaskPriorityNormal
+	}
+	options := ""
+	for _, priority := range services.TaskPriorities {
+		attr := ""
+		if priority == selected {
+			attr = " selected"
+		}
+		options += fmt.Sprintf(`<option value="%s"%s>%s</option>`, priority, attr, priority)
+	}
+	return options
+}
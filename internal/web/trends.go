@@ -0,0 +1,285 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ppl-study-planner/internal/model"
+	"ppl-study-planner/internal/services"
+)
+
+// trendDays is how much snapshot history the dashboard charts cover by
+// default.
+const trendDays = 90
+
+// trendSeries is one line of a trend chart.
+type trendSeries struct {
+	label  string
+	color  string
+	dashed bool
+	values []float64
+}
+
+// progressHistory is the JSON shape of /api/progress/history.
+type progressHistory struct {
+	Snapshots  []model.ProgressSnapshot `json:"snapshots"`
+	Projection progressProjection       `json:"projection"`
+}
+
+type progressProjection struct {
+	OK          bool    `json:"ok"`
+	Date        string  `json:"date,omitempty"`
+	TasksPerDay float64 `json:"tasks_per_day"`
+	Remaining   int     `json:"remaining"`
+	Summary     string  `json:"summary"`
+}
+
+func (s *server) loadTrend(days int) ([]model.ProgressSnapshot, time.Time, error) {
+	var plan model.StudyPlan
+	var checkride time.Time
+	if err := s.db.Order("id desc").Limit(1).Find(&plan).Error; err == nil {
+		checkride = plan.CheckrideDate
+	}
+	snapshots, err := services.ListProgressSnapshots(s.db, time.Now().AddDate(0, 0, -days))
+	return snapshots, checkride, err
+}
+
+// progressHistory serves the daily snapshots and the projected completion
+// date for other frontends. ?days= narrows the history, 0 returns it all.
+func (s *server) progressHistory(w http.ResponseWriter, r *http.Request) {
+	days := trendDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "days must be a whole number of days", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+	if days == 0 {
+		days = math.MaxInt32
+	}
+	snapshots, checkride, err := s.loadTrend(days)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	projection := services.ProjectCompletion(snapshots)
+	history := progressHistory{
+		Snapshots: snapshots,
+		Projection: progressProjection{
+			OK:          projection.OK,
+			TasksPerDay: projection.TasksPerDay,
+			Remaining:   projection.Remaining,
+			Summary:     projection.Describe(checkride),
+		},
+	}
+	if history.Snapshots == nil {
+		history.Snapshots = []model.ProgressSnapshot{}
+	}
+	if projection.OK {
+		history.Projection.Date = projection.Date.Format("2006-01-02")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(history)
+}
+
+// trendBlock charts the daily snapshots on the dashboard: progress against
+// the plan, readiness and spend against the budget.
+func (s *server) trendBlock() string {
+	snapshots, checkride, err := s.loadTrend(trendDays)
+	if err != nil || len(snapshots) < 2 {
+		return "<h3>Trends</h3><p>Trends appear after snapshots from two days; one is recorded each day the app runs.</p>"
+	}
+	dates := make([]time.Time, len(snapshots))
+	progress, planned := make([]float64, len(snapshots)), make([]float64, len(snapshots))
+	readiness, spent := make([]float64, len(snapshots)), make([]float64, len(snapshots))
+	budget := 0.0
+	for i, snapshot := range snapshots {
+		dates[i] = snapshot.Date
+		progress[i], planned[i] = snapshot.ProgressPercent, snapshot.PlannedPercent
+		readiness[i], spent[i] = snapshot.ReadinessScore, snapshot.SpentUSD
+		budget = math.Max(budget, snapshot.BudgetUSD)
+	}
+	budgetLine := make([]float64, len(snapshots))
+	for i := range budgetLine {
+		budgetLine[i] = budget
+	}
+
+	var b strings.Builder
+	b.WriteString("<h3>Trends</h3>")
+	b.WriteString("<p>" + template.HTMLEscapeString(services.ProjectCompletion(snapshots).Describe(checkride)) + "</p>")
+	b.WriteString(`<div style="display:flex;flex-wrap:wrap;gap:12px">`)
+	b.WriteString(trendChartSVG("Progress (%)", dates, 0, 100, "%.0f", []trendSeries{
+		{label: "Completed", color: "#1f6fb2", values: progress},
+		{label: "Planned", color: "#888", dashed: true, values: planned},
+	}))
+	b.WriteString(trendChartSVG("Readiness", dates, 0, 100, "%.0f", []trendSeries{
+		{label: "Score", color: "#2e7d32", values: readiness},
+	}))
+	b.WriteString(trendChartSVG("Spend (USD)", dates, 0, math.Max(budget, maxValue(spent)), "$%.0f", []trendSeries{
+		{label: "Spent", color: "#c62828", values: spent},
+		{label: "Budget", color: "#888", dashed: true, values: budgetLine},
+	}))
+	b.WriteString("</div>")
+	return b.String()
+}
+
+// trendChartSVG draws a small line chart of series over dates, with the
+// y axis from low to high labelled in format.
+func trendChartSVG(title string, dates []time.Time, low, high float64, format string, series []trendSeries) string {
+	const (
+		width, height = 340, 200
+		left, bottom  = 48, 40
+		top, right    = 24, 12
+	)
+	if high <= low {
+		high = low + 1
+	}
+	x := func(i int) float64 {
+		if len(dates) < 2 {
+			return left
+		}
+		return left + float64(i)/float64(len(dates)-1)*(width-left-right)
+	}
+	y := func(value float64) float64 {
+		return top + (high-value)/(high-low)*(height-top-bottom)
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="11">`, width, height, width, height)
+	fmt.Fprintf(&svg, `<title>%s</title><text x="%d" y="14" font-weight="bold">%s</text>`, html.EscapeString(title), left, html.EscapeString(title))
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`, left, height-bottom, width-right, height-bottom)
+	fmt.Fprintf(&svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#333"/>`, left, top, left, height-bottom)
+	for _, value := range []float64{low, (low + high) / 2, high} {
+		fmt.Fprintf(&svg, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, left-4, y(value)+4, fmt.Sprintf(format, value))
+	}
+	if len(dates) > 0 {
+		fmt.Fprintf(&svg, `<text x="%d" y="%d">%s</text>`, left, height-bottom+14, dates[0].Format("Jan 2"))
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" text-anchor="end">%s</text>`, width-right, height-bottom+14, dates[len(dates)-1].Format("Jan 2"))
+	}
+	for i, line := range series {
+		points := make([]string, len(line.values))
+		for j, value := range line.values {
+			points[j] = fmt.Sprintf("%.1f,%.1f", x(j), y(value))
+		}
+		dash := ""
+		if line.dashed {
+			dash = ` stroke-dasharray="5 4"`
+		}
+		fmt.Fprintf(&svg, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"%s/>`, strings.Join(points, " "), line.color, dash)
+		fmt.Fprintf(&svg, `<text x="%d" y="%d" fill="%s">%s</text>`, left+i*90, height-6, line.color, html.EscapeString(line.label))
+	}
+	svg.WriteString("</svg>")
+	return svg.String()
+}
+
+func maxValue(values []float64) float64 {
+	highest := 0.0
+	for _, value := range values {
+		highest = math.Max(highest, value)
+	}
+	return highest
+}
+
+// heatColors are the streak heatmap fills for activity levels 0-4; missed
+// days use missedColor.
+var heatColors = []string{"#ebedf0", "#c6e48b", "#7bc96f", "#239a3b", "#196127"}
+
+const missedColor = "#f4b6b6"
+
+// streakBlock shows the study streak over tasks and quiz answers with a
+// heatmap of the last weeks.
+func (s *server) streakBlock() string {
+	var attempts []services.MOTDAnswer
+	if s.motdAttempts != nil {
+		attempts = s.motdAttempts()
+	}
+	streaks, err := services.LoadStudyStreaks(s.db, attempts, time.Now(), services.StreakWeeks)
+	if err != nil {
+		return ""
+	}
+	status := "Complete a task or answer the quiz today to keep it going."
+	if streaks.Today {
+		status = "Studied today."
+	}
+	return fmt.Sprintf(`<h3>Study streak: %d days (longest %d)</h3><p>%s %.0f%% of days studied over the last %d weeks, %d missed.</p>%s`,
+		streaks.Current, streaks.Longest, status, streaks.Consistency(), len(streaks.Weeks), streaks.MissedDays, streakHeatmapSVG(streaks))
+}
+
+// streakHeatmapSVG draws one column per week and one row per weekday,
+// Monday on top.
+func streakHeatmapSVG(streaks services.StudyStreaks) string {
+	const (
+		cell, gap = 12, 3
+		left, top = 30, 16
+	)
+	width := left + len(streaks.Weeks)*(cell+gap)
+	height := top + 7*(cell+gap)
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="10">`, width, height, width, height)
+	svg.WriteString(`<title>Study heatmap</title>`)
+	for row, label := range []string{"Mon", "Wed", "Fri"} {
+		fmt.Fprintf(&svg, `<text x="0" y="%d">%s</text>`, top+row*2*(cell+gap)+cell-2, label)
+	}
+	for i, day := range streaks.Days {
+		week, weekday := i/7, i%7
+		if weekday == 0 && week%4 == 0 {
+			fmt.Fprintf(&svg, `<text x="%d" y="10">%s</text>`, left+week*(cell+gap), day.Date.Format("Jan 2"))
+		}
+		color := heatColors[day.Level()]
+		detail := "no study"
+		switch {
+		case day.Active():
+			var parts []string
+			if day.Tasks > 0 {
+				parts = append(parts, fmt.Sprintf("%d tasks", day.Tasks))
+			}
+			if day.Quiz {
+				parts = append(parts, "quiz")
+			}
+			detail = strings.Join(parts, " + ")
+		case day.Missed:
+			color, detail = missedColor, "missed"
+		}
+		fmt.Fprintf(&svg, `<rect x="%d" y="%d" width="%d" height="%d" rx="2" fill="%s"><title>%s: %s</title></rect>`,
+			left+week*(cell+gap), top+weekday*(cell+gap), cell, cell, color, day.Date.Format("Mon Jan 2"), detail)
+	}
+	svg.WriteString("</svg>")
+	return svg.String()
+}
+
+// studyTimeBlock compares the hours timed in the TUI or with `openppl timer`
+// against the hours planned for the same tasks.
+func (s *server) studyTimeBlock() string {
+	report, err := services.LoadStudyTimeReport(s.db)
+	if err != nil || len(report.ByCategory) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<h3>Study time: %.1f h timed</h3>", report.TotalMinutes/60)
+	b.WriteString(studyTimeTable("Category", report.ByCategory))
+	if len(report.ByArea) > 0 {
+		b.WriteString(studyTimeTable("ACS area", report.ByArea))
+	}
+	return b.String()
+}
+
+func studyTimeTable(heading string, rows []services.StudyTimeRow) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<table><tr><th>%s</th><th>Actual</th><th>Planned</th><th>Tasks</th><th>Sessions</th></tr>", heading)
+	for _, row := range rows {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%.1f h</td><td>%.1f h</td><td>%d</td><td>%d</td></tr>",
+			template.HTMLEscapeString(row.Name), row.ActualMinutes/60, row.PlannedMinutes/60, row.Tasks, row.Sessions)
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
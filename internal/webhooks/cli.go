@@ -0,0 +1,126 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+var (
+	openMOTDDB      = services.InitMOTDDB
+	loadConfig      = services.LoadWebhookConfig
+	deliveryTimeout = 2 * time.Minute
+)
+
+// Execute is the dispatcher for `openppl webhooks [subcommand]`.
+// Events live in two outboxes: the plan database (task, plan and budget
+// events) and the per-user MOTD database (quiz events). Every subcommand
+// operates on both.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "", "status":
+		return runStatus(database, stdout)
+	case "deliver", "flush":
+		return runDeliver(database, stdout)
+	case "retry":
+		return runRetry(database, stdout)
+	default:
+		fmt.Fprintln(stdout, "usage: openppl webhooks [status|deliver|retry]")
+		return 1
+	}
+}
+
+func runStatus(database *gorm.DB, stdout io.Writer) int {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load webhook config: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "Webhook endpoints:")
+	if len(cfg.Endpoints) == 0 {
+		fmt.Fprintln(stdout, "  none configured (add them to ~/.openppl/webhooks.json)")
+	}
+	for _, endpoint := range cfg.Endpoints {
+		events := "all events"
+		if len(endpoint.Events) > 0 {
+			events = fmt.Sprintf("%v", endpoint.Events)
+		}
+		fmt.Fprintf(stdout, "  %s -> %s\n", endpoint.URL, events)
+	}
+
+	for _, outbox := range outboxes(database) {
+		stats, err := services.LoadWebhookOutboxStats(outbox.db)
+		if err != nil {
+			fmt.Fprintf(stdout, "Could not read %s outbox: %v\n", outbox.name, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s outbox: %d pending, %d delivered, %d failed\n", outbox.name, stats.Pending, stats.Delivered, stats.Failed)
+	}
+	return 0
+}
+
+func runDeliver(database *gorm.DB, stdout io.Writer) int {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load webhook config: %v\n", err)
+		return 1
+	}
+	if len(cfg.Endpoints) == 0 {
+		fmt.Fprintln(stdout, "No webhook endpoints configured. Events stay queued in the outbox.")
+		return 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	exitCode := 0
+	for _, outbox := range outboxes(database) {
+		result, err := services.NewWebhookDispatcher(outbox.db, cfg).DeliverPending(ctx)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s outbox delivery failed: %v\n", outbox.name, err)
+			exitCode = 1
+			continue
+		}
+		fmt.Fprintf(stdout, "%s outbox: %d attempted, %d delivered, %d retrying, %d failed\n", outbox.name, result.Attempted, result.Delivered, result.Retrying, result.Failed)
+	}
+	return exitCode
+}
+
+func runRetry(database *gorm.DB, stdout io.Writer) int {
+	for _, outbox := range outboxes(database) {
+		count, err := services.RetryFailedWebhooks(outbox.db, time.Now())
+		if err != nil {
+			fmt.Fprintf(stdout, "Could not requeue %s outbox: %v\n", outbox.name, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "%s outbox: %d failed events requeued\n", outbox.name, count)
+	}
+	return 0
+}
+
+type namedOutbox struct {
+	name string
+	db   *gorm.DB
+}
+
+func outboxes(database *gorm.DB) []namedOutbox {
+	result := make([]namedOutbox, 0, 2)
+	if database != nil {
+		result = append(result, namedOutbox{name: "Plan", db: database})
+	}
+	if motdDB, err := openMOTDDB(); err == nil {
+		result = append(result, namedOutbox{name: "Quiz", db: motdDB})
+	}
+	return result
+}
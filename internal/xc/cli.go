@@ -0,0 +1,245 @@
+package xc
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/services"
+)
+
+const usage = `usage:
+  openppl xc plan [<from>] <to> [<to> ...] [--tail N12345] [--tas KT] [--burn GPH] [--fuel GAL]
+      [--wind 270@15[,300@20 ...]] [--variation 6W] [--night] [--solo] [--date YYYY-MM-DD]
+      [--csv FILE] [--pdf FILE] [--log] [--remarks "<text>"]
+  openppl xc log
+  openppl xc flown <id> --hours 2.4 [--date YYYY-MM-DD]`
+
+var now = time.Now
+
+// Execute is the dispatcher for `openppl xc [subcommand]`.
+func Execute(database *gorm.DB, args []string, stdout io.Writer) int {
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	switch sub {
+	case "plan", "navlog":
+		return runPlan(database, args[1:], stdout)
+	case "", "log", "logbook":
+		return runLog(database, stdout)
+	case "flown", "fly":
+		return runFlown(database, args[1:], stdout)
+	default:
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+}
+
+// runPlan builds a navlog. Airports come first, as in `openppl airports
+// distance`; a single airport starts from the school airport.
+func runPlan(database *gorm.DB, args []string, stdout io.Writer) int {
+	var codes []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		codes, args = append(codes, args[0]), args[1:]
+	}
+
+	flags := flag.NewFlagSet("xc plan", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	tail := flags.String("tail", "", "aircraft tail number (default: the only registered aircraft)")
+	tas := flags.Float64("tas", 0, "cruise true airspeed in knots (default: the aircraft's)")
+	burn := flags.Float64("burn", 0, "fuel burn in gallons per hour (default: the aircraft's)")
+	fuel := flags.Float64("fuel", 0, "usable fuel in gallons (default: the aircraft's)")
+	winds := flags.String("wind", "", "winds aloft, one for all legs or one per leg, e.g. 270@15,300@20")
+	variation := flags.String("variation", "", "magnetic variation, e.g. 6W or 3E")
+	night := flags.Bool("night", false, "night flight (45-minute fuel reserve)")
+	solo := flags.Bool("solo", false, "solo flight")
+	date := flags.String("date", "", "planned date, YYYY-MM-DD (default: today)")
+	csvPath := flags.String("csv", "", "also write the navlog as CSV to FILE")
+	pdfPath := flags.String("pdf", "", "also write the navlog as PDF to FILE")
+	logIt := flags.Bool("log", false, "record the planned flight in the logbook")
+	remarks := flags.String("remarks", "", "logbook remarks")
+	if err := flags.Parse(args); err != nil {
+		return 1
+	}
+	codes = append(codes, flags.Args()...)
+	if len(codes) == 0 {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	if len(codes) == 1 {
+		school, err := services.SchoolAirport(database)
+		if err != nil || school == "" {
+			fmt.Fprintln(stdout, "No school airport set; pass the departure airport too.")
+			return 1
+		}
+		codes = append([]string{school}, codes...)
+	}
+
+	airports, err := services.LoadAirports()
+	if err != nil {
+		fmt.Fprintf(stdout, "Warning: %v\n", err)
+		if airports == nil {
+			return 1
+		}
+	}
+	route, err := services.PlanRoute(airports, codes)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+
+	options := services.NavlogOptions{Night: *night, Date: now()}
+	if options.Performance, err = services.LoadAircraftPerformance(database, *tail); err != nil {
+		fmt.Fprintf(stdout, "Could not load aircraft: %v\n", err)
+		return 1
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "tas":
+			options.Performance.CruiseKTAS = *tas
+		case "burn":
+			options.Performance.FuelBurnGPH = *burn
+		case "fuel":
+			options.Performance.UsableFuelGal = *fuel
+		}
+	})
+	if *winds != "" {
+		for _, text := range strings.Split(*winds, ",") {
+			wind, err := services.ParseWindAloft(text)
+			if err != nil {
+				fmt.Fprintln(stdout, err)
+				return 1
+			}
+			options.Winds = append(options.Winds, wind)
+		}
+	}
+	if *variation != "" {
+		if options.VariationDeg, err = services.ParseVariation(*variation); err != nil {
+			fmt.Fprintln(stdout, err)
+			return 1
+		}
+	}
+	if *date != "" {
+		if options.Date, err = time.Parse("2006-01-02", *date); err != nil {
+			fmt.Fprintf(stdout, "Invalid --date %q, want YYYY-MM-DD\n", *date)
+			return 1
+		}
+	}
+
+	navlog, err := services.BuildNavlog(route, options)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+		return 1
+	}
+	if err := navlog.WriteText(stdout); err != nil {
+		return 1
+	}
+	for path, write := range map[string]func(io.Writer) error{*csvPath: navlog.WriteCSV, *pdfPath: navlog.WritePDF} {
+		if path == "" {
+			continue
+		}
+		if err := writeFile(path, write); err != nil {
+			fmt.Fprintf(stdout, "Could not write %s: %v\n", path, err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Wrote %s\n", path)
+	}
+
+	if *logIt {
+		entry, err := services.LogPlannedCrossCountry(database, navlog, *solo, options.Date, *remarks)
+		if err != nil {
+			fmt.Fprintf(stdout, "Could not log the flight: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Logged as planned flight #%d. After flying it: openppl xc flown %d --hours N\n", entry.ID, entry.ID)
+	}
+	return 0
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func runLog(database *gorm.DB, stdout io.Writer) int {
+	entries, err := services.ListLogbook(database)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not load logbook: %v\n", err)
+		return 1
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "No flights logged yet. Plan one with `openppl xc plan KFXE KVRB KOBE KFXE --log`.")
+	}
+	for _, entry := range entries {
+		status := fmt.Sprintf("planned %.1fh", entry.PlannedHours)
+		if entry.FlownAt != nil {
+			status = fmt.Sprintf("flown %.1fh", entry.Hours)
+		}
+		kind := "dual"
+		if entry.Solo {
+			kind = "solo"
+		}
+		if entry.LongSoloXC {
+			kind += ", long XC"
+		} else if entry.CrossCountry {
+			kind += ", XC"
+		}
+		fmt.Fprintf(stdout, "  #%-4d %s  %-13s %-16s %5.0f nm  %s\n", entry.ID, entry.Date.Format("2006-01-02"), status, kind, entry.DistanceNM, entry.Route)
+	}
+
+	progress := services.SummarizeCrossCountry(entries)
+	fmt.Fprintf(stdout, "Cross-country flown: %.1fh dual, %.1fh solo; %d planned\n", progress.DualHours, progress.SoloHours, progress.Planned)
+	for _, deficit := range progress.Deficits() {
+		fmt.Fprintf(stdout, "  - %s\n", deficit)
+	}
+	return 0
+}
+
+func runFlown(database *gorm.DB, args []string, stdout io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stdout, usage)
+		return 1
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		fmt.Fprintf(stdout, "Invalid logbook entry %q\n", args[0])
+		return 1
+	}
+	flags := flag.NewFlagSet("xc flown", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	hours := flags.Float64("hours", 0, "flight time")
+	date := flags.String("date", "", "date flown, YYYY-MM-DD (default: today)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+	flownAt := now()
+	if *date != "" {
+		if flownAt, err = time.Parse("2006-01-02", *date); err != nil {
+			fmt.Fprintf(stdout, "Invalid --date %q, want YYYY-MM-DD\n", *date)
+			return 1
+		}
+	}
+
+	entry, err := services.MarkLogbookFlown(database, uint(id), *hours, flownAt)
+	if err != nil {
+		fmt.Fprintf(stdout, "Could not update the logbook: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Logged #%d %s as flown, %.1fh\n", entry.ID, entry.Route, entry.Hours)
+	return 0
+}
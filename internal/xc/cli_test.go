@@ -0,0 +1,108 @@
+package xc
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"ppl-study-planner/internal/model"
+)
+
+func TestPlanLogAndFly(t *testing.T) {
+	db := setupXCCLITestDB(t)
+	csvPath := filepath.Join(t.TempDir(), "navlog.csv")
+
+	var out bytes.Buffer
+	args := []string{"plan", "KFXE", "KVRB", "KOBE", "KFXE", "--tas", "110", "--burn", "8.5", "--fuel", "53", "--wind", "270@15", "--variation", "6W", "--solo", "--csv", csvPath, "--log"}
+	if code := Execute(db, args, &out); code != 0 {
+		t.Fatalf("xc plan = %d; output %q", code, out.String())
+	}
+	for _, want := range []string{"KFXE", "KVRB", "Wrote " + csvPath, "Logged as planned flight #1. After flying it: openppl xc flown 1 --hours N"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected plan output to contain %q, got %q", want, out.String())
+		}
+	}
+	if data, err := os.ReadFile(csvPath); err != nil || !strings.HasPrefix(string(data), "from,to,distance_nm") {
+		t.Fatalf("expected a navlog CSV, got %q, %v", data, err)
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"flown", "1", "--hours", "2.6", "--date", "2026-06-02"}, &out); code != 0 {
+		t.Fatalf("xc flown = %d; output %q", code, out.String())
+	}
+	if !strings.Contains(out.String(), "Logged #1 KFXE KVRB KOBE KFXE as flown, 2.6h") {
+		t.Fatalf("unexpected flown output %q", out.String())
+	}
+
+	out.Reset()
+	if code := Execute(db, []string{"log"}, &out); code != 0 {
+		t.Fatalf("xc log = %d", code)
+	}
+	if !strings.Contains(out.String(), "flown 2.6h") || !strings.Contains(out.String(), "solo, long XC") || !strings.Contains(out.String(), "Cross-country flown: 0.0h dual, 2.6h solo; 0 planned") {
+		t.Fatalf("unexpected log output %q", out.String())
+	}
+}
+
+func TestPlan_RejectsBadWinds(t *testing.T) {
+	db := setupXCCLITestDB(t)
+	for args, want := range map[string]string{
+		"--wind 270@xx":         `invalid wind "270@xx"`,
+		"--wind 270@15,300@20":  "got 2 winds for 3 legs",
+		"--wind 400@10":         `invalid wind "400@10"`,
+		"--wind 270@15 --tas x": "invalid value",
+	} {
+		var out bytes.Buffer
+		full := append([]string{"plan", "KFXE", "KVRB", "KOBE", "KFXE", "--tas", "110"}, strings.Fields(args)...)
+		if code := Execute(db, full, &out); code != 1 {
+			t.Fatalf("xc plan %s = %d, want 1; output %q", args, code, out.String())
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("xc plan %s: expected %q in output, got %q", args, want, out.String())
+		}
+	}
+
+	var logged int64
+	db.Model(&model.LogbookEntry{}).Count(&logged)
+	if logged != 0 {
+		t.Fatalf("expected nothing logged, got %d entries", logged)
+	}
+}
+
+func TestInvalidInputExitsOne(t *testing.T) {
+	db := setupXCCLITestDB(t)
+	for _, args := range [][]string{
+		{"plan"},
+		{"plan", "KPBI", "--tas", "110"},
+		{"plan", "KFXE", "KPBI"},
+		{"plan", "KFXE", "KNOPE", "--tas", "110"},
+		{"plan", "KFXE", "KPBI", "--tas", "110", "--date", "June 1"},
+		{"flown"},
+		{"flown", "one", "--hours", "1"},
+		{"flown", "7", "--hours", "1"},
+		{"fly-away"},
+	} {
+		var out bytes.Buffer
+		if code := Execute(db, args, &out); code != 1 {
+			t.Fatalf("Execute(%v) = %d, want 1; output %q", args, code, out.String())
+		}
+	}
+}
+
+func setupXCCLITestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("OPENPPL_AIRPORTS_PATH", filepath.Join(t.TempDir(), "airports.csv"))
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&model.AppConfig{}, &model.Aircraft{}, &model.AircraftAD{}, &model.LogbookEntry{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
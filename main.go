@@ -11,12 +11,26 @@ import (
 	"strings"
 	"time"
 
+	"ppl-study-planner/internal/aircraft"
+	"ppl-study-planner/internal/airports"
 	"ppl-study-planner/internal/automation"
+	"ppl-study-planner/internal/checklist"
+	"ppl-study-planner/internal/daemon"
 	"ppl-study-planner/internal/db"
+	"ppl-study-planner/internal/documents"
+	"ppl-study-planner/internal/endorsements"
+	"ppl-study-planner/internal/gonogo"
+	"ppl-study-planner/internal/importer"
+	"ppl-study-planner/internal/logbook"
 	"ppl-study-planner/internal/motd"
 	"ppl-study-planner/internal/onboarding"
+	"ppl-study-planner/internal/plan"
+	"ppl-study-planner/internal/timer"
 	"ppl-study-planner/internal/tui"
+	"ppl-study-planner/internal/wb"
 	"ppl-study-planner/internal/web"
+	"ppl-study-planner/internal/webhooks"
+	"ppl-study-planner/internal/xc"
 )
 
 var (
@@ -100,6 +114,47 @@ func run() error {
 		case "motd":
 			os.Exit(runMotdCommand(remaining))
 			return nil
+		case "webhooks":
+			os.Exit(runWebhooksCommand(remaining))
+			return nil
+		case "import":
+			os.Exit(runImportCommand(remaining))
+			return nil
+		case "daemon":
+			os.Exit(runDaemonCommand(remaining))
+			return nil
+		case "plan":
+			os.Exit(plan.Execute(remaining, os.Stdout))
+			return nil
+		case "endorsements":
+			os.Exit(runEndorsementsCommand(remaining))
+			return nil
+		case "checklist":
+			os.Exit(runChecklistCommand(remaining))
+			return nil
+		case "aircraft":
+			os.Exit(runAircraftCommand(remaining))
+			return nil
+		case "documents":
+			os.Exit(runDocumentsCommand(remaining))
+			return nil
+		case "gonogo":
+			os.Exit(runGoNoGoCommand(remaining))
+			return nil
+		case "airports":
+			os.Exit(runAirportsCommand(remaining))
+			return nil
+		case "xc":
+			os.Exit(runXCCommand(remaining))
+		case "logbook":
+			os.Exit(runLogbookCommand(remaining))
+			return nil
+		case "wb":
+			os.Exit(wb.Execute(remaining, os.Stdout))
+			return nil
+		case "timer":
+			os.Exit(runTimerCommand(remaining))
+			return nil
 		case "configure":
 			return runOnboarding(true)
 		case "logs":
@@ -213,6 +268,7 @@ func printExamples() {
 	fmt.Println("- openppl motd progress")
 	fmt.Println("- openppl automation status")
 	fmt.Println("- openppl automation action --name remind --request-id req-001")
+	fmt.Println("- openppl automation action --name task.add --request-id req-002 --arg date=2026-03-04 --arg category=Theory --arg title=Airspace")
 }
 
 func printGuide() {
@@ -261,6 +317,36 @@ func resolveCommand(args []string) (string, []string) {
 		return "onboard", args[1:]
 	case "automation", "auto":
 		return "automation", args[1:]
+	case "webhooks", "webhook", "hooks":
+		return "webhooks", args[1:]
+	case "import":
+		return "import", args[1:]
+	case "daemon", "notify", "notifications":
+		return "daemon", args[1:]
+	case "plan", "syllabus":
+		return "plan", args[1:]
+	case "endorsements", "endorsement", "endorse":
+		return "endorsements", args[1:]
+	case "checklist":
+		return "checklist", args[1:]
+	case "aircraft", "plane", "planes", "fleet":
+		return "aircraft", args[1:]
+	case "documents", "document", "docs", "medical":
+		return "documents", args[1:]
+	case "gonogo", "go-no-go", "go":
+		return "gonogo", args[1:]
+	case "minimums":
+		return "gonogo", args
+	case "airports", "airport":
+		return "airports", args[1:]
+	case "xc", "navlog", "cross-country":
+		return "xc", args[1:]
+	case "logbook", "flights":
+		return "logbook", args[1:]
+	case "wb", "w&b", "weightbalance", "weight":
+		return "wb", args[1:]
+	case "timer", "focus", "pomodoro":
+		return "timer", args[1:]
 	case "highlights", "highlight":
 		return "highlights", args[1:]
 	case "quickstart", "quick":
@@ -310,6 +396,34 @@ func suggestCommand(args []string) string {
 		"onboard":    "onboard",
 		"auto":       "automation",
 		"automation": "automation",
+		"webhook":    "webhooks",
+		"webhooks":   "webhooks",
+		"import":     "import",
+		"daemon":     "daemon",
+		"plan":       "plan",
+		"template":   "plan",
+		"endorse":    "endorsements",
+		"checklist":  "checklist",
+		"aircraft":   "aircraft",
+		"airworthy":  "aircraft",
+		"documents":  "documents",
+		"medical":    "documents",
+		"gonogo":     "gonogo",
+		"nogo":       "gonogo",
+		"minimums":   "gonogo",
+		"airport":    "airports",
+		"airports":   "airports",
+		"distance":   "airports",
+		"xc":         "xc",
+		"navlog":     "xc",
+		"logbook":    "logbook",
+		"flights":    "logbook",
+		"wb":         "wb",
+		"balance":    "wb",
+		"cg":         "wb",
+		"timer":      "timer",
+		"focus":      "timer",
+		"pomodoro":   "timer",
 		"config":     "configure",
 		"configure":  "configure",
 		"help":       "help",
@@ -374,6 +488,31 @@ func usageText() string {
   openppl automation     Run non-interactive automation commands
   openppl automation status
   openppl automation action --name remind --request-id <id>
+  openppl webhooks      Show webhook outbox status
+  openppl webhooks deliver
+  openppl import ics <file>  Apply completed to-dos from an ICS file
+  openppl daemon        Run desktop reminder notifications
+  openppl daemon install
+  openppl plan template export|validate|use <file>
+  openppl endorsements  List CFI endorsements and lapse warnings
+  openppl endorsements add --kind solo --date YYYY-MM-DD --cfi "<name>" --cert <number>
+  openppl checklist     Show the pre-checkride checklist
+  openppl checklist upgrade|reset --yes
+  openppl aircraft      Check aircraft inspections and ADs for the checkride
+  openppl aircraft set N12345 --type C172 --annual YYYY-MM-DD
+  openppl documents     Show medical, student certificate and knowledge test expiry
+  openppl documents set medical --date YYYY-MM-DD
+  openppl gonogo        Check weather and PAVE/IMSAFE against personal minimums
+  openppl gonogo --airport KFXE --solo --at "YYYY-MM-DD HH:MM"
+  openppl airports      Search the airport database
+  openppl airports distance KFXE KPBI KVRB KFXE
+  openppl xc plan KFXE KPBI KSUA --wind 270@15 --pdf navlog.pdf
+  openppl xc log        Show planned and flown cross-countries against 61.109
+  openppl logbook       Show logged flight time against 61.109
+  openppl logbook add --hours 1.3 [--solo] [--xc] [--date YYYY-MM-DD]
+  openppl wb c172s front=340 rear=150 baggage1=30 --fuel 40 --burn 10
+  openppl timer start [<task-id>] [--pomodoro]  Time study on a task
+  openppl timer stop|report
   openppl version       Show installed version
   openppl web           Launch web UI and open browser
   openppl web --hostname 0.0.0.0 --port 5016
@@ -437,9 +576,131 @@ func runMotdCommand(args []string) int {
 	// IMPORTANT: do NOT call initDatabaseFn() here.
 	// The display subcommand (default) is database-free and must be fast.
 	// The recall subcommand initializes its own DB via services.InitMOTDDB().
+	// Only progress opens the study database, for the readiness score.
+	motd.OpenStudyDB = initDatabaseFn
 	return motd.Execute(args, os.Stdin, os.Stdout)
 }
 
+func runWebhooksCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return webhooks.Execute(database, args, os.Stdout)
+}
+
+func runDaemonCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return daemon.Execute(database, args, os.Stdout)
+}
+
+func runEndorsementsCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return endorsements.Execute(database, args, os.Stdout)
+}
+
+func runChecklistCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return checklist.Execute(database, args, os.Stdout)
+}
+
+func runAircraftCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return aircraft.Execute(database, args, os.Stdout)
+}
+
+func runDocumentsCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return documents.Execute(database, args, os.Stdout)
+}
+
+func runGoNoGoCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return gonogo.Execute(database, args, os.Stdout)
+}
+
+func runAirportsCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return airports.Execute(database, args, os.Stdout)
+}
+
+func runXCCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return xc.Execute(database, args, os.Stdout)
+}
+
+func runLogbookCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return logbook.Execute(database, args, os.Stdout)
+}
+
+func runTimerCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return timer.Execute(database, args, os.Stdout)
+}
+
+func runImportCommand(args []string) int {
+	database, err := initDatabaseFn()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+
+	return importer.Execute(database, args, os.Stdout)
+}
+
 func runAutomationCommand(args []string, stdout io.Writer, stderr io.Writer) int {
 	database, err := initDatabaseFn()
 	if err != nil {
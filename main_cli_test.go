@@ -17,6 +17,19 @@ func TestResolveCommand_RecognizesAliases(t *testing.T) {
 		{name: "onboarding alias", args: []string{"onboarding"}, wantCmd: "onboard", wantAfter: 0},
 		{name: "version alias", args: []string{"ver"}, wantCmd: "version", wantAfter: 0},
 		{name: "quick start phrase", args: []string{"Quick", "start"}, wantCmd: "quickstart", wantAfter: 0},
+		{name: "notify alias maps to daemon", args: []string{"notify", "once"}, wantCmd: "daemon", wantAfter: 1},
+		{name: "plan template keeps args", args: []string{"plan", "template", "validate", "school.json"}, wantCmd: "plan", wantAfter: 3},
+		{name: "endorse alias maps to endorsements", args: []string{"endorse", "add", "--kind", "solo"}, wantCmd: "endorsements", wantAfter: 3},
+		{name: "checklist keeps args", args: []string{"checklist", "reset", "--yes"}, wantCmd: "checklist", wantAfter: 2},
+		{name: "plane alias maps to aircraft", args: []string{"plane", "check", "N12345"}, wantCmd: "aircraft", wantAfter: 2},
+		{name: "medical alias maps to documents", args: []string{"medical", "set", "medical"}, wantCmd: "documents", wantAfter: 2},
+		{name: "minimums alias maps to gonogo", args: []string{"minimums", "set", "--solo"}, wantCmd: "gonogo", wantAfter: 3},
+		{name: "airport alias maps to airports", args: []string{"airport", "show", "KFXE"}, wantCmd: "airports", wantAfter: 2},
+		{name: "navlog alias maps to xc", args: []string{"navlog", "plan", "KPBI"}, wantCmd: "xc", wantAfter: 2},
+		{name: "flights alias maps to logbook", args: []string{"flights", "add", "--hours", "1.3"}, wantCmd: "logbook", wantAfter: 3},
+		{name: "weight-balance alias maps to wb", args: []string{"weight-balance", "c172s", "front=340"}, wantCmd: "wb", wantAfter: 2},
+		{name: "pomodoro alias maps to timer", args: []string{"pomodoro", "start", "--pomodoro"}, wantCmd: "timer", wantAfter: 2},
+		{name: "import keeps args", args: []string{"import", "ics", "todos.ics"}, wantCmd: "import", wantAfter: 2},
 		{name: "web keeps flags", args: []string{"web", "--hostname", "0.0.0.0"}, wantCmd: "web", wantAfter: 2},
 	}
 
@@ -3,6 +3,7 @@ import { OverviewCards, type OverviewMetrics } from "@/components/dashboard/over
 import { ProgressChart, type ProgressPoint } from "@/components/dashboard/progress-chart"
 import { TasksTable, type TaskRow } from "@/components/dashboard/tasks-table"
 import { ThemeToggle } from "@/components/theme-toggle"
+import { loadProgressHistory } from "@/lib/progress-history"
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card"
 import { SidebarInset, SidebarProvider, SidebarTrigger } from "@/components/ui/sidebar"
 
@@ -12,6 +13,7 @@ type DashboardData = {
   state: ViewState
   metrics: OverviewMetrics
   progress: ProgressPoint[]
+  projection?: string
   tasks: TaskRow[]
 }
 
@@ -59,9 +61,16 @@ function resolveState(value?: string): ViewState {
   return "success"
 }
 
-export default function DashboardPage({ searchParams }: { searchParams?: { state?: string } }) {
+export default async function DashboardPage({ searchParams }: { searchParams?: { state?: string } }) {
   const state = resolveState(searchParams?.state)
   const dashboard = buildDashboardData(state)
+  if (dashboard.progress.length > 0) {
+    const history = await loadProgressHistory()
+    if (history && history.points.length > 0) {
+      dashboard.progress = history.points
+      dashboard.projection = history.projection
+    }
+  }
 
   const stateBanner =
     state === "warning"
@@ -132,7 +141,7 @@ export default function DashboardPage({ searchParams }: { searchParams?: { state
                 <OverviewCards metrics={dashboard.metrics} />
                 <div className="grid gap-6 xl:grid-cols-5">
                   <div className="xl:col-span-3">
-                    <ProgressChart data={dashboard.progress} />
+                    <ProgressChart data={dashboard.progress} projection={dashboard.projection} />
                   </div>
                   <div className="xl:col-span-2">
                     <TasksTable data={dashboard.tasks} />
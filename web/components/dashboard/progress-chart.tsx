@@ -9,14 +9,16 @@ export interface ProgressPoint {
   week: string
   completed: number
   target: number
+  readiness?: number
 }
 
-export function ProgressChart({ data }: { data: ProgressPoint[] }) {
+export function ProgressChart({ data, projection }: { data: ProgressPoint[]; projection?: string }) {
+  const showReadiness = data.some((point) => point.readiness !== undefined)
   return (
     <Card>
       <CardHeader>
         <CardTitle>Progress vs Target</CardTitle>
-        <CardDescription>Weekly completion trend for current study block</CardDescription>
+        <CardDescription>{projection ?? "Weekly completion trend for current study block"}</CardDescription>
       </CardHeader>
       <CardContent>
         <ChartContainer>
@@ -34,6 +36,9 @@ export function ProgressChart({ data }: { data: ProgressPoint[] }) {
               />
               <Line type="monotone" dataKey="completed" stroke="var(--color-progress)" strokeWidth={3} dot={false} />
               <Line type="monotone" dataKey="target" stroke="var(--color-target)" strokeWidth={2} strokeDasharray="8 6" dot={false} />
+              {showReadiness ? (
+                <Line type="monotone" dataKey="readiness" stroke="var(--color-readiness)" strokeWidth={2} dot={false} />
+              ) : null}
             </LineChart>
           </ResponsiveContainer>
         </ChartContainer>
@@ -41,6 +46,7 @@ export function ProgressChart({ data }: { data: ProgressPoint[] }) {
           items={[
             { label: "Completed", color: "var(--color-progress)" },
             { label: "Target", color: "var(--color-target)" },
+            ...(showReadiness ? [{ label: "Readiness", color: "var(--color-readiness)" }] : []),
           ]}
         />
       </CardContent>
@@ -0,0 +1,46 @@
+import type { ProgressPoint } from "@/components/dashboard/progress-chart"
+
+type Snapshot = {
+  date: string
+  progress_percent: number
+  planned_percent: number
+  readiness_score: number
+}
+
+type History = {
+  snapshots: Snapshot[]
+  projection: { ok: boolean; date?: string; summary: string }
+}
+
+export type ProgressHistory = {
+  points: ProgressPoint[]
+  projection: string
+}
+
+// loadProgressHistory reads the daily snapshots from the Go web server at
+// OPENPPL_API_URL (for example http://127.0.0.1:8080). It returns null when
+// the URL is not set or the server cannot be reached.
+export async function loadProgressHistory(days = 90): Promise<ProgressHistory | null> {
+  const base = process.env.OPENPPL_API_URL
+  if (!base) {
+    return null
+  }
+  try {
+    const response = await fetch(`${base.replace(/\/$/, "")}/api/progress/history?days=${days}`, { cache: "no-store" })
+    if (!response.ok) {
+      return null
+    }
+    const history = (await response.json()) as History
+    return {
+      points: history.snapshots.map((snapshot) => ({
+        week: new Date(snapshot.date).toLocaleDateString("en-US", { month: "short", day: "2-digit", timeZone: "UTC" }),
+        completed: Math.round(snapshot.progress_percent),
+        target: Math.round(snapshot.planned_percent),
+        readiness: Math.round(snapshot.readiness_score),
+      })),
+      projection: history.projection.summary,
+    }
+  } catch {
+    return null
+  }
+}